@@ -0,0 +1,350 @@
+// Package alerting watches the app and service health in the background. The health is evaluated on a schedule
+// against the health config tolerances, the status transitions are kept in a history and notified to the
+// configured sinks: generic webhooks, Slack webhooks or the Alertmanager v2 API.
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// Alerter evaluates the health and keeps the alerts, i.e. the degraded or failing apps and services. It
+// notifies each sink when an alert starts firing for the sink, changes status or resolves. Unchanged firing
+// alerts are notified again once the repeat interval elapses, every evaluation for Alertmanager.
+type Alerter struct {
+	conf     config.AlertingConfig
+	getLayer func() (*business.Layer, error)
+	sinks    []alertSink
+
+	mutex          sync.RWMutex
+	alerts         map[models.AlertKey]*alertState
+	history        []models.AlertEvent
+	lastEvaluation time.Time
+}
+
+type alertSink struct {
+	name           string
+	sink           Sink
+	minSeverity    int
+	repeatInterval time.Duration
+}
+
+type alertState struct {
+	alert models.Alert
+	// lastNotified is the time of the last notification by sink, zero when not notified
+	lastNotified []time.Time
+}
+
+type delivery struct {
+	sink         alertSink
+	notification Notification
+}
+
+// leaseName is the Lease, in the Kiali deployment namespace, electing the replica evaluating the health
+const leaseName = "kiali-health-alerting"
+
+var (
+	alerter      *Alerter
+	alerterMutex sync.RWMutex
+	stopAlerter  context.CancelFunc
+)
+
+// Start starts watching the health in the background, when alerting is enabled. Health is evaluated with the
+// Kiali service account, by the replica holding the alerting Lease: the sinks are notified once whatever the
+// number of replicas.
+func Start() {
+	conf := config.Get()
+	if !conf.Alerting.Enabled {
+		return
+	}
+
+	a, err := NewAlerter(conf.Alerting, serviceAccountLayer)
+	if err != nil {
+		log.Errorf("Health alerting is not started: %v", err)
+		return
+	}
+	lock, err := kubernetes.NewLeaseLock(conf.Deployment.Namespace, leaseName)
+	if err != nil {
+		log.Errorf("Health alerting is not started: %v", err)
+		return
+	}
+
+	alerterMutex.Lock()
+	defer alerterMutex.Unlock()
+	alerter = a
+	ctx, cancel := context.WithCancel(context.Background())
+	stopAlerter = cancel
+	go kubernetes.RunLeader(ctx, "Health alerting", lock, func(leading context.Context) {
+		a.Run(leading.Done())
+	})
+	log.Infof("Health alerting started, evaluating every [%ds] with [%d] sinks when holding the Lease [%s/%s]", conf.Alerting.Interval, len(conf.Alerting.Sinks), conf.Deployment.Namespace, leaseName)
+}
+
+// Stop stops watching the health and releases the alerting Lease
+func Stop() {
+	alerterMutex.Lock()
+	defer alerterMutex.Unlock()
+	if stopAlerter != nil {
+		stopAlerter()
+		stopAlerter = nil
+	}
+	alerter = nil
+}
+
+// Get returns the running Alerter, nil when alerting is disabled. Only the Alerter of the leading replica has alerts.
+func Get() *Alerter {
+	alerterMutex.RLock()
+	defer alerterMutex.RUnlock()
+	return alerter
+}
+
+func serviceAccountLayer() (*business.Layer, error) {
+	token, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+	return business.Get(&api.AuthInfo{Token: token})
+}
+
+// NewAlerter creates an Alerter for the given config, getLayer returns the business layer evaluating the health
+func NewAlerter(conf config.AlertingConfig, getLayer func() (*business.Layer, error)) (*Alerter, error) {
+	if conf.Interval <= 0 {
+		return nil, fmt.Errorf("invalid alerting interval [%d], it must be positive", conf.Interval)
+	}
+	interval := time.Duration(conf.Interval) * time.Second
+
+	a := &Alerter{
+		conf:     conf,
+		getLayer: getLayer,
+		sinks:    make([]alertSink, 0, len(conf.Sinks)),
+		alerts:   map[models.AlertKey]*alertState{},
+		history:  []models.AlertEvent{},
+	}
+	for _, sinkConf := range conf.Sinks {
+		sink, err := NewSink(sinkConf, interval)
+		if err != nil {
+			return nil, err
+		}
+		minSeverity := models.HealthSeverity(models.HealthStatusDegraded)
+		if sinkConf.MinStatus != "" {
+			if minSeverity = models.HealthSeverity(sinkConf.MinStatus); minSeverity == 0 {
+				return nil, fmt.Errorf("alert sink [%s] has an invalid min_status [%s], expecting degraded or failure", sinkConf.Name, sinkConf.MinStatus)
+			}
+		}
+		repeatInterval := time.Duration(conf.RepeatInterval) * time.Second
+		if sinkConf.Type == config.AlertSinkAlertmanager {
+			repeatInterval = interval
+		}
+		a.sinks = append(a.sinks, alertSink{name: sinkConf.Name, sink: sink, minSeverity: minSeverity, repeatInterval: repeatInterval})
+	}
+	return a, nil
+}
+
+// Run evaluates the health every interval, until stop is closed
+func (a *Alerter) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Duration(a.conf.Interval) * time.Second)
+	defer ticker.Stop()
+
+	a.Evaluate()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			a.Evaluate()
+		}
+	}
+}
+
+// Evaluate evaluates the app and service health of the watched namespaces, and notifies the transitions. The
+// alerts of a namespace whose health can't be evaluated are kept unchanged.
+func (a *Alerter) Evaluate() {
+	layer, err := a.getLayer()
+	if err != nil {
+		log.Errorf("Health alerting: unable to initialize the business layer: %v", err)
+		return
+	}
+
+	namespaces := a.conf.Namespaces
+	if len(namespaces) == 0 {
+		nss, err := layer.Namespace.GetNamespaces()
+		if err != nil {
+			log.Errorf("Health alerting: unable to list the namespaces: %v", err)
+			return
+		}
+		for _, ns := range nss {
+			namespaces = append(namespaces, ns.Name)
+		}
+	}
+
+	now := util.Clock.Now()
+	statuses := map[models.AlertKey]models.HealthStatus{}
+	evaluated := map[string]bool{}
+	for _, namespace := range namespaces {
+		appHealth, err := layer.Health.GetNamespaceAppHealth(namespace, a.conf.RateInterval, now)
+		if err != nil {
+			log.Warningf("Health alerting: unable to get the app health of namespace [%s]: %v", namespace, err)
+			continue
+		}
+		serviceHealth, err := layer.Health.GetNamespaceServiceHealth(namespace, a.conf.RateInterval, now)
+		if err != nil {
+			log.Warningf("Health alerting: unable to get the service health of namespace [%s]: %v", namespace, err)
+			continue
+		}
+
+		evaluated[namespace] = true
+		for app, health := range appHealth {
+			statuses[models.AlertKey{Namespace: namespace, Kind: models.HealthKindApp, Name: app}] = health.Status(namespace, app)
+		}
+		for service, health := range serviceHealth {
+			statuses[models.AlertKey{Namespace: namespace, Kind: models.HealthKindService, Name: service}] = health.Status(namespace, service)
+		}
+	}
+
+	a.notify(a.update(statuses, evaluated, now))
+}
+
+// update applies the evaluated statuses of the evaluated namespaces, the apps and services not found are
+// healthy. It returns the notifications to deliver.
+func (a *Alerter) update(statuses map[models.AlertKey]models.HealthStatus, evaluated map[string]bool, now time.Time) []delivery {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	a.lastEvaluation = now
+	for key := range a.alerts {
+		if _, found := statuses[key]; !found && evaluated[key.Namespace] {
+			statuses[key] = models.HealthStatus{Status: models.HealthStatusHealthy}
+		}
+	}
+
+	keys := make([]models.AlertKey, 0, len(statuses))
+	for key := range statuses {
+		keys = append(keys, key)
+	}
+	sortAlertKeys(keys)
+
+	deliveries := []delivery{}
+	for _, key := range keys {
+		status := statuses[key]
+		state, firing := a.alerts[key]
+		previous := models.HealthStatusHealthy
+		if firing {
+			previous = state.alert.Status
+		}
+		if !firing && status.Status == models.HealthStatusHealthy {
+			continue
+		}
+
+		if !firing {
+			state = &alertState{
+				alert:        models.Alert{AlertKey: key},
+				lastNotified: make([]time.Time, len(a.sinks)),
+			}
+			a.alerts[key] = state
+		}
+		if status.Status != previous {
+			a.addEvent(models.AlertEvent{AlertKey: key, From: previous, To: status.Status, Reason: status.Reason, Time: now})
+			state.alert.Since = now
+		}
+		state.alert.HealthStatus = status
+
+		for i, sink := range a.sinks {
+			wasFiring := models.HealthSeverity(previous) >= sink.minSeverity
+			isFiring := models.HealthSeverity(status.Status) >= sink.minSeverity
+			notification := Notification{Alert: state.alert, PreviousStatus: previous, Time: now}
+
+			switch {
+			case isFiring && (status.Status != previous || sink.repeatInterval > 0 && !now.Before(state.lastNotified[i].Add(sink.repeatInterval))):
+				state.lastNotified[i] = now
+				deliveries = append(deliveries, delivery{sink: sink, notification: notification})
+			case wasFiring && !isFiring:
+				notification.Resolved = true
+				state.lastNotified[i] = time.Time{}
+				deliveries = append(deliveries, delivery{sink: sink, notification: notification})
+			}
+		}
+
+		if status.Status == models.HealthStatusHealthy {
+			delete(a.alerts, key)
+		}
+	}
+	return deliveries
+}
+
+func (a *Alerter) addEvent(event models.AlertEvent) {
+	a.history = append(a.history, event)
+	if a.conf.HistorySize > 0 && len(a.history) > a.conf.HistorySize {
+		a.history = append([]models.AlertEvent{}, a.history[len(a.history)-a.conf.HistorySize:]...)
+	}
+}
+
+func (a *Alerter) notify(deliveries []delivery) {
+	for _, d := range deliveries {
+		if err := d.sink.sink.Notify(d.notification); err != nil {
+			log.Warningf("Health alerting: unable to notify sink [%s] of [%s]: %v", d.sink.name, d.notification.Summary(), err)
+		}
+	}
+}
+
+// Alerts returns the current alerts of the given namespaces, the most severe first
+func (a *Alerter) Alerts(namespaces map[string]bool) models.Alerts {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	alerts := models.Alerts{LastEvaluation: a.lastEvaluation, Alerts: []models.Alert{}}
+	for key, state := range a.alerts {
+		if namespaces[key.Namespace] {
+			alerts.Alerts = append(alerts.Alerts, state.alert)
+		}
+	}
+	sort.Slice(alerts.Alerts, func(i, j int) bool {
+		si, sj := models.HealthSeverity(alerts.Alerts[i].Status), models.HealthSeverity(alerts.Alerts[j].Status)
+		if si != sj {
+			return si > sj
+		}
+		return alertKeyLess(alerts.Alerts[i].AlertKey, alerts.Alerts[j].AlertKey)
+	})
+	return alerts
+}
+
+// History returns the status transitions of the given namespaces, the most recent first
+func (a *Alerter) History(namespaces map[string]bool) []models.AlertEvent {
+	a.mutex.RLock()
+	defer a.mutex.RUnlock()
+
+	history := []models.AlertEvent{}
+	for i := len(a.history) - 1; i >= 0; i-- {
+		if namespaces[a.history[i].Namespace] {
+			history = append(history, a.history[i])
+		}
+	}
+	return history
+}
+
+func sortAlertKeys(keys []models.AlertKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		return alertKeyLess(keys[i], keys[j])
+	})
+}
+
+func alertKeyLess(a, b models.AlertKey) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Kind != b.Kind {
+		return a.Kind < b.Kind
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,181 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// recorder records the JSON bodies posted to a sink
+type recorder struct {
+	mutex  sync.Mutex
+	bodies []json.RawMessage
+	server *httptest.Server
+}
+
+func newRecorder() *recorder {
+	r := &recorder{}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var body json.RawMessage
+		_ = json.NewDecoder(req.Body).Decode(&body)
+		r.mutex.Lock()
+		r.bodies = append(r.bodies, body)
+		r.mutex.Unlock()
+	}))
+	return r
+}
+
+func (r *recorder) notifications(t *testing.T) []Notification {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	notifications := make([]Notification, 0, len(r.bodies))
+	for _, body := range r.bodies {
+		n := Notification{}
+		require.NoError(t, json.Unmarshal(body, &n))
+		notifications = append(notifications, n)
+	}
+	r.bodies = nil
+	return notifications
+}
+
+func newTestAlerter(t *testing.T, sinks ...config.AlertSinkConfig) *Alerter {
+	conf := config.NewConfig().Alerting
+	conf.HistorySize = 3
+	conf.Sinks = sinks
+	a, err := NewAlerter(conf, nil)
+	require.NoError(t, err)
+	return a
+}
+
+var (
+	reviewsKey = models.AlertKey{Namespace: "bookinfo", Kind: models.HealthKindApp, Name: "reviews"}
+	ratingsKey = models.AlertKey{Namespace: "bookinfo", Kind: models.HealthKindService, Name: "ratings"}
+	bookinfo   = map[string]bool{"bookinfo": true}
+)
+
+func evaluate(a *Alerter, now time.Time, statuses map[models.AlertKey]models.HealthStatus) {
+	a.notify(a.update(statuses, bookinfo, now))
+}
+
+func TestAlerterTransitions(t *testing.T) {
+	assert := assert.New(t)
+	webhook := newRecorder()
+	defer webhook.server.Close()
+	critical := newRecorder()
+	defer critical.server.Close()
+
+	a := newTestAlerter(t,
+		config.AlertSinkConfig{Name: "all", Type: config.AlertSinkWebhook, URL: webhook.server.URL},
+		config.AlertSinkConfig{Name: "critical", Type: config.AlertSinkWebhook, URL: critical.server.URL, MinStatus: models.HealthStatusFailure},
+	)
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	degraded := models.HealthStatus{Status: models.HealthStatusDegraded, Reason: "inbound http requests with code 4XX at 15.00%, threshold 10.00%"}
+	failure := models.HealthStatus{Status: models.HealthStatusFailure, Reason: "inbound http requests with code 5XX at 50.00%, threshold 10.00%"}
+	healthy := models.HealthStatus{Status: models.HealthStatusHealthy}
+
+	// healthy apps and services are not alerts
+	evaluate(a, t0, map[models.AlertKey]models.HealthStatus{reviewsKey: healthy, ratingsKey: healthy})
+	assert.Empty(a.Alerts(bookinfo).Alerts)
+	assert.Empty(webhook.notifications(t))
+
+	// healthy -> degraded is notified to the sinks notifying degraded alerts
+	evaluate(a, t0.Add(time.Minute), map[models.AlertKey]models.HealthStatus{reviewsKey: degraded, ratingsKey: healthy})
+	alerts := a.Alerts(bookinfo)
+	assert.Equal(t0.Add(time.Minute), alerts.LastEvaluation)
+	assert.Len(alerts.Alerts, 1)
+	assert.Equal(models.Alert{AlertKey: reviewsKey, HealthStatus: degraded, Since: t0.Add(time.Minute)}, alerts.Alerts[0])
+	notifications := webhook.notifications(t)
+	assert.Len(notifications, 1)
+	assert.Equal(models.HealthStatusHealthy, notifications[0].PreviousStatus)
+	assert.Equal(models.HealthStatusDegraded, notifications[0].Status)
+	assert.False(notifications[0].Resolved)
+	assert.Empty(critical.notifications(t))
+
+	// an unchanged alert is not notified again before the repeat interval
+	evaluate(a, t0.Add(2*time.Minute), map[models.AlertKey]models.HealthStatus{reviewsKey: degraded, ratingsKey: healthy})
+	assert.Empty(webhook.notifications(t))
+
+	// degraded -> failure
+	evaluate(a, t0.Add(3*time.Minute), map[models.AlertKey]models.HealthStatus{reviewsKey: failure, ratingsKey: healthy})
+	notifications = webhook.notifications(t)
+	assert.Len(notifications, 1)
+	assert.Equal(models.HealthStatusDegraded, notifications[0].PreviousStatus)
+	assert.Equal(models.HealthStatusFailure, notifications[0].Status)
+	assert.Equal(t0.Add(3*time.Minute), notifications[0].Since)
+	notifications = critical.notifications(t)
+	assert.Len(notifications, 1)
+	assert.Equal(models.HealthStatusFailure, notifications[0].Status)
+
+	// failure -> degraded resolves the alert for the sink notifying failures only
+	evaluate(a, t0.Add(4*time.Minute), map[models.AlertKey]models.HealthStatus{reviewsKey: degraded, ratingsKey: healthy})
+	notifications = webhook.notifications(t)
+	assert.Len(notifications, 1)
+	assert.False(notifications[0].Resolved)
+	notifications = critical.notifications(t)
+	assert.Len(notifications, 1)
+	assert.True(notifications[0].Resolved)
+	assert.Equal(models.HealthStatusDegraded, notifications[0].Status)
+
+	// the repeat interval elapsed
+	evaluate(a, t0.Add(4*time.Minute+time.Hour), map[models.AlertKey]models.HealthStatus{reviewsKey: degraded, ratingsKey: healthy})
+	notifications = webhook.notifications(t)
+	assert.Len(notifications, 1)
+	assert.Equal(models.HealthStatusDegraded, notifications[0].PreviousStatus)
+	assert.Empty(critical.notifications(t))
+
+	// an app no longer found in an evaluated namespace is healthy
+	evaluate(a, t0.Add(2*time.Hour), map[models.AlertKey]models.HealthStatus{ratingsKey: healthy})
+	assert.Empty(a.Alerts(bookinfo).Alerts)
+	notifications = webhook.notifications(t)
+	assert.Len(notifications, 1)
+	assert.True(notifications[0].Resolved)
+	assert.Equal(models.HealthStatusHealthy, notifications[0].Status)
+
+	// the history is capped, the most recent first
+	history := a.History(bookinfo)
+	assert.Len(history, 3)
+	assert.Equal(models.AlertEvent{AlertKey: reviewsKey, From: models.HealthStatusDegraded, To: models.HealthStatusHealthy, Time: t0.Add(2 * time.Hour)}, history[0])
+	assert.Equal(models.HealthStatusFailure, history[1].From)
+	assert.Equal(models.HealthStatusDegraded, history[1].To)
+	assert.Equal(models.HealthStatusFailure, history[2].To)
+	assert.Empty(a.History(map[string]bool{"other": true}))
+}
+
+func TestAlerterNamespaceNotEvaluated(t *testing.T) {
+	assert := assert.New(t)
+	a := newTestAlerter(t)
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	evaluate(a, t0, map[models.AlertKey]models.HealthStatus{reviewsKey: {Status: models.HealthStatusFailure}})
+	assert.Len(a.Alerts(bookinfo).Alerts, 1)
+
+	// the alerts of a namespace that could not be evaluated are kept
+	a.notify(a.update(map[models.AlertKey]models.HealthStatus{}, map[string]bool{}, t0.Add(time.Minute)))
+	assert.Len(a.Alerts(bookinfo).Alerts, 1)
+	assert.Empty(a.Alerts(map[string]bool{"other": true}).Alerts)
+}
+
+func TestNewAlerterInvalidConfig(t *testing.T) {
+	conf := config.NewConfig().Alerting
+
+	conf.Sinks = []config.AlertSinkConfig{{Name: "pager", Type: "pager", URL: "http://pager"}}
+	_, err := NewAlerter(conf, nil)
+	assert.Error(t, err)
+
+	conf.Sinks = []config.AlertSinkConfig{{Name: "slack", Type: config.AlertSinkSlack, URL: "http://slack", MinStatus: "healthy"}}
+	_, err = NewAlerter(conf, nil)
+	assert.Error(t, err)
+
+	conf.Sinks = []config.AlertSinkConfig{{Name: "slack", Type: config.AlertSinkSlack}}
+	_, err = NewAlerter(conf, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// AlertName is the Alertmanager alertname label of the health alerts
+const AlertName = "KialiHealth"
+
+// Notification is sent to a sink when an alert starts firing, or resolves, for the sink. A firing alert is
+// notified again when its status changes, or when the repeat interval elapses.
+type Notification struct {
+	models.Alert
+
+	// PreviousStatus is the health status before the transition, the same status for a repeated notification
+	PreviousStatus string `json:"previousStatus"`
+
+	// Resolved is true when the health status is no longer severe enough for the sink
+	Resolved bool `json:"resolved"`
+
+	Time time.Time `json:"time"`
+}
+
+// Summary is a one line description of the notification
+func (n Notification) Summary() string {
+	if n.Resolved {
+		return fmt.Sprintf("%s %s/%s is %s, was %s", n.Kind, n.Namespace, n.Name, n.Status, n.PreviousStatus)
+	}
+	return fmt.Sprintf("%s %s/%s is %s: %s", n.Kind, n.Namespace, n.Name, n.Status, n.Reason)
+}
+
+// Sink delivers the notifications to an external system
+type Sink interface {
+	Notify(n Notification) error
+}
+
+// NewSink creates the sink of the given configuration. The Alertmanager sink needs the interval between the
+// notifications of a firing alert, so that the alert doesn't resolve in between.
+func NewSink(conf config.AlertSinkConfig, refreshInterval time.Duration) (Sink, error) {
+	if conf.URL == "" {
+		return nil, fmt.Errorf("alert sink [%s] has no url", conf.Name)
+	}
+	transport, err := httputil.CreateTransport(&conf.Auth, &http.Transport{}, httputil.DefaultTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("alert sink [%s]: %v", conf.Name, err)
+	}
+	client := &http.Client{Transport: transport, Timeout: httputil.DefaultTimeout}
+
+	switch conf.Type {
+	case config.AlertSinkWebhook:
+		return webhookSink{client: client, url: conf.URL}, nil
+	case config.AlertSinkSlack:
+		return slackSink{client: client, url: conf.URL}, nil
+	case config.AlertSinkAlertmanager:
+		return alertmanagerSink{client: client, url: strings.TrimSuffix(conf.URL, "/") + "/api/v2/alerts", refreshInterval: refreshInterval}, nil
+	default:
+		return nil, fmt.Errorf("alert sink [%s] has an invalid type [%s], expecting webhook, slack or alertmanager", conf.Name, conf.Type)
+	}
+}
+
+// webhookSink posts the notification as JSON
+type webhookSink struct {
+	client *http.Client
+	url    string
+}
+
+func (s webhookSink) Notify(n Notification) error {
+	return postJSON(s.client, s.url, n)
+}
+
+// slackSink posts the notification as a Slack incoming webhook message
+type slackSink struct {
+	client *http.Client
+	url    string
+}
+
+type slackMessage struct {
+	Text        string            `json:"text"`
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color  string       `json:"color"`
+	Fields []slackField `json:"fields"`
+	Ts     int64        `json:"ts"`
+}
+
+type slackField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+func (s slackSink) Notify(n Notification) error {
+	color := "good"
+	title := "[RESOLVED]"
+	if !n.Resolved {
+		title = "[" + strings.ToUpper(n.Status) + "]"
+		color = "warning"
+		if n.Status == models.HealthStatusFailure {
+			color = "danger"
+		}
+	}
+
+	fields := []slackField{
+		{Title: "Namespace", Value: n.Namespace, Short: true},
+		{Title: strings.Title(n.Kind), Value: n.Name, Short: true},
+	}
+	if n.Reason != "" {
+		fields = append(fields, slackField{Title: "Reason", Value: n.Reason})
+	}
+
+	return postJSON(s.client, s.url, slackMessage{
+		Text:        title + " " + n.Summary(),
+		Attachments: []slackAttachment{{Color: color, Fields: fields, Ts: n.Time.Unix()}},
+	})
+}
+
+// alertmanagerSink posts the notification to the Alertmanager v2 API. A firing alert ends after twice the
+// refresh interval unless notified again, a resolved alert ends immediately. The severity label follows the health
+// status, so a status change also resolves the alert with the previous severity.
+type alertmanagerSink struct {
+	client          *http.Client
+	url             string
+	refreshInterval time.Duration
+}
+
+type alertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+}
+
+func (s alertmanagerSink) Notify(n Notification) error {
+	alerts := []alertmanagerAlert{}
+	if n.Resolved || n.PreviousStatus != n.Status && models.HealthSeverity(n.PreviousStatus) > 0 {
+		alerts = append(alerts, s.newAlert(n, n.PreviousStatus, n.Time))
+	}
+	if !n.Resolved {
+		alerts = append(alerts, s.newAlert(n, n.Status, n.Time.Add(2*s.refreshInterval)))
+	}
+	return postJSON(s.client, s.url, alerts)
+}
+
+func (s alertmanagerSink) newAlert(n Notification, status string, endsAt time.Time) alertmanagerAlert {
+	severity := "warning"
+	if status == models.HealthStatusFailure {
+		severity = "critical"
+	}
+	annotations := map[string]string{"summary": n.Summary()}
+	if n.Reason != "" {
+		annotations["description"] = n.Reason
+	}
+
+	return alertmanagerAlert{
+		Labels: map[string]string{
+			"alertname": AlertName,
+			"kind":      n.Kind,
+			"name":      n.Name,
+			"namespace": n.Namespace,
+			"severity":  severity,
+		},
+		Annotations: annotations,
+		StartsAt:    n.Since,
+		EndsAt:      endsAt,
+	}
+}
+
+func postJSON(client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("unexpected status [%d]: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	return nil
+}
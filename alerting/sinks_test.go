@@ -0,0 +1,119 @@
+package alerting
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+func newTestNotification() Notification {
+	t0 := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	return Notification{
+		Alert: models.Alert{
+			AlertKey:     models.AlertKey{Namespace: "bookinfo", Kind: models.HealthKindApp, Name: "reviews"},
+			HealthStatus: models.HealthStatus{Status: models.HealthStatusFailure, Reason: "workload reviews-v1 has no available replicas, 1 desired"},
+			Since:        t0,
+		},
+		PreviousStatus: models.HealthStatusDegraded,
+		Time:           t0.Add(time.Minute),
+	}
+}
+
+// postedBody starts a server recording the posted body, and its path and authorization header
+func postedBody(t *testing.T, sinkConf config.AlertSinkConfig, n Notification) (path, authorization string, body []byte) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authorization = r.Header.Get("Authorization")
+		body = make([]byte, r.ContentLength)
+		_, _ = r.Body.Read(body)
+	}))
+	defer server.Close()
+
+	sinkConf.URL = server.URL
+	sink, err := NewSink(sinkConf, time.Minute)
+	require.NoError(t, err)
+	require.NoError(t, sink.Notify(n))
+	return path, authorization, body
+}
+
+func TestSlackSink(t *testing.T) {
+	assert := assert.New(t)
+
+	_, _, body := postedBody(t, config.AlertSinkConfig{Name: "slack", Type: config.AlertSinkSlack}, newTestNotification())
+	message := slackMessage{}
+	require.NoError(t, json.Unmarshal(body, &message))
+	assert.Equal("[FAILURE] app bookinfo/reviews is failure: workload reviews-v1 has no available replicas, 1 desired", message.Text)
+	assert.Len(message.Attachments, 1)
+	assert.Equal("danger", message.Attachments[0].Color)
+	assert.Len(message.Attachments[0].Fields, 3)
+	assert.Equal(slackField{Title: "App", Value: "reviews", Short: true}, message.Attachments[0].Fields[1])
+
+	resolved := newTestNotification()
+	resolved.Status = models.HealthStatusHealthy
+	resolved.Reason = ""
+	resolved.PreviousStatus = models.HealthStatusFailure
+	resolved.Resolved = true
+	_, _, body = postedBody(t, config.AlertSinkConfig{Name: "slack", Type: config.AlertSinkSlack}, resolved)
+	require.NoError(t, json.Unmarshal(body, &message))
+	assert.Equal("[RESOLVED] app bookinfo/reviews is healthy, was failure", message.Text)
+	assert.Equal("good", message.Attachments[0].Color)
+}
+
+func TestAlertmanagerSink(t *testing.T) {
+	assert := assert.New(t)
+	n := newTestNotification()
+
+	path, authorization, body := postedBody(t, config.AlertSinkConfig{
+		Name: "alertmanager",
+		Type: config.AlertSinkAlertmanager,
+		Auth: config.Auth{Type: config.AuthTypeBearer, Token: "secret"},
+	}, n)
+	assert.Equal("/api/v2/alerts", path)
+	assert.Equal("Bearer secret", authorization)
+
+	alerts := []alertmanagerAlert{}
+	require.NoError(t, json.Unmarshal(body, &alerts))
+	// the degraded alert resolves, the failure alert fires
+	assert.Len(alerts, 2)
+	assert.Equal("warning", alerts[0].Labels["severity"])
+	assert.Equal(n.Time, alerts[0].EndsAt.UTC())
+	assert.Equal(map[string]string{
+		"alertname": AlertName,
+		"kind":      "app",
+		"name":      "reviews",
+		"namespace": "bookinfo",
+		"severity":  "critical",
+	}, alerts[1].Labels)
+	assert.Equal("workload reviews-v1 has no available replicas, 1 desired", alerts[1].Annotations["description"])
+	assert.Equal(n.Since, alerts[1].StartsAt.UTC())
+	assert.Equal(n.Time.Add(2*time.Minute), alerts[1].EndsAt.UTC())
+}
+
+func TestWebhookSink(t *testing.T) {
+	assert := assert.New(t)
+
+	_, authorization, body := postedBody(t, config.AlertSinkConfig{
+		Name: "webhook",
+		Type: config.AlertSinkWebhook,
+		Auth: config.Auth{Type: config.AuthTypeBasic, Username: "kiali", Password: "secret"},
+	}, newTestNotification())
+	assert.Equal("Basic a2lhbGk6c2VjcmV0", authorization)
+
+	payload := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal("bookinfo", payload["namespace"])
+	assert.Equal("app", payload["kind"])
+	assert.Equal("reviews", payload["name"])
+	assert.Equal("failure", payload["status"])
+	assert.Equal("degraded", payload["previousStatus"])
+	assert.Equal(false, payload["resolved"])
+	assert.Equal("2021-03-01T10:00:00Z", payload["since"])
+}
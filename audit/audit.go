@@ -0,0 +1,161 @@
+// Package audit keeps the trail of the write operations performed through the Kiali API. Each record holds the
+// user, the object, the operation, the patch and the object state before and after the operation. The records
+// are kept in a pluggable store and can be queried with filters.
+package audit
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// The supported stores
+const (
+	StoreFilesystem string = "filesystem"
+	StoreMemory     string = "memory"
+)
+
+// Store is implemented by each audit record persistence backend
+type Store interface {
+	// Query returns the records matching the query, the most recent first
+	Query(q Query) ([]models.AuditRecord, error)
+
+	// Save stores the record, removing the oldest records if the store is at capacity
+	Save(record models.AuditRecord) error
+}
+
+// Query filters the audit records, the zero values match every record
+type Query struct {
+	// Namespaces of the records, nil for all the namespaces
+	Namespaces map[string]bool
+	User       string
+	Operation  string
+	ObjectType string
+	Name       string
+	From       time.Time
+	To         time.Time
+	// Limit is the maximum number of records returned, 0 means no limit
+	Limit int
+}
+
+// Matches returns true if the record satisfies every filter of the query
+func (q Query) Matches(record *models.AuditRecord) bool {
+	if q.Namespaces != nil && !q.Namespaces[record.Namespace] {
+		return false
+	}
+	if q.User != "" && q.User != record.User {
+		return false
+	}
+	if q.Operation != "" && q.Operation != record.Operation {
+		return false
+	}
+	if q.ObjectType != "" && !strings.EqualFold(q.ObjectType, record.ObjectType) {
+		return false
+	}
+	if q.Name != "" && q.Name != record.Name {
+		return false
+	}
+	if !q.From.IsZero() && record.Timestamp.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && record.Timestamp.After(q.To) {
+		return false
+	}
+	return true
+}
+
+var store Store
+var storeMutex sync.Mutex
+
+// GetStore returns the configured audit store, initializing it on first use
+func GetStore() (Store, error) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+
+	if store == nil {
+		conf := config.Get().Audit
+		if !conf.Enabled {
+			return nil, errors.New("audit trail is disabled")
+		}
+		s, err := NewStore(conf)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	}
+	return store, nil
+}
+
+// SetStore replaces the global audit store, it is intended for testing
+func SetStore(s Store) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	store = s
+}
+
+// NewStore returns the store described by the configuration
+func NewStore(conf config.AuditConfig) (Store, error) {
+	switch conf.Store {
+	case StoreFilesystem:
+		return NewFilesystemStore(conf.Path, conf.MaxRecords)
+	case StoreMemory:
+		return NewMemoryStore(conf.MaxRecords), nil
+	default:
+		return nil, fmt.Errorf("audit store [%s] not supported", conf.Store)
+	}
+}
+
+// Record assigns an ID and a timestamp to the record and saves it in the audit store. It also logs the operation
+// when the audit log is enabled. Audit failures are logged, they never fail the audited operation.
+func Record(record models.AuditRecord) {
+	if record.Timestamp.IsZero() {
+		record.Timestamp = util.Clock.Now()
+	}
+
+	if config.Get().Server.AuditLog {
+		log.Infof("AUDIT User [%s] Msg [%s]", record.User, message(&record))
+	}
+
+	if !config.Get().Audit.Enabled {
+		return
+	}
+	s, err := GetStore()
+	if err != nil {
+		log.Errorf("Audit record of [%s] on [%s/%s/%s] not saved: %v", record.Operation, record.Namespace, record.ObjectType, record.Name, err)
+		return
+	}
+	if record.ID == "" {
+		if record.ID, err = newID(record.Timestamp); err != nil {
+			log.Errorf("Audit record of [%s] on [%s/%s/%s] not saved: %v", record.Operation, record.Namespace, record.ObjectType, record.Name, err)
+			return
+		}
+	}
+	if err := s.Save(record); err != nil {
+		log.Errorf("Audit record of [%s] on [%s/%s/%s] not saved: %v", record.Operation, record.Namespace, record.ObjectType, record.Name, err)
+	}
+}
+
+// message is the audit log line of the record
+func message(record *models.AuditRecord) string {
+	msg := fmt.Sprintf("%s on Namespace: %s Type: %s Name: %s", strings.ToUpper(record.Operation), record.Namespace, record.ObjectType, record.Name)
+	if record.Patch != "" {
+		msg += " Patch: " + record.Patch
+	}
+	return msg
+}
+
+// newID returns a time-ordered ID, the random suffix avoids collisions between records of the same nanosecond
+func newID(t time.Time) (string, error) {
+	suffix, err := util.CryptoRandomBytes(4)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%019d-%x", t.UnixNano(), suffix), nil
+}
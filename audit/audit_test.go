@@ -0,0 +1,64 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+func TestQueryMatches(t *testing.T) {
+	assert := assert.New(t)
+
+	record := testRecord("1", "alice", "bookinfo")
+	assert.True(Query{}.Matches(&record))
+	assert.True(Query{User: "alice", Operation: models.AuditUpdate, ObjectType: "VirtualServices", Name: "reviews"}.Matches(&record))
+	assert.False(Query{User: "bob"}.Matches(&record))
+	assert.False(Query{Operation: models.AuditDelete}.Matches(&record))
+	assert.False(Query{Namespaces: map[string]bool{}}.Matches(&record))
+	assert.True(Query{From: record.Timestamp, To: record.Timestamp}.Matches(&record))
+	assert.False(Query{From: record.Timestamp.Add(time.Second)}.Matches(&record))
+	assert.False(Query{To: record.Timestamp.Add(-time.Second)}.Matches(&record))
+}
+
+func TestRecord(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NewConfig()
+	config.Set(conf)
+	util.Clock = util.ClockMock{Time: time.Unix(1600000000, 0)}
+	defer func() { util.Clock = util.RealClock{} }()
+	store := NewMemoryStore(10)
+	SetStore(store)
+	defer SetStore(nil)
+
+	Record(models.AuditRecord{User: "alice", Operation: models.AuditDelete, Namespace: "bookinfo", ObjectType: "gateways", Name: "bookinfo-gateway"})
+	Record(models.AuditRecord{User: "bob", Operation: models.AuditCreate, Namespace: "bookinfo", ObjectType: "gateways", Name: "bookinfo-gateway"})
+
+	records, err := store.Query(Query{})
+	require.NoError(err)
+	require.Len(records, 2)
+	assert.Equal("bob", records[0].User)
+	assert.Equal(time.Unix(1600000000, 0), records[0].Timestamp)
+	assert.Regexp(`^1600000000000000000-[0-9a-f]{8}$`, records[0].ID)
+	assert.NotEqual(records[0].ID, records[1].ID)
+
+	// nothing is recorded when the audit trail is disabled
+	conf.Audit.Enabled = false
+	config.Set(conf)
+	Record(models.AuditRecord{User: "carol", Operation: models.AuditDelete, Namespace: "bookinfo", ObjectType: "gateways", Name: "bookinfo-gateway"})
+	records, err = store.Query(Query{})
+	require.NoError(err)
+	assert.Len(records, 2)
+}
+
+func TestMessage(t *testing.T) {
+	record := testRecord("1", "alice", "bookinfo")
+	assert.Equal(t, `UPDATE on Namespace: bookinfo Type: virtualservices Name: reviews Patch: {"spec":{"http":[]}}`, message(&record))
+}
@@ -0,0 +1,188 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// query returns the records matching q, records must be ordered oldest first
+func query(records []models.AuditRecord, q Query) []models.AuditRecord {
+	result := []models.AuditRecord{}
+	for i := len(records) - 1; i >= 0; i-- {
+		if q.Limit > 0 && len(result) >= q.Limit {
+			break
+		}
+		if q.Matches(&records[i]) {
+			result = append(result, records[i])
+		}
+	}
+	return result
+}
+
+// FilesystemStore appends the records to a file, one JSON record by line
+type FilesystemStore struct {
+	path       string
+	maxRecords int
+	mutex      sync.Mutex
+	count      int
+}
+
+// NewFilesystemStore returns a store using the file at path, the file and its directory are created if necessary
+func NewFilesystemStore(path string, maxRecords int) (*FilesystemStore, error) {
+	if path == "" {
+		return nil, errors.New("audit path is not configured")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("unable to create audit directory [%s]: %v", filepath.Dir(path), err)
+	}
+	fs := &FilesystemStore{path: path, maxRecords: maxRecords}
+	records, err := fs.read()
+	if err != nil {
+		return nil, err
+	}
+	fs.count = len(records)
+	return fs, nil
+}
+
+// read returns the stored records, oldest first
+func (fs *FilesystemStore) read() ([]models.AuditRecord, error) {
+	data, err := ioutil.ReadFile(fs.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []models.AuditRecord{}, nil
+		}
+		return nil, err
+	}
+
+	records := []models.AuditRecord{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), len(data)+1)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		record := models.AuditRecord{}
+		if err := json.Unmarshal(line, &record); err != nil {
+			log.Warningf("Skipping unreadable audit record in [%s]: %v", fs.path, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// Query is required by the Store interface
+func (fs *FilesystemStore) Query(q Query) ([]models.AuditRecord, error) {
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	records, err := fs.read()
+	if err != nil {
+		return nil, err
+	}
+	return query(records, q), nil
+}
+
+// Save is required by the Store interface
+func (fs *FilesystemStore) Save(record models.AuditRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	f, err := os.OpenFile(fs.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fs.count++
+
+	if fs.maxRecords > 0 && fs.count > fs.maxRecords {
+		return fs.prune()
+	}
+	return nil
+}
+
+// prune rewrites the file with the most recent records only, the caller must hold the lock
+func (fs *FilesystemStore) prune() error {
+	records, err := fs.read()
+	if err != nil {
+		return err
+	}
+	if len(records) > fs.maxRecords {
+		log.Debugf("Removing [%d] audit records, audit record limit [%d] exceeded", len(records)-fs.maxRecords, fs.maxRecords)
+		records = records[len(records)-fs.maxRecords:]
+	}
+
+	var buffer bytes.Buffer
+	for _, record := range records {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		buffer.Write(data)
+		buffer.WriteByte('\n')
+	}
+	// write and then rename, so that a partially written file is never read
+	tmpPath := fs.path + ".tmp"
+	if err = ioutil.WriteFile(tmpPath, buffer.Bytes(), 0600); err == nil {
+		err = os.Rename(tmpPath, fs.path)
+	}
+	if err != nil {
+		return err
+	}
+	fs.count = len(records)
+	return nil
+}
+
+// MemoryStore keeps the records in memory, they do not survive a restart
+type MemoryStore struct {
+	maxRecords int
+	mutex      sync.RWMutex
+	records    []models.AuditRecord
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore(maxRecords int) *MemoryStore {
+	return &MemoryStore{maxRecords: maxRecords, records: []models.AuditRecord{}}
+}
+
+// Query is required by the Store interface
+func (ms *MemoryStore) Query(q Query) ([]models.AuditRecord, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	return query(ms.records, q), nil
+}
+
+// Save is required by the Store interface
+func (ms *MemoryStore) Save(record models.AuditRecord) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	ms.records = append(ms.records, record)
+	if ms.maxRecords > 0 && len(ms.records) > ms.maxRecords {
+		ms.records = ms.records[len(ms.records)-ms.maxRecords:]
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/models"
+)
+
+func testRecord(id, user, namespace string) models.AuditRecord {
+	return models.AuditRecord{
+		ID:         id,
+		Timestamp:  time.Unix(1600000000, 0).UTC(),
+		User:       user,
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: "virtualservices",
+		Name:       "reviews",
+		Patch:      `{"spec":{"http":[]}}`,
+		Before:     []byte(`{"spec":{"http":[{"route":[]}]}}`),
+		After:      []byte(`{"spec":{"http":[]}}`),
+	}
+}
+
+func testStore(t *testing.T, store Store) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	records, err := store.Query(Query{})
+	require.NoError(err)
+	assert.Empty(records)
+
+	require.NoError(store.Save(testRecord("1", "alice", "bookinfo")))
+	require.NoError(store.Save(testRecord("2", "bob", "bookinfo")))
+	require.NoError(store.Save(testRecord("3", "alice", "travels")))
+
+	// max of 2 records, the oldest is pruned
+	records, err = store.Query(Query{})
+	require.NoError(err)
+	require.Len(records, 2)
+	assert.Equal("3", records[0].ID)
+	assert.Equal("2", records[1].ID)
+	assert.Equal(testRecord("3", "alice", "travels"), records[0])
+
+	records, err = store.Query(Query{Namespaces: map[string]bool{"bookinfo": true}})
+	require.NoError(err)
+	require.Len(records, 1)
+	assert.Equal("bob", records[0].User)
+
+	records, err = store.Query(Query{Limit: 1})
+	require.NoError(err)
+	require.Len(records, 1)
+	assert.Equal("3", records[0].ID)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(2))
+}
+
+func TestFilesystemStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "audit")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "audit", "records.jsonl")
+	store, err := NewFilesystemStore(path, 2)
+	require.NoError(t, err)
+	testStore(t, store)
+
+	// the records survive a restart
+	store, err = NewFilesystemStore(path, 2)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(testRecord("4", "carol", "bookinfo")))
+	records, err := store.Query(Query{})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "4", records[0].ID)
+	assert.Equal(t, "3", records[1].ID)
+}
@@ -0,0 +1,148 @@
+package business
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// podAccessLogs holds the parsed proxy log entries of a pod
+type podAccessLogs struct {
+	pod     string
+	entries []LogEntry
+}
+
+// GetAccessLogSummary aggregates the proxy access logs of all the pods of a workload over the time window of the
+// log options: top paths, response codes, response flags, upstream hosts and slowest requests. The limit bounds
+// the number of items of each aggregation.
+func (in *WorkloadService) GetAccessLogSummary(namespace, workloadName, workloadType string, opts *LogOptions, limit int) (*models.AccessLogSummary, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	workload, err := fetchWorkload(in.businessLayer, namespace, workloadName, workloadType)
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []string{}
+	for _, pod := range workload.Pods {
+		if len(pod.IstioContainers) > 0 {
+			pods = append(pods, pod.Name)
+		}
+	}
+
+	podsLogs := make([]podAccessLogs, len(pods))
+	wg := sync.WaitGroup{}
+	wg.Add(len(pods))
+	errChan := make(chan error, len(pods))
+	for i, pod := range pods {
+		go func(i int, pod string) {
+			defer wg.Done()
+			podLog, err := in.getParsedLogs(namespace, pod, opts)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			podsLogs[i] = podAccessLogs{pod: pod, entries: podLog.Entries}
+		}(i, pod)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+
+	summary := summarizeAccessLogs(podsLogs, limit)
+	summary.Namespace = namespace
+	summary.Workload = workloadName
+	summary.Pods = pods
+	summary.EndTime = util.Clock.Now()
+	if opts.SinceTime != nil {
+		summary.StartTime = opts.SinceTime.Time
+		if opts.Duration != nil && summary.StartTime.Add(*opts.Duration).Before(summary.EndTime) {
+			summary.EndTime = summary.StartTime.Add(*opts.Duration)
+		}
+	}
+	return summary, nil
+}
+
+// summarizeAccessLogs aggregates the access log entries, skipping the other proxy log entries
+func summarizeAccessLogs(podsLogs []podAccessLogs, limit int) *models.AccessLogSummary {
+	summary := &models.AccessLogSummary{}
+	paths, codes, flags, hosts := map[string]int{}, map[string]int{}, map[string]int{}, map[string]int{}
+	requests := []models.AccessLogRequest{}
+
+	for _, podLogs := range podsLogs {
+		for _, entry := range podLogs.entries {
+			al := entry.AccessLog
+			if al == nil {
+				continue
+			}
+			summary.Requests++
+			if al.UriPath != "" {
+				paths[al.UriPath]++
+			}
+			codes[al.StatusCode]++
+			// "-" stands for no flag or no upstream host
+			if al.ResponseFlags != "-" && al.ResponseFlags != "" {
+				for _, flag := range strings.Split(al.ResponseFlags, ",") {
+					flags[flag]++
+				}
+			}
+			if al.UpstreamService != "-" && al.UpstreamService != "" {
+				hosts[al.UpstreamService]++
+			}
+
+			duration, _ := strconv.ParseInt(al.Duration, 10, 64)
+			requests = append(requests, models.AccessLogRequest{
+				Pod:           podLogs.pod,
+				Timestamp:     time.Unix(entry.TimestampUnix, 0).UTC(),
+				Method:        al.Method,
+				Path:          al.UriPath,
+				StatusCode:    al.StatusCode,
+				ResponseFlags: al.ResponseFlags,
+				UpstreamHost:  al.UpstreamService,
+				RequestID:     al.RequestId,
+				Duration:      duration,
+			})
+		}
+	}
+
+	summary.TopPaths = topCounts(paths, limit)
+	summary.StatusCodes = topCounts(codes, limit)
+	summary.ResponseFlags = topCounts(flags, limit)
+	summary.UpstreamHosts = topCounts(hosts, limit)
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].Duration > requests[j].Duration
+	})
+	if len(requests) > limit {
+		requests = requests[:limit]
+	}
+	summary.SlowestRequests = requests
+	return summary
+}
+
+// topCounts returns the counts sorted by decreasing count then value, at most limit
+func topCounts(counts map[string]int, limit int) []models.AccessLogCount {
+	result := make([]models.AccessLogCount, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, models.AccessLogCount{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Value < result[j].Value
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
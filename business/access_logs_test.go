@@ -0,0 +1,59 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nitishm/engarde/pkg/parser"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func fakeAccessLogEntry(path, code, flags, upstream, duration string, timestamp int64) LogEntry {
+	return LogEntry{
+		TimestampUnix: timestamp,
+		AccessLog: &parser.AccessLog{
+			Method:          "GET",
+			UriPath:         path,
+			StatusCode:      code,
+			ResponseFlags:   flags,
+			UpstreamService: upstream,
+			Duration:        duration,
+		},
+	}
+}
+
+func TestSummarizeAccessLogs(t *testing.T) {
+	assert := assert.New(t)
+
+	summary := summarizeAccessLogs([]podAccessLogs{
+		{pod: "reviews-v1-1", entries: []LogEntry{
+			fakeAccessLogEntry("/reviews/0", "200", "-", "10.0.0.1:9080", "12", 1614592800),
+			fakeAccessLogEntry("/reviews/0", "503", "UH", "-", "0", 1614592801),
+			{Message: "warning envoy config", Severity: "WARN"},
+		}},
+		{pod: "reviews-v1-2", entries: []LogEntry{
+			fakeAccessLogEntry("/reviews/1", "200", "-", "10.0.0.2:9080", "350", 1614592802),
+			fakeAccessLogEntry("/reviews/0", "504", "UT,URX", "10.0.0.2:9080", "15000", 1614592803),
+		}},
+	}, 2)
+
+	assert.Equal(4, summary.Requests)
+	assert.Equal([]models.AccessLogCount{{Value: "/reviews/0", Count: 3}, {Value: "/reviews/1", Count: 1}}, summary.TopPaths)
+	assert.Equal([]models.AccessLogCount{{Value: "200", Count: 2}, {Value: "503", Count: 1}}, summary.StatusCodes)
+	assert.Equal([]models.AccessLogCount{{Value: "UH", Count: 1}, {Value: "URX", Count: 1}}, summary.ResponseFlags)
+	assert.Equal([]models.AccessLogCount{{Value: "10.0.0.2:9080", Count: 2}, {Value: "10.0.0.1:9080", Count: 1}}, summary.UpstreamHosts)
+
+	assert.Len(summary.SlowestRequests, 2)
+	assert.Equal(int64(15000), summary.SlowestRequests[0].Duration)
+	assert.Equal("reviews-v1-2", summary.SlowestRequests[0].Pod)
+	assert.Equal("UT,URX", summary.SlowestRequests[0].ResponseFlags)
+	assert.Equal(time.Date(2021, 3, 1, 10, 0, 3, 0, time.UTC), summary.SlowestRequests[0].Timestamp)
+	assert.Equal(int64(350), summary.SlowestRequests[1].Duration)
+
+	empty := summarizeAccessLogs([]podAccessLogs{}, 10)
+	assert.Equal(0, empty.Requests)
+	assert.Empty(empty.TopPaths)
+	assert.NotNil(empty.SlowestRequests)
+}
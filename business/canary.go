@@ -0,0 +1,339 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/common/model"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// CanaryRolloutAnnotation is the annotation of the VirtualService of a service holding its canary rollout
+const CanaryRolloutAnnotation = "kiali.io/canary-rollout"
+
+// canaryMaxHoldIntervals is the default maximum hold of a step without canary request, in intervals
+const canaryMaxHoldIntervals = 10
+
+// CanaryService runs the canary rollouts of the services. The state of a rollout is kept in an annotation of the
+// service VirtualService, so that a restart resumes the progressing rollouts; the canary controller advances them.
+type CanaryService struct {
+	prom          prometheus.ClientInterface
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// StartCanaryRollout validates the rollout and routes the weight of its first step to the canary, with the
+// credentials of the user starting it. A progressing rollout of the service must be aborted first. The routing is
+// validated, a routing introducing validation errors is only applied when forced.
+func (in *CanaryService) StartCanaryRollout(namespace, service string, spec models.CanarySpec, force bool, startedBy string) (*models.CanaryRollout, error) {
+	if config.Get().Deployment.ViewOnlyMode {
+		return nil, errors2.NewForbidden(schema.GroupResource{Group: "kiali.io", Resource: "canaryrollouts"}, service, fmt.Errorf("Kiali is in view only mode"))
+	}
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if err := validateCanarySpec(spec); err != nil {
+		return nil, errors2.NewBadRequest(err.Error())
+	}
+	versions, err := in.businessLayer.TrafficRouting.getServiceVersions(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	for _, version := range []string{spec.Baseline, spec.Canary} {
+		if !versions[version] {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("service [%s] has no workload of version [%s]", service, version))
+		}
+	}
+	if current, err := in.GetCanaryRollout(namespace, service); err == nil && current.Status.Phase == models.CanaryProgressing {
+		return nil, errors2.NewAlreadyExists(schema.GroupResource{Group: "kiali.io", Resource: "canaryrollouts"}, service)
+	} else if err != nil && !errors2.IsNotFound(err) {
+		return nil, err
+	}
+
+	rollout := &models.CanaryRollout{
+		Namespace: namespace,
+		Service:   service,
+		StartedBy: startedBy,
+		Spec:      spec,
+		Status: models.CanaryStatus{
+			Phase:       models.CanaryProgressing,
+			Weight:      spec.Steps[0],
+			StepStarted: time.Now(),
+			Message:     fmt.Sprintf("step 1/%d", len(spec.Steps)),
+		},
+	}
+	if err = in.routeCanary(rollout, force); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// GetCanaryRollout returns the canary rollout of a service, the last one when it's over
+func (in *CanaryService) GetCanaryRollout(namespace, service string) (*models.CanaryRollout, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	virtualService, _, err := in.businessLayer.TrafficRouting.getServiceRouting(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if virtualService != nil {
+		if rollout, found := parseCanaryRollout(virtualService); found {
+			return rollout, nil
+		}
+	}
+	return nil, kubernetes.NewNotFound(service, "Kiali", "CanaryRollout")
+}
+
+// GetCanaryRollouts returns the canary rollouts of the services of a namespace
+func (in *CanaryService) GetCanaryRollouts(namespace string) ([]models.CanaryRollout, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	virtualServices, err := in.businessLayer.TrafficRouting.getIstioObjects(namespace, kubernetes.VirtualServices)
+	if err != nil {
+		return nil, err
+	}
+	rollouts := []models.CanaryRollout{}
+	for _, vs := range virtualServices {
+		if rollout, found := parseCanaryRollout(vs); found {
+			rollouts = append(rollouts, *rollout)
+		}
+	}
+	return rollouts, nil
+}
+
+// AbortCanaryRollout stops a progressing canary rollout, all the traffic is routed back to the baseline
+func (in *CanaryService) AbortCanaryRollout(namespace, service string) (*models.CanaryRollout, error) {
+	rollout, err := in.GetCanaryRollout(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if rollout.Status.Phase != models.CanaryProgressing {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("the canary rollout of service [%s] is not progressing", service))
+	}
+	rollout.Status.Phase, rollout.Status.Weight, rollout.Status.Message = models.CanaryAborted, 0, "aborted"
+	if err = in.routeCanary(rollout, true); err != nil {
+		return nil, err
+	}
+	return rollout, nil
+}
+
+// AdvanceCanaryRollout checks a progressing canary rollout once its current step is held for the interval: the
+// rollout advances to its next step, or the canary is promoted, when the canary meets the success criteria, and it
+// is rolled back otherwise. A step without canary traffic is held for another interval, up to the max hold. It
+// returns true when the rollout is checked and updated.
+func (in *CanaryService) AdvanceCanaryRollout(namespace, service string, now time.Time) (*models.CanaryRollout, bool, error) {
+	rollout, err := in.GetCanaryRollout(namespace, service)
+	if err != nil {
+		return nil, false, err
+	}
+	interval, _ := time.ParseDuration(rollout.Spec.Interval)
+	if rollout.Status.Phase != models.CanaryProgressing || now.Before(rollout.Status.StepStarted.Add(interval)) {
+		return rollout, false, nil
+	}
+
+	check, err := in.checkCanary(rollout, interval, now)
+	if err != nil {
+		return nil, false, err
+	}
+	rollout.Status = nextCanaryStatus(rollout.Spec, rollout.Status, check, now)
+	if err = in.routeCanary(rollout, true); err != nil {
+		return nil, false, err
+	}
+	if rollout.Status.Phase != models.CanaryProgressing {
+		log.Infof("Canary rollout of service [%s/%s] is over: %s, %s", namespace, service, rollout.Status.Phase, rollout.Status.Message)
+	}
+	return rollout, true, nil
+}
+
+func validateCanarySpec(spec models.CanarySpec) error {
+	if spec.Baseline == "" || spec.Canary == "" || spec.Baseline == spec.Canary {
+		return fmt.Errorf("a canary rollout requires distinct baseline and canary versions")
+	}
+	if len(spec.Steps) == 0 {
+		return fmt.Errorf("a canary rollout requires at least a step")
+	}
+	for i, weight := range spec.Steps {
+		if weight <= 0 || weight >= 100 || (i > 0 && weight <= spec.Steps[i-1]) {
+			return fmt.Errorf("the canary step weights must be increasing and between 1 and 99")
+		}
+	}
+	if interval, err := time.ParseDuration(spec.Interval); err != nil || interval < time.Minute {
+		return fmt.Errorf("invalid canary interval [%s], it must be at least 1m", spec.Interval)
+	}
+	if spec.MaxErrorRate < 0 || spec.MaxErrorRate > 100 {
+		return fmt.Errorf("the canary max error rate must be a percentage")
+	}
+	if spec.MaxLatencyP95 < 0 {
+		return fmt.Errorf("the canary max latency can't be negative")
+	}
+	if spec.MaxHold != "" {
+		interval, _ := time.ParseDuration(spec.Interval)
+		if maxHold, err := time.ParseDuration(spec.MaxHold); err != nil || maxHold < interval {
+			return fmt.Errorf("invalid canary max hold [%s], it must be at least the interval", spec.MaxHold)
+		}
+	}
+	return nil
+}
+
+// canaryMaxHold returns the maximum hold of a step without canary request
+func canaryMaxHold(spec models.CanarySpec) time.Duration {
+	if maxHold, err := time.ParseDuration(spec.MaxHold); err == nil {
+		return maxHold
+	}
+	interval, _ := time.ParseDuration(spec.Interval)
+	return canaryMaxHoldIntervals * interval
+}
+
+// nextCanaryStatus returns the status of a rollout after the check of its current step
+func nextCanaryStatus(spec models.CanarySpec, status models.CanaryStatus, check models.CanaryCheck, now time.Time) models.CanaryStatus {
+	next := status
+	next.LastCheck, next.HeldSince = &check, nil
+	switch {
+	case check.ErrorRate == nil:
+		heldSince := status.StepStarted
+		if status.HeldSince != nil {
+			heldSince = *status.HeldSince
+		}
+		if maxHold := canaryMaxHold(spec); now.Sub(heldSince) >= maxHold {
+			next.Phase, next.Weight = models.CanaryRolledBack, 0
+			next.Message = fmt.Sprintf("rolled back at step %d/%d, the canary had no request for %s", status.Step+1, len(spec.Steps), maxHold)
+			break
+		}
+		next.StepStarted, next.HeldSince = now, &heldSince
+		next.Message = fmt.Sprintf("step %d/%d held, the canary had no request", status.Step+1, len(spec.Steps))
+	case *check.ErrorRate > spec.MaxErrorRate:
+		next.Phase, next.Weight = models.CanaryRolledBack, 0
+		next.Message = fmt.Sprintf("rolled back at step %d/%d, error rate %.2f%% above %.2f%%", status.Step+1, len(spec.Steps), *check.ErrorRate, spec.MaxErrorRate)
+	case spec.MaxLatencyP95 > 0 && check.LatencyP95 != nil && *check.LatencyP95 > spec.MaxLatencyP95:
+		next.Phase, next.Weight = models.CanaryRolledBack, 0
+		next.Message = fmt.Sprintf("rolled back at step %d/%d, p95 latency %.0fms above %.0fms", status.Step+1, len(spec.Steps), *check.LatencyP95, spec.MaxLatencyP95)
+	case status.Step+1 < len(spec.Steps):
+		next.LastCheck.Passed = true
+		next.Step, next.Weight, next.StepStarted = status.Step+1, spec.Steps[status.Step+1], now
+		next.Message = fmt.Sprintf("step %d/%d", next.Step+1, len(spec.Steps))
+	default:
+		next.LastCheck.Passed = true
+		next.Phase, next.Step, next.Weight = models.CanarySucceeded, len(spec.Steps), 100
+		next.Message = "canary promoted"
+	}
+	return next
+}
+
+// checkCanary returns the error rate and the p95 latency of the canary requests over the interval
+func (in *CanaryService) checkCanary(rollout *models.CanaryRollout, interval time.Duration, now time.Time) (models.CanaryCheck, error) {
+	check := models.CanaryCheck{Timestamp: now}
+	rateInterval := fmt.Sprintf("%ds", int(interval.Seconds()))
+
+	rates, err := in.prom.GetServiceRequestRates(rollout.Namespace, rollout.Service, rateInterval, now)
+	if err != nil {
+		return check, err
+	}
+	check.ErrorRate = canaryErrorRate(rates, rollout.Spec.Canary)
+
+	if rollout.Spec.MaxLatencyP95 > 0 {
+		labels := fmt.Sprintf(`{reporter="destination",destination_service_name="%s",destination_service_namespace="%s",destination_version="%s"}`,
+			rollout.Service, rollout.Namespace, rollout.Spec.Canary)
+		quantiles, err := in.prom.FetchHistogramValues("istio_request_duration_milliseconds", labels, "", rateInterval, false, []string{"0.95"}, now)
+		if err != nil {
+			return check, err
+		}
+		if vector := quantiles["0.95"]; len(vector) > 0 && !math.IsNaN(float64(vector[0].Value)) {
+			latency := float64(vector[0].Value)
+			check.LatencyP95 = &latency
+		}
+	}
+	return check, nil
+}
+
+// canaryErrorRate returns the percentage of failed requests to the version, nil when it had no request
+func canaryErrorRate(rates model.Vector, version string) *float64 {
+	rqHealth := models.NewEmptyRequestHealth()
+	for _, sample := range rates {
+		if string(sample.Metric["destination_version"]) == version {
+			rqHealth.AggregateInbound(sample)
+		}
+	}
+	rqHealth.CombineReporters()
+
+	var total, failed float64
+	for protocol, codes := range rqHealth.Inbound {
+		for code, rate := range codes {
+			total += rate
+			if isFailedRequest(protocol, code) {
+				failed += rate
+			}
+		}
+	}
+	if total <= 0 {
+		return nil
+	}
+	errorRate := 100 * failed / total
+	return &errorRate
+}
+
+// routeCanary routes the rollout weight to the canary and the rest to the baseline, and stores the rollout in the
+// VirtualService
+func (in *CanaryService) routeCanary(rollout *models.CanaryRollout, force bool) error {
+	versions := map[string]bool{rollout.Spec.Baseline: true, rollout.Spec.Canary: true}
+	routing, err := in.businessLayer.TrafficRouting.newTrafficRouting(rollout.Namespace, rollout.Service, versions)
+	if err != nil {
+		return err
+	}
+	if err = routing.apply(models.TrafficIntent{Shift: &models.TrafficShift{Version: rollout.Spec.Canary, Weight: rollout.Status.Weight}}); err != nil {
+		return errors2.NewBadRequest(err.Error())
+	}
+
+	state, err := json.Marshal(rollout)
+	if err != nil {
+		return err
+	}
+	meta := routing.virtualService.GetObjectMeta()
+	annotations := make(map[string]string, len(meta.Annotations)+1)
+	for k, v := range meta.Annotations {
+		annotations[k] = v
+	}
+	annotations[CanaryRolloutAnnotation] = string(state)
+	meta.Annotations = annotations
+	routing.virtualService.SetObjectMeta(meta)
+
+	body, err := json.Marshal(routing.changedObjects())
+	if err != nil {
+		return err
+	}
+	result, err := in.businessLayer.IstioConfig.ApplyIstioConfigBulk(rollout.Namespace, body, force, false)
+	if err != nil {
+		return err
+	}
+	if !result.Applied {
+		if result.Error != "" {
+			return fmt.Errorf("canary routing of service [%s] not applied: %s", rollout.Service, result.Error)
+		}
+		return errors2.NewBadRequest(fmt.Sprintf("canary routing of service [%s] not applied, it introduces validation errors", rollout.Service))
+	}
+	return nil
+}
+
+// parseCanaryRollout returns the canary rollout stored in a VirtualService
+func parseCanaryRollout(virtualService kubernetes.IstioObject) (*models.CanaryRollout, bool) {
+	state, found := virtualService.GetObjectMeta().Annotations[CanaryRolloutAnnotation]
+	if !found {
+		return nil, false
+	}
+	rollout := &models.CanaryRollout{}
+	if err := json.Unmarshal([]byte(state), rollout); err != nil {
+		log.Warningf("Invalid canary rollout annotation in VirtualService [%s/%s]: %v", virtualService.GetObjectMeta().Namespace, virtualService.GetObjectMeta().Name, err)
+		return nil, false
+	}
+	return rollout, true
+}
@@ -0,0 +1,128 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/models"
+)
+
+func canarySample(version, code string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"reporter":            "destination",
+			"request_protocol":    "http",
+			"response_code":       model.LabelValue(code),
+			"destination_version": model.LabelValue(version),
+		},
+		Value: model.SampleValue(value),
+	}
+}
+
+func testCanarySpec() models.CanarySpec {
+	return models.CanarySpec{
+		Baseline:      "v1",
+		Canary:        "v2",
+		Steps:         []int{10, 50},
+		Interval:      "5m",
+		MaxErrorRate:  1,
+		MaxLatencyP95: 500,
+	}
+}
+
+func TestValidateCanarySpec(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.NoError(validateCanarySpec(testCanarySpec()))
+	for _, invalid := range []func(*models.CanarySpec){
+		func(s *models.CanarySpec) { s.Canary = "v1" },
+		func(s *models.CanarySpec) { s.Baseline = "" },
+		func(s *models.CanarySpec) { s.Steps = nil },
+		func(s *models.CanarySpec) { s.Steps = []int{50, 10} },
+		func(s *models.CanarySpec) { s.Steps = []int{10, 100} },
+		func(s *models.CanarySpec) { s.Interval = "30s" },
+		func(s *models.CanarySpec) { s.Interval = "5 minutes" },
+		func(s *models.CanarySpec) { s.MaxErrorRate = -1 },
+		func(s *models.CanarySpec) { s.MaxLatencyP95 = -1 },
+		func(s *models.CanarySpec) { s.MaxHold = "1m" },
+	} {
+		spec := testCanarySpec()
+		invalid(&spec)
+		assert.Error(validateCanarySpec(spec), spec)
+	}
+}
+
+func TestCanaryErrorRate(t *testing.T) {
+	assert := assert.New(t)
+
+	rates := model.Vector{
+		canarySample("v1", "500", 5),
+		canarySample("v1", "200", 5),
+		canarySample("v2", "200", 9.5),
+		canarySample("v2", "503", 0.5),
+	}
+	errorRate := canaryErrorRate(rates, "v2")
+	require.NotNil(t, errorRate)
+	assert.InDelta(5.0, *errorRate, 0.0001)
+	assert.Nil(canaryErrorRate(rates, "v3"))
+}
+
+func TestNextCanaryStatus(t *testing.T) {
+	assert := assert.New(t)
+	spec := testCanarySpec()
+	started := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	now := started.Add(5 * time.Minute)
+	first := models.CanaryStatus{Phase: models.CanaryProgressing, Weight: 10, StepStarted: started}
+	low, high := 0.5, 2.0
+	fast, slow := 100.0, 800.0
+
+	// the canary meets the criteria, the rollout advances and is then promoted
+	next := nextCanaryStatus(spec, first, models.CanaryCheck{Timestamp: now, ErrorRate: &low, LatencyP95: &fast}, now)
+	assert.Equal(models.CanaryProgressing, next.Phase)
+	assert.Equal(1, next.Step)
+	assert.Equal(50, next.Weight)
+	assert.Equal(now, next.StepStarted)
+	assert.True(next.LastCheck.Passed)
+
+	next = nextCanaryStatus(spec, next, models.CanaryCheck{Timestamp: now, ErrorRate: &low}, now)
+	assert.Equal(models.CanarySucceeded, next.Phase)
+	assert.Equal(2, next.Step)
+	assert.Equal(100, next.Weight)
+
+	// without canary traffic the step is held
+	next = nextCanaryStatus(spec, first, models.CanaryCheck{Timestamp: now}, now)
+	assert.Equal(models.CanaryProgressing, next.Phase)
+	assert.Equal(0, next.Step)
+	assert.Equal(10, next.Weight)
+	assert.Equal(now, next.StepStarted)
+	assert.Equal(&started, next.HeldSince)
+	assert.False(next.LastCheck.Passed)
+
+	// up to the max hold, 10 intervals by default
+	later := started.Add(50 * time.Minute)
+	held := nextCanaryStatus(spec, next, models.CanaryCheck{Timestamp: later}, later)
+	assert.Equal(models.CanaryRolledBack, held.Phase)
+	assert.Equal(0, held.Weight)
+	assert.Nil(held.HeldSince)
+	assert.Contains(held.Message, "the canary had no request for 50m0s")
+
+	// the hold ends with canary traffic
+	next = nextCanaryStatus(spec, next, models.CanaryCheck{Timestamp: now, ErrorRate: &low}, now)
+	assert.Equal(1, next.Step)
+	assert.Nil(next.HeldSince)
+
+	// the error rate or the latency roll the canary back
+	next = nextCanaryStatus(spec, first, models.CanaryCheck{Timestamp: now, ErrorRate: &high, LatencyP95: &fast}, now)
+	assert.Equal(models.CanaryRolledBack, next.Phase)
+	assert.Equal(0, next.Weight)
+	assert.Contains(next.Message, "error rate 2.00% above 1.00%")
+
+	next = nextCanaryStatus(spec, first, models.CanaryCheck{Timestamp: now, ErrorRate: &low, LatencyP95: &slow}, now)
+	assert.Equal(models.CanaryRolledBack, next.Phase)
+	assert.Contains(next.Message, "p95 latency 800ms above 500ms")
+	assert.False(next.LastCheck.Passed)
+}
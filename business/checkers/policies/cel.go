@@ -0,0 +1,128 @@
+package policies
+
+// Cel.go compiles and evaluates the Common Expression Language (https://github.com/google/cel-spec) expressions
+// of the validation policies with cel-go. The expressions are type checked against the object and ns (the
+// namespace of the object) variables, both dynamically typed, and can use the standard CEL macros and functions
+// and the string extensions of cel-go (e.g. lowerAscii() or replace()).
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+)
+
+// celEnv declares the variables of the policy expressions
+var celEnv, celEnvErr = cel.NewEnv(
+	cel.Declarations(
+		decls.NewVar("object", decls.Dyn),
+		decls.NewVar("ns", decls.Dyn),
+	),
+	ext.Strings(),
+)
+
+// Program is a compiled CEL expression, it can be evaluated concurrently
+type Program struct {
+	expression string
+	program    cel.Program
+}
+
+// Compile parses and type checks a CEL expression
+func Compile(expression string) (*Program, error) {
+	if celEnvErr != nil {
+		return nil, celEnvErr
+	}
+	ast, issues := celEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	program, err := celEnv.Program(ast, cel.EvalOptions(cel.OptOptimize))
+	if err != nil {
+		return nil, err
+	}
+	return &Program{expression: expression, program: program}, nil
+}
+
+// Eval evaluates the expression with the given variables, the result is a cel-go native value
+func (p *Program) Eval(vars map[string]interface{}) (interface{}, error) {
+	val, _, err := p.program.Eval(vars)
+	if err != nil {
+		return nil, err
+	}
+	return val.Value(), nil
+}
+
+// EvalBool evaluates an expression that must result in a bool
+func (p *Program) EvalBool(vars map[string]interface{}) (bool, error) {
+	val, _, err := p.program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	b, ok := val.(types.Bool)
+	if !ok {
+		return false, fmt.Errorf("expression result is %s, expecting bool", val.Type().TypeName())
+	}
+	return bool(b), nil
+}
+
+// NormalizeValue converts a Go value (e.g. an unstructured k8s object) to the JSON-like values the
+// expressions operate on. As in the Kubernetes unstructured objects, integral numbers are CEL ints and
+// the other numbers are CEL doubles: a policy compares spec.http[0].retries.attempts with 3 and a
+// fault percentage with 0.5.
+func NormalizeValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case nil, bool, int64, string:
+		return v
+	case int:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint:
+		return int64(v)
+	case uint32:
+		return int64(v)
+	case uint64:
+		return int64(v)
+	case float32:
+		return normalizeNumber(float64(v))
+	case float64:
+		return normalizeNumber(v)
+	case map[string]string:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = e
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[k] = NormalizeValue(e)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = NormalizeValue(e)
+		}
+		return l
+	case []string:
+		l := make([]interface{}, len(v))
+		for i, e := range v {
+			l[i] = e
+		}
+		return l
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// normalizeNumber returns the number as an int64 when it's integral, JSON numbers are decoded as float64
+func normalizeNumber(f float64) interface{} {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+	return f
+}
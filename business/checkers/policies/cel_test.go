@@ -0,0 +1,122 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEval(t *testing.T) {
+	vars := map[string]interface{}{
+		"object": NormalizeValue(map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"name":   "reviews",
+				"labels": map[string]string{"team": "bookinfo"},
+			},
+			"spec": map[string]interface{}{
+				"hosts": []string{"reviews", "reviews.bookinfo.svc.cluster.local"},
+				"http": []interface{}{
+					map[string]interface{}{"timeout": "5s", "retries": map[string]interface{}{"attempts": int64(3)}},
+					map[string]interface{}{"route": []interface{}{}},
+				},
+				"fault": map[string]interface{}{"percentage": 0.5},
+			},
+		}),
+	}
+
+	cases := []struct {
+		expression string
+		expected   interface{}
+	}{
+		{`1 + 2 * 3`, int64(7)},
+		{`(1 + 2) * 3 - 10 % 4`, int64(7)},
+		{`-2 < 1 && !false`, true},
+		{`'a' + "b" == "ab"`, true},
+		{`true ? 'yes' : 'no'`, "yes"},
+		{`object.metadata.name`, "reviews"},
+		{`object.metadata.labels["team"] == 'bookinfo'`, true},
+		{`object.spec.http[0].retries.attempts >= 3`, true},
+		{`size(object.spec.hosts)`, int64(2)},
+		{`object.spec.hosts.size() == 2`, true},
+		{`'reviews' in object.spec.hosts`, true},
+		{`'team' in object.metadata.labels`, true},
+		{`has(object.spec.gateways)`, false},
+		{`has(object.spec.http)`, true},
+		{`object.spec.http.all(r, has(r.timeout))`, false},
+		{`object.spec.http.exists(r, has(r.timeout))`, true},
+		{`object.spec.http.exists_one(r, has(r.route))`, true},
+		{`object.spec.http.filter(r, has(r.timeout)).size()`, int64(1)},
+		{`object.spec.hosts.map(h, h.endsWith('.local')) == [false, true]`, true},
+		{`object.spec.hosts.all(h, h.startsWith('reviews') && h.matches('^[a-z.]+$'))`, true},
+		{`object.metadata.name.contains('view') && object.metadata.name.upperAscii() == 'REVIEWS'`, true},
+		{`[1, 2] + [3] == [1, 2, 3]`, true},
+		{`{'a': 1}.a == 1`, true},
+		{`int('42') == 42 && string(42) == '42' && double(1) == 1.0`, true},
+		// ints and doubles are distinct types
+		{`object.spec.http[0].retries.attempts == 3 && object.spec.fault.percentage == 0.5`, true},
+		// errors are absorbed when the other operand determines the result
+		{`object.spec.tls.mode == 'DISABLE' || true`, true},
+		{`false && object.spec.tls.mode == 'DISABLE'`, false},
+		{`!has(object.spec.tls) || object.spec.tls.mode != 'DISABLE'`, true},
+	}
+
+	for _, c := range cases {
+		p, err := Compile(c.expression)
+		if !assert.NoError(t, err, c.expression) {
+			continue
+		}
+		val, err := p.Eval(vars)
+		assert.NoError(t, err, c.expression)
+		assert.Equal(t, c.expected, val, c.expression)
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	vars := map[string]interface{}{
+		"object": NormalizeValue(map[string]interface{}{"spec": map[string]interface{}{"replicas": 1.0}}),
+	}
+
+	cases := []string{
+		`object.spec.tls.mode == 'DISABLE'`,
+		`object.spec.tls.mode == 'DISABLE' && true`,
+		`object.spec.replicas == 1.0`,
+		`[1][2]`,
+		`1 / 0`,
+	}
+	for _, c := range cases {
+		p, err := Compile(c)
+		if !assert.NoError(t, err, c) {
+			continue
+		}
+		_, err = p.Eval(vars)
+		assert.Error(t, err, c)
+	}
+
+	_, err := Compile(`object.spec.hosts.size() == 2`)
+	assert.NoError(t, err)
+
+	p, _ := Compile(`1 + 1`)
+	_, err = p.EvalBool(vars)
+	assert.Error(t, err)
+}
+
+func TestCompileErrors(t *testing.T) {
+	cases := []string{
+		``,
+		`1 +`,
+		`object.`,
+		`(1 + 2`,
+		`'unterminated`,
+		`has(object)`,
+		`object.spec.http.all(1, true)`,
+		`unknownFunction(1)`,
+		`unknown == 1`,
+		`1 + 'a'`,
+		`1 # 2`,
+		`1 2`,
+	}
+	for _, c := range cases {
+		_, err := Compile(c)
+		assert.Error(t, err, c)
+	}
+}
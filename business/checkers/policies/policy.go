@@ -0,0 +1,192 @@
+// Package policies provides user-defined validation policies: CEL expressions evaluated against Istio
+// objects, reported as standard Istio checks with custom codes.
+package policies
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// LanguageCEL is the default, and only supported, policy expression language
+const LanguageCEL = "cel"
+
+// Policy is a compiled validation policy
+type Policy struct {
+	config.ValidationPolicy
+	kinds      map[string]bool
+	namespaces *regexp.Regexp
+	program    *Program
+	severity   models.SeverityLevel
+}
+
+// NewPolicy validates and compiles a validation policy
+func NewPolicy(vp config.ValidationPolicy) (*Policy, error) {
+	if vp.Code == "" || vp.Expression == "" || vp.Message == "" {
+		return nil, fmt.Errorf("policy [%s] requires a code, an expression and a message", vp.Code)
+	}
+	if vp.Language != "" && vp.Language != LanguageCEL {
+		return nil, fmt.Errorf("policy [%s] has unsupported language [%s], expecting %s", vp.Code, vp.Language, LanguageCEL)
+	}
+
+	p := &Policy{
+		ValidationPolicy: vp,
+		kinds:            make(map[string]bool, len(vp.Kinds)),
+		severity:         models.WarningSeverity,
+	}
+	switch models.SeverityLevel(vp.Severity) {
+	case "", models.WarningSeverity:
+	case models.ErrorSeverity:
+		p.severity = models.ErrorSeverity
+	default:
+		return nil, fmt.Errorf("policy [%s] has invalid severity [%s], expecting error or warning", vp.Code, vp.Severity)
+	}
+	for _, kind := range vp.Kinds {
+		p.kinds[strings.ToLower(kind)] = true
+	}
+
+	var err error
+	if vp.Namespaces != "" {
+		if p.namespaces, err = regexp.Compile("^(?:" + vp.Namespaces + ")$"); err != nil {
+			return nil, fmt.Errorf("policy [%s] has invalid namespaces [%s]: %v", vp.Code, vp.Namespaces, err)
+		}
+	}
+	if p.program, err = Compile(vp.Expression); err != nil {
+		return nil, fmt.Errorf("policy [%s] has invalid expression: %v", vp.Code, err)
+	}
+
+	return p, nil
+}
+
+// AppliesTo returns true if the policy applies to the objects of the given type (e.g. virtualservice) in
+// the given namespace.
+func (p *Policy) AppliesTo(objectType, namespace string) bool {
+	if len(p.kinds) > 0 && !p.kinds[objectType] {
+		return false
+	}
+	return p.namespaces == nil || p.namespaces.MatchString(namespace)
+}
+
+// Check evaluates the policy against the object, it returns nil if the object is valid. An object for which
+// the expression fails to evaluate (e.g. a missing field not guarded by has()) gets a warning with the
+// evaluation error.
+func (p *Policy) Check(object kubernetes.IstioObject, namespace models.Namespace) *models.IstioCheck {
+	valid, err := p.program.EvalBool(map[string]interface{}{
+		"ns": map[string]interface{}{
+			"name":   namespace.Name,
+			"labels": NormalizeValue(namespace.Labels),
+		},
+		"object": objectValue(object),
+	})
+	if err != nil {
+		log.Warningf("Validation policy [%s] not evaluated for [%s/%s]: %v", p.Code, object.GetObjectMeta().Namespace, object.GetObjectMeta().Name, err)
+		return &models.IstioCheck{
+			Code:     p.Code,
+			Message:  fmt.Sprintf("Validation policy could not be evaluated: %v", err),
+			Severity: models.WarningSeverity,
+			Path:     p.Path,
+		}
+	}
+	if valid {
+		return nil
+	}
+	return &models.IstioCheck{
+		Code:     p.Code,
+		Message:  p.Message,
+		Severity: p.severity,
+		Path:     p.Path,
+	}
+}
+
+// objectValue returns the object as in its yaml, the status is not included
+func objectValue(object kubernetes.IstioObject) map[string]interface{} {
+	meta := object.GetObjectMeta()
+	typeMeta := object.GetTypeMeta()
+	return map[string]interface{}{
+		"apiVersion": typeMeta.APIVersion,
+		"kind":       typeMeta.Kind,
+		"metadata": map[string]interface{}{
+			"name":        meta.Name,
+			"namespace":   meta.Namespace,
+			"labels":      NormalizeValue(meta.Labels),
+			"annotations": NormalizeValue(meta.Annotations),
+		},
+		"spec": NormalizeValue(object.GetSpec()),
+	}
+}
+
+// LoadPolicies returns the compiled policies of the config and of the optional ConfigMap, whose data keys
+// hold yaml lists of policies. Invalid policies are logged and skipped. Policies are sorted by code.
+func LoadPolicies(conf config.ValidationPolicies, configMap *core_v1.ConfigMap) []*Policy {
+	vps := append([]config.ValidationPolicy{}, conf.Rules...)
+	if configMap != nil {
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			var cmPolicies []config.ValidationPolicy
+			if err := yaml.Unmarshal([]byte(configMap.Data[key]), &cmPolicies); err != nil {
+				log.Warningf("Ignoring validation policies of ConfigMap [%s] key [%s]: %v", configMap.Name, key, err)
+				continue
+			}
+			vps = append(vps, cmPolicies...)
+		}
+	}
+
+	policies := make([]*Policy, 0, len(vps))
+	for _, vp := range vps {
+		p, err := NewPolicy(vp)
+		if err != nil {
+			log.Warningf("Ignoring validation policy: %v", err)
+			continue
+		}
+		policies = append(policies, p)
+	}
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Code < policies[j].Code
+	})
+	return policies
+}
+
+// Cache keeps the policies compiled by LoadPolicies. They are compiled again when the rules of the config or the
+// resourceVersion of the ConfigMap change.
+type Cache struct {
+	lock         sync.Mutex
+	rules        []config.ValidationPolicy
+	configMapKey string
+	policies     []*Policy
+}
+
+// Load returns the compiled policies of the config and of the optional ConfigMap, from the cache when they are
+// unchanged. A ConfigMap without resourceVersion is never cached.
+func (c *Cache) Load(conf config.ValidationPolicies, configMap *core_v1.ConfigMap) []*Policy {
+	configMapKey := ""
+	if configMap != nil {
+		if configMap.ResourceVersion == "" {
+			return LoadPolicies(conf, configMap)
+		}
+		configMapKey = configMap.Namespace + "/" + configMap.Name + "/" + configMap.ResourceVersion
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.policies == nil || c.configMapKey != configMapKey || !reflect.DeepEqual(c.rules, conf.Rules) {
+		c.policies = LoadPolicies(conf, configMap)
+		c.rules = append([]config.ValidationPolicy{}, conf.Rules...)
+		c.configMapKey = configMapKey
+	}
+	return c.policies
+}
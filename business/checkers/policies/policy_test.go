@@ -0,0 +1,166 @@
+package policies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestNewPolicy(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "true"})
+	assert.Error(err, "message is required")
+
+	_, err = NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "true", Message: "m", Language: "rego"})
+	assert.Error(err, "rego is not supported")
+
+	_, err = NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "true", Message: "m", Severity: "info"})
+	assert.Error(err, "invalid severity")
+
+	_, err = NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "true", Message: "m", Namespaces: "prod-("})
+	assert.Error(err, "invalid namespaces")
+
+	_, err = NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "object.", Message: "m"})
+	assert.Error(err, "invalid expression")
+
+	p, err := NewPolicy(config.ValidationPolicy{Code: "KIA-0001", Expression: "true", Message: "m", Language: "cel"})
+	assert.NoError(err)
+	assert.Equal(models.WarningSeverity, p.severity)
+}
+
+func TestAppliesTo(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := NewPolicy(config.ValidationPolicy{
+		Code:       "ORG-0001",
+		Expression: "true",
+		Kinds:      []string{"VirtualService", "destinationrule"},
+		Message:    "m",
+		Namespaces: "prod-.*",
+	})
+	assert.NoError(err)
+
+	assert.True(p.AppliesTo("virtualservice", "prod-east"))
+	assert.True(p.AppliesTo("destinationrule", "prod-east"))
+	assert.False(p.AppliesTo("gateway", "prod-east"))
+	assert.False(p.AppliesTo("virtualservice", "staging"))
+	assert.False(p.AppliesTo("virtualservice", "my-prod-east"))
+
+	p, err = NewPolicy(config.ValidationPolicy{Code: "ORG-0001", Expression: "true", Message: "m"})
+	assert.NoError(err)
+	assert.True(p.AppliesTo("gateway", "any"))
+}
+
+func TestCheck(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	p, err := NewPolicy(config.ValidationPolicy{
+		Code:       "ORG-0001",
+		Expression: "object.spec.http.all(r, has(r.timeout)) || !has(ns.labels.env) || ns.labels.env != 'prod'",
+		Message:    "HTTP routes must set a timeout",
+		Path:       "spec/http",
+		Severity:   "error",
+	})
+	assert.NoError(err)
+
+	vs := data.CreateVirtualService()
+	prod := models.Namespace{Name: "test", Labels: map[string]string{"env": "prod"}}
+
+	check := p.Check(vs, prod)
+	assert.NotNil(check)
+	assert.Equal("ORG-0001", check.Code)
+	assert.Equal("HTTP routes must set a timeout", check.Message)
+	assert.Equal(models.ErrorSeverity, check.Severity)
+	assert.Equal("spec/http", check.Path)
+
+	assert.Nil(p.Check(vs, models.Namespace{Name: "test"}))
+	assert.Nil(p.Check(data.CreateVirtualServiceWithServiceEntryTarget(), prod))
+
+	// evaluation errors, here no http routes, are reported as warnings
+	check = p.Check(data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"}), prod)
+	assert.NotNil(check)
+	assert.Equal("ORG-0001", check.Code)
+	assert.Equal(models.WarningSeverity, check.Severity)
+	assert.Contains(check.Message, "could not be evaluated")
+
+	// route weights are uint64
+	p, err = NewPolicy(config.ValidationPolicy{
+		Code:       "ORG-0002",
+		Expression: "object.spec.http.all(h, h.route.all(r, !has(r.weight) || r.weight < 100))",
+		Message:    "m",
+	})
+	assert.NoError(err)
+	vs = data.AddRoutesToVirtualService("http", data.CreateRoute("reviews", "v2", 100),
+		data.CreateEmptyVirtualService("reviews", "test", []string{"reviews"}))
+	assert.NotNil(p.Check(vs, prod))
+	assert.Nil(p.Check(data.CreateVirtualService(), prod))
+}
+
+func TestLoadPolicies(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.ValidationPolicies{
+		Rules: []config.ValidationPolicy{
+			{Code: "ORG-0002", Expression: "true", Message: "m"},
+			{Code: "ORG-0003", Expression: "true", Message: "m", Language: "rego"},
+		},
+	}
+	configMap := &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "kiali-validation-policies"},
+		Data: map[string]string{
+			"networking.yaml": `
+- code: ORG-0001
+  expression: has(object.spec.hosts)
+  kinds: [virtualservice]
+  message: Hosts are required
+  severity: error
+`,
+			"invalid.yaml": "code: not a list",
+		},
+	}
+
+	policies := LoadPolicies(conf, configMap)
+	assert.Len(policies, 2)
+	assert.Equal("ORG-0001", policies[0].Code)
+	assert.Equal(models.ErrorSeverity, policies[0].severity)
+	assert.True(policies[0].AppliesTo("virtualservice", "bookinfo"))
+	assert.False(policies[0].AppliesTo("gateway", "bookinfo"))
+	assert.Equal("ORG-0002", policies[1].Code)
+
+	assert.Len(LoadPolicies(conf, nil), 1)
+}
+
+func TestCacheLoad(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.ValidationPolicies{
+		Rules: []config.ValidationPolicy{{Code: "ORG-0002", Expression: "true", Message: "m"}},
+	}
+	configMap := &core_v1.ConfigMap{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "kiali-validation-policies", ResourceVersion: "1"},
+		Data:       map[string]string{"policies.yaml": "[{code: ORG-0001, expression: 'true', message: m}]"},
+	}
+
+	cache := Cache{}
+	policies := cache.Load(conf, configMap)
+	assert.Len(policies, 2)
+	assert.Same(policies[0], cache.Load(conf, configMap)[0])
+
+	// a new resourceVersion of the ConfigMap is compiled again
+	configMap.ResourceVersion = "2"
+	configMap.Data = map[string]string{}
+	assert.Len(cache.Load(conf, configMap), 1)
+
+	// as a change of the config rules
+	conf.Rules = append(conf.Rules, config.ValidationPolicy{Code: "ORG-0003", Expression: "true", Message: "m"})
+	assert.Len(cache.Load(conf, configMap), 2)
+}
@@ -0,0 +1,58 @@
+package checkers
+
+import (
+	"github.com/kiali/kiali/business/checkers/policies"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// PolicyChecker evaluates the user-defined validation policies against the Istio objects
+type PolicyChecker struct {
+	IstioObjects map[string][]kubernetes.IstioObject // key: object type, e.g. virtualservice
+	Namespaces   models.Namespaces
+	Policies     []*policies.Policy
+}
+
+func (p PolicyChecker) Check() models.IstioValidations {
+	validations := models.IstioValidations{}
+	if len(p.Policies) == 0 {
+		return validations
+	}
+
+	namespaces := make(map[string]models.Namespace, len(p.Namespaces))
+	for _, ns := range p.Namespaces {
+		namespaces[ns.Name] = ns
+	}
+
+	for objectType, objects := range p.IstioObjects {
+		for _, object := range objects {
+			meta := object.GetObjectMeta()
+			namespace, found := namespaces[meta.Namespace]
+			if !found {
+				namespace = models.Namespace{Name: meta.Namespace}
+			}
+			validations.MergeValidations(p.runSingleChecks(objectType, object, namespace))
+		}
+	}
+
+	return validations
+}
+
+func (p PolicyChecker) runSingleChecks(objectType string, object kubernetes.IstioObject, namespace models.Namespace) models.IstioValidations {
+	key, validation := EmptyValidValidation(object.GetObjectMeta().Name, namespace.Name, objectType)
+
+	for _, policy := range p.Policies {
+		if !policy.AppliesTo(objectType, namespace.Name) {
+			continue
+		}
+		if check := policy.Check(object, namespace); check != nil {
+			validation.Checks = append(validation.Checks, check)
+			validation.Valid = validation.Valid && check.Severity != models.ErrorSeverity
+		}
+	}
+
+	if len(validation.Checks) == 0 {
+		return models.IstioValidations{}
+	}
+	return models.IstioValidations{key: validation}
+}
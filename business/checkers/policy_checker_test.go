@@ -0,0 +1,84 @@
+package checkers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/business/checkers/policies"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestPolicyChecker(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	rules := config.ValidationPolicies{
+		Rules: []config.ValidationPolicy{
+			{
+				Code:       "ORG-0001",
+				Expression: "object.spec.http.all(r, has(r.timeout))",
+				Kinds:      []string{"virtualservice"},
+				Message:    "HTTP routes must set a timeout",
+			},
+			{
+				Code:       "ORG-0002",
+				Expression: "!has(object.spec.trafficPolicy) || object.spec.trafficPolicy.tls.mode != 'DISABLE'",
+				Kinds:      []string{"destinationrule"},
+				Message:    "TLS must not be disabled in production",
+				Namespaces: "prod-.*",
+				Severity:   "error",
+			},
+		},
+	}
+
+	disabledMTLS := func(ns string) kubernetes.IstioObject {
+		return data.AddTrafficPolicyToDestinationRule(data.CreateDisabledMTLSTrafficPolicyForDestinationRules(),
+			data.CreateTestDestinationRule(ns, "reviews", "reviews"))
+	}
+
+	validations := PolicyChecker{
+		IstioObjects: map[string][]kubernetes.IstioObject{
+			VirtualCheckerType: {
+				data.CreateVirtualService(),
+				data.CreateVirtualServiceWithServiceEntryTarget(),
+			},
+			DestinationRuleCheckerType: {
+				disabledMTLS("prod-east"),
+				disabledMTLS("staging"),
+				data.CreateTestDestinationRule("prod-west", "reviews", "reviews"),
+			},
+		},
+		Namespaces: models.Namespaces{{Name: "test"}, {Name: "prod-east"}},
+		Policies:   policies.LoadPolicies(rules, nil),
+	}.Check()
+
+	assert.Len(validations, 2)
+
+	vs := validations[models.IstioValidationKey{ObjectType: VirtualCheckerType, Name: "reviews", Namespace: "test"}]
+	assert.NotNil(vs)
+	assert.True(vs.Valid)
+	assert.Len(vs.Checks, 1)
+	assert.Equal("ORG-0001", vs.Checks[0].Code)
+	assert.Equal(models.WarningSeverity, vs.Checks[0].Severity)
+
+	dr := validations[models.IstioValidationKey{ObjectType: DestinationRuleCheckerType, Name: "reviews", Namespace: "prod-east"}]
+	assert.NotNil(dr)
+	assert.False(dr.Valid)
+	assert.Len(dr.Checks, 1)
+	assert.Equal("ORG-0002", dr.Checks[0].Code)
+	assert.Equal(models.ErrorSeverity, dr.Checks[0].Severity)
+}
+
+func TestPolicyCheckerNoPolicies(t *testing.T) {
+	validations := PolicyChecker{
+		IstioObjects: map[string][]kubernetes.IstioObject{
+			VirtualCheckerType: {data.CreateVirtualService()},
+		},
+	}.Check()
+	assert.Empty(t, validations)
+}
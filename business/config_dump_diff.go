@@ -0,0 +1,202 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// The config dumps of large meshes weigh tens of MB: each pod keeps its last maxConfigDumpSnapshotsPerPod
+// snapshots, and all the snapshots share a budget of maxConfigDumpSnapshotsBytes, the oldest are removed first.
+// The snapshots are kept in memory, they don't survive a restart.
+const (
+	maxConfigDumpSnapshotsPerPod = 5
+	maxConfigDumpSnapshotsBytes  = 64 * 1024 * 1024
+)
+
+// configDumpSnapshot is a stored config dump, JSON encoded
+type configDumpSnapshot struct {
+	models.ConfigDumpSnapshotInfo
+	dump []byte
+}
+
+var (
+	// the snapshots by namespace/pod, oldest first
+	configDumpSnapshots      = map[string][]*configDumpSnapshot{}
+	configDumpSnapshotsBytes int
+	configDumpSnapshotsMutex sync.RWMutex
+)
+
+// SaveConfigDump stores the current config dump of a pod, to compare it later with DiffConfigDumps
+func (in *ProxyStatusService) SaveConfigDump(namespace, pod string) (*models.ConfigDumpSnapshotInfo, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	dump, err := in.k8s.GetConfigDump(namespace, pod)
+	if err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(dump)
+	if err != nil {
+		return nil, err
+	}
+	suffix, err := util.CryptoRandomBytes(4)
+	if err != nil {
+		return nil, err
+	}
+
+	now := util.Clock.Now()
+	snapshot := &configDumpSnapshot{
+		ConfigDumpSnapshotInfo: models.ConfigDumpSnapshotInfo{
+			ID:        fmt.Sprintf("%d-%x", now.Unix(), suffix),
+			Namespace: namespace,
+			Pod:       pod,
+			Created:   now,
+		},
+		dump: encoded,
+	}
+	if err := storeConfigDumpSnapshot(snapshot, maxConfigDumpSnapshotsPerPod, maxConfigDumpSnapshotsBytes); err != nil {
+		return nil, err
+	}
+	return &snapshot.ConfigDumpSnapshotInfo, nil
+}
+
+// storeConfigDumpSnapshot adds the snapshot, removing the oldest ones of its pod beyond perPod and the oldest ones
+// of all the pods beyond the bytes budget
+func storeConfigDumpSnapshot(snapshot *configDumpSnapshot, perPod, budget int) error {
+	if len(snapshot.dump) > budget {
+		return fmt.Errorf("config dump of pod [%s/%s] is %d bytes, over the %d bytes of the snapshots", snapshot.Namespace, snapshot.Pod, len(snapshot.dump), budget)
+	}
+
+	configDumpSnapshotsMutex.Lock()
+	defer configDumpSnapshotsMutex.Unlock()
+	key := snapshot.Namespace + "/" + snapshot.Pod
+	configDumpSnapshots[key] = append(configDumpSnapshots[key], snapshot)
+	configDumpSnapshotsBytes += len(snapshot.dump)
+	for len(configDumpSnapshots[key]) > perPod {
+		removeOldestConfigDumpSnapshot(key)
+	}
+	for configDumpSnapshotsBytes > budget {
+		oldestKey := ""
+		for k, snapshots := range configDumpSnapshots {
+			if oldestKey == "" || snapshots[0].Created.Before(configDumpSnapshots[oldestKey][0].Created) {
+				oldestKey = k
+			}
+		}
+		removeOldestConfigDumpSnapshot(oldestKey)
+	}
+	return nil
+}
+
+func removeOldestConfigDumpSnapshot(key string) {
+	snapshots := configDumpSnapshots[key]
+	configDumpSnapshotsBytes -= len(snapshots[0].dump)
+	if len(snapshots) == 1 {
+		delete(configDumpSnapshots, key)
+		return
+	}
+	configDumpSnapshots[key] = snapshots[1:]
+}
+
+// ListConfigDumps returns the stored config dumps of a pod, oldest first
+func (in *ProxyStatusService) ListConfigDumps(namespace, pod string) ([]models.ConfigDumpSnapshotInfo, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	configDumpSnapshotsMutex.RLock()
+	defer configDumpSnapshotsMutex.RUnlock()
+	infos := []models.ConfigDumpSnapshotInfo{}
+	for _, s := range configDumpSnapshots[namespace+"/"+pod] {
+		infos = append(infos, s.ConfigDumpSnapshotInfo)
+	}
+	return infos, nil
+}
+
+// DiffConfigDumps compares the parsed listeners, clusters, routes and bootstrap of two config dumps. When
+// snapshot is set, it returns the changes from the stored dump to the current dump of the pod, otherwise from
+// the current dump of the pod to the current dump of the other pod.
+func (in *ProxyStatusService) DiffConfigDumps(namespace, pod, otherNamespace, otherPod, snapshot string) (*models.EnvoyProxyDumpDiff, error) {
+	var from, to *kubernetes.ConfigDump
+	var fromRef, toRef models.ConfigDumpRef
+
+	if snapshot != "" {
+		stored, err := in.getConfigDumpSnapshot(snapshot)
+		if err != nil {
+			return nil, err
+		}
+		from, fromRef = stored.ConfigDump, models.ConfigDumpRef{Namespace: stored.Namespace, Pod: stored.Pod, Snapshot: stored.ID, Created: stored.Created}
+		if to, toRef, err = in.currentConfigDump(namespace, pod); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if from, fromRef, err = in.currentConfigDump(namespace, pod); err != nil {
+			return nil, err
+		}
+		if to, toRef, err = in.currentConfigDump(otherNamespace, otherPod); err != nil {
+			return nil, err
+		}
+	}
+
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	nss := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		nss = append(nss, ns.Name)
+	}
+
+	diff, err := models.DiffConfigDumps(from, to, nss)
+	if err != nil {
+		return nil, err
+	}
+	diff.From, diff.To = fromRef, toRef
+	return diff, nil
+}
+
+func (in *ProxyStatusService) currentConfigDump(namespace, pod string) (*kubernetes.ConfigDump, models.ConfigDumpRef, error) {
+	ref := models.ConfigDumpRef{Namespace: namespace, Pod: pod, Created: util.Clock.Now()}
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, ref, err
+	}
+	dump, err := in.k8s.GetConfigDump(namespace, pod)
+	return dump, ref, err
+}
+
+// getConfigDumpSnapshot returns the stored dump, if its namespace is accessible
+func (in *ProxyStatusService) getConfigDumpSnapshot(id string) (*models.ConfigDumpSnapshot, error) {
+	var snapshot *configDumpSnapshot
+	configDumpSnapshotsMutex.RLock()
+	for _, snapshots := range configDumpSnapshots {
+		for _, s := range snapshots {
+			if s.ID == id {
+				snapshot = s
+				break
+			}
+		}
+		if snapshot != nil {
+			break
+		}
+	}
+	configDumpSnapshotsMutex.RUnlock()
+
+	notFound := kubernetes.NewNotFound(id, "Kiali", "ConfigDumpSnapshot")
+	if snapshot == nil {
+		return nil, notFound
+	}
+	if _, err := in.businessLayer.Namespace.GetNamespace(snapshot.Namespace); err != nil {
+		// don't disclose the existence of the snapshot
+		return nil, notFound
+	}
+	dump := &kubernetes.ConfigDump{}
+	if err := json.Unmarshal(snapshot.dump, dump); err != nil {
+		return nil, err
+	}
+	return &models.ConfigDumpSnapshot{ConfigDumpSnapshotInfo: snapshot.ConfigDumpSnapshotInfo, ConfigDump: dump}, nil
+}
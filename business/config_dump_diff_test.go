@@ -0,0 +1,55 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/models"
+)
+
+func TestStoreConfigDumpSnapshot(t *testing.T) {
+	assert := assert.New(t)
+	defer func() {
+		configDumpSnapshots = map[string][]*configDumpSnapshot{}
+		configDumpSnapshotsBytes = 0
+	}()
+
+	created := time.Unix(0, 0)
+	snapshot := func(id, pod string, size int) *configDumpSnapshot {
+		created = created.Add(time.Second)
+		return &configDumpSnapshot{
+			ConfigDumpSnapshotInfo: models.ConfigDumpSnapshotInfo{ID: id, Namespace: "bookinfo", Pod: pod, Created: created},
+			dump:                   make([]byte, size),
+		}
+	}
+	ids := func(pod string) []string {
+		ids := []string{}
+		for _, s := range configDumpSnapshots["bookinfo/"+pod] {
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	// the oldest snapshots of the pod are removed beyond 2
+	assert.NoError(storeConfigDumpSnapshot(snapshot("a1", "a", 10), 2, 100))
+	assert.NoError(storeConfigDumpSnapshot(snapshot("a2", "a", 10), 2, 100))
+	assert.NoError(storeConfigDumpSnapshot(snapshot("a3", "a", 10), 2, 100))
+	assert.Equal([]string{"a2", "a3"}, ids("a"))
+	assert.Equal(20, configDumpSnapshotsBytes)
+
+	// the oldest snapshots of all the pods are removed beyond 100 bytes
+	assert.NoError(storeConfigDumpSnapshot(snapshot("b1", "b", 90), 2, 100))
+	assert.Equal([]string{"a3"}, ids("a"))
+	assert.Equal([]string{"b1"}, ids("b"))
+	assert.NoError(storeConfigDumpSnapshot(snapshot("b2", "b", 10), 2, 100))
+	assert.Empty(ids("a"))
+	assert.NotContains(configDumpSnapshots, "bookinfo/a")
+	assert.Equal([]string{"b1", "b2"}, ids("b"))
+	assert.Equal(100, configDumpSnapshotsBytes)
+
+	// a dump over the budget is not stored
+	assert.Error(storeConfigDumpSnapshot(snapshot("c1", "c", 101), 2, 100))
+	assert.Empty(ids("c"))
+}
@@ -8,6 +8,7 @@ import (
 	"sync"
 
 	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
@@ -547,9 +548,13 @@ func (in *IstioConfigService) ParseJsonForCreate(resourceType string, body []byt
 	return marshalled, nil
 }
 
-// DeleteIstioConfigDetail deletes the given Istio resource
+// DeleteIstioConfigDetail deletes the given Istio resource, the previous state is kept as a revision
 func (in *IstioConfigService) DeleteIstioConfigDetail(api, namespace, resourceType, name string) (err error) {
+	current, currentErr := in.currentRevisionObject(namespace, resourceType, name)
 	err = in.k8s.DeleteIstioObject(api, namespace, resourceType, name)
+	if err == nil {
+		saveIstioConfigRevision(namespace, resourceType, name, models.RevisionDelete, current, currentErr)
+	}
 
 	// Cache is stopped after a Create/Update/Delete operation to force a refresh
 	if kialiCache != nil && err == nil {
@@ -558,8 +563,14 @@ func (in *IstioConfigService) DeleteIstioConfigDetail(api, namespace, resourceTy
 	return err
 }
 
+// UpdateIstioConfigDetail patches the given Istio resource, the previous state is kept as a revision
 func (in *IstioConfigService) UpdateIstioConfigDetail(api, namespace, resourceType, name, jsonPatch string) (models.IstioConfigDetails, error) {
-	return in.modifyIstioConfigDetail(api, namespace, resourceType, name, jsonPatch, false)
+	current, currentErr := in.currentRevisionObject(namespace, resourceType, name)
+	istioConfigDetail, err := in.modifyIstioConfigDetail(api, namespace, resourceType, name, jsonPatch, false)
+	if err == nil {
+		saveIstioConfigRevision(namespace, resourceType, name, models.RevisionUpdate, current, currentErr)
+	}
+	return istioConfigDetail, err
 }
 
 func (in *IstioConfigService) modifyIstioConfigDetail(api, namespace, resourceType, name, json string, create bool) (models.IstioConfigDetails, error) {
@@ -634,6 +645,74 @@ func (in *IstioConfigService) CreateIstioConfigDetail(api, namespace, resourceTy
 	return in.modifyIstioConfigDetail(api, namespace, resourceType, "", json, true)
 }
 
+// DryRunCreateIstioConfigDetail validates the creation of the given Istio resource, the resource is not created
+func (in *IstioConfigService) DryRunCreateIstioConfigDetail(namespace, resourceType string, body []byte) (models.IstioValidationsDryRun, error) {
+	json, err := in.ParseJsonForCreate(resourceType, body)
+	if err != nil {
+		return models.IstioValidationsDryRun{}, errors2.NewBadRequest(err.Error())
+	}
+	proposed := &kubernetes.GenericIstioObject{}
+	if err = parseProposedIstioObject([]byte(json), proposed); err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+	if proposed.Name == "" {
+		return models.IstioValidationsDryRun{}, errors2.NewBadRequest("metadata.name is required")
+	}
+	if current, err := in.k8s.GetIstioObject(namespace, resourceType, proposed.Name); err == nil && current != nil {
+		return models.IstioValidationsDryRun{}, errors2.NewAlreadyExists(schema.GroupResource{Group: kubernetes.ResourceTypesToAPI[resourceType], Resource: resourceType}, proposed.Name)
+	} else if err != nil && !errors2.IsNotFound(err) {
+		return models.IstioValidationsDryRun{}, err
+	}
+	proposed.Namespace = namespace
+
+	return in.businessLayer.Validations.ValidateIstioObjectChange(namespace, resourceType, proposed)
+}
+
+// DryRunUpdateIstioConfigDetail validates the update, by a json merge patch, of the given Istio resource, the
+// resource is not updated
+func (in *IstioConfigService) DryRunUpdateIstioConfigDetail(namespace, resourceType, name, jsonPatch string) (models.IstioValidationsDryRun, error) {
+	current, err := in.k8s.GetIstioObject(namespace, resourceType, name)
+	if err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+
+	currentJson, err := json.Marshal(current)
+	if err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+	var target, patch interface{}
+	if err = json.Unmarshal(currentJson, &target); err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+	if err = json.Unmarshal([]byte(jsonPatch), &patch); err != nil {
+		return models.IstioValidationsDryRun{}, errors2.NewBadRequest("invalid json merge patch: " + err.Error())
+	}
+	proposedJson, err := json.Marshal(util.MergePatch(target, patch))
+	if err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+
+	proposed := &kubernetes.GenericIstioObject{}
+	if err = parseProposedIstioObject(proposedJson, proposed); err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+	// as in the api server, a patch can't rename nor move the object
+	proposed.Name = name
+	proposed.Namespace = namespace
+
+	return in.businessLayer.Validations.ValidateIstioObjectChange(namespace, resourceType, proposed)
+}
+
+func parseProposedIstioObject(body []byte, proposed *kubernetes.GenericIstioObject) error {
+	if err := json.Unmarshal(body, proposed); err != nil {
+		return errors2.NewBadRequest("invalid Istio object: " + err.Error())
+	}
+	if proposed.Spec == nil {
+		proposed.Spec = map[string]interface{}{}
+	}
+	return nil
+}
+
 func (in *IstioConfigService) GetIstioConfigPermissions(namespaces []string) models.IstioConfigPermissions {
 	istioConfigPermissions := make(models.IstioConfigPermissions, len(namespaces))
 
@@ -0,0 +1,220 @@
+package business
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// bulkApplyOrder lists the object types supported by a bulk apply, in the order they are applied: an object is
+// applied after the objects it references, e.g. a VirtualService after its Gateways and the DestinationRules
+// defining its subsets.
+var bulkApplyOrder = []string{
+	kubernetes.Gateways,
+	kubernetes.ServiceEntries,
+	kubernetes.DestinationRules,
+	kubernetes.VirtualServices,
+	kubernetes.Sidecars,
+	kubernetes.PeerAuthentications,
+	kubernetes.RequestAuthentications,
+	kubernetes.AuthorizationPolicies,
+}
+
+type bulkApplyObject struct {
+	objectType string
+	proposed   kubernetes.IstioObject
+	// the revision object of the proposed object
+	object json.RawMessage
+	// the revision object of the current object, nil when the object is created
+	previous json.RawMessage
+}
+
+// bulkApplyObjectType returns the object type of an Istio kind supported by a bulk apply and its apply order, empty
+// and -1 if the kind is not supported
+func bulkApplyObjectType(kind string) (string, int) {
+	for i, objectType := range bulkApplyOrder {
+		if kubernetes.PluralType[objectType] == kind {
+			return objectType, i
+		}
+	}
+	return "", -1
+}
+
+// parseBulkApply decodes the Istio objects of a bulk apply and sorts them in apply order. The objects must belong
+// to the namespace, objects without namespace are set in it.
+func (in *IstioConfigService) parseBulkApply(namespace string, body []byte) ([]bulkApplyObject, error) {
+	manifests := kubernetes.Manifests{}
+	if err := manifests.Decode(bytes.NewReader(body), namespace); err != nil {
+		return nil, errors2.NewBadRequest("invalid Istio objects: " + err.Error())
+	}
+	if len(manifests.IstioObjects) == 0 {
+		return nil, errors2.NewBadRequest("no Istio objects to apply")
+	}
+	if n := manifests.Count() - len(manifests.IstioObjects); n > 0 {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("only Istio objects can be applied, found [%d] other objects", n))
+	}
+
+	objects := make([]bulkApplyObject, 0, len(manifests.IstioObjects))
+	order := make([]int, 0, len(manifests.IstioObjects))
+	names := map[string]bool{}
+	for _, proposed := range manifests.IstioObjects {
+		kind, meta := proposed.GetTypeMeta().Kind, proposed.GetObjectMeta()
+		objectType, i := bulkApplyObjectType(kind)
+		if objectType == "" {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] can't be applied, the kind is not supported", kind, meta.Name))
+		}
+		if meta.Namespace != namespace {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] belongs to namespace [%s], not to [%s]", kind, meta.Name, meta.Namespace, namespace))
+		}
+		key := objectType + "/" + meta.Name
+		if names[key] {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] is duplicated", kind, meta.Name))
+		}
+		names[key] = true
+
+		object, err := revisionObject(proposed)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = in.ParseJsonForCreate(objectType, object); err != nil {
+			return nil, errors2.NewBadRequest(fmt.Sprintf("%s [%s] is invalid: %v", kind, meta.Name, err))
+		}
+		if proposed.GetSpec() == nil {
+			proposed.SetSpec(map[string]interface{}{})
+		}
+		objects = append(objects, bulkApplyObject{objectType: objectType, proposed: proposed, object: object})
+		order = append(order, i)
+	}
+
+	sort.Stable(bulkApplySort{objects: objects, order: order})
+	return objects, nil
+}
+
+// bulkApplySort sorts the objects by the apply order of their type, keeping the order of the objects of a same type
+type bulkApplySort struct {
+	objects []bulkApplyObject
+	order   []int
+}
+
+func (s bulkApplySort) Len() int           { return len(s.objects) }
+func (s bulkApplySort) Less(i, j int) bool { return s.order[i] < s.order[j] }
+func (s bulkApplySort) Swap(i, j int) {
+	s.objects[i], s.objects[j] = s.objects[j], s.objects[i]
+	s.order[i], s.order[j] = s.order[j], s.order[i]
+}
+
+// ApplyIstioConfigBulk applies a set of Istio objects of a namespace, decoded from yaml or json manifests. The
+// objects are validated together against the current Istio config: a set introducing validation errors is only
+// applied when forced, and it is never applied with dryRun. The objects are applied in dependency order, missing
+// objects are created and existing objects are replaced. When an object fails, the objects already applied are
+// rolled back, in reverse order: the created objects are deleted and the updated objects are patched back to their
+// previous state.
+func (in *IstioConfigService) ApplyIstioConfigBulk(namespace string, body []byte, force, dryRun bool) (*models.IstioConfigBulkApply, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	objects, err := in.parseBulkApply(namespace, body)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.IstioConfigBulkApply{Objects: make([]models.IstioConfigBulkApplyObject, 0, len(objects))}
+	objectTypes := make([]string, 0, len(objects))
+	proposed := make([]kubernetes.IstioObject, 0, len(objects))
+	for i := range objects {
+		object := &objects[i]
+		name := object.proposed.GetObjectMeta().Name
+		if object.previous, err = in.currentRevisionObject(namespace, object.objectType, name); err != nil {
+			return nil, err
+		}
+		operation := models.BulkApplyUpdate
+		if object.previous == nil {
+			operation = models.BulkApplyCreate
+		}
+		result.Objects = append(result.Objects, models.IstioConfigBulkApplyObject{
+			ObjectType: object.objectType,
+			Name:       name,
+			Object:     object.object,
+			Previous:   object.previous,
+			Operation:  operation,
+			Status:     models.BulkApplySkipped,
+		})
+		objectTypes = append(objectTypes, object.objectType)
+		proposed = append(proposed, object.proposed)
+	}
+
+	if result.Validations, err = in.businessLayer.Validations.ValidateIstioObjectsChange(namespace, objectTypes, proposed); err != nil {
+		return nil, err
+	}
+	if dryRun || (result.Validations.IntroducesErrors() && !force) {
+		return result, nil
+	}
+
+	for i, object := range objects {
+		if err = in.applyBulkObject(namespace, object); err != nil {
+			result.Objects[i].Status, result.Objects[i].Error = models.BulkApplyFailed, err.Error()
+			result.Error = fmt.Sprintf("%s [%s] failed: %v", object.objectType, result.Objects[i].Name, err)
+			in.rollbackBulkApply(namespace, objects[:i], result.Objects[:i])
+			return result, nil
+		}
+		result.Objects[i].Status = models.BulkApplyApplied
+	}
+	result.Applied = true
+
+	// the replaced objects are kept as revisions once all the objects are applied, a rolled back apply leaves no revision
+	for _, object := range objects {
+		saveIstioConfigRevision(namespace, object.objectType, object.proposed.GetObjectMeta().Name, models.RevisionUpdate, object.previous, nil)
+	}
+	return result, nil
+}
+
+// applyBulkObject creates the object, or patches the current object into the proposed one
+func (in *IstioConfigService) applyBulkObject(namespace string, object bulkApplyObject) error {
+	api := GetIstioAPI(object.objectType)
+	if object.previous == nil {
+		_, err := in.CreateIstioConfigDetail(api, namespace, object.objectType, object.object)
+		return err
+	}
+	patch, err := revisionPatch(object.previous, object.object)
+	if err != nil {
+		return err
+	}
+	_, err = in.modifyIstioConfigDetail(api, namespace, object.objectType, object.proposed.GetObjectMeta().Name, patch, false)
+	return err
+}
+
+// rollbackBulkApply reverts the applied objects in reverse order, the results are updated with the rollback status
+func (in *IstioConfigService) rollbackBulkApply(namespace string, objects []bulkApplyObject, results []models.IstioConfigBulkApplyObject) {
+	for i := len(objects) - 1; i >= 0; i-- {
+		object := objects[i]
+		api, name := GetIstioAPI(object.objectType), object.proposed.GetObjectMeta().Name
+
+		var err error
+		if object.previous == nil {
+			err = in.k8s.DeleteIstioObject(api, namespace, object.objectType, name)
+		} else {
+			var patch string
+			if patch, err = revisionPatch(object.object, object.previous); err == nil {
+				_, err = in.k8s.UpdateIstioObject(api, namespace, object.objectType, name, patch)
+			}
+		}
+		if err != nil {
+			log.Errorf("Rollback of Istio object [%s/%s/%s] failed: %v", namespace, object.objectType, name, err)
+			results[i].Status, results[i].Error = models.BulkApplyRollbackFailed, err.Error()
+		} else {
+			results[i].Status = models.BulkApplyRolledBack
+		}
+	}
+
+	// Cache is stopped after a Create/Update/Delete operation to force a refresh
+	if kialiCache != nil {
+		kialiCache.RefreshNamespace(namespace)
+	}
+}
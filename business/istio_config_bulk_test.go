@@ -0,0 +1,136 @@
+package business
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+const testBulkApply = `
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: product-canary
+spec:
+  hosts:
+  - product
+  http:
+  - route:
+    - destination:
+        host: product
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: product-dr
+  namespace: test
+spec:
+  host: product
+`
+
+func mockBulkApply() (*IstioConfigService, *kubetest.K8SClientMock) {
+	conf := config.NewConfig()
+	config.Set(conf)
+	istioConfigRevisions = map[istioConfigRevisionKey]*istioConfigRevisionHistory{}
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	notFound := errors2.NewNotFound(schema.GroupResource{Resource: "virtualservices"}, "product-canary")
+	k8s.On("GetIstioObject", "test", "destinationrules", "product-dr").Return(fakeCombinedIstioDetails().DestinationRules[0], nil)
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-canary").Return((*kubernetes.GenericIstioObject)(nil), notFound)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "destinationrules", "product-dr", mock.AnythingOfType("string")).Return(data.CreateEmptyDestinationRule("test", "product-dr", "product"), nil)
+	return &vs.businessLayer.IstioConfig, k8s
+}
+
+func TestApplyIstioConfigBulk(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	configService, k8s := mockBulkApply()
+	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return(data.CreateEmptyVirtualService("product-canary", "test", []string{"product"}), nil)
+
+	dryRun, err := configService.ApplyIstioConfigBulk("test", []byte(testBulkApply), false, true)
+	require.NoError(err)
+	assert.False(dryRun.Applied)
+	require.Len(dryRun.Validations.Validations, 2)
+	assert.Equal("product-dr", dryRun.Validations.Validations[0].Name)
+	k8s.AssertNotCalled(t, "UpdateIstioObject", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	result, err := configService.ApplyIstioConfigBulk("test", []byte(testBulkApply), true, false)
+	require.NoError(err)
+	assert.True(result.Applied)
+	assert.Empty(result.Error)
+	// the destination rule is applied first
+	require.Len(result.Objects, 2)
+	assert.Equal(models.IstioConfigBulkApplyObject{
+		ObjectType: "destinationrules",
+		Name:       "product-dr",
+		Object:     result.Objects[0].Object,
+		Previous:   result.Objects[0].Previous,
+		Operation:  models.BulkApplyUpdate,
+		Status:     models.BulkApplyApplied,
+	}, result.Objects[0])
+	assert.Contains(string(result.Objects[0].Previous), `"subsets"`)
+	assert.Equal(models.BulkApplyCreate, result.Objects[1].Operation)
+	assert.Equal(models.BulkApplyApplied, result.Objects[1].Status)
+	assert.Nil(result.Objects[1].Previous)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+	k8s.AssertNumberOfCalls(t, "CreateIstioObject", 1)
+
+	// the updated destination rule is kept as a revision
+	revisions, err := configService.GetIstioConfigRevisions("test", "destinationrules", "product-dr")
+	require.NoError(err)
+	assert.Len(revisions, 1)
+}
+
+func TestApplyIstioConfigBulkRollback(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	configService, k8s := mockBulkApply()
+	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return((*kubernetes.GenericIstioObject)(nil), errors.New("admission webhook denied the request"))
+
+	result, err := configService.ApplyIstioConfigBulk("test", []byte(testBulkApply), true, false)
+	require.NoError(err)
+	assert.False(result.Applied)
+	assert.Contains(result.Error, "product-canary")
+	assert.Equal(models.BulkApplyRolledBack, result.Objects[0].Status)
+	assert.Equal(models.BulkApplyFailed, result.Objects[1].Status)
+
+	// the destination rule is patched back to its previous state
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 2)
+	patch := k8s.Calls[len(k8s.Calls)-1].Arguments.String(4)
+	assert.Contains(patch, "subsets")
+
+	// the rolled back update is not kept as a revision
+	revisions, err := configService.GetIstioConfigRevisions("test", "destinationrules", "product-dr")
+	require.NoError(err)
+	assert.Empty(revisions)
+}
+
+func TestApplyInvalidIstioConfigBulk(t *testing.T) {
+	configService, _ := mockBulkApply()
+
+	for _, body := range []string{
+		"",
+		"kind: [VirtualService",
+		testBulkApply + "---\napiVersion: v1\nkind: Service\nmetadata:\n  name: product\n",
+		testBulkApply + "---\napiVersion: networking.istio.io/v1alpha3\nkind: DestinationRule\nmetadata:\n  name: product-dr\n",
+		"apiVersion: networking.istio.io/v1alpha3\nkind: Gateway\nmetadata:\n  name: gw\n  namespace: bookinfo\n",
+		"apiVersion: networking.istio.io/v1alpha3\nkind: EnvoyFilter\nmetadata:\n  name: filter\n",
+	} {
+		_, err := configService.ApplyIstioConfigBulk("test", []byte(body), false, false)
+		assert.True(t, errors2.IsBadRequest(err), body)
+	}
+}
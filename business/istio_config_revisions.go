@@ -0,0 +1,262 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// The revisions are kept in memory, they don't survive a restart of Kiali. Each Istio object keeps its last
+// maxIstioConfigRevisions revisions, and the revisions of up to maxIstioConfigRevisionObjects objects are kept: the
+// history of the object saved the longest ago is removed first.
+const (
+	maxIstioConfigRevisions       = 10
+	maxIstioConfigRevisionObjects = 1000
+)
+
+type istioConfigRevisionKey struct {
+	namespace  string
+	objectType string
+	name       string
+}
+
+type istioConfigRevisionHistory struct {
+	lastRevision int
+	// the sequence number of the last save, the least recently saved history is removed first
+	lastSave  uint64
+	revisions []models.IstioConfigRevision
+}
+
+var (
+	istioConfigRevisions      = map[istioConfigRevisionKey]*istioConfigRevisionHistory{}
+	istioConfigRevisionsSaves uint64
+	istioConfigRevisionsMutex sync.RWMutex
+)
+
+// revisionObject returns the part of an Istio object kept in its revisions: its name, namespace, labels and
+// annotations, and its spec. The server populated fields would only add noise to the diffs and the restores.
+func revisionObject(object kubernetes.IstioObject) (json.RawMessage, error) {
+	meta := object.GetObjectMeta()
+	metadata := map[string]interface{}{
+		"name":      meta.Name,
+		"namespace": meta.Namespace,
+	}
+	if len(meta.Labels) > 0 {
+		metadata["labels"] = meta.Labels
+	}
+	if len(meta.Annotations) > 0 {
+		metadata["annotations"] = meta.Annotations
+	}
+	return json.Marshal(map[string]interface{}{
+		"metadata": metadata,
+		"spec":     object.GetSpec(),
+	})
+}
+
+// revisionPatch returns the json merge patch changing the from revision object into the to revision object
+func revisionPatch(from, to json.RawMessage) (string, error) {
+	var fromValue, toValue interface{}
+	if err := json.Unmarshal(from, &fromValue); err != nil {
+		return "", err
+	}
+	if err := json.Unmarshal(to, &toValue); err != nil {
+		return "", err
+	}
+	patch, err := json.Marshal(util.CreateMergePatch(fromValue, toValue))
+	if err != nil {
+		return "", err
+	}
+	return string(patch), nil
+}
+
+// currentRevisionObject returns the revision object of the current Istio object, nil when it does not exist
+func (in *IstioConfigService) currentRevisionObject(namespace, objectType, name string) (json.RawMessage, error) {
+	current, err := in.k8s.GetIstioObject(namespace, objectType, name)
+	if err != nil {
+		if errors2.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if current == nil {
+		return nil, nil
+	}
+	return revisionObject(current)
+}
+
+// saveIstioConfigRevision stores the state of an Istio object replaced by a successful operation. A state that could
+// not be read is not stored, it doesn't fail the operation.
+func saveIstioConfigRevision(namespace, objectType, name, operation string, object json.RawMessage, err error) {
+	if err != nil {
+		log.Warningf("Revision of Istio object [%s/%s/%s] not stored: %v", namespace, objectType, name, err)
+		return
+	}
+	if object == nil {
+		return
+	}
+	key := istioConfigRevisionKey{namespace: namespace, objectType: objectType, name: name}
+
+	istioConfigRevisionsMutex.Lock()
+	defer istioConfigRevisionsMutex.Unlock()
+	history, found := istioConfigRevisions[key]
+	if !found {
+		if len(istioConfigRevisions) >= maxIstioConfigRevisionObjects {
+			removeOldestIstioConfigRevisionHistory()
+		}
+		history = &istioConfigRevisionHistory{}
+		istioConfigRevisions[key] = history
+	}
+	istioConfigRevisionsSaves++
+	history.lastSave = istioConfigRevisionsSaves
+	history.lastRevision++
+	history.revisions = append(history.revisions, models.IstioConfigRevision{
+		Revision:  history.lastRevision,
+		Timestamp: time.Now(),
+		Operation: operation,
+		Object:    object,
+	})
+	if len(history.revisions) > maxIstioConfigRevisions {
+		history.revisions = append([]models.IstioConfigRevision{}, history.revisions[len(history.revisions)-maxIstioConfigRevisions:]...)
+	}
+}
+
+// removeOldestIstioConfigRevisionHistory removes the history of the object saved the longest ago, the caller must
+// hold the revisions lock
+func removeOldestIstioConfigRevisionHistory() {
+	var oldestKey istioConfigRevisionKey
+	var oldest *istioConfigRevisionHistory
+	for key, history := range istioConfigRevisions {
+		if oldest == nil || history.lastSave < oldest.lastSave {
+			oldestKey, oldest = key, history
+		}
+	}
+	if oldest != nil {
+		log.Debugf("Removing the revisions of Istio object [%s/%s/%s], over %d objects", oldestKey.namespace, oldestKey.objectType, oldestKey.name, maxIstioConfigRevisionObjects)
+		delete(istioConfigRevisions, oldestKey)
+	}
+}
+
+// GetIstioConfigRevisions returns the stored revisions of an Istio object, oldest first
+func (in *IstioConfigService) GetIstioConfigRevisions(namespace, objectType, name string) ([]models.IstioConfigRevision, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	istioConfigRevisionsMutex.RLock()
+	defer istioConfigRevisionsMutex.RUnlock()
+	revisions := []models.IstioConfigRevision{}
+	if history, found := istioConfigRevisions[istioConfigRevisionKey{namespace: namespace, objectType: objectType, name: name}]; found {
+		revisions = append(revisions, history.revisions...)
+	}
+	return revisions, nil
+}
+
+// getIstioConfigRevision returns a stored revision of an Istio object, the caller must check the namespace access
+func getIstioConfigRevision(namespace, objectType, name string, revision int) (*models.IstioConfigRevision, error) {
+	istioConfigRevisionsMutex.RLock()
+	defer istioConfigRevisionsMutex.RUnlock()
+	if history, found := istioConfigRevisions[istioConfigRevisionKey{namespace: namespace, objectType: objectType, name: name}]; found {
+		for _, r := range history.revisions {
+			if r.Revision == revision {
+				return &r, nil
+			}
+		}
+	}
+	return nil, kubernetes.NewNotFound(fmt.Sprintf("%s/%s/%s revision %d", namespace, objectType, name, revision), "Kiali", "IstioConfigRevision")
+}
+
+// DiffIstioConfigRevisions compares two revisions of an Istio object. When to is 0, the revision is compared with
+// the current object.
+func (in *IstioConfigService) DiffIstioConfigRevisions(namespace, objectType, name string, from, to int) (*models.IstioConfigRevisionDiff, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	fromRevision, err := getIstioConfigRevision(namespace, objectType, name, from)
+	if err != nil {
+		return nil, err
+	}
+	var toObject json.RawMessage
+	if to == 0 {
+		if toObject, err = in.currentRevisionObject(namespace, objectType, name); err != nil {
+			return nil, err
+		}
+		if toObject == nil {
+			// the object is deleted
+			toObject = json.RawMessage("{}")
+		}
+	} else {
+		toRevision, err := getIstioConfigRevision(namespace, objectType, name, to)
+		if err != nil {
+			return nil, err
+		}
+		toObject = toRevision.Object
+	}
+
+	return &models.IstioConfigRevisionDiff{
+		From:   from,
+		To:     to,
+		Fields: models.DiffIstioConfigRevisions(fromRevision.Object, toObject),
+	}, nil
+}
+
+// RestoreIstioConfigRevision validates a revision of an Istio object against the current Istio config and applies
+// it: the current object is patched back to the revision, or the revision is created again when the object was
+// deleted. A revision introducing validation errors is only applied when forced. The replaced object is kept as a
+// new revision.
+func (in *IstioConfigService) RestoreIstioConfigRevision(api, namespace, objectType, name string, revision int, force bool) (*models.IstioConfigRevisionRestore, error) {
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	restored, err := getIstioConfigRevision(namespace, objectType, name, revision)
+	if err != nil {
+		return nil, err
+	}
+	current, err := in.currentRevisionObject(namespace, objectType, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.IstioConfigRevisionRestore{}
+	if current == nil {
+		if result.Validations, err = in.DryRunCreateIstioConfigDetail(namespace, objectType, restored.Object); err != nil {
+			return nil, err
+		}
+		if result.Validations.IntroducesErrors() && !force {
+			return result, nil
+		}
+		details, err := in.CreateIstioConfigDetail(api, namespace, objectType, restored.Object)
+		if err != nil {
+			return nil, err
+		}
+		result.Applied, result.Details = true, &details
+		return result, nil
+	}
+
+	patch, err := revisionPatch(current, restored.Object)
+	if err != nil {
+		return nil, err
+	}
+	if result.Validations, err = in.DryRunUpdateIstioConfigDetail(namespace, objectType, name, patch); err != nil {
+		return nil, err
+	}
+	if result.Validations.IntroducesErrors() && !force {
+		return result, nil
+	}
+	details, err := in.modifyIstioConfigDetail(api, namespace, objectType, name, patch, false)
+	if err != nil {
+		return nil, err
+	}
+	saveIstioConfigRevision(namespace, objectType, name, models.RevisionRestore, current, nil)
+	result.Applied, result.Details = true, &details
+	return result, nil
+}
@@ -0,0 +1,147 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+// brokenVirtualService routes to a missing host
+func brokenVirtualService() kubernetes.IstioObject {
+	vs := data.CreateEmptyVirtualService("product-vs", "test", []string{"product"})
+	vs.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"route": []interface{}{
+				map[string]interface{}{"destination": map[string]interface{}{"host": "missing"}},
+			},
+		},
+	}
+	return vs
+}
+
+func TestIstioConfigRevisions(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+	istioConfigRevisions = map[istioConfigRevisionKey]*istioConfigRevisionHistory{}
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	fixed := fakeCombinedIstioDetails().VirtualServices[0]
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-vs").Return(brokenVirtualService(), nil).Once()
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-vs").Return(fixed, nil)
+	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "product-vs", mock.AnythingOfType("string")).Return(fixed, nil)
+	configService := vs.businessLayer.IstioConfig
+
+	// the broken virtual service is fixed, the broken state is kept
+	_, err := configService.UpdateIstioConfigDetail("networking.istio.io", "test", "virtualservices", "product-vs", "{}")
+	require.NoError(err)
+	revisions, err := configService.GetIstioConfigRevisions("test", "virtualservices", "product-vs")
+	require.NoError(err)
+	require.Len(revisions, 1)
+	assert.Equal(1, revisions[0].Revision)
+	assert.Equal(models.RevisionUpdate, revisions[0].Operation)
+	assert.JSONEq(`{
+		"metadata": {"name": "product-vs", "namespace": "test"},
+		"spec": {"hosts": ["product"], "http": [{"route": [{"destination": {"host": "missing"}}]}]}
+	}`, string(revisions[0].Object))
+
+	diff, err := configService.DiffIstioConfigRevisions("test", "virtualservices", "product-vs", 1, 0)
+	require.NoError(err)
+	assert.Equal(0, diff.To)
+	assert.Contains(diff.Fields, models.FieldChange{Field: "spec.http[0].route[0].destination.host", From: "missing", To: "product"})
+	assert.Contains(diff.Fields, models.FieldChange{Field: "spec.tcp[0].route[0].destination.host", To: "product"})
+
+	_, err = configService.DiffIstioConfigRevisions("test", "virtualservices", "product-vs", 2, 0)
+	assert.True(errors2.IsNotFound(err))
+
+	// restoring the broken state introduces an error, it is only applied when forced
+	restore, err := configService.RestoreIstioConfigRevision("networking.istio.io", "test", "virtualservices", "product-vs", 1, false)
+	require.NoError(err)
+	assert.False(restore.Applied)
+	assert.Nil(restore.Details)
+	assert.Contains(validationChanges(restore.Validations.Introduced), "virtualservice/product-vs KIA1101 error")
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 1)
+
+	restore, err = configService.RestoreIstioConfigRevision("networking.istio.io", "test", "virtualservices", "product-vs", 1, true)
+	require.NoError(err)
+	assert.True(restore.Applied)
+	require.NotNil(restore.Details)
+	k8s.AssertNumberOfCalls(t, "UpdateIstioObject", 2)
+	patch := k8s.Calls[len(k8s.Calls)-1].Arguments.String(4)
+	assert.JSONEq(`{"spec": {"http": [{"route": [{"destination": {"host": "missing"}}]}], "tcp": null}}`, patch)
+
+	// the restore is a revision too
+	revisions, err = configService.GetIstioConfigRevisions("test", "virtualservices", "product-vs")
+	require.NoError(err)
+	require.Len(revisions, 2)
+	assert.Equal(2, revisions[1].Revision)
+	assert.Equal(models.RevisionRestore, revisions[1].Operation)
+}
+
+func TestRestoreDeletedIstioConfigRevision(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+	istioConfigRevisions = map[istioConfigRevisionKey]*istioConfigRevisionHistory{}
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	fixed := fakeCombinedIstioDetails().VirtualServices[0]
+	notFound := errors2.NewNotFound(schema.GroupResource{Resource: "virtualservices"}, "product-vs")
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-vs").Return(fixed, nil).Once()
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-vs").Return((*kubernetes.GenericIstioObject)(nil), notFound)
+	k8s.On("DeleteIstioObject", "networking.istio.io", "test", "virtualservices", "product-vs").Return(nil)
+	k8s.On("CreateIstioObject", "networking.istio.io", "test", "virtualservices", mock.AnythingOfType("string")).Return(fixed, nil)
+	configService := vs.businessLayer.IstioConfig
+
+	require.NoError(configService.DeleteIstioConfigDetail("networking.istio.io", "test", "virtualservices", "product-vs"))
+
+	diff, err := configService.DiffIstioConfigRevisions("test", "virtualservices", "product-vs", 1, 0)
+	require.NoError(err)
+	assert.Contains(diff.Fields, models.FieldChange{Field: "metadata.name", From: "product-vs"})
+
+	restore, err := configService.RestoreIstioConfigRevision("networking.istio.io", "test", "virtualservices", "product-vs", 1, false)
+	require.NoError(err)
+	assert.True(restore.Applied)
+
+	created := map[string]interface{}{}
+	require.NoError(json.Unmarshal([]byte(k8s.Calls[len(k8s.Calls)-1].Arguments.String(3)), &created))
+	assert.Equal("VirtualService", created["kind"])
+	assert.Equal("product-vs", created["metadata"].(map[string]interface{})["name"])
+}
+
+func TestIstioConfigRevisionsBound(t *testing.T) {
+	assert := assert.New(t)
+	istioConfigRevisions = map[istioConfigRevisionKey]*istioConfigRevisionHistory{}
+	defer func() { istioConfigRevisions = map[istioConfigRevisionKey]*istioConfigRevisionHistory{} }()
+
+	object := json.RawMessage(`{"spec":{}}`)
+	saveIstioConfigRevision("test", "virtualservices", "vs-0", models.RevisionUpdate, object, nil)
+	for i := 1; i < maxIstioConfigRevisionObjects; i++ {
+		saveIstioConfigRevision("test", "virtualservices", fmt.Sprintf("vs-%d", i), models.RevisionUpdate, object, nil)
+	}
+	// vs-0 is saved again, vs-1 is now the least recently saved
+	saveIstioConfigRevision("test", "virtualservices", "vs-0", models.RevisionUpdate, object, nil)
+	assert.Len(istioConfigRevisions, maxIstioConfigRevisionObjects)
+
+	saveIstioConfigRevision("test", "virtualservices", "vs-new", models.RevisionUpdate, object, nil)
+	assert.Len(istioConfigRevisions, maxIstioConfigRevisionObjects)
+	assert.Contains(istioConfigRevisions, istioConfigRevisionKey{namespace: "test", objectType: "virtualservices", name: "vs-0"})
+	assert.Contains(istioConfigRevisions, istioConfigRevisionKey{namespace: "test", objectType: "virtualservices", name: "vs-new"})
+	assert.NotContains(istioConfigRevisions, istioConfigRevisionKey{namespace: "test", objectType: "virtualservices", name: "vs-1"})
+}
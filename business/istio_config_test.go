@@ -661,6 +661,8 @@ func TestDeleteIstioConfigDetails(t *testing.T) {
 
 func mockDeleteIstioConfigDetails() IstioConfigService {
 	k8s := new(kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "test", "virtualservices", "reviews-to-delete").Return(&kubernetes.GenericIstioObject{}, nil)
+	k8s.On("GetIstioObject", "test", "templates", "listchecker-to-delete").Return(&kubernetes.GenericIstioObject{}, nil)
 	k8s.On("DeleteIstioObject", "networking.istio.io", "test", "virtualservices", "reviews-to-delete").Return(nil)
 	k8s.On("DeleteIstioObject", "config.istio.io", "test", "templates", "listchecker-to-delete").Return(nil)
 	return IstioConfigService{k8s: k8s}
@@ -693,6 +695,8 @@ func mockUpdateIstioConfigDetails() IstioConfigService {
 			Namespace: "test",
 		},
 	}
+	k8s.On("GetIstioObject", "test", "virtualservices", "reviews-to-update").Return(updatedVirtualService, nil)
+	k8s.On("GetIstioObject", "test", "templates", "listchecker-to-update").Return(updatedTemplate, nil)
 	k8s.On("UpdateIstioObject", "networking.istio.io", "test", "virtualservices", "reviews-to-update", mock.AnythingOfType("string")).Return(updatedVirtualService, nil)
 	k8s.On("UpdateIstioObject", "config.istio.io", "test", "templates", "listchecker-to-update", mock.AnythingOfType("string")).Return(updatedTemplate, nil)
 	return IstioConfigService{k8s: k8s}
@@ -9,6 +9,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/policies"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/kubernetes"
 	"github.com/kiali/kiali/log"
@@ -95,7 +96,7 @@ func (in *IstioValidationsService) GetValidations(namespace, service string) (mo
 		}
 	}
 
-	objectCheckers := in.getAllObjectCheckers(namespace, istioDetails, exportedResources, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus)
+	objectCheckers := getAllObjectCheckers(namespace, istioDetails, exportedResources, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus, in.getValidationPolicies())
 
 	if service != "" {
 		objectCheckers = append(objectCheckers, in.getServiceCheckers(namespace, services, deployments, pods)...)
@@ -116,7 +117,7 @@ func (in *IstioValidationsService) getServiceCheckers(namespace string, services
 	}
 }
 
-func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, exportedResources kubernetes.ExportedResources, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryStatus []*kubernetes.RegistryStatus) []ObjectChecker {
+func getAllObjectCheckers(namespace string, istioDetails kubernetes.IstioDetails, exportedResources kubernetes.ExportedResources, services []core_v1.Service, workloadsPerNamespace map[string]models.WorkloadList, workloads models.WorkloadList, gatewaysPerNamespace [][]kubernetes.IstioObject, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails, namespaces []models.Namespace, registryStatus []*kubernetes.RegistryStatus, validationPolicies []*policies.Policy) []ObjectChecker {
 	return []ObjectChecker{
 		checkers.NoServiceChecker{Namespace: namespace, Namespaces: namespaces, IstioDetails: &istioDetails, Services: services, WorkloadList: workloads, GatewaysPerNamespace: gatewaysPerNamespace, AuthorizationDetails: &rbacDetails, RegistryStatus: registryStatus},
 		checkers.VirtualServiceChecker{Namespace: namespace, Namespaces: namespaces, DestinationRules: istioDetails.DestinationRules, VirtualServices: istioDetails.VirtualServices, ExportedDestinationRules: exportedResources.DestinationRules, ExportedVirtualServices: exportedResources.VirtualServices},
@@ -127,9 +128,51 @@ func (in *IstioValidationsService) getAllObjectCheckers(namespace string, istioD
 		checkers.AuthorizationPolicyChecker{AuthorizationPolicies: rbacDetails.AuthorizationPolicies, Namespace: namespace, Namespaces: namespaces, Services: services, ServiceEntries: istioDetails.ServiceEntries, WorkloadList: workloads, MtlsDetails: mtlsDetails, VirtualServices: istioDetails.VirtualServices, RegistryStatus: registryStatus},
 		checkers.SidecarChecker{Sidecars: istioDetails.Sidecars, Namespaces: namespaces, WorkloadList: workloads, Services: services, ServiceEntries: istioDetails.ServiceEntries},
 		checkers.RequestAuthenticationChecker{RequestAuthentications: istioDetails.RequestAuthentications, WorkloadList: workloads},
+		checkers.PolicyChecker{IstioObjects: policyCheckedObjects(istioDetails, mtlsDetails, rbacDetails), Namespaces: namespaces, Policies: validationPolicies},
 	}
 }
 
+// policyCheckedObjects returns the namespace Istio objects validated by the user-defined policies, by object type
+func policyCheckedObjects(istioDetails kubernetes.IstioDetails, mtlsDetails kubernetes.MTLSDetails, rbacDetails kubernetes.RBACDetails) map[string][]kubernetes.IstioObject {
+	return map[string][]kubernetes.IstioObject{
+		checkers.AuthorizationPolicyCheckerType:   rbacDetails.AuthorizationPolicies,
+		checkers.DestinationRuleCheckerType:       istioDetails.DestinationRules,
+		checkers.GatewayCheckerType:               istioDetails.Gateways,
+		checkers.PeerAuthenticationCheckerType:    mtlsDetails.PeerAuthentications,
+		checkers.RequestAuthenticationCheckerType: istioDetails.RequestAuthentications,
+		checkers.ServiceEntryCheckerType:          istioDetails.ServiceEntries,
+		checkers.SidecarCheckerType:               istioDetails.Sidecars,
+		checkers.VirtualCheckerType:               istioDetails.VirtualServices,
+	}
+}
+
+// validationPoliciesCache caches the compiled validation policies between the validations
+var validationPoliciesCache policies.Cache
+
+// getValidationPolicies returns the user-defined validation policies of the config and, if configured, of the
+// policies ConfigMap in the Kiali deployment namespace. A ConfigMap that can't be fetched is logged and skipped.
+// The policies are only compiled again when the config or the ConfigMap change.
+func (in *IstioValidationsService) getValidationPolicies() []*policies.Policy {
+	cfg := config.Get()
+	policiesConfig := cfg.KialiFeatureFlags.Validations.Policies
+
+	var configMap *core_v1.ConfigMap
+	if policiesConfig.ConfigMapName != "" {
+		var err error
+		if IsNamespaceCached(cfg.Deployment.Namespace) {
+			configMap, err = kialiCache.GetConfigMap(cfg.Deployment.Namespace, policiesConfig.ConfigMapName)
+		} else {
+			configMap, err = in.k8s.GetConfigMap(cfg.Deployment.Namespace, policiesConfig.ConfigMapName)
+		}
+		if err != nil {
+			log.Warningf("Unable to fetch validation policies ConfigMap [%s/%s]: %v", cfg.Deployment.Namespace, policiesConfig.ConfigMapName, err)
+			configMap = nil
+		}
+	}
+
+	return validationPoliciesCache.Load(policiesConfig, configMap)
+}
+
 // GetIstioObjectValidations validates a single Istio object of the given type with the given name found in the given namespace.
 func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, objectType string, object string) (models.IstioValidations, error) {
 	var istioDetails kubernetes.IstioDetails
@@ -226,7 +269,149 @@ func (in *IstioValidationsService) GetIstioObjectValidations(namespace string, o
 		return models.IstioValidations{}, err
 	}
 
-	return runObjectCheckers(objectCheckers).FilterByKey(models.ObjectTypeSingular[objectType], object), nil
+	singularType := models.ObjectTypeSingular[objectType]
+	objectCheckers = append(objectCheckers, checkers.PolicyChecker{
+		IstioObjects: map[string][]kubernetes.IstioObject{
+			singularType: policyCheckedObjects(istioDetails, mtlsDetails, rbacDetails)[singularType],
+		},
+		Namespaces: namespaces,
+		Policies:   in.getValidationPolicies(),
+	})
+
+	return runObjectCheckers(objectCheckers).FilterByKey(singularType, object), nil
+}
+
+// ValidateIstioObjectChange validates, without applying it, the change of an Istio object of the given type in
+// the given namespace: the proposed object replaces the current one, with the same name, or it is added to the
+// namespace Istio objects. It returns the validation of the proposed object and the checks that the change
+// introduces or resolves, on the object or on the objects related to it.
+func (in *IstioValidationsService) ValidateIstioObjectChange(namespace string, objectType string, proposed kubernetes.IstioObject) (models.IstioValidationsDryRun, error) {
+	dryRun, err := in.ValidateIstioObjectsChange(namespace, []string{objectType}, []kubernetes.IstioObject{proposed})
+	if err != nil {
+		return models.IstioValidationsDryRun{}, err
+	}
+	return models.IstioValidationsDryRun{
+		Validation: dryRun.Validations[0],
+		Introduced: dryRun.Introduced,
+		Resolved:   dryRun.Resolved,
+	}, nil
+}
+
+// ValidateIstioObjectsChange validates, without applying it, the change of a set of Istio objects in the given
+// namespace, objectTypes holding the type of each object. The objects are validated together: each proposed object
+// replaces the current one, with the same name, or it is added to the namespace Istio objects. It returns the
+// validation of each proposed object and the checks that the change introduces or resolves.
+func (in *IstioValidationsService) ValidateIstioObjectsChange(namespace string, objectTypes []string, proposed []kubernetes.IstioObject) (models.IstioValidationsBulkDryRun, error) {
+	var istioDetails kubernetes.IstioDetails
+	var exportedResources kubernetes.ExportedResources
+	var namespaces models.Namespaces
+	var services []core_v1.Service
+	var workloads models.WorkloadList
+	var workloadsPerNamespace map[string]models.WorkloadList
+	var gatewaysPerNamespace [][]kubernetes.IstioObject
+	var mtlsDetails kubernetes.MTLSDetails
+	var rbacDetails kubernetes.RBACDetails
+	var registryStatus []*kubernetes.RegistryStatus
+
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return models.IstioValidationsBulkDryRun{}, err
+	}
+
+	wg := sync.WaitGroup{}
+	errChan := make(chan error, 1)
+
+	wg.Add(10)
+	go in.fetchNamespaces(&namespaces, errChan, &wg)
+	go in.fetchDetails(&istioDetails, namespace, errChan, &wg)
+	go in.fetchExportedResources(&exportedResources, namespace, errChan, &wg)
+	go in.fetchServices(&services, namespace, errChan, &wg)
+	go in.fetchWorkloads(&workloads, namespace, errChan, &wg)
+	go in.fetchAllWorkloads(&workloadsPerNamespace, errChan, &wg)
+	go in.fetchGatewaysPerNamespace(&gatewaysPerNamespace, errChan, &wg)
+	go in.fetchNonLocalmTLSConfigs(&mtlsDetails, namespace, errChan, &wg)
+	go in.fetchAuthorizationDetails(&rbacDetails, namespace, errChan, &wg)
+	go in.fetchRegistryStatus(&registryStatus, errChan, &wg)
+	wg.Wait()
+
+	close(errChan)
+	for e := range errChan {
+		if e != nil { // Check that default value wasn't returned
+			return models.IstioValidationsBulkDryRun{}, e
+		}
+	}
+
+	validationPolicies := in.getValidationPolicies()
+	current := runObjectCheckers(getAllObjectCheckers(namespace, istioDetails, exportedResources, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus, validationPolicies))
+
+	// the fetched slices are shared with the cache, the proposed objects are set in copies
+	for i, object := range proposed {
+		switch objectTypes[i] {
+		case kubernetes.AuthorizationPolicies:
+			rbacDetails.AuthorizationPolicies = replaceIstioObject(rbacDetails.AuthorizationPolicies, object)
+		case kubernetes.DestinationRules:
+			istioDetails.DestinationRules = replaceIstioObject(istioDetails.DestinationRules, object)
+			mtlsDetails.DestinationRules = replaceIstioObject(mtlsDetails.DestinationRules, object)
+		case kubernetes.Gateways:
+			istioDetails.Gateways = replaceIstioObject(istioDetails.Gateways, object)
+			proposedGatewaysPerNamespace := make([][]kubernetes.IstioObject, 0, len(gatewaysPerNamespace)+1)
+			for _, gateways := range gatewaysPerNamespace {
+				proposedGatewaysPerNamespace = append(proposedGatewaysPerNamespace, removeIstioObject(gateways, object))
+			}
+			gatewaysPerNamespace = append(proposedGatewaysPerNamespace, []kubernetes.IstioObject{object})
+		case kubernetes.PeerAuthentications:
+			mtlsDetails.PeerAuthentications = replaceIstioObject(mtlsDetails.PeerAuthentications, object)
+			if namespace == config.Get().IstioNamespace {
+				mtlsDetails.MeshPeerAuthentications = replaceIstioObject(mtlsDetails.MeshPeerAuthentications, object)
+			}
+		case kubernetes.RequestAuthentications:
+			istioDetails.RequestAuthentications = replaceIstioObject(istioDetails.RequestAuthentications, object)
+		case kubernetes.ServiceEntries:
+			istioDetails.ServiceEntries = replaceIstioObject(istioDetails.ServiceEntries, object)
+		case kubernetes.Sidecars:
+			istioDetails.Sidecars = replaceIstioObject(istioDetails.Sidecars, object)
+		case kubernetes.VirtualServices:
+			istioDetails.VirtualServices = replaceIstioObject(istioDetails.VirtualServices, object)
+		default:
+			return models.IstioValidationsBulkDryRun{}, fmt.Errorf("object type not found: %v", objectTypes[i])
+		}
+	}
+
+	changed := runObjectCheckers(getAllObjectCheckers(namespace, istioDetails, exportedResources, services, workloadsPerNamespace, workloads, gatewaysPerNamespace, mtlsDetails, rbacDetails, namespaces, registryStatus, validationPolicies))
+
+	validations := make([]*models.IstioValidation, 0, len(proposed))
+	for i, object := range proposed {
+		key, validation := checkers.EmptyValidValidation(object.GetObjectMeta().Name, namespace, models.ObjectTypeSingular[objectTypes[i]])
+		if v, found := changed[key]; found {
+			validation = v
+		}
+		validations = append(validations, validation)
+	}
+
+	return models.IstioValidationsBulkDryRun{
+		Validations: validations,
+		Introduced:  changed.ChecksNotIn(current),
+		Resolved:    current.ChecksNotIn(changed),
+	}, nil
+}
+
+// replaceIstioObject returns a copy of the objects where the object with the same namespace and name is replaced, or
+// appended if not found.
+func replaceIstioObject(objects []kubernetes.IstioObject, object kubernetes.IstioObject) []kubernetes.IstioObject {
+	return append(removeIstioObject(objects, object), object)
+}
+
+// removeIstioObject returns a copy of the objects without the object with the same namespace and name
+func removeIstioObject(objects []kubernetes.IstioObject, object kubernetes.IstioObject) []kubernetes.IstioObject {
+	meta := object.GetObjectMeta()
+	result := make([]kubernetes.IstioObject, 0, len(objects)+1)
+	for _, o := range objects {
+		if o.GetObjectMeta().Name != meta.Name || o.GetObjectMeta().Namespace != meta.Namespace {
+			result = append(result, o)
+		}
+	}
+	return result
 }
 
 func runObjectCheckers(objectCheckers []ObjectChecker) models.IstioValidations {
@@ -547,6 +732,10 @@ func (in *IstioValidationsService) fetchExportedResources(exportedResources *kub
 }
 
 func (in *IstioValidationsService) filterExportToNamespacesIstioObjects(namespace string, currentIstioObjects *[]kubernetes.IstioObject) *[]kubernetes.IstioObject {
+	return filterExportToNamespacesIstioObjects(namespace, currentIstioObjects)
+}
+
+func filterExportToNamespacesIstioObjects(namespace string, currentIstioObjects *[]kubernetes.IstioObject) *[]kubernetes.IstioObject {
 	var result []kubernetes.IstioObject
 	for _, vs := range *currentIstioObjects {
 		if exportToSpec, found := vs.GetSpec()["exportTo"]; found {
@@ -46,6 +46,77 @@ func TestGetIstioObjectValidations(t *testing.T) {
 	assert.NotEmpty(validations)
 }
 
+func TestValidateIstioObjectChange(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+
+	// the route is moved to a missing host: the virtual service host is not found, and the destination rule
+	// subset is no longer referenced, its missing labels are not an error anymore
+	proposed := data.CreateEmptyVirtualService("product-vs", "test", []string{"product"})
+	proposed.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"route": []interface{}{
+				map[string]interface{}{"destination": map[string]interface{}{"host": "missing"}},
+			},
+		},
+	}
+	dryRun, err := vs.ValidateIstioObjectChange("test", "virtualservices", proposed)
+	assert.NoError(err)
+	assert.False(dryRun.Validation.Valid)
+	assert.Equal([]string{
+		"destinationrule/product-dr KIA0203 unknown",
+		"virtualservice/product-vs KIA1101 error",
+	}, validationChanges(dryRun.Introduced))
+	assert.Equal([]string{
+		"destinationrule/product-dr KIA0203 error",
+	}, validationChanges(dryRun.Resolved))
+	assert.Equal([]models.IstioValidationKey{{ObjectType: "destinationrule", Namespace: "test", Name: "product-dr"}}, dryRun.Introduced[0].References)
+
+	// removing the subset without matching workloads, the virtual service subset is still found in the mocked
+	// exported destination rules
+	dryRun, err = vs.ValidateIstioObjectChange("test", "destinationrules", data.CreateEmptyDestinationRule("test", "product-dr", "product"))
+	assert.NoError(err)
+	assert.True(dryRun.Validation.Valid)
+	assert.Empty(dryRun.Introduced)
+	assert.Equal([]string{
+		"destinationrule/product-dr KIA0203 error",
+	}, validationChanges(dryRun.Resolved))
+
+	_, err = vs.ValidateIstioObjectChange("test", "envoyfilters", proposed)
+	assert.Error(err)
+}
+
+func TestDryRunUpdateIstioConfigDetail(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	vs := mockCombinedValidationService(fakeCombinedIstioDetails(), []string{"details", "product", "customer"}, fakePods())
+	k8s := vs.k8s.(*kubetest.K8SClientMock)
+	k8s.On("GetIstioObject", "test", "virtualservices", "product-vs").Return(fakeCombinedIstioDetails().VirtualServices[0], nil)
+
+	patch := `{"spec": {"http": [{"route": [{"destination": {"host": "missing"}}]}], "tcp": null}}`
+	dryRun, err := vs.businessLayer.IstioConfig.DryRunUpdateIstioConfigDetail("test", "virtualservices", "product-vs", patch)
+	assert.NoError(err)
+	assert.Equal("product-vs", dryRun.Validation.Name)
+	assert.False(dryRun.Validation.Valid)
+	assert.Contains(validationChanges(dryRun.Introduced), "virtualservice/product-vs KIA1101 error")
+
+	_, err = vs.businessLayer.IstioConfig.DryRunUpdateIstioConfigDetail("test", "virtualservices", "product-vs", "{")
+	assert.Error(err)
+}
+
+func validationChanges(changes []models.IstioValidationChange) []string {
+	result := make([]string, 0, len(changes))
+	for _, c := range changes {
+		result = append(result, fmt.Sprintf("%s/%s %s %s", c.ObjectType, c.Name, c.Check.Code, c.Check.Severity))
+	}
+	return result
+}
+
 func TestGatewayValidation(t *testing.T) {
 	assert := assert.New(t)
 	conf := config.NewConfig()
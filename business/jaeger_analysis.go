@@ -0,0 +1,376 @@
+package business
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// spanTree indexes the spans of a trace by parent
+type spanTree struct {
+	trace    *jaegerModels.Trace
+	roots    []*jaegerModels.Span
+	children map[jaegerModels.SpanID][]*jaegerModels.Span
+}
+
+func newSpanTree(trace *jaegerModels.Trace) *spanTree {
+	tree := &spanTree{trace: trace, children: map[jaegerModels.SpanID][]*jaegerModels.Span{}}
+	ids := map[jaegerModels.SpanID]bool{}
+	for i := range trace.Spans {
+		ids[trace.Spans[i].SpanID] = true
+	}
+	for i := range trace.Spans {
+		span := &trace.Spans[i]
+		if parent := parentSpanID(span); parent != "" && ids[parent] {
+			tree.children[parent] = append(tree.children[parent], span)
+		} else {
+			tree.roots = append(tree.roots, span)
+		}
+	}
+	byStart := func(spans []*jaegerModels.Span) {
+		sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime < spans[j].StartTime })
+	}
+	byStart(tree.roots)
+	for _, children := range tree.children {
+		byStart(children)
+	}
+	return tree
+}
+
+func parentSpanID(span *jaegerModels.Span) jaegerModels.SpanID {
+	for _, ref := range span.References {
+		if ref.RefType == jaegerModels.ChildOf {
+			return ref.SpanID
+		}
+	}
+	return span.ParentSpanID
+}
+
+func (t *spanTree) service(span *jaegerModels.Span) string {
+	if span.Process != nil {
+		return span.Process.ServiceName
+	}
+	return t.trace.Processes[span.ProcessID].ServiceName
+}
+
+// duration returns the time between the start of the first span and the end of the last one
+func (t *spanTree) duration() uint64 {
+	var start, end uint64
+	for i, span := range t.trace.Spans {
+		if i == 0 || span.StartTime < start {
+			start = span.StartTime
+		}
+		if span.StartTime+span.Duration > end {
+			end = span.StartTime + span.Duration
+		}
+	}
+	return end - start
+}
+
+// selfTime returns the time of a span not covered by any of its children, children running in parallel are
+// counted once
+func (t *spanTree) selfTime(span *jaegerModels.Span) uint64 {
+	start, end := span.StartTime, span.StartTime+span.Duration
+	covered := uint64(0)
+	cursor := start
+	// the children are sorted by start time
+	for _, child := range t.children[span.SpanID] {
+		childStart, childEnd := child.StartTime, child.StartTime+child.Duration
+		if childStart < cursor {
+			childStart = cursor
+		}
+		if childEnd > end {
+			childEnd = end
+		}
+		if childEnd > childStart {
+			covered += childEnd - childStart
+			cursor = childEnd
+		}
+	}
+	return span.Duration - covered
+}
+
+// criticalPath adds to times the time each span spends on the critical path of the span, ending at cursor: walking
+// back from the end, the time is spent in the last finishing child, or in the span itself when no child runs.
+func (t *spanTree) criticalPath(span *jaegerModels.Span, cursor uint64, times map[*jaegerModels.Span]uint64) {
+	start := span.StartTime
+	if end := span.StartTime + span.Duration; end < cursor {
+		cursor = end
+	}
+	children := append([]*jaegerModels.Span{}, t.children[span.SpanID]...)
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].StartTime+children[i].Duration > children[j].StartTime+children[j].Duration
+	})
+	for _, child := range children {
+		if cursor <= start {
+			break
+		}
+		if child.StartTime >= cursor {
+			continue
+		}
+		childEnd := child.StartTime + child.Duration
+		if childEnd > cursor {
+			childEnd = cursor
+		}
+		times[span] += cursor - childEnd
+		t.criticalPath(child, childEnd, times)
+		cursor = child.StartTime
+		if cursor < start {
+			cursor = start
+		}
+	}
+	if cursor > start {
+		times[span] += cursor - start
+	}
+}
+
+func microsToMillis(micros uint64) float64 {
+	return float64(micros) / 1000.0
+}
+
+// GetAppTracesAnalysis returns the latency breakdown of the traces of an app matching the query: the self and
+// child time of each service, the duration percentiles of each span operation and the time of each span operation
+// on the critical path.
+func (in *JaegerService) GetAppTracesAnalysis(ns, app string, query models.TracingQuery) (*models.TracesAnalysis, error) {
+	r, err := in.GetAppTraces(ns, app, query)
+	if err != nil {
+		return nil, err
+	}
+	return analyzeTraces(r.Data), nil
+}
+
+type operationKey struct {
+	service   string
+	operation string
+}
+
+func analyzeTraces(traces []jaegerModels.Trace) *models.TracesAnalysis {
+	services := map[string]*models.ServiceLatency{}
+	durations := map[operationKey][]uint64{}
+	critical := map[operationKey]uint64{}
+	total := uint64(0)
+
+	for i := range traces {
+		tree := newSpanTree(&traces[i])
+		total += tree.duration()
+
+		for j := range traces[i].Spans {
+			span := &traces[i].Spans[j]
+			service := tree.service(span)
+			latency, found := services[service]
+			if !found {
+				latency = &models.ServiceLatency{Service: service}
+				services[service] = latency
+			}
+			self := tree.selfTime(span)
+			latency.Spans++
+			latency.SelfTime += microsToMillis(self)
+			latency.ChildTime += microsToMillis(span.Duration - self)
+
+			key := operationKey{service: service, operation: span.OperationName}
+			durations[key] = append(durations[key], span.Duration)
+		}
+
+		times := map[*jaegerModels.Span]uint64{}
+		for _, root := range tree.roots {
+			tree.criticalPath(root, root.StartTime+root.Duration, times)
+		}
+		for span, time := range times {
+			critical[operationKey{service: tree.service(span), operation: span.OperationName}] += time
+		}
+	}
+
+	analysis := &models.TracesAnalysis{
+		Traces:       len(traces),
+		Services:     []models.ServiceLatency{},
+		Operations:   []models.OperationLatency{},
+		CriticalPath: []models.CriticalPathLatency{},
+	}
+	for _, latency := range services {
+		analysis.Services = append(analysis.Services, *latency)
+	}
+	sort.Slice(analysis.Services, func(i, j int) bool {
+		if analysis.Services[i].SelfTime != analysis.Services[j].SelfTime {
+			return analysis.Services[i].SelfTime > analysis.Services[j].SelfTime
+		}
+		return analysis.Services[i].Service < analysis.Services[j].Service
+	})
+
+	for key, values := range durations {
+		analysis.Operations = append(analysis.Operations, operationLatency(key, values))
+	}
+	sort.Slice(analysis.Operations, func(i, j int) bool {
+		a, b := analysis.Operations[i], analysis.Operations[j]
+		if a.P95 != b.P95 {
+			return a.P95 > b.P95
+		}
+		return a.Service+a.Operation < b.Service+b.Operation
+	})
+
+	for key, time := range critical {
+		latency := models.CriticalPathLatency{Service: key.service, Operation: key.operation, Time: microsToMillis(time)}
+		if total > 0 {
+			latency.Share = float64(time) / float64(total)
+		}
+		analysis.CriticalPath = append(analysis.CriticalPath, latency)
+	}
+	sort.Slice(analysis.CriticalPath, func(i, j int) bool {
+		a, b := analysis.CriticalPath[i], analysis.CriticalPath[j]
+		if a.Time != b.Time {
+			return a.Time > b.Time
+		}
+		return a.Service+a.Operation < b.Service+b.Operation
+	})
+	return analysis
+}
+
+// operationLatency returns the statistics of the span durations, the percentiles use the nearest rank
+func operationLatency(key operationKey, durations []uint64) models.OperationLatency {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	sum := uint64(0)
+	for _, d := range durations {
+		sum += d
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(durations)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		return microsToMillis(durations[rank])
+	}
+	return models.OperationLatency{
+		Service:   key.service,
+		Operation: key.operation,
+		Count:     len(durations),
+		Min:       microsToMillis(durations[0]),
+		Max:       microsToMillis(durations[len(durations)-1]),
+		Mean:      microsToMillis(sum) / float64(len(durations)),
+		P50:       percentile(0.50),
+		P90:       percentile(0.90),
+		P95:       percentile(0.95),
+		P99:       percentile(0.99),
+	}
+}
+
+// CompareTraces compares a trace with a base trace, e.g. a slow trace with a typical one of the same operation.
+// The spans are matched by their service:operation path from the root span, and by order of start among the
+// siblings having the same path.
+func (in *JaegerService) CompareTraces(baseTraceID, traceID string) (*models.TraceDiff, error) {
+	base, err := in.GetJaegerTraceDetail(baseTraceID)
+	if err != nil {
+		return nil, err
+	}
+	if base == nil {
+		return nil, kubernetes.NewNotFound(baseTraceID, "Kiali", "Trace")
+	}
+	trace, err := in.GetJaegerTraceDetail(traceID)
+	if err != nil {
+		return nil, err
+	}
+	if trace == nil {
+		return nil, kubernetes.NewNotFound(traceID, "Kiali", "Trace")
+	}
+	return diffTraces(&base.Data, &trace.Data), nil
+}
+
+// pathSpan is a span with its self time, at a path of a trace
+type pathSpan struct {
+	service   string
+	operation string
+	duration  uint64
+	selfTime  uint64
+}
+
+// spansByPath returns the spans of a trace by path, and the paths in tree order
+func spansByPath(tree *spanTree) (map[string]pathSpan, []string) {
+	spans := map[string]pathSpan{}
+	paths := []string{}
+	var walk func(parentPath string, siblings []*jaegerModels.Span)
+	walk = func(parentPath string, siblings []*jaegerModels.Span) {
+		occurrences := map[string]int{}
+		for _, span := range siblings {
+			label := tree.service(span) + ":" + span.OperationName
+			path := label
+			if parentPath != "" {
+				path = parentPath + " > " + label
+			}
+			if n := occurrences[label]; n > 0 {
+				path = fmt.Sprintf("%s [%d]", path, n+1)
+			}
+			occurrences[label]++
+
+			spans[path] = pathSpan{
+				service:   tree.service(span),
+				operation: span.OperationName,
+				duration:  span.Duration,
+				selfTime:  tree.selfTime(span),
+			}
+			paths = append(paths, path)
+			walk(path, tree.children[span.SpanID])
+		}
+	}
+	walk("", tree.roots)
+	return spans, paths
+}
+
+func diffTraces(base, trace *jaegerModels.Trace) *models.TraceDiff {
+	baseTree, tree := newSpanTree(base), newSpanTree(trace)
+	baseSpans, basePaths := spansByPath(baseTree)
+	spans, paths := spansByPath(tree)
+
+	diff := &models.TraceDiff{
+		BaseTraceID:  string(base.TraceID),
+		TraceID:      string(trace.TraceID),
+		BaseDuration: microsToMillis(baseTree.duration()),
+		Duration:     microsToMillis(tree.duration()),
+		Spans:        []models.SpanDiff{},
+	}
+	diff.DurationDelta = diff.Duration - diff.BaseDuration
+
+	for _, path := range paths {
+		span := spans[path]
+		spanDiff := models.SpanDiff{
+			Path:      path,
+			Service:   span.service,
+			Operation: span.operation,
+			Status:    models.SpanDiffAdded,
+			Duration:  microsToMillis(span.duration),
+			SelfTime:  microsToMillis(span.selfTime),
+		}
+		if baseSpan, found := baseSpans[path]; found {
+			spanDiff.Status = models.SpanDiffMatched
+			spanDiff.BaseDuration = microsToMillis(baseSpan.duration)
+			spanDiff.BaseSelfTime = microsToMillis(baseSpan.selfTime)
+		}
+		spanDiff.DurationDelta = spanDiff.Duration - spanDiff.BaseDuration
+		spanDiff.SelfTimeDelta = spanDiff.SelfTime - spanDiff.BaseSelfTime
+		diff.Spans = append(diff.Spans, spanDiff)
+	}
+	for _, path := range basePaths {
+		if _, found := spans[path]; found {
+			continue
+		}
+		baseSpan := baseSpans[path]
+		diff.Spans = append(diff.Spans, models.SpanDiff{
+			Path:          path,
+			Service:       baseSpan.service,
+			Operation:     baseSpan.operation,
+			Status:        models.SpanDiffRemoved,
+			BaseDuration:  microsToMillis(baseSpan.duration),
+			DurationDelta: -microsToMillis(baseSpan.duration),
+			BaseSelfTime:  microsToMillis(baseSpan.selfTime),
+			SelfTimeDelta: -microsToMillis(baseSpan.selfTime),
+		})
+	}
+
+	// stable, the spans with the same delta keep the tree order
+	sort.SliceStable(diff.Spans, func(i, j int) bool {
+		return math.Abs(diff.Spans[i].SelfTimeDelta) > math.Abs(diff.Spans[j].SelfTimeDelta)
+	})
+	return diff
+}
@@ -0,0 +1,161 @@
+package business
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/models"
+)
+
+// analysisSpan returns a span starting and ending at the given milliseconds
+func analysisSpan(id, parent jaegerModels.SpanID, process jaegerModels.ProcessID, operation string, start, end uint64) jaegerModels.Span {
+	span := jaegerModels.Span{
+		SpanID:        id,
+		ProcessID:     process,
+		OperationName: operation,
+		StartTime:     1000000 + start*1000,
+		Duration:      (end - start) * 1000,
+	}
+	if parent != "" {
+		span.References = []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, SpanID: parent}}
+	}
+	return span
+}
+
+var analysisProcesses = map[jaegerModels.ProcessID]jaegerModels.Process{
+	"p1": {ServiceName: "productpage.bookinfo"},
+	"p2": {ServiceName: "details.bookinfo"},
+	"p3": {ServiceName: "reviews.bookinfo"},
+	"p4": {ServiceName: "ratings.bookinfo"},
+}
+
+// productpage calls details and reviews in parallel, reviews calls ratings
+func typicalTrace() jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: "typical",
+		Spans: []jaegerModels.Span{
+			analysisSpan("s1", "", "p1", "GET /productpage", 0, 100),
+			analysisSpan("s2", "s1", "p2", "GET /details", 10, 30),
+			analysisSpan("s3", "s1", "p3", "GET /reviews", 20, 90),
+			analysisSpan("s4", "s3", "p4", "GET /ratings", 40, 60),
+		},
+		Processes: analysisProcesses,
+	}
+}
+
+// ratings is 60ms slower, and details is not called
+func slowTrace() jaegerModels.Trace {
+	return jaegerModels.Trace{
+		TraceID: "slow",
+		Spans: []jaegerModels.Span{
+			analysisSpan("s1", "", "p1", "GET /productpage", 0, 160),
+			analysisSpan("s3", "s1", "p3", "GET /reviews", 20, 150),
+			analysisSpan("s4", "s3", "p4", "GET /ratings", 40, 120),
+		},
+		Processes: analysisProcesses,
+	}
+}
+
+func TestAnalyzeTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	analysis := analyzeTraces([]jaegerModels.Trace{typicalTrace()})
+
+	assert.Equal(1, analysis.Traces)
+	// the parallel children of productpage cover 10ms to 90ms
+	assert.Equal([]models.ServiceLatency{
+		{Service: "reviews.bookinfo", Spans: 1, SelfTime: 50, ChildTime: 20},
+		{Service: "details.bookinfo", Spans: 1, SelfTime: 20, ChildTime: 0},
+		{Service: "productpage.bookinfo", Spans: 1, SelfTime: 20, ChildTime: 80},
+		{Service: "ratings.bookinfo", Spans: 1, SelfTime: 20, ChildTime: 0},
+	}, analysis.Services)
+
+	// details runs in parallel of reviews, only its first 10ms are on the critical path
+	require.Len(t, analysis.CriticalPath, 4)
+	assert.Equal(models.CriticalPathLatency{Service: "reviews.bookinfo", Operation: "GET /reviews", Time: 50, Share: 0.5}, analysis.CriticalPath[0])
+	assert.Equal(models.CriticalPathLatency{Service: "details.bookinfo", Operation: "GET /details", Time: 10, Share: 0.1}, analysis.CriticalPath[3])
+
+	require.Len(t, analysis.Operations, 4)
+	assert.Equal("GET /productpage", analysis.Operations[0].Operation)
+}
+
+func TestOperationLatency(t *testing.T) {
+	assert := assert.New(t)
+
+	durations := []uint64{}
+	for i := uint64(100); i > 0; i-- {
+		durations = append(durations, i*1000)
+	}
+	latency := operationLatency(operationKey{service: "reviews.bookinfo", operation: "GET /reviews"}, durations)
+
+	assert.Equal(models.OperationLatency{
+		Service:   "reviews.bookinfo",
+		Operation: "GET /reviews",
+		Count:     100,
+		Min:       1,
+		Max:       100,
+		Mean:      50.5,
+		P50:       50,
+		P90:       90,
+		P95:       95,
+		P99:       99,
+	}, latency)
+}
+
+func TestDiffTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	typical, slow := typicalTrace(), slowTrace()
+	diff := diffTraces(&typical, &slow)
+
+	assert.Equal("typical", diff.BaseTraceID)
+	assert.Equal("slow", diff.TraceID)
+	assert.Equal(60.0, diff.DurationDelta)
+	require.Len(t, diff.Spans, 4)
+
+	// the extra latency comes from ratings
+	assert.Equal(models.SpanDiff{
+		Path:          "productpage.bookinfo:GET /productpage > reviews.bookinfo:GET /reviews > ratings.bookinfo:GET /ratings",
+		Service:       "ratings.bookinfo",
+		Operation:     "GET /ratings",
+		Status:        models.SpanDiffMatched,
+		BaseDuration:  20,
+		Duration:      80,
+		DurationDelta: 60,
+		BaseSelfTime:  20,
+		SelfTime:      80,
+		SelfTimeDelta: 60,
+	}, diff.Spans[0])
+	assert.Equal(models.SpanDiffRemoved, diff.Spans[1].Status)
+	assert.Equal("details.bookinfo", diff.Spans[1].Service)
+	assert.Equal(-20.0, diff.Spans[1].SelfTimeDelta)
+	assert.Equal("productpage.bookinfo", diff.Spans[2].Service)
+	assert.Equal(10.0, diff.Spans[2].SelfTimeDelta)
+	assert.Equal(0.0, diff.Spans[3].SelfTimeDelta)
+}
+
+func TestSpansByPathSiblings(t *testing.T) {
+	assert := assert.New(t)
+
+	trace := jaegerModels.Trace{
+		Spans: []jaegerModels.Span{
+			analysisSpan("s1", "", "p1", "GET /productpage", 0, 100),
+			analysisSpan("s3", "s1", "p3", "GET /reviews", 50, 90),
+			analysisSpan("s2", "s1", "p3", "GET /reviews", 10, 40),
+		},
+		Processes: analysisProcesses,
+	}
+	spans, paths := spansByPath(newSpanTree(&trace))
+
+	assert.Equal([]string{
+		"productpage.bookinfo:GET /productpage",
+		"productpage.bookinfo:GET /productpage > reviews.bookinfo:GET /reviews",
+		"productpage.bookinfo:GET /productpage > reviews.bookinfo:GET /reviews [2]",
+	}, paths)
+	// the siblings are ordered by start time
+	assert.Equal(uint64(30000), spans[paths[1]].duration)
+	assert.Equal(uint64(40000), spans[paths[2]].duration)
+}
@@ -16,6 +16,7 @@ import (
 // Layer is a container for fast access to inner services
 type Layer struct {
 	App            AppService
+	Canary         CanaryService
 	Health         HealthService
 	IstioConfig    IstioConfigService
 	IstioStatus    IstioStatusService
@@ -27,9 +28,11 @@ type Layer struct {
 	OpenshiftOAuth OpenshiftOAuthService
 	ProxyStatus    ProxyStatusService
 	RegistryStatus RegistryStatusService
+	SLO            SLOService
 	Svc            SvcService
 	TLS            TLSService
 	TokenReview    TokenReviewService
+	TrafficRouting TrafficRoutingService
 	Validations    IstioValidationsService
 	Workload       WorkloadService
 }
@@ -98,9 +101,9 @@ func Get(authInfo *api.AuthInfo) (*Layer, error) {
 		prometheusClient = prom
 	}
 
-	// Create Jaeger client
+	// Create the tracing client, Jaeger or Tempo
 	jaegerLoader := func() (jaeger.ClientInterface, error) {
-		return jaeger.NewClient(authInfo.Token)
+		return jaeger.NewTracingClient(authInfo.Token)
 	}
 
 	return NewWithBackends(k8s, prometheusClient, jaegerLoader), nil
@@ -117,6 +120,7 @@ func SetWithBackends(cf kubernetes.ClientFactory, prom prometheus.ClientInterfac
 func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInterface, jaegerClient JaegerLoader) *Layer {
 	temporaryLayer := &Layer{}
 	temporaryLayer.App = AppService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.Canary = CanaryService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Health = HealthService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioConfig = IstioConfigService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.IstioStatus = IstioStatusService{k8s: k8s, businessLayer: temporaryLayer}
@@ -128,9 +132,11 @@ func NewWithBackends(k8s kubernetes.ClientInterface, prom prometheus.ClientInter
 	temporaryLayer.OpenshiftOAuth = OpenshiftOAuthService{k8s: k8s}
 	temporaryLayer.ProxyStatus = ProxyStatusService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.RegistryStatus = RegistryStatusService{k8s: k8s, businessLayer: temporaryLayer}
+	temporaryLayer.SLO = SLOService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Svc = SvcService{prom: prom, k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.TLS = TLSService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.TokenReview = NewTokenReview(k8s)
+	temporaryLayer.TrafficRouting = TrafficRoutingService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Validations = IstioValidationsService{k8s: k8s, businessLayer: temporaryLayer}
 	temporaryLayer.Workload = WorkloadService{k8s: k8s, prom: prom, businessLayer: temporaryLayer}
 
@@ -1,12 +1,14 @@
 package business
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/models"
@@ -468,3 +470,96 @@ func createSample(value float64) *model.Sample {
 		Metric:    model.Metric{},
 	}
 }
+
+func TestGetMetricsExemplars(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	prom := new(prometheustest.PromClientMock)
+	srv := NewMetricsService(prom)
+
+	q := models.IstioMetricsQuery{
+		Namespace: "bookinfo",
+		App:       "productpage",
+	}
+	q.FillDefaults()
+	q.Filters = []string{"request_duration_millis"}
+	q.ByLabels = []string{"destination_canonical_service"}
+	q.Exemplars = true
+
+	histo := prometheus.Histogram{
+		"avg": prometheus.Metric{Matrix: model.Matrix{
+			{Metric: model.Metric{"destination_canonical_service": "reviews"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 25}}},
+			{Metric: model.Metric{"destination_canonical_service": "details"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 5}}},
+		}},
+		"0.99": prometheus.Metric{Matrix: model.Matrix{
+			{Metric: model.Metric{"destination_canonical_service": "reviews"}, Values: []model.SamplePair{{Timestamp: 1000, Value: 45}}},
+		}},
+	}
+	exemplars := []prometheus.ExemplarSeries{{
+		SeriesLabels: model.LabelSet{"destination_canonical_service": "reviews", "le": "50"},
+		Exemplars: []prometheus.Exemplar{
+			{Labels: model.LabelSet{"trace_id": "t2", "span_id": "s2"}, Value: 42, Timestamp: 2000},
+			{Labels: model.LabelSet{"trace_id": "t1"}, Value: 30, Timestamp: 1000},
+			// no trace, skipped
+			{Labels: model.LabelSet{}, Value: 12, Timestamp: 1500},
+		},
+	}}
+	prom.On("FetchHistogramRange", "istio_request_duration_milliseconds", mock.AnythingOfType("string"), "destination_canonical_service", &q.RangeQuery).Return(histo)
+	prom.On("FetchExemplars", "istio_request_duration_milliseconds", mock.AnythingOfType("string"), &q.RangeQuery).Return(exemplars, nil)
+
+	metrics, err := srv.GetMetrics(q, func(string) float64 { return 0.001 })
+
+	assert.Nil(err)
+	durations := metrics["request_duration_millis"]
+	assert.Len(durations, 3)
+	// the exemplars are only attached to the avg series
+	assert.Equal("0.99", durations[0].Stat)
+	assert.Empty(durations[0].Exemplars)
+	assert.Equal("avg", durations[1].Stat)
+	assert.Equal([]models.Exemplar{
+		{Timestamp: 1000, Value: 0.03, TraceID: "t1"},
+		{Timestamp: 2000, Value: 0.042, TraceID: "t2", Labels: map[string]string{"span_id": "s2"}},
+	}, durations[1].Exemplars)
+	assert.Empty(durations[2].Exemplars)
+
+	// the exemplar timestamps are in seconds, as the datapoints
+	metric, err := json.Marshal(durations[1])
+	assert.Nil(err)
+	assert.JSONEq(`{
+		"labels": {"destination_canonical_service": "reviews"},
+		"datapoints": [[1, "0.025"]],
+		"exemplars": [
+			{"timestamp": 1, "value": 0.03, "traceId": "t1"},
+			{"timestamp": 2, "value": 0.042, "traceId": "t2", "labels": {"span_id": "s2"}}
+		],
+		"stat": "avg",
+		"name": "request_duration_millis"
+	}`, string(metric))
+	var decoded struct {
+		Exemplars []models.Exemplar `json:"exemplars"`
+	}
+	assert.Nil(json.Unmarshal(metric, &decoded))
+	assert.Equal(durations[1].Exemplars, decoded.Exemplars)
+}
+
+func TestGetMetricsExemplarsUnavailable(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+	prom := new(prometheustest.PromClientMock)
+	srv := NewMetricsService(prom)
+
+	q := models.IstioMetricsQuery{Namespace: "bookinfo", App: "productpage"}
+	q.FillDefaults()
+	q.Filters = []string{"request_duration_millis"}
+	q.Exemplars = true
+
+	prom.MockHistogram("istio_request_duration_milliseconds", `{reporter="source",source_workload_namespace="bookinfo",source_canonical_service="productpage"}`, &q.RangeQuery, 10, 20)
+	prom.On("FetchExemplars", "istio_request_duration_milliseconds", mock.AnythingOfType("string"), &q.RangeQuery).Return([]prometheus.ExemplarSeries(nil), fmt.Errorf("exemplar storage disabled"))
+
+	// the metrics are still returned
+	metrics, err := srv.GetMetrics(q, nil)
+
+	assert.Nil(err)
+	assert.Len(metrics["request_duration_millis"], 2)
+	assert.Empty(metrics["request_duration_millis"][0].Exemplars)
+}
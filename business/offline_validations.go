@@ -0,0 +1,226 @@
+package business
+
+import (
+	"sort"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/business/checkers"
+	"github.com/kiali/kiali/business/checkers/policies"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// ValidateManifests runs all the object checkers against the objects of the manifests, without a cluster, e.g. to
+// validate the Istio config of a GitOps repository before it is applied. The checker inputs are built as the
+// IstioValidationsService builds them for each namespace, with the manifests as the only source of objects:
+//   - the registry status holds the manifests services
+//   - the mesh config is read from the Istio ConfigMap, if found in the manifests, otherwise defaults apply
+//   - the validation policies are read from the config and the policies ConfigMap, if found in the manifests
+func ValidateManifests(manifests kubernetes.Manifests) models.IstioValidations {
+	cfg := config.Get()
+
+	namespaces := manifestsNamespaces(manifests)
+	workloadsPerNamespace := manifestsWorkloads(manifests, namespaces)
+
+	istioDetailsPerNamespace := map[string]*kubernetes.IstioDetails{}
+	rbacDetailsPerNamespace := map[string]*kubernetes.RBACDetails{}
+	peerAuthnsPerNamespace := map[string][]kubernetes.IstioObject{}
+	var allDestinationRules []kubernetes.IstioObject
+	for _, ns := range namespaces {
+		istioDetailsPerNamespace[ns.Name] = &kubernetes.IstioDetails{}
+		rbacDetailsPerNamespace[ns.Name] = &kubernetes.RBACDetails{}
+	}
+	for _, object := range manifests.IstioObjects {
+		ns := object.GetObjectMeta().Namespace
+		istioDetails := istioDetailsPerNamespace[ns]
+		switch object.GetTypeMeta().Kind {
+		case kubernetes.AuthorizationPoliciesType:
+			rbacDetailsPerNamespace[ns].AuthorizationPolicies = append(rbacDetailsPerNamespace[ns].AuthorizationPolicies, object)
+		case kubernetes.DestinationRuleType:
+			istioDetails.DestinationRules = append(istioDetails.DestinationRules, object)
+			allDestinationRules = append(allDestinationRules, object)
+		case kubernetes.GatewayType:
+			istioDetails.Gateways = append(istioDetails.Gateways, object)
+		case kubernetes.PeerAuthenticationsType:
+			peerAuthnsPerNamespace[ns] = append(peerAuthnsPerNamespace[ns], object)
+		case kubernetes.RequestAuthenticationsType:
+			istioDetails.RequestAuthentications = append(istioDetails.RequestAuthentications, object)
+		case kubernetes.ServiceEntryType:
+			istioDetails.ServiceEntries = append(istioDetails.ServiceEntries, object)
+		case kubernetes.SidecarType:
+			istioDetails.Sidecars = append(istioDetails.Sidecars, object)
+		case kubernetes.VirtualServiceType:
+			istioDetails.VirtualServices = append(istioDetails.VirtualServices, object)
+		}
+	}
+
+	gatewaysPerNamespace := make([][]kubernetes.IstioObject, 0, len(namespaces))
+	for _, ns := range namespaces {
+		gatewaysPerNamespace = append(gatewaysPerNamespace, istioDetailsPerNamespace[ns.Name].Gateways)
+	}
+
+	servicesPerNamespace := map[string][]core_v1.Service{}
+	registryStatus := make([]*kubernetes.RegistryStatus, 0, len(manifests.Services))
+	for _, svc := range manifests.Services {
+		servicesPerNamespace[svc.Namespace] = append(servicesPerNamespace[svc.Namespace], svc)
+		registryStatus = append(registryStatus, &kubernetes.RegistryStatus{
+			RegistryService: kubernetes.RegistryService{
+				Hostname: svc.Name + "." + svc.Namespace + "." + cfg.ExternalServices.Istio.IstioIdentityDomain,
+			},
+		})
+	}
+
+	istioConfigMap := findConfigMap(manifests, cfg.IstioNamespace, cfg.ExternalServices.Istio.ConfigMapName)
+	enabledAutoMtls := true
+	if icm, err := kubernetes.GetIstioConfigMap(istioConfigMap); err == nil {
+		enabledAutoMtls = icm.GetEnableAutoMtls()
+	}
+
+	policiesConfig := cfg.KialiFeatureFlags.Validations.Policies
+	var policiesConfigMap *core_v1.ConfigMap
+	if policiesConfig.ConfigMapName != "" {
+		policiesConfigMap = findConfigMap(manifests, cfg.Deployment.Namespace, policiesConfig.ConfigMapName)
+	}
+	validationPolicies := policies.LoadPolicies(policiesConfig, policiesConfigMap)
+
+	objectCheckers := []ObjectChecker{}
+	for _, ns := range namespaces {
+		istioDetails := *istioDetailsPerNamespace[ns.Name]
+
+		exportedResources := kubernetes.ExportedResources{}
+		for _, other := range namespaces {
+			if other.Name == ns.Name {
+				continue
+			}
+			otherDetails := istioDetailsPerNamespace[other.Name]
+			exportedResources.VirtualServices = append(exportedResources.VirtualServices, *filterExportToNamespacesIstioObjects(ns.Name, &otherDetails.VirtualServices)...)
+			exportedResources.DestinationRules = append(exportedResources.DestinationRules, *filterExportToNamespacesIstioObjects(ns.Name, &otherDetails.DestinationRules)...)
+			exportedResources.ServiceEntries = append(exportedResources.ServiceEntries, *filterExportToNamespacesIstioObjects(ns.Name, &otherDetails.ServiceEntries)...)
+		}
+
+		mtlsDetails := kubernetes.MTLSDetails{
+			DestinationRules:        allDestinationRules,
+			MeshPeerAuthentications: peerAuthnsPerNamespace[cfg.IstioNamespace],
+			PeerAuthentications:     peerAuthnsPerNamespace[ns.Name],
+			EnabledAutoMtls:         enabledAutoMtls,
+		}
+
+		objectCheckers = append(objectCheckers, getAllObjectCheckers(ns.Name, istioDetails, exportedResources, servicesPerNamespace[ns.Name], workloadsPerNamespace, workloadsPerNamespace[ns.Name], gatewaysPerNamespace, mtlsDetails, *rbacDetailsPerNamespace[ns.Name], namespaces, registryStatus, nil)...)
+	}
+
+	// policies are evaluated once for all the namespaces
+	policyObjects := map[string][]kubernetes.IstioObject{}
+	for _, ns := range namespaces {
+		mtlsDetails := kubernetes.MTLSDetails{PeerAuthentications: peerAuthnsPerNamespace[ns.Name]}
+		for objectType, objects := range policyCheckedObjects(*istioDetailsPerNamespace[ns.Name], mtlsDetails, *rbacDetailsPerNamespace[ns.Name]) {
+			policyObjects[objectType] = append(policyObjects[objectType], objects...)
+		}
+	}
+	objectCheckers = append(objectCheckers, checkers.PolicyChecker{IstioObjects: policyObjects, Namespaces: namespaces, Policies: validationPolicies})
+
+	return runObjectCheckers(objectCheckers)
+}
+
+// manifestsNamespaces returns the namespaces of the manifests, declared or not, sorted by name
+func manifestsNamespaces(manifests kubernetes.Manifests) models.Namespaces {
+	namespaces := map[string]models.Namespace{}
+	for _, ns := range manifests.Namespaces {
+		namespaces[ns.Name] = models.Namespace{Name: ns.Name, Labels: ns.Labels}
+	}
+	addNamespace := func(name string) {
+		if _, found := namespaces[name]; !found {
+			namespaces[name] = models.Namespace{Name: name}
+		}
+	}
+	for _, object := range manifests.IstioObjects {
+		addNamespace(object.GetObjectMeta().Namespace)
+	}
+	for _, svc := range manifests.Services {
+		addNamespace(svc.Namespace)
+	}
+
+	result := make(models.Namespaces, 0, len(namespaces))
+	for _, ns := range namespaces {
+		result = append(result, ns)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// manifestsWorkloads returns the workloads of the manifests by namespace. ReplicaSets and Pods owned by a
+// controller are not workloads on their own.
+func manifestsWorkloads(manifests kubernetes.Manifests, namespaces models.Namespaces) map[string]models.WorkloadList {
+	workloads := map[string]models.WorkloadList{}
+	for _, ns := range namespaces {
+		workloads[ns.Name] = models.WorkloadList{Namespace: ns, Workloads: []models.WorkloadListItem{}}
+	}
+	add := func(namespace string, w *models.Workload) {
+		wl, found := workloads[namespace]
+		if !found {
+			wl = models.WorkloadList{Namespace: models.Namespace{Name: namespace}}
+		}
+		item := models.WorkloadListItem{}
+		item.ParseWorkload(w)
+		wl.Workloads = append(wl.Workloads, item)
+		workloads[namespace] = wl
+	}
+
+	for i := range manifests.Deployments {
+		w := &models.Workload{}
+		w.ParseDeployment(&manifests.Deployments[i])
+		add(manifests.Deployments[i].Namespace, w)
+	}
+	for i := range manifests.ReplicaSets {
+		if len(manifests.ReplicaSets[i].OwnerReferences) == 0 {
+			w := &models.Workload{}
+			w.ParseReplicaSet(&manifests.ReplicaSets[i])
+			add(manifests.ReplicaSets[i].Namespace, w)
+		}
+	}
+	for i := range manifests.ReplicationControllers {
+		w := &models.Workload{}
+		w.ParseReplicationController(&manifests.ReplicationControllers[i])
+		add(manifests.ReplicationControllers[i].Namespace, w)
+	}
+	for i := range manifests.StatefulSets {
+		w := &models.Workload{}
+		w.ParseStatefulSet(&manifests.StatefulSets[i])
+		add(manifests.StatefulSets[i].Namespace, w)
+	}
+	for i := range manifests.DaemonSets {
+		w := &models.Workload{}
+		w.ParseDaemonSet(&manifests.DaemonSets[i])
+		add(manifests.DaemonSets[i].Namespace, w)
+	}
+	for i := range manifests.Jobs {
+		w := &models.Workload{}
+		w.ParseJob(&manifests.Jobs[i])
+		add(manifests.Jobs[i].Namespace, w)
+	}
+	for i := range manifests.CronJobs {
+		w := &models.Workload{}
+		w.ParseCronJob(&manifests.CronJobs[i])
+		add(manifests.CronJobs[i].Namespace, w)
+	}
+	for i := range manifests.Pods {
+		if len(manifests.Pods[i].OwnerReferences) == 0 {
+			w := &models.Workload{}
+			w.ParsePod(&manifests.Pods[i])
+			add(manifests.Pods[i].Namespace, w)
+		}
+	}
+	return workloads
+}
+
+func findConfigMap(manifests kubernetes.Manifests, namespace, name string) *core_v1.ConfigMap {
+	for i := range manifests.ConfigMaps {
+		if cm := &manifests.ConfigMaps[i]; cm.Namespace == namespace && cm.Name == name {
+			return cm
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package business
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+const offlineManifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  selector:
+    app: reviews
+  ports:
+  - name: http
+    port: 9080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reviews-v1
+  namespace: bookinfo
+spec:
+  template:
+    metadata:
+      labels:
+        app: reviews
+        version: v1
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: reviews
+        subset: v1
+      weight: 50
+    - destination:
+        host: ratings
+      weight: 50
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: DestinationRule
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  host: reviews
+  subsets:
+  - name: v1
+    labels:
+      version: v1
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: details
+  namespace: bookinfo
+spec:
+  hosts:
+  - details
+  http:
+  - route:
+    - destination:
+        host: details
+`
+
+func decodeOfflineManifests(t *testing.T, yaml string) kubernetes.Manifests {
+	manifests := kubernetes.Manifests{}
+	assert.NoError(t, manifests.Decode(strings.NewReader(yaml), "default"))
+	return manifests
+}
+
+func TestValidateManifests(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	validations := ValidateManifests(decodeOfflineManifests(t, offlineManifests))
+
+	reviews := validations[models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}]
+	assert.NotNil(reviews)
+	assert.False(reviews.Valid)
+	assert.Len(reviews.Checks, 1)
+	assert.Equal("KIA1101", reviews.Checks[0].Code)
+	assert.Equal("spec/http[0]/route[1]/destination/host", reviews.Checks[0].Path)
+
+	details := validations[models.IstioValidationKey{ObjectType: "virtualservice", Name: "details", Namespace: "bookinfo"}]
+	assert.NotNil(details)
+	assert.False(details.Valid)
+	assert.Equal("KIA1101", details.Checks[0].Code)
+
+	dr := validations[models.IstioValidationKey{ObjectType: "destinationrule", Name: "reviews", Namespace: "bookinfo"}]
+	assert.NotNil(dr)
+	assert.True(dr.Valid)
+	assert.Empty(dr.Checks)
+
+	ratings := offlineManifests + `
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: ratings
+  namespace: bookinfo
+`
+	validations = ValidateManifests(decodeOfflineManifests(t, ratings))
+	reviews = validations[models.IstioValidationKey{ObjectType: "virtualservice", Name: "reviews", Namespace: "bookinfo"}]
+	for _, c := range reviews.Checks {
+		t.Logf("%+v", c)
+	}
+	assert.True(reviews.Valid)
+	assert.Empty(reviews.Checks)
+}
+
+func TestValidateManifestsPolicies(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.KialiFeatureFlags.Validations.Ignore = []string{"KIA1101"}
+	conf.KialiFeatureFlags.Validations.Policies.ConfigMapName = "kiali-validation-policies"
+	config.Set(conf)
+
+	policies := offlineManifests + `
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: kiali-validation-policies
+  namespace: istio-system
+data:
+  routes.yaml: |
+    - code: ORG-0001
+      expression: object.spec.http.all(r, has(r.timeout))
+      kinds: [virtualservice]
+      message: HTTP routes must set a timeout
+      severity: error
+`
+	validations := ValidateManifests(decodeOfflineManifests(t, policies))
+
+	for _, name := range []string{"reviews", "details"} {
+		vs := validations[models.IstioValidationKey{ObjectType: "virtualservice", Name: name, Namespace: "bookinfo"}]
+		assert.NotNil(vs)
+		assert.False(vs.Valid)
+		assert.Len(vs.Checks, 1)
+		assert.Equal("ORG-0001", vs.Checks[0].Code)
+	}
+}
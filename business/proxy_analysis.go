@@ -0,0 +1,372 @@
+package business
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// istioListenerPorts are the ports of the listeners set up by Istio itself, not restricted by a Sidecar
+var istioListenerPorts = map[int]bool{15001: true, 15006: true, 15020: true, 15021: true, 15090: true}
+
+// proxyConfig is the parsed config dump of a pod proxy
+type proxyConfig struct {
+	clusters  models.Clusters
+	listeners models.Listeners
+	routes    models.Routes
+}
+
+// GetWorkloadProxyChecks cross-checks the proxy config of the workload pods against the Istio config applying
+// to them: the VirtualService routes and DestinationRule subsets missing from the proxy, the listeners on ports
+// excluded by the Sidecar egress and the stale proxy config.
+func (in *ProxyStatusService) GetWorkloadProxyChecks(namespace string, workload *models.Workload) ([]*models.IstioCheck, error) {
+	namespaces, err := in.businessLayer.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+	nss := make([]string, 0, len(namespaces))
+	for _, ns := range namespaces {
+		nss = append(nss, ns.Name)
+	}
+
+	istioConfigs, err := in.getIstioConfigs(namespace, nss)
+	if err != nil {
+		return nil, err
+	}
+	sidecar := workloadSidecar(namespace, workload.Labels, istioConfigs)
+
+	checks := []*models.IstioCheck{}
+	for _, pod := range workload.Pods {
+		checks = append(checks, staleProxyChecks(pod)...)
+		if len(pod.IstioContainers) == 0 || pod.Status != "Running" {
+			continue
+		}
+		dump, err := in.k8s.GetConfigDump(namespace, pod.Name)
+		if err != nil {
+			log.Debugf("Skipping the proxy config checks of pod [%s/%s]: %v", namespace, pod.Name, err)
+			continue
+		}
+		proxy, err := parseProxyConfig(dump, nss)
+		if err != nil {
+			log.Debugf("Skipping the proxy config checks of pod [%s/%s]: %v", namespace, pod.Name, err)
+			continue
+		}
+		checks = append(checks, proxyConfigChecks(namespace, pod.Name, proxy, istioConfigs, sidecar)...)
+	}
+	return checks, nil
+}
+
+// getIstioConfigs returns the VirtualServices and DestinationRules of the namespaces, and the Sidecars of the
+// workload and Istio namespaces
+func (in *ProxyStatusService) getIstioConfigs(namespace string, namespaces []string) ([]models.IstioConfigList, error) {
+	istioConfigs := make([]models.IstioConfigList, len(namespaces))
+	wg := sync.WaitGroup{}
+	wg.Add(len(namespaces))
+	errChan := make(chan error, len(namespaces))
+	for i, ns := range namespaces {
+		go func(i int, ns string) {
+			defer wg.Done()
+			criteria := IstioConfigCriteria{
+				Namespace:               ns,
+				IncludeVirtualServices:  true,
+				IncludeDestinationRules: true,
+				IncludeSidecars:         ns == namespace || ns == config.Get().IstioNamespace,
+			}
+			istioConfig, err := in.businessLayer.IstioConfig.GetIstioConfigList(criteria)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			istioConfigs[i] = istioConfig
+		}(i, ns)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+	return istioConfigs, nil
+}
+
+func parseProxyConfig(dump *kubernetes.ConfigDump, namespaces []string) (*proxyConfig, error) {
+	proxy := &proxyConfig{}
+	if err := proxy.clusters.Parse(dump); err != nil {
+		return nil, err
+	}
+	if err := proxy.listeners.Parse(dump); err != nil {
+		return nil, err
+	}
+	if err := proxy.routes.Parse(dump, namespaces); err != nil {
+		return nil, err
+	}
+	return proxy, nil
+}
+
+// staleProxyChecks flags the xDS types whose last config sent by istiod was not acknowledged by the pod proxy
+func staleProxyChecks(pod *models.Pod) []*models.IstioCheck {
+	checks := []*models.IstioCheck{}
+	if pod.ProxyStatus == nil {
+		return checks
+	}
+	for _, xds := range []struct {
+		name   string
+		status string
+	}{{"CDS", pod.ProxyStatus.CDS}, {"EDS", pod.ProxyStatus.EDS}, {"LDS", pod.ProxyStatus.LDS}, {"RDS", pod.ProxyStatus.RDS}} {
+		if strings.HasPrefix(xds.status, "Stale") {
+			check := models.Build("proxy.status.stale", fmt.Sprintf("pods/%s/proxystatus/%s", pod.Name, xds.name))
+			checks = append(checks, &check)
+		}
+	}
+	return checks
+}
+
+// workloadSidecar returns the Sidecar applying to the workload: the one selecting it, otherwise the default of
+// its namespace, otherwise the default of the Istio namespace. It returns nil when there is none.
+func workloadSidecar(namespace string, labels map[string]string, istioConfigs []models.IstioConfigList) *models.Sidecar {
+	var nsDefault, rootDefault *models.Sidecar
+	for i := range istioConfigs {
+		for j := range istioConfigs[i].Sidecars {
+			sc := &istioConfigs[i].Sidecars[j]
+			selector, hasSelector := sidecarSelector(sc)
+			switch {
+			case sc.Metadata.Namespace == namespace && hasSelector:
+				if labelsMatch(selector, labels) {
+					return sc
+				}
+			case sc.Metadata.Namespace == namespace && nsDefault == nil:
+				nsDefault = sc
+			case sc.Metadata.Namespace == config.Get().IstioNamespace && !hasSelector && rootDefault == nil:
+				rootDefault = sc
+			}
+		}
+	}
+	if nsDefault != nil {
+		return nsDefault
+	}
+	return rootDefault
+}
+
+func sidecarSelector(sc *models.Sidecar) (map[string]interface{}, bool) {
+	ws, ok := sc.Spec.WorkloadSelector.(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	labels, ok := ws["labels"].(map[string]interface{})
+	return labels, ok && len(labels) > 0
+}
+
+func labelsMatch(selector map[string]interface{}, labels map[string]string) bool {
+	for key, value := range selector {
+		if label, found := labels[key]; !found || label != value {
+			return false
+		}
+	}
+	return true
+}
+
+// proxyConfigChecks returns the checks of the proxy config of a pod against the Istio config
+func proxyConfigChecks(namespace, pod string, proxy *proxyConfig, istioConfigs []models.IstioConfigList, sidecar *models.Sidecar) []*models.IstioCheck {
+	checks := []*models.IstioCheck{}
+
+	routedVirtualServices := map[string]bool{}
+	for _, r := range proxy.routes {
+		routedVirtualServices[r.VirtualService] = true
+	}
+	subsets := map[string]map[string]bool{}
+	for _, c := range proxy.clusters {
+		if c.Direction != "outbound" {
+			continue
+		}
+		host := c.ServiceFQDN.String()
+		if subsets[host] == nil {
+			subsets[host] = map[string]bool{}
+		}
+		subsets[host][c.Subset] = true
+	}
+
+	for _, istioConfig := range istioConfigs {
+		for _, vs := range istioConfig.VirtualServices.Items {
+			if !isMeshHTTPVirtualService(vs) || !exportedTo(vs.Spec.ExportTo, vs.Metadata.Namespace, namespace) || !sidecarImports(sidecar, vs.Metadata.Namespace, vs.Spec.Hosts) {
+				continue
+			}
+			if !routedVirtualServices[fmt.Sprintf("%s.%s", vs.Metadata.Name, vs.Metadata.Namespace)] {
+				check := models.Build("proxy.virtualservice.routemissing", fmt.Sprintf("pods/%s/virtualservices/%s/%s", pod, vs.Metadata.Namespace, vs.Metadata.Name))
+				checks = append(checks, &check)
+			}
+		}
+
+		for _, dr := range istioConfig.DestinationRules.Items {
+			host, ok := dr.Spec.Host.(string)
+			if !ok || !exportedTo(dr.Spec.ExportTo, dr.Metadata.Namespace, namespace) {
+				continue
+			}
+			// the host clusters are out of the proxy scope when there is none
+			hostSubsets, found := subsets[kubernetes.ParseHost(host, dr.Metadata.Namespace, "").String()]
+			if !found {
+				continue
+			}
+			for _, subset := range destinationRuleSubsets(dr) {
+				if !hostSubsets[subset] {
+					check := models.Build("proxy.destinationrule.subsetmissing", fmt.Sprintf("pods/%s/destinationrules/%s/%s/subsets/%s", pod, dr.Metadata.Namespace, dr.Metadata.Name, subset))
+					checks = append(checks, &check)
+				}
+			}
+		}
+	}
+
+	if ports, restricted := sidecarPorts(sidecar); restricted {
+		flagged := map[int]bool{}
+		for _, l := range proxy.listeners {
+			port := int(l.Port)
+			if istioListenerPorts[port] || ports[port] || flagged[port] {
+				continue
+			}
+			flagged[port] = true
+			check := models.Build("proxy.sidecar.listenernotexcluded", fmt.Sprintf("pods/%s/listeners/%s:%d", pod, l.Address, port))
+			checks = append(checks, &check)
+		}
+	}
+
+	sort.SliceStable(checks, func(i, j int) bool {
+		return checks[i].Path < checks[j].Path
+	})
+	return checks
+}
+
+// isMeshHTTPVirtualService returns true when the VirtualService has http routes applying to the sidecars
+func isMeshHTTPVirtualService(vs models.VirtualService) bool {
+	if http, ok := vs.Spec.Http.([]interface{}); !ok || len(http) == 0 {
+		return false
+	}
+	gateways, ok := vs.Spec.Gateways.([]interface{})
+	if !ok || len(gateways) == 0 {
+		return true
+	}
+	for _, gw := range gateways {
+		if gw == "mesh" {
+			return true
+		}
+	}
+	return false
+}
+
+// exportedTo returns true when the exportTo field of an object of the objectNamespace exports it to the namespace
+func exportedTo(exportTo interface{}, objectNamespace, namespace string) bool {
+	namespaces, ok := exportTo.([]interface{})
+	if !ok || len(namespaces) == 0 {
+		return true
+	}
+	for _, ns := range namespaces {
+		if ns == "*" || ns == namespace || (ns == "." && objectNamespace == namespace) {
+			return true
+		}
+	}
+	return false
+}
+
+// sidecarImports returns true when the Sidecar egress imports one of the hosts of the namespace, or when there is
+// no Sidecar
+func sidecarImports(sidecar *models.Sidecar, namespace string, hosts []string) bool {
+	if sidecar == nil {
+		return true
+	}
+	egress, ok := sidecar.Spec.Egress.([]interface{})
+	if !ok || len(egress) == 0 {
+		return true
+	}
+	for _, e := range egress {
+		listener, ok := e.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		egressHosts, _ := listener["hosts"].([]interface{})
+		for _, eh := range egressHosts {
+			parts := strings.SplitN(fmt.Sprintf("%v", eh), "/", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			if parts[0] != "*" && parts[0] != namespace && !(parts[0] == "." && sidecar.Metadata.Namespace == namespace) {
+				continue
+			}
+			for _, host := range hosts {
+				if dnsMatches(parts[1], kubernetes.ParseHost(host, namespace, "").String()) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func dnsMatches(pattern, host string) bool {
+	if pattern == "*" || pattern == host {
+		return true
+	}
+	return strings.HasPrefix(pattern, "*") && strings.HasSuffix(host, strings.TrimPrefix(pattern, "*"))
+}
+
+func destinationRuleSubsets(dr models.DestinationRule) []string {
+	names := []string{}
+	subsets, _ := dr.Spec.Subsets.([]interface{})
+	for _, s := range subsets {
+		if subset, ok := s.(map[string]interface{}); ok {
+			if name, ok := subset["name"].(string); ok {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// sidecarPorts returns the egress and ingress ports of the Sidecar, and true when all its egress listeners have a
+// port, restricting the outbound listeners to those ports
+func sidecarPorts(sidecar *models.Sidecar) (map[int]bool, bool) {
+	if sidecar == nil {
+		return nil, false
+	}
+	egress, ok := sidecar.Spec.Egress.([]interface{})
+	if !ok || len(egress) == 0 {
+		return nil, false
+	}
+	ports := map[int]bool{}
+	for _, e := range egress {
+		port, found := listenerPort(e)
+		if !found {
+			return nil, false
+		}
+		ports[port] = true
+	}
+	ingress, _ := sidecar.Spec.Ingress.([]interface{})
+	for _, i := range ingress {
+		if port, found := listenerPort(i); found {
+			ports[port] = true
+		}
+	}
+	return ports, true
+}
+
+// listenerPort returns the port number of a Sidecar egress or ingress listener
+func listenerPort(listener interface{}) (int, bool) {
+	l, ok := listener.(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	port, ok := l["port"].(map[string]interface{})
+	if !ok {
+		return 0, false
+	}
+	switch number := port["number"].(type) {
+	case float64:
+		return int(number), true
+	case int64:
+		return int(number), true
+	case int:
+		return number, true
+	}
+	return 0, false
+}
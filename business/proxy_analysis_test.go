@@ -0,0 +1,152 @@
+package business
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+func fakeProxyConfig() *proxyConfig {
+	reviews := kubernetes.Host{Service: "reviews", Namespace: "bookinfo", Cluster: "svc.cluster.local", CompleteInput: true}
+	return &proxyConfig{
+		clusters: models.Clusters{
+			{ServiceFQDN: reviews, Port: 9080, Direction: "outbound"},
+			{ServiceFQDN: reviews, Port: 9080, Subset: "v1", Direction: "outbound"},
+			{ServiceFQDN: reviews, Port: 9080, Subset: "v2", Direction: "outbound"},
+		},
+		listeners: models.Listeners{
+			{Address: "0.0.0.0", Port: 15001},
+			{Address: "0.0.0.0", Port: 9080},
+			{Address: "10.0.0.12", Port: 3306},
+		},
+		routes: models.Routes{
+			{Name: "9080", Domains: reviews, Match: "/*", VirtualService: "reviews.bookinfo"},
+		},
+	}
+}
+
+func fakeProxyIstioConfig() models.IstioConfigList {
+	vsReviews := models.VirtualService{}
+	vsReviews.Metadata = meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}
+	vsReviews.Spec.Hosts = []string{"reviews"}
+	vsReviews.Spec.Http = []interface{}{map[string]interface{}{}}
+
+	vsRatings := models.VirtualService{}
+	vsRatings.Metadata = meta_v1.ObjectMeta{Name: "ratings", Namespace: "bookinfo"}
+	vsRatings.Spec.Hosts = []string{"ratings"}
+	vsRatings.Spec.Http = []interface{}{map[string]interface{}{}}
+
+	// bound to a gateway only, not applying to the sidecars
+	vsGateway := models.VirtualService{}
+	vsGateway.Metadata = meta_v1.ObjectMeta{Name: "bookinfo", Namespace: "bookinfo"}
+	vsGateway.Spec.Hosts = []string{"*"}
+	vsGateway.Spec.Gateways = []interface{}{"bookinfo-gateway"}
+	vsGateway.Spec.Http = []interface{}{map[string]interface{}{}}
+
+	drReviews := models.DestinationRule{}
+	drReviews.Metadata = meta_v1.ObjectMeta{Name: "reviews", Namespace: "bookinfo"}
+	drReviews.Spec.Host = "reviews"
+	drReviews.Spec.Subsets = []interface{}{
+		map[string]interface{}{"name": "v1"},
+		map[string]interface{}{"name": "v2"},
+		map[string]interface{}{"name": "v3"},
+	}
+
+	// no cluster for the host, out of the proxy scope
+	drDetails := models.DestinationRule{}
+	drDetails.Metadata = meta_v1.ObjectMeta{Name: "details", Namespace: "bookinfo"}
+	drDetails.Spec.Host = "details"
+	drDetails.Spec.Subsets = []interface{}{map[string]interface{}{"name": "v1"}}
+
+	return models.IstioConfigList{
+		Namespace:        models.Namespace{Name: "bookinfo"},
+		VirtualServices:  models.VirtualServices{Items: []models.VirtualService{vsReviews, vsRatings, vsGateway}},
+		DestinationRules: models.DestinationRules{Items: []models.DestinationRule{drReviews, drDetails}},
+	}
+}
+
+func fakeProxySidecar(namespace string, selector map[string]interface{}, egress ...interface{}) models.Sidecar {
+	sc := models.Sidecar{}
+	sc.Metadata = meta_v1.ObjectMeta{Name: "sidecar", Namespace: namespace}
+	if selector != nil {
+		sc.Spec.WorkloadSelector = map[string]interface{}{"labels": selector}
+	}
+	sc.Spec.Egress = egress
+	return sc
+}
+
+func checkPaths(checks []*models.IstioCheck) map[string]string {
+	paths := map[string]string{}
+	for _, c := range checks {
+		paths[c.Path] = c.Code
+	}
+	return paths
+}
+
+func TestProxyConfigChecks(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	checks := proxyConfigChecks("bookinfo", "productpage-v1-1", fakeProxyConfig(), []models.IstioConfigList{fakeProxyIstioConfig()}, nil)
+	assert.Equal(map[string]string{
+		"pods/productpage-v1-1/virtualservices/bookinfo/ratings":             "KIA1501",
+		"pods/productpage-v1-1/destinationrules/bookinfo/reviews/subsets/v3": "KIA1502",
+	}, checkPaths(checks))
+	assert.Equal(models.WarningSeverity, checks[0].Severity)
+
+	// the Sidecar does not import ratings and restricts the listeners to the port 9080
+	sidecar := fakeProxySidecar("bookinfo", nil, map[string]interface{}{
+		"port":  map[string]interface{}{"number": float64(9080), "protocol": "HTTP", "name": "http"},
+		"hosts": []interface{}{"./reviews.bookinfo.svc.cluster.local", "istio-system/*"},
+	})
+	checks = proxyConfigChecks("bookinfo", "productpage-v1-1", fakeProxyConfig(), []models.IstioConfigList{fakeProxyIstioConfig()}, &sidecar)
+	assert.Equal(map[string]string{
+		"pods/productpage-v1-1/destinationrules/bookinfo/reviews/subsets/v3": "KIA1502",
+		"pods/productpage-v1-1/listeners/10.0.0.12:3306":                     "KIA1503",
+	}, checkPaths(checks))
+
+	// an egress listener without port does not restrict the listeners
+	sidecar = fakeProxySidecar("bookinfo", nil, map[string]interface{}{"hosts": []interface{}{"*/*"}})
+	checks = proxyConfigChecks("bookinfo", "productpage-v1-1", fakeProxyConfig(), []models.IstioConfigList{fakeProxyIstioConfig()}, &sidecar)
+	assert.Len(checks, 2)
+	assert.Equal("KIA1501", checkPaths(checks)["pods/productpage-v1-1/virtualservices/bookinfo/ratings"])
+}
+
+func TestWorkloadSidecar(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	root := fakeProxySidecar("istio-system", nil)
+	root.Metadata.Name = "root"
+	nsDefault := fakeProxySidecar("bookinfo", nil)
+	nsDefault.Metadata.Name = "default"
+	selecting := fakeProxySidecar("bookinfo", map[string]interface{}{"app": "reviews"})
+	selecting.Metadata.Name = "reviews"
+	istioConfigs := []models.IstioConfigList{
+		{Sidecars: models.Sidecars{root}},
+		{Sidecars: models.Sidecars{nsDefault, selecting}},
+	}
+
+	assert.Equal("reviews", workloadSidecar("bookinfo", map[string]string{"app": "reviews", "version": "v1"}, istioConfigs).Metadata.Name)
+	assert.Equal("default", workloadSidecar("bookinfo", map[string]string{"app": "ratings"}, istioConfigs).Metadata.Name)
+	assert.Equal("root", workloadSidecar("travel", map[string]string{"app": "cars"}, istioConfigs).Metadata.Name)
+	assert.Nil(workloadSidecar("travel", map[string]string{}, istioConfigs[1:]))
+}
+
+func TestStaleProxyChecks(t *testing.T) {
+	assert := assert.New(t)
+
+	pod := &models.Pod{Name: "reviews-v1-1", ProxyStatus: &models.ProxyStatus{CDS: "Synced", EDS: "Synced", LDS: "Stale (Never Acknowledged)", RDS: "Stale"}}
+	checks := staleProxyChecks(pod)
+	assert.Equal(map[string]string{
+		"pods/reviews-v1-1/proxystatus/LDS": "KIA1504",
+		"pods/reviews-v1-1/proxystatus/RDS": "KIA1504",
+	}, checkPaths(checks))
+
+	assert.Empty(staleProxyChecks(&models.Pod{Name: "reviews-v1-1"}))
+}
@@ -209,13 +209,14 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 	var vs, dr []kubernetes.IstioObject
 	var ws models.Workloads
 	var nsmtls models.MTLSStatus
+	var slos *models.ServiceSLOs
 
 	conf := config.Get()
 	additionalDetails := models.GetAdditionalDetails(conf, svc.ObjectMeta.Annotations)
 
 	wg := sync.WaitGroup{}
 	wg.Add(5)
-	errChan := make(chan error, 5)
+	errChan := make(chan error, 8)
 
 	labelsSelector := labels.Set(svc.Spec.Selector).String()
 	// If service doesn't have any selector, we can't know which are the pods and workloads applying.
@@ -257,6 +258,18 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 		}
 	}()
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err2 error
+		slos, err2 = in.businessLayer.SLO.getServiceSLOs(svc, queryTime)
+		if err2 != nil {
+			// The objectives are informative, they never fail the service details
+			log.Warningf("Unable to evaluate the SLOs of service [%s/%s]: %v", namespace, service, err2)
+			slos = nil
+		}
+	}()
+
 	go func() {
 		defer wg.Done()
 		var err2 error
@@ -328,6 +341,9 @@ func (in *SvcService) GetService(namespace, service, interval string, queryTime
 	}
 
 	s := models.ServiceDetails{Workloads: wo, Health: hth, NamespaceMTLS: nsmtls, AdditionalDetails: additionalDetails}
+	if slos != nil && len(slos.Objectives) > 0 {
+		s.SLOs = slos
+	}
 	s.SetService(svc)
 	s.SetPods(kubernetes.FilterPodsForEndpoints(eps, pods))
 	s.SetIstioSidecar(wo)
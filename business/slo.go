@@ -0,0 +1,247 @@
+package business
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// SLOService deals with the service level objectives: it computes their service level indicators, remaining
+// error budget and burn rates from the Istio metrics
+type SLOService struct {
+	prom          prometheus.ClientInterface
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// sliQuery identifies the bad requests ratio of an objective over an interval
+type sliQuery struct {
+	sloType   string
+	threshold float64
+	interval  string
+}
+
+// GetServiceSLOs returns the objectives of a service with their service level indicator, remaining error
+// budget and burn rates at the query time
+func (in *SLOService) GetServiceSLOs(namespace, service string, queryTime time.Time) (*models.ServiceSLOs, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+
+	svc, err := in.businessLayer.Svc.getService(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	return in.getServiceSLOs(svc, queryTime)
+}
+
+func (in *SLOService) getServiceSLOs(svc *core_v1.Service, queryTime time.Time) (*models.ServiceSLOs, error) {
+	objectives, source := serviceObjectives(svc.Namespace, svc.Name, svc.Annotations)
+	slos := &models.ServiceSLOs{
+		Namespace:  svc.Namespace,
+		Service:    svc.Name,
+		Source:     source,
+		Objectives: []models.SLOStatus{},
+	}
+	if len(objectives) == 0 {
+		return slos, nil
+	}
+
+	// the objectives may share queries, each distinct query runs once
+	burnRateWindows := config.Get().SLO.BurnRateWindows
+	queries := map[sliQuery]bool{}
+	for _, slo := range objectives {
+		queries[sliQuery{sloType: slo.Type, threshold: slo.Threshold, interval: slo.Window}] = true
+		for _, w := range burnRateWindows {
+			queries[sliQuery{sloType: slo.Type, threshold: slo.Threshold, interval: w.Long}] = true
+			queries[sliQuery{sloType: slo.Type, threshold: slo.Threshold, interval: w.Short}] = true
+		}
+	}
+
+	var mutex sync.Mutex
+	ratios := make(map[sliQuery]*float64, len(queries))
+	wg := sync.WaitGroup{}
+	wg.Add(len(queries))
+	errChan := make(chan error, len(queries))
+	for query := range queries {
+		go func(query sliQuery) {
+			defer wg.Done()
+			ratio, err := in.badRequestsRatio(svc.Namespace, svc.Name, query, queryTime)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			mutex.Lock()
+			ratios[query] = ratio
+			mutex.Unlock()
+		}(query)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+
+	for _, slo := range objectives {
+		slos.Objectives = append(slos.Objectives, evaluateSLO(slo, burnRateWindows, func(interval string) *float64 {
+			return ratios[sliQuery{sloType: slo.Type, threshold: slo.Threshold, interval: interval}]
+		}))
+	}
+	return slos, nil
+}
+
+// badRequestsRatio returns the ratio of the bad requests to the service over the interval, nil when the service
+// had no request
+func (in *SLOService) badRequestsRatio(namespace, service string, query sliQuery, queryTime time.Time) (*float64, error) {
+	var total, bad float64
+	switch query.sloType {
+	case config.SLOAvailability:
+		rates, err := in.prom.GetServiceRequestRates(namespace, service, query.interval, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		rqHealth := models.NewEmptyRequestHealth()
+		for _, sample := range rates {
+			rqHealth.AggregateInbound(sample)
+		}
+		rqHealth.CombineReporters()
+		for protocol, codes := range rqHealth.Inbound {
+			for code, rate := range codes {
+				total += rate
+				if isFailedRequest(protocol, code) {
+					bad += rate
+				}
+			}
+		}
+	case config.SLOLatency:
+		threshold := strconv.FormatFloat(query.threshold, 'f', -1, 64)
+		rates, err := in.prom.GetServiceRequestDurationRates(namespace, service, threshold, query.interval, queryTime)
+		if err != nil {
+			return nil, err
+		}
+		var within float64
+		for _, sample := range rates {
+			switch string(sample.Metric["le"]) {
+			case "+Inf":
+				total = float64(sample.Value)
+			case threshold:
+				within = float64(sample.Value)
+			}
+		}
+		bad = total - within
+	default:
+		return nil, fmt.Errorf("invalid objective type [%s]", query.sloType)
+	}
+
+	if total <= 0 {
+		return nil, nil
+	}
+	ratio := bad / total
+	return &ratio, nil
+}
+
+// isFailedRequest returns true for the http 5xx responses, the grpc errors and the requests without response
+func isFailedRequest(protocol, code string) bool {
+	if code == "-" {
+		return true
+	}
+	if protocol == "grpc" {
+		return code != "0"
+	}
+	return strings.HasPrefix(code, "5")
+}
+
+// evaluateSLO computes the service level indicator, the remaining error budget and the burn rates of an
+// objective, badRatio returning the bad requests ratio over an interval
+func evaluateSLO(slo config.SLO, burnRateWindows []config.BurnRateWindow, badRatio func(interval string) *float64) models.SLOStatus {
+	errorBudget := 1 - slo.Target/100
+	status := models.SLOStatus{SLO: slo, BurnRates: []models.BurnRate{}}
+
+	if ratio := badRatio(slo.Window); ratio != nil {
+		sli := (1 - *ratio) * 100
+		remaining := (1 - *ratio/errorBudget) * 100
+		status.SLI = &sli
+		status.ErrorBudgetRemaining = &remaining
+	}
+
+	burnRate := func(interval string) *float64 {
+		ratio := badRatio(interval)
+		if ratio == nil {
+			return nil
+		}
+		rate := *ratio / errorBudget
+		return &rate
+	}
+	for _, w := range burnRateWindows {
+		br := models.BurnRate{BurnRateWindow: w, LongBurnRate: burnRate(w.Long), ShortBurnRate: burnRate(w.Short)}
+		br.Firing = br.LongBurnRate != nil && br.ShortBurnRate != nil && *br.LongBurnRate >= w.Threshold && *br.ShortBurnRate >= w.Threshold
+		status.BurnRates = append(status.BurnRates, br)
+	}
+	return status
+}
+
+// serviceObjectives returns the objectives of the slo.kiali.io/objectives annotation, if set and valid, otherwise
+// of the first SLO config entry matching the service. The invalid configured objectives are ignored.
+func serviceObjectives(namespace, service string, annotations map[string]string) ([]config.SLO, string) {
+	sloConfig := config.Get().SLO
+	withDefaults := func(objectives []config.SLO) []config.SLO {
+		result := make([]config.SLO, 0, len(objectives))
+		for _, slo := range objectives {
+			if slo.Window == "" {
+				slo.Window = sloConfig.Window
+			}
+			if slo.Name == "" {
+				slo.Name = slo.Type
+			}
+			result = append(result, slo)
+		}
+		return result
+	}
+
+	if annotation, ok := annotations[string(models.SLOAnnotation)]; ok {
+		objectives, err := models.ParseSLOAnnotation(annotation)
+		if err == nil {
+			return withDefaults(objectives), models.SLOSourceAnnotation
+		}
+		log.Warningf("Ignoring invalid [%s] annotation of service [%s/%s]: %v", models.SLOAnnotation, namespace, service, err)
+	}
+
+	for _, s := range sloConfig.Services {
+		if sloMatches(s.Namespace, namespace) && sloMatches(s.Name, service) {
+			objectives := []config.SLO{}
+			for _, slo := range withDefaults(s.Objectives) {
+				if err := models.ValidateSLO(slo); err != nil {
+					log.Warningf("Ignoring invalid SLO config of service [%s/%s]: %v", namespace, service, err)
+					continue
+				}
+				objectives = append(objectives, slo)
+			}
+			return objectives, models.SLOSourceConfig
+		}
+	}
+	return []config.SLO{}, models.SLOSourceConfig
+}
+
+func sloMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		log.Warningf("Ignoring invalid SLO config pattern [%s]: %v", pattern, err)
+		return false
+	}
+	return re.MatchString(value)
+}
@@ -0,0 +1,134 @@
+package business
+
+import (
+	"testing"
+	"time"
+
+	osproject_v1 "github.com/openshift/api/project/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+func requestRate(code string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"reporter":         "destination",
+			"request_protocol": "http",
+			"response_code":    model.LabelValue(code),
+		},
+		Value: model.SampleValue(value),
+	}
+}
+
+func durationRate(le string, value float64) *model.Sample {
+	return &model.Sample{Metric: model.Metric{"le": model.LabelValue(le)}, Value: model.SampleValue(value)}
+}
+
+func TestGetServiceSLOs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	k8s := new(kubetest.K8SClientMock)
+	prom := new(prometheustest.PromClientMock)
+	config.Set(config.NewConfig())
+
+	queryTime := time.Date(2017, 01, 15, 0, 0, 0, 0, time.UTC)
+	k8s.On("IsOpenShift").Return(true)
+	k8s.On("GetProject", "ns").Return(&osproject_v1.Project{}, nil)
+	k8s.On("GetService", "ns", "reviews").Return(&core_v1.Service{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "reviews",
+			Namespace:   "ns",
+			Annotations: map[string]string{string(models.SLOAnnotation): "availability,99;latency,99,30d,250"},
+		},
+	}, nil)
+
+	prom.On("GetServiceRequestRates", "ns", "reviews", "30d", queryTime).Return(model.Vector{requestRate("200", 199), requestRate("503", 1)}, nil)
+	prom.On("GetServiceRequestRates", "ns", "reviews", "1h", queryTime).Return(model.Vector{requestRate("200", 80), requestRate("500", 10), requestRate("0", 10)}, nil)
+	prom.On("GetServiceRequestRates", "ns", "reviews", "5m", queryTime).Return(model.Vector{requestRate("200", 70), requestRate("404", 10), requestRate("500", 20)}, nil)
+	prom.On("GetServiceRequestRates", "ns", "reviews", mock.AnythingOfType("string"), queryTime).Return(model.Vector{}, nil)
+	prom.On("GetServiceRequestDurationRates", "ns", "reviews", "250", "30d", queryTime).Return(model.Vector{durationRate("250", 90), durationRate("+Inf", 100)}, nil)
+	prom.On("GetServiceRequestDurationRates", "ns", "reviews", "250", mock.AnythingOfType("string"), queryTime).Return(model.Vector{}, nil)
+
+	layer := NewWithBackends(k8s, prom, nil)
+	slos, err := layer.SLO.GetServiceSLOs("ns", "reviews", queryTime)
+	require.NoError(err)
+
+	assert.Equal("ns", slos.Namespace)
+	assert.Equal("reviews", slos.Service)
+	assert.Equal(models.SLOSourceAnnotation, slos.Source)
+	require.Len(slos.Objectives, 2)
+
+	availability := slos.Objectives[0]
+	assert.Equal(config.SLO{Name: "availability", Type: config.SLOAvailability, Target: 99, Window: "30d"}, availability.SLO)
+	assert.InDelta(99.5, *availability.SLI, 0.0001)
+	assert.InDelta(50, *availability.ErrorBudgetRemaining, 0.0001)
+	require.Len(availability.BurnRates, 3)
+	assert.InDelta(20, *availability.BurnRates[0].LongBurnRate, 0.0001)
+	assert.InDelta(20, *availability.BurnRates[0].ShortBurnRate, 0.0001)
+	assert.True(availability.BurnRates[0].Firing)
+	assert.Nil(availability.BurnRates[1].LongBurnRate)
+	assert.False(availability.BurnRates[1].Firing)
+
+	latency := slos.Objectives[1]
+	assert.Equal(config.SLO{Name: "latency", Type: config.SLOLatency, Target: 99, Threshold: 250, Window: "30d"}, latency.SLO)
+	assert.InDelta(90, *latency.SLI, 0.0001)
+	assert.InDelta(-900, *latency.ErrorBudgetRemaining, 0.0001)
+	assert.Nil(latency.BurnRates[0].LongBurnRate)
+	assert.False(latency.BurnRates[0].Firing)
+
+	// each distinct query runs once: 30d, 1h, 5m, 6h, 30m and 3d
+	prom.AssertNumberOfCalls(t, "GetServiceRequestRates", 6)
+	prom.AssertNumberOfCalls(t, "GetServiceRequestDurationRates", 6)
+}
+
+func TestServiceObjectives(t *testing.T) {
+	assert := assert.New(t)
+
+	conf := config.NewConfig()
+	conf.SLO.Window = "7d"
+	conf.SLO.Services = []config.ServiceSLOs{
+		{
+			Namespace: "bookinfo",
+			Name:      "reviews|ratings",
+			Objectives: []config.SLO{
+				{Name: "fast", Type: config.SLOLatency, Target: 95, Threshold: 100, Window: "1d"},
+				{Type: config.SLOAvailability, Target: 99.9},
+				{Type: config.SLOLatency, Target: 99},
+			},
+		},
+		{
+			Objectives: []config.SLO{{Type: config.SLOAvailability, Target: 99}},
+		},
+	}
+	config.Set(conf)
+
+	objectives, source := serviceObjectives("bookinfo", "ratings", map[string]string{})
+	assert.Equal(models.SLOSourceConfig, source)
+	// the latency objective without threshold is ignored
+	assert.Equal([]config.SLO{
+		{Name: "fast", Type: config.SLOLatency, Target: 95, Threshold: 100, Window: "1d"},
+		{Name: "availability", Type: config.SLOAvailability, Target: 99.9, Window: "7d"},
+	}, objectives)
+
+	objectives, _ = serviceObjectives("bookinfo", "details", map[string]string{})
+	assert.Equal([]config.SLO{{Name: "availability", Type: config.SLOAvailability, Target: 99, Window: "7d"}}, objectives)
+
+	// the annotation replaces the config, unless invalid
+	objectives, source = serviceObjectives("bookinfo", "ratings", map[string]string{string(models.SLOAnnotation): "availability,95,1h"})
+	assert.Equal(models.SLOSourceAnnotation, source)
+	assert.Equal([]config.SLO{{Name: "availability", Type: config.SLOAvailability, Target: 95, Window: "1h"}}, objectives)
+
+	objectives, source = serviceObjectives("bookinfo", "details", map[string]string{string(models.SLOAnnotation): "availability,100"})
+	assert.Equal(models.SLOSourceConfig, source)
+	assert.Len(objectives, 1)
+}
@@ -0,0 +1,541 @@
+package business
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	errors2 "k8s.io/apimachinery/pkg/api/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// TrafficRoutingService translates high level traffic intents of a service into its VirtualService and
+// DestinationRule
+type TrafficRoutingService struct {
+	k8s           kubernetes.ClientInterface
+	businessLayer *Layer
+}
+
+// GenerateTrafficRouting returns the VirtualService and the DestinationRule of a service implementing the intent.
+// The versions of the service are the version labels of its workloads, each routed version gets a subset in the
+// DestinationRule. The current VirtualService and DestinationRule of the service are merged with the intent, the
+// routes and subsets not concerned by the intent are kept. The DestinationRule is nil when no subset is added, e.g.
+// for a timeout. Nothing is applied.
+func (in *TrafficRoutingService) GenerateTrafficRouting(namespace, service string, intent models.TrafficIntent) (*models.TrafficRouting, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if err := validateTrafficIntent(intent); err != nil {
+		return nil, errors2.NewBadRequest(err.Error())
+	}
+
+	versions, err := in.getServiceVersions(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	if intent.Shift != nil && !versions[intent.Shift.Version] {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("service [%s] has no workload of version [%s]", service, intent.Shift.Version))
+	}
+	if intent.Mirror != nil && !versions[intent.Mirror.Version] {
+		return nil, errors2.NewBadRequest(fmt.Sprintf("service [%s] has no workload of version [%s]", service, intent.Mirror.Version))
+	}
+
+	routing, err := in.newTrafficRouting(namespace, service, versions)
+	if err != nil {
+		return nil, err
+	}
+	if err = routing.apply(intent); err != nil {
+		return nil, errors2.NewBadRequest(err.Error())
+	}
+
+	return &models.TrafficRouting{
+		VirtualService:  routing.virtualService,
+		DestinationRule: routing.changedDestinationRule(),
+	}, nil
+}
+
+// ApplyTrafficRouting generates the routing of a service implementing the intent and applies it, the VirtualService
+// and the DestinationRule are validated together and rolled back together as in a bulk apply.
+func (in *TrafficRoutingService) ApplyTrafficRouting(namespace, service string, intent models.TrafficIntent, force, dryRun bool) (*models.TrafficRouting, error) {
+	routing, err := in.GenerateTrafficRouting(namespace, service, intent)
+	if err != nil {
+		return nil, err
+	}
+	objects := []kubernetes.IstioObject{routing.VirtualService}
+	if routing.DestinationRule != nil {
+		objects = []kubernetes.IstioObject{routing.DestinationRule, routing.VirtualService}
+	}
+	body, err := json.Marshal(objects)
+	if err != nil {
+		return nil, err
+	}
+	if routing.Apply, err = in.businessLayer.IstioConfig.ApplyIstioConfigBulk(namespace, body, force, dryRun); err != nil {
+		return nil, err
+	}
+	return routing, nil
+}
+
+func validateTrafficIntent(intent models.TrafficIntent) error {
+	if intent.Shift == nil && intent.Mirror == nil && intent.Fault == nil && intent.Timeout == "" && intent.Retries == nil {
+		return fmt.Errorf("the traffic intent is empty")
+	}
+	if shift := intent.Shift; shift != nil {
+		if shift.Version == "" || shift.Weight < 0 || shift.Weight > 100 {
+			return fmt.Errorf("a traffic shift requires a version and a weight between 0 and 100")
+		}
+	}
+	if mirror := intent.Mirror; mirror != nil {
+		if mirror.Version == "" || mirror.Percentage <= 0 || mirror.Percentage > 100 {
+			return fmt.Errorf("a traffic mirror requires a version and a percentage between 0 and 100")
+		}
+	}
+	if fault := intent.Fault; fault != nil {
+		if fault.Percentage <= 0 || fault.Percentage > 100 {
+			return fmt.Errorf("a fault requires a percentage between 0 and 100")
+		}
+		if fault.HttpStatus == 0 && fault.Delay == "" {
+			return fmt.Errorf("a fault requires an http status or a delay")
+		}
+		if fault.HttpStatus != 0 && (fault.HttpStatus < 200 || fault.HttpStatus > 599) {
+			return fmt.Errorf("invalid fault http status [%d]", fault.HttpStatus)
+		}
+		if err := validateDuration("fault delay", fault.Delay); err != nil {
+			return err
+		}
+	}
+	if err := validateDuration("timeout", intent.Timeout); err != nil {
+		return err
+	}
+	if retries := intent.Retries; retries != nil {
+		if retries.Attempts <= 0 {
+			return fmt.Errorf("retries require a positive number of attempts")
+		}
+		if err := validateDuration("per try timeout", retries.PerTryTimeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDuration(name, duration string) error {
+	if duration == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(duration); err != nil || d <= 0 {
+		return fmt.Errorf("invalid %s [%s]", name, duration)
+	}
+	return nil
+}
+
+// getServiceVersions returns the values of the version label of the workloads selected by the service
+func (in *TrafficRoutingService) getServiceVersions(namespace, service string) (map[string]bool, error) {
+	svc, err := in.businessLayer.Svc.GetServiceDefinition(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	workloads, err := in.businessLayer.Workload.GetWorkloadList(namespace, false)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := map[string]bool{}
+	if len(svc.Service.Selectors) == 0 {
+		return versions, nil
+	}
+	selector := labels.Set(svc.Service.Selectors).AsSelector()
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	for _, workload := range workloads.Workloads {
+		if version, found := workload.Labels[versionLabel]; found && selector.Matches(labels.Set(workload.Labels)) {
+			versions[version] = true
+		}
+	}
+	return versions, nil
+}
+
+// newTrafficRouting returns the routing of the service between the versions, from copies of its current
+// VirtualService and DestinationRule
+func (in *TrafficRoutingService) newTrafficRouting(namespace, service string, versions map[string]bool) (*trafficRouting, error) {
+	virtualService, destinationRule, err := in.getServiceRouting(namespace, service)
+	if err != nil {
+		return nil, err
+	}
+	return &trafficRouting{
+		namespace:       namespace,
+		service:         service,
+		host:            fmt.Sprintf("%s.%s.%s", service, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain),
+		versions:        versions,
+		virtualService:  virtualService,
+		destinationRule: destinationRule,
+	}, nil
+}
+
+// getServiceRouting returns copies of the VirtualService declaring the service host and of the DestinationRule of
+// the service, nil when they don't exist. Without DestinationRule in the service namespace, the mesh wide one of the
+// Istio namespace visible from the service namespace applies.
+func (in *TrafficRoutingService) getServiceRouting(namespace, service string) (kubernetes.IstioObject, kubernetes.IstioObject, error) {
+	virtualServices, err := in.getIstioObjects(namespace, kubernetes.VirtualServices)
+	if err != nil {
+		return nil, nil, err
+	}
+	destinationRules, err := in.getIstioObjects(namespace, kubernetes.DestinationRules)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var virtualService, destinationRule kubernetes.IstioObject
+	for _, vs := range virtualServices {
+		if hosts, ok := vs.GetSpec()["hosts"].([]interface{}); ok {
+			for _, host := range hosts {
+				if h, ok := host.(string); ok && kubernetes.FilterByHost(h, service, namespace) {
+					virtualService = vs
+					break
+				}
+			}
+		}
+		if virtualService != nil {
+			break
+		}
+	}
+	if drs := kubernetes.FilterDestinationRules(destinationRules, namespace, service); len(drs) > 0 {
+		destinationRule = drs[0]
+	} else if istioNamespace := config.Get().IstioNamespace; istioNamespace != namespace {
+		meshDestinationRules, err := in.getIstioObjects(istioNamespace, kubernetes.DestinationRules)
+		// the user may not see the Istio namespace
+		if err != nil && !errors2.IsForbidden(err) {
+			return nil, nil, err
+		}
+		destinationRule = meshDestinationRule(*filterExportToNamespacesIstioObjects(namespace, &meshDestinationRules), namespace, service)
+	}
+
+	if virtualService, err = copyRoutingObject(virtualService, kubernetes.VirtualServices); err != nil {
+		return nil, nil, err
+	}
+	if destinationRule, err = copyRoutingObject(destinationRule, kubernetes.DestinationRules); err != nil {
+		return nil, nil, err
+	}
+	return virtualService, destinationRule, nil
+}
+
+// meshDestinationRule returns the DestinationRule of the Istio namespace of the service FQDN, or else of the most
+// specific wildcard host matching it, nil when none applies
+func meshDestinationRule(destinationRules []kubernetes.IstioObject, namespace, service string) kubernetes.IstioObject {
+	fqdn := fmt.Sprintf("%s.%s.%s", service, namespace, config.Get().ExternalServices.Istio.IstioIdentityDomain)
+	var wildcardRule kubernetes.IstioObject
+	wildcardHost := ""
+	for _, dr := range destinationRules {
+		host, _ := dr.GetSpec()["host"].(string)
+		if host == fqdn {
+			return dr
+		}
+		if strings.HasPrefix(host, "*") && strings.HasSuffix(fqdn, host[1:]) && (wildcardRule == nil || len(host) > len(wildcardHost)) {
+			wildcardRule = dr
+			wildcardHost = host
+		}
+	}
+	return wildcardRule
+}
+
+func (in *TrafficRoutingService) getIstioObjects(namespace, resourceType string) ([]kubernetes.IstioObject, error) {
+	if IsResourceCached(namespace, resourceType) {
+		return kialiCache.GetIstioObjects(namespace, resourceType, "")
+	}
+	return in.k8s.GetIstioObjects(namespace, resourceType, "")
+}
+
+// copyRoutingObject returns a copy of the object without its server populated fields, the spec is deep copied
+func copyRoutingObject(object kubernetes.IstioObject, resourceType string) (kubernetes.IstioObject, error) {
+	if object == nil {
+		return nil, nil
+	}
+	spec := map[string]interface{}{}
+	data, err := json.Marshal(object.GetSpec())
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	meta := object.GetObjectMeta()
+	return &kubernetes.GenericIstioObject{
+		TypeMeta: meta_v1.TypeMeta{Kind: kubernetes.PluralType[resourceType], APIVersion: kubernetes.ApiNetworkingVersion},
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        meta.Name,
+			Namespace:   meta.Namespace,
+			Labels:      meta.Labels,
+			Annotations: meta.Annotations,
+		},
+		Spec: spec,
+	}, nil
+}
+
+// trafficRouting merges traffic intents into the VirtualService and the DestinationRule of a service
+type trafficRouting struct {
+	host            string
+	namespace       string
+	service         string
+	versions        map[string]bool
+	virtualService  kubernetes.IstioObject
+	destinationRule kubernetes.IstioObject
+	// subsetsAdded is true when a subset is added to the DestinationRule
+	subsetsAdded bool
+}
+
+func (r *trafficRouting) apply(intent models.TrafficIntent) error {
+	if r.destinationRule == nil {
+		r.destinationRule = &kubernetes.GenericIstioObject{
+			TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.DestinationRuleType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: meta_v1.ObjectMeta{Name: r.service, Namespace: r.namespace},
+			Spec:       map[string]interface{}{"host": r.host},
+		}
+	} else if host, ok := r.destinationRule.GetSpec()["host"].(string); ok && r.destinationRule.GetObjectMeta().Namespace == r.namespace {
+		// the routes use the host of the current destination rule, as its subsets are defined for it
+		r.host = host
+	}
+	if r.virtualService == nil {
+		r.virtualService = &kubernetes.GenericIstioObject{
+			TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.VirtualServiceType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: meta_v1.ObjectMeta{Name: r.service, Namespace: r.namespace},
+			Spec:       map[string]interface{}{"hosts": []interface{}{r.service}},
+		}
+	}
+
+	route, _ := r.defaultRoute()
+	if intent.Shift != nil {
+		if err := r.shift(route, *intent.Shift); err != nil {
+			return err
+		}
+	}
+	if intent.Mirror != nil {
+		route["mirror"] = map[string]interface{}{"host": r.host, "subset": r.subset(intent.Mirror.Version)}
+		route["mirrorPercentage"] = map[string]interface{}{"value": intent.Mirror.Percentage}
+	}
+	if intent.Timeout != "" {
+		route["timeout"] = intent.Timeout
+	}
+	if retries := intent.Retries; retries != nil {
+		policy := map[string]interface{}{"attempts": retries.Attempts}
+		if retries.PerTryTimeout != "" {
+			policy["perTryTimeout"] = retries.PerTryTimeout
+		}
+		if retries.RetryOn != "" {
+			policy["retryOn"] = retries.RetryOn
+		}
+		route["retries"] = policy
+	}
+	if fault := intent.Fault; fault != nil {
+		faultRoute := route
+		if fault.Path != "" {
+			faultRoute = r.pathRoute(fault.Path)
+		}
+		injection := map[string]interface{}{}
+		if fault.HttpStatus != 0 {
+			injection["abort"] = map[string]interface{}{
+				"httpStatus": fault.HttpStatus,
+				"percentage": map[string]interface{}{"value": fault.Percentage},
+			}
+		}
+		if fault.Delay != "" {
+			injection["delay"] = map[string]interface{}{
+				"fixedDelay": fault.Delay,
+				"percentage": map[string]interface{}{"value": fault.Percentage},
+			}
+		}
+		faultRoute["fault"] = injection
+	}
+
+	// the mesh wide rule is not changed, the service gets its own rule, which takes precedence
+	if r.subsetsAdded && r.destinationRule.GetObjectMeta().Namespace != r.namespace {
+		spec := r.destinationRule.GetSpec()
+		spec["host"] = r.host
+		delete(spec, "exportTo")
+		r.destinationRule = &kubernetes.GenericIstioObject{
+			TypeMeta:   meta_v1.TypeMeta{Kind: kubernetes.DestinationRuleType, APIVersion: kubernetes.ApiNetworkingVersion},
+			ObjectMeta: meta_v1.ObjectMeta{Name: r.service, Namespace: r.namespace},
+			Spec:       spec,
+		}
+	}
+	return nil
+}
+
+// changedDestinationRule returns the DestinationRule when subsets were added to it, nil when it is unchanged
+func (r *trafficRouting) changedDestinationRule() kubernetes.IstioObject {
+	if !r.subsetsAdded {
+		return nil
+	}
+	return r.destinationRule
+}
+
+// changedObjects returns the objects to apply, the DestinationRule first as the routes use its subsets
+func (r *trafficRouting) changedObjects() []kubernetes.IstioObject {
+	if dr := r.changedDestinationRule(); dr != nil {
+		return []kubernetes.IstioObject{dr, r.virtualService}
+	}
+	return []kubernetes.IstioObject{r.virtualService}
+}
+
+// httpRoutes returns the http routes of the VirtualService
+func (r *trafficRouting) httpRoutes() []interface{} {
+	routes, _ := r.virtualService.GetSpec()["http"].([]interface{})
+	return routes
+}
+
+// defaultRoute returns the last http route without match conditions and its index, the route is appended when the
+// VirtualService has none
+func (r *trafficRouting) defaultRoute() (map[string]interface{}, int) {
+	routes := r.httpRoutes()
+	for i := len(routes) - 1; i >= 0; i-- {
+		if route, ok := routes[i].(map[string]interface{}); ok {
+			if _, found := route["match"]; !found {
+				return route, i
+			}
+		}
+	}
+	route := map[string]interface{}{
+		"route": []interface{}{
+			map[string]interface{}{"destination": map[string]interface{}{"host": r.host}},
+		},
+	}
+	r.virtualService.GetSpec()["http"] = append(routes, route)
+	return route, len(routes)
+}
+
+// pathRoute returns the http route matching only the path prefix. When the VirtualService has none, a route to the
+// destinations of the default route is inserted before the default route.
+func (r *trafficRouting) pathRoute(path string) map[string]interface{} {
+	defaultRoute, defaultIndex := r.defaultRoute()
+	routes := r.httpRoutes()
+	match := []interface{}{map[string]interface{}{"uri": map[string]interface{}{"prefix": path}}}
+	matchJson, _ := json.Marshal(match)
+	for _, route := range routes {
+		if route, ok := route.(map[string]interface{}); ok {
+			if routeMatchJson, err := json.Marshal(route["match"]); err == nil && string(routeMatchJson) == string(matchJson) {
+				return route
+			}
+		}
+	}
+
+	route := map[string]interface{}{"match": match, "route": defaultRoute["route"]}
+	r.virtualService.GetSpec()["http"] = append(routes[:defaultIndex], append([]interface{}{route}, routes[defaultIndex:]...)...)
+	return route
+}
+
+// subset returns the name of the DestinationRule subset of a version, the subset is added when missing
+func (r *trafficRouting) subset(version string) string {
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	spec := r.destinationRule.GetSpec()
+	subsets, _ := spec["subsets"].([]interface{})
+	for _, s := range subsets {
+		if subset, ok := s.(map[string]interface{}); ok {
+			if subsetLabels, ok := subset["labels"].(map[string]interface{}); ok && len(subsetLabels) == 1 && subsetLabels[versionLabel] == version {
+				if name, ok := subset["name"].(string); ok {
+					return name
+				}
+			}
+		}
+	}
+	spec["subsets"] = append(subsets, map[string]interface{}{
+		"name":   version,
+		"labels": map[string]interface{}{versionLabel: version},
+	})
+	r.subsetsAdded = true
+	return version
+}
+
+// subsetVersion returns the version of a DestinationRule subset, empty if the subset is not a version subset
+func (r *trafficRouting) subsetVersion(name string) string {
+	versionLabel := config.Get().IstioLabels.VersionLabelName
+	subsets, _ := r.destinationRule.GetSpec()["subsets"].([]interface{})
+	for _, s := range subsets {
+		if subset, ok := s.(map[string]interface{}); ok && subset["name"] == name {
+			if subsetLabels, ok := subset["labels"].(map[string]interface{}); ok && len(subsetLabels) == 1 {
+				version, _ := subsetLabels[versionLabel].(string)
+				return version
+			}
+		}
+	}
+	return ""
+}
+
+// shift routes the shifted weight to the version and splits the rest between the other versions, keeping their
+// current weights proportions
+func (r *trafficRouting) shift(route map[string]interface{}, shift models.TrafficShift) error {
+	others := make([]string, 0, len(r.versions))
+	for version := range r.versions {
+		if version != shift.Version {
+			others = append(others, version)
+		}
+	}
+	sort.Strings(others)
+	if len(others) == 0 && shift.Weight < 100 {
+		return fmt.Errorf("version [%s] is the only version, it can't get less than 100%% of the traffic", shift.Version)
+	}
+
+	// the current weights of the versions, the destinations without subset are ignored
+	current := map[string]float64{}
+	destinations, _ := route["route"].([]interface{})
+	for _, d := range destinations {
+		destination, _ := d.(map[string]interface{})
+		target, _ := destination["destination"].(map[string]interface{})
+		subset, _ := target["subset"].(string)
+		if version := r.subsetVersion(subset); version != "" {
+			weight, found := destination["weight"]
+			if !found && len(destinations) == 1 {
+				current[version] = 100
+			} else if w, ok := weight.(float64); ok {
+				current[version] = w
+			}
+		}
+	}
+
+	weights := shiftWeights(others, current, 100-shift.Weight)
+	weights[shift.Version] = shift.Weight
+	routed := make([]interface{}, 0, len(weights))
+	for _, version := range append([]string{shift.Version}, others...) {
+		if weights[version] > 0 {
+			routed = append(routed, map[string]interface{}{
+				"destination": map[string]interface{}{"host": r.host, "subset": r.subset(version)},
+				"weight":      weights[version],
+			})
+		}
+	}
+	route["route"] = routed
+	return nil
+}
+
+// shiftWeights splits the remaining weight between the versions, proportionally to their current weights or evenly
+// when they have no weight. The rounding remainder goes to the first weighted version.
+func shiftWeights(versions []string, current map[string]float64, remaining int) map[string]int {
+	weights := make(map[string]int, len(versions)+1)
+	if len(versions) == 0 {
+		return weights
+	}
+	total := 0.0
+	for _, version := range versions {
+		total += current[version]
+	}
+	assigned := 0
+	for _, version := range versions {
+		if total > 0 {
+			weights[version] = int(float64(remaining) * current[version] / total)
+		} else {
+			weights[version] = remaining / len(versions)
+		}
+		assigned += weights[version]
+	}
+	for _, version := range versions {
+		if total == 0 || current[version] > 0 {
+			weights[version] += remaining - assigned
+			break
+		}
+	}
+	return weights
+}
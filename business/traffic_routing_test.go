@@ -0,0 +1,193 @@
+package business
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/tests/data"
+)
+
+func TestShiftWeights(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal(map[string]int{"v1": 40, "v2": 40}, shiftWeights([]string{"v1", "v2"}, map[string]float64{}, 80))
+	assert.Equal(map[string]int{"v1": 27, "v2": 26, "v3": 26}, shiftWeights([]string{"v1", "v2", "v3"}, map[string]float64{}, 79))
+	assert.Equal(map[string]int{"v1": 60, "v2": 20}, shiftWeights([]string{"v1", "v2"}, map[string]float64{"v1": 75, "v2": 25}, 80))
+	assert.Equal(map[string]int{"v1": 0, "v2": 80}, shiftWeights([]string{"v1", "v2"}, map[string]float64{"v2": 100}, 80))
+	assert.Empty(shiftWeights([]string{}, map[string]float64{}, 0))
+}
+
+func TestValidateTrafficIntent(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Error(validateTrafficIntent(models.TrafficIntent{}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Shift: &models.TrafficShift{Version: "v3", Weight: 120}}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Mirror: &models.TrafficMirror{Version: "v2"}}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Fault: &models.TrafficFault{Percentage: 5}}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Fault: &models.TrafficFault{Percentage: 5, HttpStatus: 42}}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Timeout: "2 seconds"}))
+	assert.Error(validateTrafficIntent(models.TrafficIntent{Retries: &models.TrafficRetries{}}))
+	assert.NoError(validateTrafficIntent(models.TrafficIntent{
+		Shift:   &models.TrafficShift{Version: "v3", Weight: 20},
+		Fault:   &models.TrafficFault{Path: "/api", Percentage: 5, HttpStatus: 503},
+		Timeout: "2s",
+		Retries: &models.TrafficRetries{Attempts: 3, PerTryTimeout: "500ms"},
+	}))
+}
+
+func TestTrafficRoutingMerge(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	conf := config.NewConfig()
+	config.Set(conf)
+
+	// reviews routes 75% to v1 and 25% to v2, its destination rule keeps a traffic policy
+	vs := data.CreateEmptyVirtualService("reviews", "bookinfo", []string{"reviews"})
+	vs.GetSpec()["http"] = []interface{}{
+		map[string]interface{}{
+			"route": []interface{}{
+				map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "stable"}, "weight": 75},
+				map[string]interface{}{"destination": map[string]interface{}{"host": "reviews", "subset": "v2"}, "weight": 25},
+			},
+		},
+	}
+	dr := data.CreateEmptyDestinationRule("bookinfo", "reviews", "reviews")
+	data.AddSubsetToDestinationRule(data.CreateSubset("stable", "v1"), dr)
+	data.AddSubsetToDestinationRule(data.CreateSubset("v2", "v2"), dr)
+	data.AddTrafficPolicyToDestinationRule(data.CreateMTLSTrafficPolicyForDestinationRules(), dr)
+
+	virtualService, err := copyRoutingObject(vs, kubernetes.VirtualServices)
+	require.NoError(err)
+	destinationRule, err := copyRoutingObject(dr, kubernetes.DestinationRules)
+	require.NoError(err)
+	routing := trafficRouting{
+		namespace:       "bookinfo",
+		service:         "reviews",
+		host:            "reviews.bookinfo.svc.cluster.local",
+		versions:        map[string]bool{"v1": true, "v2": true, "v3": true},
+		virtualService:  virtualService,
+		destinationRule: destinationRule,
+	}
+	require.NoError(routing.apply(models.TrafficIntent{
+		Shift:   &models.TrafficShift{Version: "v3", Weight: 20},
+		Mirror:  &models.TrafficMirror{Version: "v2", Percentage: 10},
+		Fault:   &models.TrafficFault{Path: "/api", Percentage: 5, HttpStatus: 503},
+		Timeout: "2s",
+		Retries: &models.TrafficRetries{Attempts: 3},
+	}))
+
+	// the source objects are not modified
+	assert.Len(vs.GetSpec()["http"], 1)
+	assert.Len(dr.GetSpec()["subsets"], 2)
+
+	spec, _ := json.Marshal(routing.virtualService.GetSpec())
+	assert.JSONEq(`{
+		"hosts": ["reviews"],
+		"http": [
+			{
+				"match": [{"uri": {"prefix": "/api"}}],
+				"fault": {"abort": {"httpStatus": 503, "percentage": {"value": 5}}},
+				"route": [
+					{"destination": {"host": "reviews", "subset": "v3"}, "weight": 20},
+					{"destination": {"host": "reviews", "subset": "stable"}, "weight": 60},
+					{"destination": {"host": "reviews", "subset": "v2"}, "weight": 20}
+				]
+			},
+			{
+				"route": [
+					{"destination": {"host": "reviews", "subset": "v3"}, "weight": 20},
+					{"destination": {"host": "reviews", "subset": "stable"}, "weight": 60},
+					{"destination": {"host": "reviews", "subset": "v2"}, "weight": 20}
+				],
+				"mirror": {"host": "reviews", "subset": "v2"},
+				"mirrorPercentage": {"value": 10},
+				"timeout": "2s",
+				"retries": {"attempts": 3}
+			}
+		]
+	}`, string(spec))
+
+	require.NotNil(routing.changedDestinationRule())
+	spec, _ = json.Marshal(routing.changedDestinationRule().GetSpec())
+	assert.JSONEq(`{
+		"host": "reviews",
+		"trafficPolicy": {"tls": {"mode": "ISTIO_MUTUAL"}},
+		"subsets": [
+			{"name": "stable", "labels": {"version": "v1"}},
+			{"name": "v2", "labels": {"version": "v2"}},
+			{"name": "v3", "labels": {"version": "v3"}}
+		]
+	}`, string(spec))
+
+	// a version alone can't give away traffic
+	routing = trafficRouting{namespace: "bookinfo", service: "ratings", host: "ratings.bookinfo.svc.cluster.local", versions: map[string]bool{"v1": true}}
+	assert.Error(routing.apply(models.TrafficIntent{Shift: &models.TrafficShift{Version: "v1", Weight: 50}}))
+
+	// new objects route to the service FQDN, a timeout adds no subset: no DestinationRule
+	routing = trafficRouting{namespace: "bookinfo", service: "ratings", host: "ratings.bookinfo.svc.cluster.local", versions: map[string]bool{"v1": true}}
+	require.NoError(routing.apply(models.TrafficIntent{Timeout: "1s"}))
+	assert.Equal("ratings", routing.virtualService.GetObjectMeta().Name)
+	assert.Nil(routing.changedDestinationRule())
+	assert.Equal([]kubernetes.IstioObject{routing.virtualService}, routing.changedObjects())
+	spec, _ = json.Marshal(routing.virtualService.GetSpec())
+	assert.JSONEq(`{"hosts": ["ratings"], "http": [{"route": [{"destination": {"host": "ratings.bookinfo.svc.cluster.local"}}], "timeout": "1s"}]}`, string(spec))
+}
+
+func TestTrafficRoutingMeshDestinationRule(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	config.Set(config.NewConfig())
+
+	// the mesh wide rule of the Istio namespace defines the v1 and v2 subsets of the bookinfo services
+	meshDr := data.CreateEmptyDestinationRule("istio-system", "bookinfo", "*.bookinfo.svc.cluster.local")
+	data.AddSubsetToDestinationRule(data.CreateSubset("v1", "v1"), meshDr)
+	data.AddSubsetToDestinationRule(data.CreateSubset("v2", "v2"), meshDr)
+	otherDr := data.CreateEmptyDestinationRule("istio-system", "default", "*.local")
+	fqdnDr := data.CreateEmptyDestinationRule("istio-system", "ratings", "ratings.bookinfo.svc.cluster.local")
+	drs := []kubernetes.IstioObject{otherDr, meshDr, fqdnDr}
+	assert.Equal(meshDr, meshDestinationRule(drs, "bookinfo", "reviews"))
+	assert.Equal(fqdnDr, meshDestinationRule(drs, "bookinfo", "ratings"))
+	assert.Equal(otherDr, meshDestinationRule(drs, "travels", "cars"))
+	assert.Nil(meshDestinationRule(drs[1:], "travels", "cars"))
+
+	newRouting := func() *trafficRouting {
+		destinationRule, err := copyRoutingObject(meshDr, kubernetes.DestinationRules)
+		require.NoError(err)
+		return &trafficRouting{
+			namespace:       "bookinfo",
+			service:         "reviews",
+			host:            "reviews.bookinfo.svc.cluster.local",
+			versions:        map[string]bool{"v1": true, "v2": true, "v3": true},
+			destinationRule: destinationRule,
+		}
+	}
+
+	// the subsets are defined: the mesh wide rule is not applied
+	routing := newRouting()
+	require.NoError(routing.apply(models.TrafficIntent{Mirror: &models.TrafficMirror{Version: "v2", Percentage: 10}}))
+	assert.Nil(routing.changedDestinationRule())
+
+	// a new subset: the service gets its own rule
+	routing = newRouting()
+	require.NoError(routing.apply(models.TrafficIntent{Mirror: &models.TrafficMirror{Version: "v3", Percentage: 10}}))
+	dr := routing.changedDestinationRule()
+	require.NotNil(dr)
+	assert.Equal("reviews", dr.GetObjectMeta().Name)
+	assert.Equal("bookinfo", dr.GetObjectMeta().Namespace)
+	spec, _ := json.Marshal(dr.GetSpec())
+	assert.JSONEq(`{
+		"host": "reviews.bookinfo.svc.cluster.local",
+		"subsets": [
+			{"name": "v1", "labels": {"version": "v1"}},
+			{"name": "v2", "labels": {"version": "v2"}},
+			{"name": "v3", "labels": {"version": "v3"}}
+		]
+	}`, string(spec))
+	assert.Len(meshDr.GetSpec()["subsets"], 2)
+}
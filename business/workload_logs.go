@@ -0,0 +1,332 @@
+package business
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// Log stream event names
+const (
+	LogEventEntries = "logs"  // data: PodLog, the new entries
+	LogEventError   = "error" // data: LogStreamError
+)
+
+// maxLogLineSize is the longest followed log line, e.g. a stack trace logged as a single line
+const maxLogLineSize = 1024 * 1024
+
+// LogStreamError is the data of a LogEventError
+type LogStreamError struct {
+	Error string `json:"error"`
+}
+
+// LogFilter selects the log entries on the server side
+type LogFilter struct {
+	// Regexp matches the entry message, nil for all
+	Regexp *regexp.Regexp
+	// Severities are the accepted severities (ERROR, WARN, INFO, DEBUG, TRACE), empty for all
+	Severities map[string]bool
+}
+
+func (f LogFilter) matches(entry LogEntry) bool {
+	if len(f.Severities) > 0 && !f.Severities[entry.Severity] {
+		return false
+	}
+	return f.Regexp == nil || f.Regexp.MatchString(entry.Message)
+}
+
+// logSource is a container of a pod
+type logSource struct {
+	pod       string
+	container string
+	isProxy   bool
+}
+
+// logSources returns the containers of the pods, the proxy included
+func logSources(pods models.Pods) []logSource {
+	sources := []logSource{}
+	for _, pod := range pods {
+		for _, c := range pod.Containers {
+			sources = append(sources, logSource{pod: pod.Name, container: c.Name})
+		}
+		for _, c := range pod.IstioContainers {
+			sources = append(sources, logSource{pod: pod.Name, container: c.Name, isProxy: c.Name == "istio-proxy"})
+		}
+	}
+	return sources
+}
+
+// GetWorkloadLogsPods returns the pods of a workload, whose logs are aggregated
+func (in *WorkloadService) GetWorkloadLogsPods(namespace, workloadName, workloadType string) (models.Pods, error) {
+	// Check if user has access to the namespace (RBAC) in cache scenarios and/or
+	// if namespace is accessible from Kiali (Deployment.AccessibleNamespaces)
+	if _, err := in.businessLayer.Namespace.GetNamespace(namespace); err != nil {
+		return nil, err
+	}
+	workload, err := fetchWorkload(in.businessLayer, namespace, workloadName, workloadType)
+	if err != nil {
+		return nil, err
+	}
+	return workload.Pods, nil
+}
+
+// GetAppLogsPods returns the pods of an app, whose logs are aggregated
+func (in *AppService) GetAppLogsPods(namespace, app string) (models.Pods, error) {
+	selector := labels.Set{config.Get().IstioLabels.AppLabelName: app}.String()
+	pods, err := in.businessLayer.Workload.GetPods(namespace, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, kubernetes.NewNotFound(app, "Kiali", "App")
+	}
+	return pods, nil
+}
+
+// GetAggregatedLogs returns the logs of all the containers of the pods, the proxy included, merged in timestamp
+// order. Each entry is tagged with its pod and container.
+func (in *WorkloadService) GetAggregatedLogs(namespace string, pods models.Pods, opts *LogOptions, filter LogFilter) (*PodLog, error) {
+	sources := logSources(pods)
+
+	sourcesEntries := make([][]LogEntry, len(sources))
+	wg := sync.WaitGroup{}
+	wg.Add(len(sources))
+	errChan := make(chan error, len(sources))
+	for i, source := range sources {
+		go func(i int, source logSource) {
+			defer wg.Done()
+			sourceOpts := *opts
+			sourceOpts.Container = source.container
+			sourceOpts.IsProxy = source.isProxy
+			podLog, err := in.getParsedLogs(namespace, source.pod, &sourceOpts)
+			if err != nil {
+				// e.g. a container waiting to start, the other containers logs are still returned
+				log.Debugf("Skipping the logs of container [%s] of pod [%s/%s]: %v", source.container, namespace, source.pod, err)
+				errChan <- err
+				return
+			}
+			for _, entry := range podLog.Entries {
+				if filter.matches(entry) {
+					entry.Pod = source.pod
+					entry.Container = source.container
+					sourcesEntries[i] = append(sourcesEntries[i], entry)
+				}
+			}
+		}(i, source)
+	}
+	wg.Wait()
+	if len(sources) > 0 && len(errChan) == len(sources) {
+		return nil, <-errChan
+	}
+
+	entries := []LogEntry{}
+	for _, sourceEntries := range sourcesEntries {
+		entries = append(entries, sourceEntries...)
+	}
+	// stable, the entries of a container sharing a timestamp keep their order
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].TimestampUnix < entries[j].TimestampUnix
+	})
+	if opts.TailLines != nil && len(entries) > int(*opts.TailLines) {
+		entries = entries[len(entries)-int(*opts.TailLines):]
+	}
+	return &PodLog{Entries: entries}, nil
+}
+
+// LogCursor is the position of a followed log stream: the last second sent, by the Kubernetes log times, and the
+// number of entries of that second sent for each pod/container. The log API has a second precision, the entries of
+// that second already sent are skipped when following from the cursor. It is the id of the LogEventEntries events, a
+// reconnecting client sends it back as the Last-Event-ID.
+type LogCursor struct {
+	TimestampUnix int64
+	// Sent counts the entries by "pod/container"
+	Sent map[string]int
+}
+
+// NewLogCursor returns the cursor after the sent entries, at the given time when there is none
+func NewLogCursor(now time.Time, sent []LogEntry) LogCursor {
+	cursor := LogCursor{TimestampUnix: now.Unix(), Sent: map[string]int{}}
+	if len(sent) > 0 {
+		cursor.TimestampUnix = 0
+	}
+	for _, entry := range sent {
+		cursor.add(entry)
+	}
+	return cursor
+}
+
+// ParseLogCursor parses a cursor formatted by LogCursor.String
+func ParseLogCursor(value string) (LogCursor, error) {
+	fields := strings.Split(value, ";")
+	ts, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return LogCursor{}, fmt.Errorf("invalid log cursor [%s]", value)
+	}
+	cursor := LogCursor{TimestampUnix: ts, Sent: map[string]int{}}
+	for _, field := range fields[1:] {
+		i := strings.LastIndex(field, "=")
+		if i < 0 {
+			return LogCursor{}, fmt.Errorf("invalid log cursor [%s]", value)
+		}
+		n, err := strconv.Atoi(field[i+1:])
+		if err != nil || n < 0 {
+			return LogCursor{}, fmt.Errorf("invalid log cursor [%s]", value)
+		}
+		cursor.Sent[field[:i]] = n
+	}
+	return cursor, nil
+}
+
+// String formats the cursor as "<timestamp>;<pod>/<container>=<count>;...", the pod and container names are DNS
+// labels
+func (c LogCursor) String() string {
+	keys := make([]string, 0, len(c.Sent))
+	for key := range c.Sent {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(strconv.FormatInt(c.TimestampUnix, 10))
+	for _, key := range keys {
+		fmt.Fprintf(&b, ";%s=%d", key, c.Sent[key])
+	}
+	return b.String()
+}
+
+// add moves the cursor after a sent entry
+func (c *LogCursor) add(entry LogEntry) {
+	if entry.k8sTimestampUnix > c.TimestampUnix {
+		c.TimestampUnix = entry.k8sTimestampUnix
+		c.Sent = map[string]int{}
+	}
+	if entry.k8sTimestampUnix == c.TimestampUnix {
+		c.Sent[logSourceKey(entry.Pod, entry.Container)]++
+	}
+}
+
+// FollowLogs follows the logs of all the containers of the pods from the cursor, in a Kubernetes log stream per
+// container. Every interval the new entries are sent to the client as a LogEventEntries, in timestamp order, even
+// when empty, letting the client know the logs are current. The events id is the cursor after their entries. A
+// container which logs can't be followed, e.g. one waiting to start, is sent as a LogEventError, without id, and the
+// other containers are still followed. It returns when the context is done or a send fails.
+func (in *WorkloadService) FollowLogs(ctx context.Context, interval time.Duration, namespace string, pods models.Pods, from LogCursor, filter LogFilter, send func(event, id string, data interface{}) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the streams restart at the cursor second: its entries already sent are the first ones of each container
+	skip := map[string]int{}
+	cursor := LogCursor{TimestampUnix: from.TimestampUnix, Sent: map[string]int{}}
+	for key, n := range from.Sent {
+		skip[key] = n
+		cursor.Sent[key] = n
+	}
+
+	since := time.Unix(from.TimestampUnix, 0)
+	entriesChan := make(chan LogEntry)
+	errChan := make(chan error)
+	for _, source := range logSources(pods) {
+		go in.followLogSource(ctx, namespace, source, since, filter, entriesChan, errChan)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	entries := []LogEntry{}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-entriesChan:
+			key := logSourceKey(entry.Pod, entry.Container)
+			if entry.k8sTimestampUnix == from.TimestampUnix && skip[key] > 0 {
+				skip[key]--
+				continue
+			}
+			entries = append(entries, entry)
+		case err := <-errChan:
+			if err := send(LogEventError, "", LogStreamError{Error: err.Error()}); err != nil {
+				return err
+			}
+		case <-ticker.C:
+			// stable, the entries of a container sharing a timestamp keep their order
+			sort.SliceStable(entries, func(i, j int) bool {
+				return entries[i].TimestampUnix < entries[j].TimestampUnix
+			})
+			for _, entry := range entries {
+				cursor.add(entry)
+			}
+			if err := send(LogEventEntries, cursor.String(), PodLog{Entries: entries}); err != nil {
+				return err
+			}
+			entries = []LogEntry{}
+		}
+	}
+}
+
+// followLogSource streams the logs of a container to the entries channel until the context is done or the stream
+// ends, e.g. when the container is terminated
+func (in *WorkloadService) followLogSource(ctx context.Context, namespace string, source logSource, since time.Time, filter LogFilter, entriesChan chan<- LogEntry, errChan chan<- error) {
+	opts := core_v1.PodLogOptions{
+		Container:  source.container,
+		Follow:     true,
+		Timestamps: true,
+		SinceTime:  &meta_v1.Time{Time: since},
+	}
+	stream, err := in.k8s.StreamPodLogs(namespace, source.pod, &opts)
+	if err != nil {
+		log.Debugf("Log stream failed to follow the logs of container [%s] of pod [%s/%s]: %v", source.container, namespace, source.pod, err)
+		select {
+		case errChan <- fmt.Errorf("container [%s] of pod [%s]: %v", source.container, source.pod, err):
+		case <-ctx.Done():
+		}
+		return
+	}
+	// closing the stream unblocks the scanner
+	go func() {
+		<-ctx.Done()
+		stream.Close()
+	}()
+
+	scanner := bufio.NewScanner(stream)
+	scanner.Buffer(make([]byte, 64*1024), maxLogLineSize)
+	for scanner.Scan() {
+		entry, _, ok := parseLogLine(scanner.Text(), source.isProxy)
+		if !ok || !filter.matches(entry) {
+			continue
+		}
+		entry.Pod = source.pod
+		entry.Container = source.container
+		select {
+		case entriesChan <- entry:
+		case <-ctx.Done():
+			return
+		}
+	}
+	// the client is told the container is no longer followed, e.g. on a line over the maximum size
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		log.Debugf("Log stream of container [%s] of pod [%s/%s] ended: %v", source.container, namespace, source.pod, err)
+		select {
+		case errChan <- fmt.Errorf("container [%s] of pod [%s]: %v", source.container, source.pod, err):
+		case <-ctx.Done():
+		}
+	}
+}
+
+func logSourceKey(pod, container string) string {
+	return pod + "/" + container
+}
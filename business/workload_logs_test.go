@@ -0,0 +1,199 @@
+package business
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/models"
+)
+
+func TestGetAggregatedLogs(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	container := func(name string) interface{} {
+		return mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool { return opts.Container == name })
+	}
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("GetPodLogs", "bookinfo", "reviews-v1-1", container("reviews")).Return(&kubernetes.PodLogs{
+		Logs: "2021-03-01T10:00:01Z INFO starting reviews\n2021-03-01T10:00:04Z ERROR request failed",
+	}, nil)
+	k8s.On("GetPodLogs", "bookinfo", "reviews-v1-1", container("istio-proxy")).Return(&kubernetes.PodLogs{
+		Logs: "2021-03-01T10:00:02Z WARN envoy config rejected",
+	}, nil)
+	k8s.On("GetPodLogs", "bookinfo", "reviews-v1-2", mock.Anything).Return((*kubernetes.PodLogs)(nil), errors.New("container is waiting to start"))
+
+	svc := setupWorkloadService(k8s)
+	pods := models.Pods{
+		{Name: "reviews-v1-1", Containers: []*models.ContainerInfo{{Name: "reviews"}}, IstioContainers: []*models.ContainerInfo{{Name: "istio-proxy"}}},
+		{Name: "reviews-v1-2", Containers: []*models.ContainerInfo{{Name: "reviews"}}},
+	}
+
+	podLog, err := svc.GetAggregatedLogs("bookinfo", pods, &LogOptions{}, LogFilter{})
+	require.NoError(t, err)
+	assert.Len(podLog.Entries, 3)
+	for i := 1; i < len(podLog.Entries); i++ {
+		assert.True(podLog.Entries[i-1].TimestampUnix <= podLog.Entries[i].TimestampUnix)
+	}
+	for _, entry := range podLog.Entries {
+		assert.Equal("reviews-v1-1", entry.Pod)
+	}
+	assert.Equal("istio-proxy", podLog.Entries[1].Container)
+	assert.Equal("WARN", podLog.Entries[1].Severity)
+
+	// filtered, then tailed
+	tailLines := int64(1)
+	podLog, err = svc.GetAggregatedLogs("bookinfo", pods, &LogOptions{PodLogOptions: core_v1.PodLogOptions{TailLines: &tailLines}}, LogFilter{Regexp: regexp.MustCompile("reviews")})
+	require.NoError(t, err)
+	assert.Len(podLog.Entries, 1)
+	assert.Equal("INFO starting reviews", podLog.Entries[0].Message)
+
+	// all the containers failing
+	_, err = svc.GetAggregatedLogs("bookinfo", pods[1:], &LogOptions{}, LogFilter{})
+	assert.Error(err)
+}
+
+func TestLogFilter(t *testing.T) {
+	assert := assert.New(t)
+
+	entry := LogEntry{Message: "GET /reviews/0 503 UH", Severity: "ERROR"}
+	assert.True(LogFilter{}.matches(entry))
+	assert.True(LogFilter{Regexp: regexp.MustCompile(`50\d`), Severities: map[string]bool{"ERROR": true}}.matches(entry))
+	assert.False(LogFilter{Regexp: regexp.MustCompile(`^POST`)}.matches(entry))
+	assert.False(LogFilter{Severities: map[string]bool{"WARN": true, "INFO": true}}.matches(entry))
+}
+
+func TestFollowLogs(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	since := time.Date(2021, 3, 1, 10, 0, 11, 0, time.UTC)
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("StreamPodLogs", "bookinfo", "reviews-v1-1", mock.MatchedBy(func(opts *core_v1.PodLogOptions) bool {
+		return opts.Container == "reviews" && opts.Follow && opts.SinceTime.Time.Equal(since)
+	})).Return(ioutil.NopCloser(strings.NewReader(
+		// the since time second is streamed again
+		"2021-03-01T10:00:11Z INFO b\n2021-03-01T10:00:11Z INFO c\n2021-03-01T10:00:12Z ERROR d\n2021-03-01T10:00:13Z DEBUG e\n",
+	)), nil)
+	k8s.On("StreamPodLogs", "bookinfo", "reviews-v1-1", mock.Anything).Return(ioutil.NopCloser(strings.NewReader("")), errors.New("container is waiting to start"))
+
+	svc := setupWorkloadService(k8s)
+	pods := models.Pods{
+		{Name: "reviews-v1-1", Containers: []*models.ContainerInfo{{Name: "reviews"}}, IstioContainers: []*models.ContainerInfo{{Name: "istio-proxy"}}},
+	}
+	// "INFO b" was sent
+	cursor := LogCursor{TimestampUnix: since.Unix(), Sent: map[string]int{"reviews-v1-1/reviews": 1}}
+	filter := LogFilter{Severities: map[string]bool{"INFO": true, "ERROR": true}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errorEvents := 0
+	messages := []string{}
+	lastID := ""
+	err := svc.FollowLogs(ctx, time.Millisecond, "bookinfo", pods, cursor, filter, func(event, id string, data interface{}) error {
+		switch event {
+		case LogEventError:
+			errorEvents++
+			assert.Empty(id)
+		case LogEventEntries:
+			lastID = id
+			for _, entry := range data.(PodLog).Entries {
+				assert.Equal("reviews", entry.Container)
+				messages = append(messages, entry.Message)
+			}
+		}
+		if errorEvents == 1 && len(messages) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal(1, errorEvents)
+	assert.Equal([]string{"INFO c", "ERROR d"}, messages)
+	assert.Equal(fmt.Sprintf("%d;reviews-v1-1/reviews=1", since.Unix()+1), lastID)
+}
+
+func TestLogCursor(t *testing.T) {
+	assert := assert.New(t)
+
+	now := time.Date(2021, 3, 1, 10, 0, 20, 0, time.UTC)
+	assert.Equal(LogCursor{TimestampUnix: now.Unix(), Sent: map[string]int{}}, NewLogCursor(now, nil))
+
+	cursor := NewLogCursor(now, []LogEntry{
+		{Pod: "reviews-v1-1", Container: "reviews", k8sTimestampUnix: 10},
+		{Pod: "reviews-v1-1", Container: "reviews", k8sTimestampUnix: 11},
+		{Pod: "reviews-v1-1", Container: "istio-proxy", k8sTimestampUnix: 11},
+		{Pod: "reviews-v1-1", Container: "reviews", k8sTimestampUnix: 11},
+	})
+	assert.Equal("11;reviews-v1-1/istio-proxy=1;reviews-v1-1/reviews=2", cursor.String())
+
+	parsed, err := ParseLogCursor(cursor.String())
+	assert.NoError(err)
+	assert.Equal(cursor, parsed)
+
+	for _, invalid := range []string{"", "abc", "11;reviews-v1-1/reviews", "11;reviews-v1-1/reviews=x"} {
+		_, err := ParseLogCursor(invalid)
+		assert.Error(err, invalid)
+	}
+}
+
+type failingLogStream struct{}
+
+func (failingLogStream) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestFollowLogsLongLineAndStreamError(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	long := strings.Repeat("x", 100*1024)
+	k8s := new(kubetest.K8SClientMock)
+	k8s.On("IsOpenShift").Return(false)
+	k8s.On("StreamPodLogs", "bookinfo", "reviews-v1-1", mock.Anything).Return(ioutil.NopCloser(io.MultiReader(
+		strings.NewReader("2021-03-01T10:00:12Z INFO "+long+"\n"),
+		failingLogStream{},
+	)), nil)
+
+	svc := setupWorkloadService(k8s)
+	pods := models.Pods{{Name: "reviews-v1-1", Containers: []*models.ContainerInfo{{Name: "reviews"}}}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	errorMessages := []string{}
+	messages := []string{}
+	err := svc.FollowLogs(ctx, time.Millisecond, "bookinfo", pods, LogCursor{TimestampUnix: 1614592811}, LogFilter{}, func(event, id string, data interface{}) error {
+		switch event {
+		case LogEventError:
+			errorMessages = append(errorMessages, data.(LogStreamError).Error)
+		case LogEventEntries:
+			for _, entry := range data.(PodLog).Entries {
+				messages = append(messages, entry.Message)
+			}
+		}
+		if len(errorMessages) == 1 && len(messages) == 1 {
+			cancel()
+		}
+		return nil
+	})
+	assert.NoError(err)
+	assert.Equal([]string{"INFO " + long}, messages)
+	assert.Equal([]string{"container [reviews] of pod [reviews-v1-1]: connection reset"}, errorMessages)
+}
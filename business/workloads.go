@@ -51,6 +51,11 @@ type LogEntry struct {
 	Timestamp     string            `json:"timestamp,omitempty"`
 	TimestampUnix int64             `json:"timestampUnix,omitempty"`
 	AccessLog     *parser.AccessLog `json:"accessLog,omitempty"`
+	// Pod and Container are set for the logs aggregated from several containers
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	// k8sTimestampUnix is the Kubernetes log time, which positions the entry in the log stream
+	k8sTimestampUnix int64
 }
 
 // LogOptions holds query parameter values
@@ -325,102 +330,25 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	lines := strings.Split(podLog.Logs, "\n")
 	entries := make([]LogEntry, 0)
 
-	var startTime *time.Time
 	var endTime *time.Time
-	if k8sOpts.SinceTime != nil {
-		startTime = &k8sOpts.SinceTime.Time
-	}
-
 	for _, line := range lines {
-		entry := LogEntry{
-			Message:       "",
-			Timestamp:     "",
-			TimestampUnix: 0,
-			Severity:      "INFO",
-		}
-
-		splitted := strings.SplitN(line, " ", 2)
-		if len(splitted) != 2 {
-			log.Debugf("Skipping unexpected log line [%s]", line)
-			continue
-		}
-
-		// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
-		splittedTimestamp := strings.Split(splitted[0], ".")
-		if len(splittedTimestamp) == 1 {
-			entry.Timestamp = splittedTimestamp[0]
-		} else {
-			entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
-		}
-
-		entry.Message = strings.TrimSpace(splitted[1])
-		if entry.Message == "" {
-			log.Debugf("Skipping empty log line [%s]", line)
+		entry, k8sTimestamp, ok := parseLogLine(line, opts.IsProxy)
+		if !ok {
 			continue
 		}
 
 		// If we are past the requested time window then stop processing
-		parsedTimestamp, err := time.Parse(time.RFC3339, entry.Timestamp)
-		if err == nil {
-			if startTime == nil {
-				startTime = &parsedTimestamp
-			}
-
-			if isBounded {
-				if endTime == nil {
-					end := parsedTimestamp.Add(*opts.Duration)
-					endTime = &end
-				}
-
-				if parsedTimestamp.After(*endTime) {
-					break
-				}
+		if isBounded {
+			if endTime == nil {
+				end := k8sTimestamp.Add(*opts.Duration)
+				endTime = &end
 			}
-		} else {
-			log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
-			continue
-		}
-
-		severity := severityRegexp.FindString(line)
-		if severity != "" {
-			entry.Severity = strings.ToUpper(severity)
-		}
-
-		// If this is an istio access log, then parse it out. Prefer the access log time over the k8s time
-		// as it is the actual time as opposed to the k8s store time.
-		if opts.IsProxy {
-			engardeParser := parser.New(parser.IstioProxyAccessLogsPattern)
-			al, err := engardeParser.Parse(entry.Message)
-			if err == nil {
-				entry.AccessLog = al
-				t, err := time.Parse(time.RFC3339, al.Timestamp)
-				if err == nil {
-					parsedTimestamp = t
-				}
 
-				// clear accessLog fields we don't need in the returned JSON
-				entry.AccessLog.MixerStatus = ""
-				entry.AccessLog.OriginalMessage = ""
-				entry.AccessLog.ParseError = ""
-			} else {
-				log.Debugf("AccessLog parse failure: %s", err.Error())
-				// try to parse out the time manually
-				tokens := strings.SplitN(entry.Message, " ", 2)
-				timestampToken := strings.Trim(tokens[0], "[]")
-				t, err := time.Parse(time.RFC3339, timestampToken)
-				if err == nil {
-					parsedTimestamp = t
-				}
+			if k8sTimestamp.After(*endTime) {
+				break
 			}
 		}
 
-		// override the timestamp with a simpler format
-		timestamp := fmt.Sprintf("%d-%02d-%02d %02d:%02d:%02d",
-			parsedTimestamp.Year(), parsedTimestamp.Month(), parsedTimestamp.Day(),
-			parsedTimestamp.Hour(), parsedTimestamp.Minute(), parsedTimestamp.Second())
-		entry.Timestamp = timestamp
-		entry.TimestampUnix = parsedTimestamp.Unix()
-
 		entries = append(entries, entry)
 	}
 
@@ -435,6 +363,86 @@ func (in *WorkloadService) getParsedLogs(namespace, name string, opts *LogOption
 	return &message, err
 }
 
+// parseLogLine parses a "<k8s timestamp> <message>" log line, ok is false for an empty or unexpected line. The entry
+// timestamp is the access log time for the proxy, the k8s timestamp is also returned.
+func parseLogLine(line string, isProxy bool) (LogEntry, time.Time, bool) {
+	entry := LogEntry{
+		Message:       "",
+		Timestamp:     "",
+		TimestampUnix: 0,
+		Severity:      "INFO",
+	}
+
+	splitted := strings.SplitN(line, " ", 2)
+	if len(splitted) != 2 {
+		log.Debugf("Skipping unexpected log line [%s]", line)
+		return LogEntry{}, time.Time{}, false
+	}
+
+	// k8s promises RFC3339 or RFC3339Nano timestamp, ensure RFC3339
+	splittedTimestamp := strings.Split(splitted[0], ".")
+	if len(splittedTimestamp) == 1 {
+		entry.Timestamp = splittedTimestamp[0]
+	} else {
+		entry.Timestamp = fmt.Sprintf("%sZ", splittedTimestamp[0])
+	}
+
+	entry.Message = strings.TrimSpace(splitted[1])
+	if entry.Message == "" {
+		log.Debugf("Skipping empty log line [%s]", line)
+		return LogEntry{}, time.Time{}, false
+	}
+
+	parsedTimestamp, err := time.Parse(time.RFC3339, entry.Timestamp)
+	if err != nil {
+		log.Debugf("Failed to parse log timestamp (skipping) [%s], %s", entry.Timestamp, err.Error())
+		return LogEntry{}, time.Time{}, false
+	}
+	k8sTimestamp := parsedTimestamp
+
+	severity := severityRegexp.FindString(line)
+	if severity != "" {
+		entry.Severity = strings.ToUpper(severity)
+	}
+
+	// If this is an istio access log, then parse it out. Prefer the access log time over the k8s time
+	// as it is the actual time as opposed to the k8s store time.
+	if isProxy {
+		engardeParser := parser.New(parser.IstioProxyAccessLogsPattern)
+		al, err := engardeParser.Parse(entry.Message)
+		if err == nil {
+			entry.AccessLog = al
+			t, err := time.Parse(time.RFC3339, al.Timestamp)
+			if err == nil {
+				parsedTimestamp = t
+			}
+
+			// clear accessLog fields we don't need in the returned JSON
+			entry.AccessLog.MixerStatus = ""
+			entry.AccessLog.OriginalMessage = ""
+			entry.AccessLog.ParseError = ""
+		} else {
+			log.Debugf("AccessLog parse failure: %s", err.Error())
+			// try to parse out the time manually
+			tokens := strings.SplitN(entry.Message, " ", 2)
+			timestampToken := strings.Trim(tokens[0], "[]")
+			t, err := time.Parse(time.RFC3339, timestampToken)
+			if err == nil {
+				parsedTimestamp = t
+			}
+		}
+	}
+
+	// override the timestamp with a simpler format
+	timestamp := fmt.Sprintf("%d-%02d-%02d %02d:%02d:%02d",
+		parsedTimestamp.Year(), parsedTimestamp.Month(), parsedTimestamp.Day(),
+		parsedTimestamp.Hour(), parsedTimestamp.Minute(), parsedTimestamp.Second())
+	entry.Timestamp = timestamp
+	entry.TimestampUnix = parsedTimestamp.Unix()
+	entry.k8sTimestampUnix = k8sTimestamp.Unix()
+	return entry, k8sTimestamp, true
+}
+
 // GetPodLogs returns pod logs given the provided options
 func (in *WorkloadService) GetPodLogs(namespace, name string, opts *LogOptions) (*PodLog, error) {
 	return in.getParsedLogs(namespace, name, opts)
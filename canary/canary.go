@@ -0,0 +1,156 @@
+// Package canary runs the canary controller in the background: the progressing canary rollouts of the services
+// are checked on a schedule, and advanced to their next step, promoted or rolled back. The rollouts are stored in
+// the VirtualServices of the services, so a restart resumes them.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/audit"
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+// ControllerUser is the user of the audit records of the rollout steps performed by the controller
+const ControllerUser = "kiali-canary-controller"
+
+// leaseName is the Lease, in the Kiali deployment namespace, electing the replica running the controller
+const leaseName = "kiali-canary-controller"
+
+var (
+	controllerMutex sync.Mutex
+	stopController  context.CancelFunc
+)
+
+// Start starts the canary controller, when it is enabled and Kiali is not in view only mode. The rollouts are
+// advanced with the Kiali service account, by the replica holding the controller Lease.
+func Start() {
+	conf := config.Get()
+	if !conf.Canary.Enabled {
+		return
+	}
+	if conf.Deployment.ViewOnlyMode {
+		log.Warning("Canary controller is not started: Kiali is in view only mode")
+		return
+	}
+	if conf.Canary.Interval <= 0 {
+		log.Errorf("Canary controller is not started: invalid interval [%d], it must be positive", conf.Canary.Interval)
+		return
+	}
+	lock, err := kubernetes.NewLeaseLock(conf.Deployment.Namespace, leaseName)
+	if err != nil {
+		log.Errorf("Canary controller is not started: %v", err)
+		return
+	}
+
+	controllerMutex.Lock()
+	defer controllerMutex.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	stopController = cancel
+	interval := time.Duration(conf.Canary.Interval) * time.Second
+	go kubernetes.RunLeader(ctx, "Canary controller", lock, func(leading context.Context) {
+		Run(leading.Done(), interval, serviceAccountLayer)
+	})
+	log.Infof("Canary controller started, checking the rollouts every [%ds] when holding the Lease [%s/%s]", conf.Canary.Interval, conf.Deployment.Namespace, leaseName)
+}
+
+// Stop stops the canary controller and releases its Lease, the progressing rollouts are resumed on the next start
+func Stop() {
+	controllerMutex.Lock()
+	defer controllerMutex.Unlock()
+	if stopController != nil {
+		stopController()
+		stopController = nil
+	}
+}
+
+func serviceAccountLayer() (*business.Layer, error) {
+	token, err := kubernetes.GetKialiToken()
+	if err != nil {
+		return nil, err
+	}
+	return business.Get(&api.AuthInfo{Token: token})
+}
+
+// Run advances the rollouts every interval, until stop is closed
+func Run(stop <-chan struct{}, interval time.Duration, getLayer func() (*business.Layer, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	Advance(getLayer)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			Advance(getLayer)
+		}
+	}
+}
+
+// Advance advances the progressing rollouts of the namespaces accessible to Kiali. A rollout that can't be advanced
+// is left unchanged, it is checked again on the next run.
+func Advance(getLayer func() (*business.Layer, error)) {
+	layer, err := getLayer()
+	if err != nil {
+		log.Errorf("Canary controller: unable to initialize the business layer: %v", err)
+		return
+	}
+	namespaces, err := layer.Namespace.GetNamespaces()
+	if err != nil {
+		log.Errorf("Canary controller: unable to list the namespaces: %v", err)
+		return
+	}
+
+	now := util.Clock.Now()
+	for _, ns := range namespaces {
+		rollouts, err := layer.Canary.GetCanaryRollouts(ns.Name)
+		if err != nil {
+			log.Warningf("Canary controller: unable to get the rollouts of namespace [%s]: %v", ns.Name, err)
+			continue
+		}
+		for i := range rollouts {
+			rollout := &rollouts[i]
+			if rollout.Status.Phase != models.CanaryProgressing {
+				continue
+			}
+			advanced, updated, err := layer.Canary.AdvanceCanaryRollout(ns.Name, rollout.Service, now)
+			if err != nil {
+				log.Warningf("Canary controller: unable to advance the rollout of service [%s/%s]: %v", ns.Name, rollout.Service, err)
+				continue
+			}
+			if updated {
+				recordStep(rollout, advanced)
+			}
+		}
+	}
+}
+
+// recordStep records the update of a rollout by the controller in the audit trail
+func recordStep(before, after *models.CanaryRollout) {
+	record := models.AuditRecord{
+		User:       ControllerUser,
+		Operation:  models.AuditUpdate,
+		Namespace:  after.Namespace,
+		ObjectType: "canaryrollouts",
+		Name:       after.Service,
+		Patch:      after.Status.Message,
+	}
+	var err error
+	if record.Before, err = json.Marshal(before); err != nil {
+		log.Debugf("Audited canary rollout state not available: %v", err)
+	}
+	if record.After, err = json.Marshal(after); err != nil {
+		log.Debugf("Audited canary rollout state not available: %v", err)
+	}
+	audit.Record(record)
+}
@@ -67,6 +67,12 @@ const (
 	DashboardsDiscoveryAuto    = "auto"
 )
 
+// The supported tracing backends
+const (
+	TracingProviderJaeger = "jaeger"
+	TracingProviderTempo  = "tempo"
+)
+
 // Global configuration for the application.
 var configuration Config
 var rwMutex sync.RWMutex
@@ -88,6 +94,65 @@ type Server struct {
 	WebSchema                  string `yaml:"web_schema,omitempty"`
 }
 
+// AuditConfig describes configuration for keeping the trail of the write operations performed through the API
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Maximum number of records kept, the oldest are removed when exceeded. 0 means no limit.
+	MaxRecords int `yaml:"max_records,omitempty"`
+	// File holding the records when using the filesystem store
+	Path string `yaml:"path,omitempty"`
+	// Audit store: filesystem | memory
+	Store string `yaml:"store,omitempty"`
+}
+
+// CanaryConfig describes configuration for the canary controller, advancing the progressing canary rollouts. The
+// controller patches the VirtualServices and DestinationRules of the rollouts with the Kiali service account, and
+// only runs in the replica holding the kiali-canary-controller Lease of the Kiali deployment namespace: the service
+// account requires write access to the routing of the services and to the Lease. It never runs in view only mode.
+type CanaryConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval (in seconds) between the checks of the progressing rollouts. Each rollout step is held for the
+	// interval of the rollout, the steps are checked at least this often.
+	Interval int `yaml:"interval,omitempty"`
+}
+
+// Health alert sink types
+const (
+	AlertSinkAlertmanager = "alertmanager"
+	AlertSinkSlack        = "slack"
+	AlertSinkWebhook      = "webhook"
+)
+
+// AlertSinkConfig describes a destination of the health alert notifications
+type AlertSinkConfig struct {
+	Auth Auth `yaml:"auth,omitempty"`
+	// Minimum health status notified: degraded | failure (default: degraded)
+	MinStatus string `yaml:"min_status,omitempty"`
+	Name      string `yaml:"name"`
+	// Sink type: webhook | slack | alertmanager
+	Type string `yaml:"type"`
+	// URL of the webhook, or the Alertmanager base URL (e.g. http://alertmanager:9093)
+	URL string `yaml:"url"`
+}
+
+// AlertingConfig describes configuration for watching the health in the background and notifying its transitions.
+// The health is only evaluated in the replica holding the kiali-health-alerting Lease of the Kiali deployment
+// namespace, which keeps the alerts and their history: the service account requires write access to the Lease.
+type AlertingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Maximum number of health transitions kept in the history
+	HistorySize int `yaml:"history_size,omitempty"`
+	// Interval (in seconds) between health evaluations
+	Interval int `yaml:"interval,omitempty"`
+	// Namespaces watched, all the namespaces accessible to Kiali when empty
+	Namespaces []string `yaml:"namespaces,omitempty"`
+	// Rate interval of the request error rates, e.g. 5m
+	RateInterval string `yaml:"rate_interval,omitempty"`
+	// Interval (in seconds) between the notifications of an alert whose status does not change. 0 means no repeat.
+	RepeatInterval int               `yaml:"repeat_interval,omitempty"`
+	Sinks          []AlertSinkConfig `yaml:"sinks,omitempty"`
+}
+
 // Auth provides authentication data for external services
 type Auth struct {
 	CAFile             string `yaml:"ca_file"`
@@ -161,11 +226,81 @@ type TracingConfig struct {
 	InClusterURL         string   `yaml:"in_cluster_url"`
 	IsCore               bool     `yaml:"is_core,omitempty"`
 	NamespaceSelector    bool     `yaml:"namespace_selector"`
+	Provider             string   `yaml:"provider,omitempty"` // Tracing backend: jaeger | tempo. UseGRPC applies only to jaeger.
 	URL                  string   `yaml:"url"`
 	UseGRPC              bool     `yaml:"use_grpc"`
 	WhiteListIstioSystem []string `yaml:"whitelist_istio_system"`
 }
 
+// GraphFederationClusterConfig describes the Prometheus of a remote cluster whose telemetry is federated into the
+// graph. The cluster must be part of the mesh, as discovered from the Istio remote secrets (see the mesh clusters).
+type GraphFederationClusterConfig struct {
+	// Cluster name, the CLUSTER_ID as known by the Control Plane
+	Name       string           `yaml:"name"`
+	Prometheus PrometheusConfig `yaml:"prometheus,omitempty"`
+}
+
+// GraphFederationConfig describes configuration for merging the traffic of the remote clusters of the mesh into the
+// graph. The remote clusters are discovered from the Istio remote secrets, only those with a configured Prometheus
+// are federated.
+type GraphFederationConfig struct {
+	Clusters []GraphFederationClusterConfig `yaml:"clusters,omitempty"`
+	Enabled  bool                           `yaml:"enabled"`
+}
+
+// GraphSnapshotsConfig describes configuration for persisting graph snapshots. Snapshots are disabled by default,
+// the filesystem store requires an explicitly configured path, e.g. on a persistent volume.
+type GraphSnapshotsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Maximum number of snapshots kept, the oldest are removed when exceeded. 0 means no limit.
+	MaxSnapshots int `yaml:"max_snapshots,omitempty"`
+	// Directory holding the snapshots when using the filesystem store, required by that store
+	Path string `yaml:"path,omitempty"`
+	// Snapshot store: filesystem | memory
+	Store string `yaml:"store,omitempty"`
+}
+
+// GraphSpanMetricsConfig describes the OpenTelemetry span-metrics series used by the "otel" graph telemetry
+// vendor. The defaults match the collector's spanmetrics connector, the legacy spanmetrics processor
+// instead reports "calls_total" and "latency" and has no metric namespace.
+type GraphSpanMetricsConfig struct {
+	// Counter of spans, e.g. traces_spanmetrics_calls_total or calls_total
+	CallsMetric string `yaml:"calls_metric,omitempty"`
+	// Optional label holding the gRPC status code of the span
+	GrpcStatusCodeLabel string `yaml:"grpc_status_code_label,omitempty"`
+	// Optional label holding the HTTP status code of the span
+	HTTPStatusCodeLabel string `yaml:"http_status_code_label,omitempty"`
+	// Span duration histogram in milliseconds, without the _bucket/_sum/_count suffix
+	LatencyMetric string `yaml:"latency_metric,omitempty"`
+	// Label holding the namespace of the reporting service
+	NamespaceLabel string `yaml:"namespace_label,omitempty"`
+	// Label holding the called service on client spans (requires the peer.service dimension)
+	PeerServiceLabel string `yaml:"peer_service_label,omitempty"`
+	// Label holding the reporting service
+	ServiceLabel string `yaml:"service_label,omitempty"`
+	// Optional label holding the version of the reporting service
+	ServiceVersionLabel string `yaml:"service_version_label,omitempty"`
+	SpanKindLabel       string `yaml:"span_kind_label,omitempty"`
+	StatusCodeLabel     string `yaml:"status_code_label,omitempty"`
+}
+
+// GraphStreamingConfig describes configuration for streaming live graph updates
+type GraphStreamingConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Minimum interval (in seconds) a client can request between graph updates
+	MinRefreshInterval int `yaml:"min_refresh_interval,omitempty"`
+	// Default interval (in seconds) between graph updates
+	RefreshInterval int `yaml:"refresh_interval,omitempty"`
+}
+
+// GraphConfig describes configuration for graph features that are not UI defaults
+type GraphConfig struct {
+	Federation  GraphFederationConfig  `yaml:"federation,omitempty"`
+	Snapshots   GraphSnapshotsConfig   `yaml:"snapshots,omitempty"`
+	SpanMetrics GraphSpanMetricsConfig `yaml:"span_metrics,omitempty"`
+	Streaming   GraphStreamingConfig   `yaml:"streaming,omitempty"`
+}
+
 // IstioConfig describes configuration used for istio links
 type IstioConfig struct {
 	ComponentStatuses                 ComponentStatuses   `yaml:"component_status,omitempty"`
@@ -348,9 +483,38 @@ type UIDefaults struct {
 	RefreshInterval   string          `yaml:"refresh_interval,omitempty" json:"refreshInterval,omitempty"`
 }
 
+// ValidationPolicy is a user-defined validation rule, evaluated against each Istio object of the
+// matching kinds in the matching namespaces.
+type ValidationPolicy struct {
+	// Code of the reported check, e.g. ORG0001. It can be ignored like the built-in check codes.
+	Code string `yaml:"code"`
+	// Expression that is true for valid objects. CEL variables: object (the Istio object, as in its
+	// yaml) and ns (its namespace, with name and labels). namespace is a reserved word in CEL.
+	Expression string `yaml:"expression"`
+	// Istio object types, e.g. virtualservice or destinationrule. Empty matches all types.
+	Kinds []string `yaml:"kinds,omitempty"`
+	// Language of the expression: cel (default)
+	Language string `yaml:"language,omitempty"`
+	Message  string `yaml:"message"`
+	// Regular expression (full match) of the namespaces the policy applies to. Empty matches all namespaces.
+	Namespaces string `yaml:"namespaces,omitempty"`
+	// Path of the check in the object yaml, e.g. spec/http
+	Path string `yaml:"path,omitempty"`
+	// Severity of the reported check: error | warning (default)
+	Severity string `yaml:"severity,omitempty"`
+}
+
+// ValidationPolicies defines the user-defined validation rules
+type ValidationPolicies struct {
+	// Optional ConfigMap in the Kiali deployment namespace, each data key holding a yaml list of policies
+	ConfigMapName string             `yaml:"config_map_name,omitempty"`
+	Rules         []ValidationPolicy `yaml:"rules,omitempty"`
+}
+
 // Validations defines default settings configured for the Validations subsystem
 type Validations struct {
-	Ignore []string `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+	Ignore   []string           `yaml:"ignore,omitempty" json:"ignore,omitempty"`
+	Policies ValidationPolicies `yaml:"policies,omitempty" json:"-"`
 }
 
 // KialiFeatureFlags available from the CR
@@ -383,15 +547,59 @@ type HealthConfig struct {
 	Rate []Rate `yaml:"rate,omitempty" json:"rate,omitempty"`
 }
 
+// SLO types
+const (
+	SLOAvailability = "availability"
+	SLOLatency      = "latency"
+)
+
+// SLO config, a service level objective: the percentage of good requests over a rolling window. Good requests
+// are the requests not failing for an availability objective, and the requests served within the threshold for
+// a latency objective.
+type SLO struct {
+	Name      string  `yaml:"name,omitempty" json:"name"`
+	Target    float64 `yaml:"target" json:"target"`
+	Threshold float64 `yaml:"threshold,omitempty" json:"threshold,omitempty"` // milliseconds, an Istio request duration bucket
+	Type      string  `yaml:"type" json:"type"`                               // availability | latency
+	Window    string  `yaml:"window,omitempty" json:"window"`                 // default SLOConfig.Window
+}
+
+// ServiceSLOs config, the objectives of the services matching the namespace and name regular expressions
+type ServiceSLOs struct {
+	Namespace  string `yaml:"namespace,omitempty" json:"namespace,omitempty"`
+	Name       string `yaml:"name,omitempty" json:"name,omitempty"`
+	Objectives []SLO  `yaml:"objectives" json:"objectives"`
+}
+
+// BurnRateWindow config, a multi-window burn rate alert: it fires when the error budget burn rates over both the
+// long and the short windows reach the threshold
+type BurnRateWindow struct {
+	Long      string  `yaml:"long" json:"long"`
+	Short     string  `yaml:"short" json:"short"`
+	Threshold float64 `yaml:"threshold" json:"threshold"`
+}
+
+// SLOConfig describes the service level objectives and how their error budget burn is tracked. The objectives
+// of a service can also be set with the slo.kiali.io/objectives annotation, replacing the configured ones.
+type SLOConfig struct {
+	BurnRateWindows []BurnRateWindow `yaml:"burn_rate_windows,omitempty"`
+	Services        []ServiceSLOs    `yaml:"services,omitempty"`
+	Window          string           `yaml:"window,omitempty"` // default rolling window
+}
+
 // Config defines full YAML configuration.
 type Config struct {
 	AdditionalDisplayDetails []AdditionalDisplayItem             `yaml:"additional_display_details,omitempty"`
+	Alerting                 AlertingConfig                      `yaml:"alerting,omitempty"`
 	API                      ApiConfig                           `yaml:"api,omitempty"`
+	Audit                    AuditConfig                         `yaml:"audit,omitempty"`
 	Auth                     AuthConfig                          `yaml:"auth,omitempty"`
+	Canary                   CanaryConfig                        `yaml:"canary,omitempty"`
 	CustomDashboards         dashboards.MonitoringDashboardsList `yaml:"custom_dashboards,omitempty"`
 	Deployment               DeploymentConfig                    `yaml:"deployment,omitempty"`
 	Extensions               Extensions                          `yaml:"extensions,omitempty"`
 	ExternalServices         ExternalServices                    `yaml:"external_services,omitempty"`
+	Graph                    GraphConfig                         `yaml:"graph,omitempty"`
 	HealthConfig             HealthConfig                        `yaml:"health_config,omitempty" json:"healthConfig,omitempty"`
 	Identity                 security.Identity                   `yaml:",omitempty"`
 	InCluster                bool                                `yaml:"in_cluster,omitempty"`
@@ -402,6 +610,7 @@ type Config struct {
 	KubernetesConfig         KubernetesConfig                    `yaml:"kubernetes_config,omitempty"`
 	LoginToken               LoginToken                          `yaml:"login_token,omitempty"`
 	Server                   Server                              `yaml:",omitempty"`
+	SLO                      SLOConfig                           `yaml:"slo,omitempty"`
 }
 
 // NewConfig creates a default Config struct
@@ -409,6 +618,15 @@ func NewConfig() (c *Config) {
 	c = &Config{
 		InCluster:      true,
 		IstioNamespace: "istio-system",
+		Alerting: AlertingConfig{
+			Enabled:        false,
+			HistorySize:    500,
+			Interval:       60,
+			Namespaces:     []string{},
+			RateInterval:   "5m",
+			RepeatInterval: 3600,
+			Sinks:          []AlertSinkConfig{},
+		},
 		API: ApiConfig{
 			Namespaces: ApiNamespacesConfig{
 				Exclude: []string{
@@ -420,6 +638,12 @@ func NewConfig() (c *Config) {
 				},
 			},
 		},
+		Audit: AuditConfig{
+			Enabled:    true,
+			MaxRecords: 10000,
+			Path:       "/tmp/kiali/audit/records.jsonl",
+			Store:      "memory",
+		},
 		Auth: AuthConfig{
 			Strategy: "token",
 			OpenId: OpenIdConfig{
@@ -441,6 +665,10 @@ func NewConfig() (c *Config) {
 				ClientIdPrefix: "kiali",
 			},
 		},
+		Canary: CanaryConfig{
+			Enabled:  false,
+			Interval: 30,
+		},
 		CustomDashboards: dashboards.GetBuiltInMonitoringDashboards(),
 		Deployment: DeploymentConfig{
 			AccessibleNamespaces: []string{"**"},
@@ -518,11 +746,41 @@ func NewConfig() (c *Config) {
 				InClusterURL:         "http://tracing.istio-system:16685/jaeger",
 				IsCore:               false,
 				NamespaceSelector:    true,
+				Provider:             TracingProviderJaeger,
 				URL:                  "",
 				UseGRPC:              true,
 				WhiteListIstioSystem: []string{"jaeger-query", "istio-ingressgateway"},
 			},
 		},
+		Graph: GraphConfig{
+			Federation: GraphFederationConfig{
+				Clusters: []GraphFederationClusterConfig{},
+				Enabled:  false,
+			},
+			Snapshots: GraphSnapshotsConfig{
+				Enabled:      false,
+				MaxSnapshots: 100,
+				Path:         "",
+				Store:        "filesystem",
+			},
+			SpanMetrics: GraphSpanMetricsConfig{
+				CallsMetric:         "traces_spanmetrics_calls_total",
+				GrpcStatusCodeLabel: "rpc_grpc_status_code",
+				HTTPStatusCodeLabel: "http_status_code",
+				LatencyMetric:       "traces_spanmetrics_duration_milliseconds",
+				NamespaceLabel:      "service_namespace",
+				PeerServiceLabel:    "peer_service",
+				ServiceLabel:        "service_name",
+				ServiceVersionLabel: "service_version",
+				SpanKindLabel:       "span_kind",
+				StatusCodeLabel:     "status_code",
+			},
+			Streaming: GraphStreamingConfig{
+				Enabled:            true,
+				MinRefreshInterval: 5,
+				RefreshInterval:    15,
+			},
+		},
 		IstioLabels: IstioLabels{
 			AppLabelName:       "app",
 			InjectionLabelName: "istio-injection",
@@ -598,6 +856,15 @@ func NewConfig() (c *Config) {
 			WebHistoryMode:             "browser",
 			WebSchema:                  "",
 		},
+		SLO: SLOConfig{
+			BurnRateWindows: []BurnRateWindow{
+				{Long: "1h", Short: "5m", Threshold: 14.4},
+				{Long: "6h", Short: "30m", Threshold: 6},
+				{Long: "3d", Short: "6h", Threshold: 1},
+			},
+			Services: []ServiceSLOs{},
+			Window:   "30d",
+		},
 	}
 
 	return
@@ -667,6 +934,16 @@ func (conf Config) String() (str string) {
 	obf.ExternalServices.Grafana.Auth.Obfuscate()
 	obf.ExternalServices.Prometheus.Auth.Obfuscate()
 	obf.ExternalServices.Tracing.Auth.Obfuscate()
+	obf.Alerting.Sinks = make([]AlertSinkConfig, len(conf.Alerting.Sinks))
+	for i, sink := range conf.Alerting.Sinks {
+		sink.Auth.Obfuscate()
+		obf.Alerting.Sinks[i] = sink
+	}
+	obf.Graph.Federation.Clusters = make([]GraphFederationClusterConfig, len(conf.Graph.Federation.Clusters))
+	for i, cluster := range conf.Graph.Federation.Clusters {
+		cluster.Prometheus.Auth.Obfuscate()
+		obf.Graph.Federation.Clusters[i] = cluster
+	}
 	obf.Identity.Obfuscate()
 	obf.LoginToken.Obfuscate()
 	obf.Auth.OpenId.ClientSecret = "xxx"
@@ -70,6 +70,8 @@ func TestSensitiveDataObfuscation(t *testing.T) {
 	conf.ExternalServices.Tracing.Auth.Username = "my-username"
 	conf.ExternalServices.Tracing.Auth.Password = "my-password"
 	conf.ExternalServices.Tracing.Auth.Token = "my-token"
+	conf.Alerting.Sinks = []AlertSinkConfig{{Name: "slack", Type: AlertSinkSlack, Auth: Auth{Token: "my-token"}}}
+	conf.Graph.Federation.Clusters = []GraphFederationClusterConfig{{Name: "east", Prometheus: PrometheusConfig{Auth: Auth{Password: "my-password"}}}}
 	conf.LoginToken.SigningKey = "my-signkey"
 	conf.LoginToken.ExpirationSeconds = 12345
 
@@ -85,6 +87,8 @@ func TestSensitiveDataObfuscation(t *testing.T) {
 	assert.Equal(t, "my-username", conf.ExternalServices.Grafana.Auth.Username)
 	assert.Equal(t, "my-password", conf.ExternalServices.Prometheus.Auth.Password)
 	assert.Equal(t, "my-token", conf.ExternalServices.Tracing.Auth.Token)
+	assert.Equal(t, "my-token", conf.Alerting.Sinks[0].Auth.Token)
+	assert.Equal(t, "my-password", conf.Graph.Federation.Clusters[0].Prometheus.Auth.Password)
 	assert.Equal(t, "my-signkey", conf.LoginToken.SigningKey)
 }
 
@@ -4,7 +4,10 @@ import (
 	jaegerModels "github.com/jaegertracing/jaeger/model/json"
 
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph/analysis"
 	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/snapshot"
+	"github.com/kiali/kiali/graph/stream"
 	"github.com/kiali/kiali/handlers"
 	"github.com/kiali/kiali/jaeger"
 	"github.com/kiali/kiali/models"
@@ -34,7 +37,7 @@ type AggregateValueParam struct {
 	Name string `json:"aggregateValue"`
 }
 
-// swagger:parameters appMetrics appDetails graphApp graphAppVersion appDashboard appSpans appTraces errorTraces
+// swagger:parameters appMetrics appDetails appLogs graphApp graphAppVersion appDashboard appSpans appTraces appTracesAnalysis errorTraces
 type AppParam struct {
 	// The app name (label value).
 	//
@@ -61,6 +64,34 @@ type ClusterParam struct {
 	Name string `json:"container"`
 }
 
+// swagger:parameters podProxyDumpDiff
+type ConfigDumpDiffPodParam struct {
+	// The pod to compare with, either pod or snapshot is required.
+	//
+	// in: query
+	// required: false
+	Name string `json:"pod"`
+}
+
+// swagger:parameters podProxyDumpDiff
+type ConfigDumpDiffNamespaceParam struct {
+	// The namespace of the pod to compare with.
+	//
+	// in: query
+	// required: false
+	// default: the namespace of the pod
+	Name string `json:"namespace"`
+}
+
+// swagger:parameters podProxyDumpDiff
+type ConfigDumpDiffSnapshotParam struct {
+	// The ID of the stored dump to compare with, either pod or snapshot is required.
+	//
+	// in: query
+	// required: false
+	Name string `json:"snapshot"`
+}
+
 // swagger:parameters podLogs
 type ContainerParam struct {
 	// The pod container name. Optional for single-container pod. Otherwise required.
@@ -70,7 +101,7 @@ type ContainerParam struct {
 	Name string `json:"container"`
 }
 
-// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceUpdate appSpans serviceSpans workloadSpans appTraces serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource
+// swagger:parameters istioConfigList workloadList workloadDetails workloadUpdate serviceDetails serviceSLOs serviceUpdate appSpans serviceSpans workloadSpans appTraces appTracesAnalysis serviceTraces workloadTraces errorTraces workloadValidations appList serviceMetrics aggregateMetrics appMetrics workloadMetrics istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype serviceList appDetails graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespace graphService graphWorkload namespaceMetrics customDashboard appDashboard serviceDashboard workloadDashboard istioConfigCreate istioConfigCreateSubtype namespaceUpdate namespaceTls podDetails podLogs namespaceValidations workloadAccessLogSummary workloadLogs appLogs getIter8Experiments postIter8Experiments patchIter8Experiments deleteIter8Experiments podProxyDump podProxyResource podProxyDumpDiff podProxyDumpSnapshots podProxyDumpSnapshotCreate istioConfigRevisions istioConfigRevisionsDiff istioConfigRevisionRestore istioConfigBulkApply serviceTrafficRouting canaryRollout canaryRolloutStart canaryRolloutAbort
 type NamespaceParam struct {
 	// The namespace name.
 	//
@@ -88,7 +119,7 @@ type NameParam struct {
 	Name string `json:"name"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigRevisions istioConfigRevisionsDiff istioConfigRevisionRestore
 type ObjectNameParam struct {
 	// The Istio object name.
 	//
@@ -97,7 +128,7 @@ type ObjectNameParam struct {
 	Name string `json:"object"`
 }
 
-// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype
+// swagger:parameters istioConfigDetails istioConfigDetailsSubtype istioConfigDelete istioConfigDeleteSubtype istioConfigUpdate istioConfigUpdateSubtype istioConfigCreate istioConfigCreateSubtype istioConfigRevisions istioConfigRevisionsDiff istioConfigRevisionRestore
 type ObjectTypeParam struct {
 	// The Istio object type.
 	//
@@ -107,7 +138,74 @@ type ObjectTypeParam struct {
 	Name string `json:"object_type"`
 }
 
-// swagger:parameters podDetails podLogs podProxyDump podProxyResource
+// swagger:parameters istioConfigUpdate istioConfigCreate istioConfigBulkApply serviceTrafficRouting
+type DryRunParam struct {
+	// Validate the change, on the Istio objects and on the objects related to them, without applying it.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"dryRun"`
+}
+
+// swagger:parameters istioConfigRevisionRestore
+type RevisionParam struct {
+	// The revision number.
+	//
+	// in: path
+	// required: true
+	Name int `json:"revision"`
+}
+
+// swagger:parameters istioConfigRevisionRestore istioConfigBulkApply serviceTrafficRouting canaryRolloutStart
+type ForceParam struct {
+	// Apply the revision, or the Istio objects, even if it introduces validation errors.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"force"`
+}
+
+// swagger:parameters serviceTrafficRouting
+type ApplyParam struct {
+	// Apply the generated routing.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"apply"`
+}
+
+// Posted traffic intent of a service
+// swagger:parameters serviceTrafficRouting
+type TrafficIntentBody struct {
+	// in: body
+	Body models.TrafficIntent
+}
+
+// Posted canary rollout of a service
+// swagger:parameters canaryRolloutStart
+type CanarySpecBody struct {
+	// in: body
+	Body models.CanarySpec
+}
+
+// swagger:parameters istioConfigRevisionsDiff
+type RevisionsDiffParams struct {
+	// The compared revision.
+	//
+	// in: query
+	// required: true
+	From int `json:"from"`
+	// The revision compared with, the current object when not set.
+	//
+	// in: query
+	// required: false
+	To int `json:"to"`
+}
+
+// swagger:parameters podDetails podLogs podProxyDump podProxyResource podProxyDumpDiff podProxyDumpSnapshots podProxyDumpSnapshotCreate
 type PodParam struct {
 	// The pod name.
 	//
@@ -116,6 +214,62 @@ type PodParam struct {
 	Name string `json:"pod"`
 }
 
+// swagger:parameters alerts alertsHistory
+type AlertsNamespacesParam struct {
+	// Comma-separated list of namespaces to filter the alerts. The namespaces not accessible to the client are ignored.
+	//
+	// in: query
+	// required: false
+	// default: all the namespaces accessible to the client
+	Name string `json:"namespaces"`
+}
+
+// swagger:parameters auditRecords
+type AuditParams struct {
+	// Comma-separated list of namespaces to filter the records. The namespaces not accessible to the client are ignored.
+	//
+	// in: query
+	// required: false
+	// default: all the namespaces accessible to the client
+	Namespaces string `json:"namespaces"`
+	// The user performing the operations
+	//
+	// in: query
+	// required: false
+	User string `json:"user"`
+	// The operation: create, update, delete or restore
+	//
+	// in: query
+	// required: false
+	Operation string `json:"operation"`
+	// The object type, e.g. virtualservices, workloads, services, namespaces or experiments
+	//
+	// in: query
+	// required: false
+	ObjectType string `json:"objectType"`
+	// The object name
+	//
+	// in: query
+	// required: false
+	Name string `json:"name"`
+	// The oldest records returned, RFC 3339 time
+	//
+	// in: query
+	// required: false
+	From string `json:"from"`
+	// The most recent records returned, RFC 3339 time
+	//
+	// in: query
+	// required: false
+	To string `json:"to"`
+	// The maximum number of records returned
+	//
+	// in: query
+	// required: false
+	// default: no limit
+	Limit int `json:"limit"`
+}
+
 // swagger:parameters podProxyResource
 type ResourceParam struct {
 	// The discovery service resource
@@ -125,7 +279,7 @@ type ResourceParam struct {
 	Name string `json:"resource"`
 }
 
-// swagger:parameters serviceDetails serviceUpdate serviceMetrics graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces
+// swagger:parameters serviceDetails serviceUpdate serviceMetrics serviceSLOs graphService graphAggregateByService serviceDashboard serviceSpans serviceTraces serviceTrafficRouting canaryRollout canaryRolloutStart canaryRolloutAbort
 type ServiceParam struct {
 	// The service name.
 	//
@@ -134,7 +288,7 @@ type ServiceParam struct {
 	Name string `json:"service"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadAccessLogSummary workloadLogs appLogs
 type SinceTimeParam struct {
 	// The start time for fetching logs. UNIX time in seconds. Default is all logs.
 	//
@@ -143,17 +297,18 @@ type SinceTimeParam struct {
 	Name string `json:"sinceTime"`
 }
 
-// swagger:parameters podLogs
+// swagger:parameters podLogs workloadAccessLogSummary workloadLogs appLogs
 type DurationLogParam struct {
 	// Query time-range duration (Golang string duration). Duration starts on
 	// `sinceTime` if set, or the time for the first log message if not set.
+	// For the access log summary, without `sinceTime` the window is the last duration, 10m by default.
 	//
 	// in: query
 	// required: false
 	Name string `json:"duration"`
 }
 
-// swagger:parameters traceDetails
+// swagger:parameters graphTrace traceDetails tracesComparison
 type TraceIDParam struct {
 	// The trace ID.
 	//
@@ -162,6 +317,15 @@ type TraceIDParam struct {
 	Name string `json:"traceID"`
 }
 
+// swagger:parameters tracesComparison
+type BaseTraceIDParam struct {
+	// The ID of the base trace, e.g. a typical trace of the same operation.
+	//
+	// in: path
+	// required: true
+	Name string `json:"baseTraceID"`
+}
+
 // swagger:parameters customDashboard
 type DashboardParam struct {
 	// The dashboard resource name.
@@ -171,7 +335,7 @@ type DashboardParam struct {
 	Name string `json:"dashboard"`
 }
 
-// swagger:parameters workloadDetails workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
+// swagger:parameters workloadDetails workloadAccessLogSummary workloadLogs workloadUpdate workloadValidations workloadMetrics graphWorkload workloadDashboard workloadSpans workloadTraces
 type WorkloadParam struct {
 	// The workload name.
 	//
@@ -180,22 +344,117 @@ type WorkloadParam struct {
 	Name string `json:"workload"`
 }
 
+// swagger:parameters workloadDetails
+type ProxyChecksParam struct {
+	// When present, the proxy config of the workload pods is checked against the Istio config.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"proxyChecks"`
+}
+
+// swagger:parameters workloadLogs appLogs
+type LogFilterParam struct {
+	// A regular expression matching the messages of the returned log entries.
+	//
+	// in: query
+	// required: false
+	Name string `json:"filter"`
+}
+
+// swagger:parameters workloadLogs appLogs
+type LogSeverityParam struct {
+	// The comma separated severities of the returned log entries (ERROR, WARN, INFO, DEBUG, TRACE). Default is all.
+	//
+	// in: query
+	// required: false
+	Name string `json:"severity"`
+}
+
+// swagger:parameters workloadLogs appLogs
+type LogFollowParam struct {
+	// When true, the logs are streamed as server-sent events: "logs" events with the new entries every 2 seconds.
+	// The containers of the pods of the request are followed, the client reconnects to follow new pods.
+	//
+	// in: query
+	// required: false
+	Name bool `json:"follow"`
+}
+
+// swagger:parameters workloadLogs appLogs
+type LogLastEventIDParam struct {
+	// In follow mode, the id of the last "logs" event received: a reconnecting client resumes the stream after it,
+	// without the initial fetch.
+	//
+	// in: header
+	// required: false
+	Name string `json:"Last-Event-ID"`
+}
+
+// swagger:parameters workloadLogs appLogs
+type LogTailLinesParam struct {
+	// The number of last lines of each container logs, then of the merged logs. Default is all.
+	//
+	// in: query
+	// required: false
+	Name int `json:"tailLines"`
+}
+
+// swagger:parameters workloadAccessLogSummary
+type AccessLogLimitParam struct {
+	// The maximum number of items of each access log aggregation. Default is 10.
+	//
+	// in: query
+	// required: false
+	Name int `json:"limit"`
+}
+
 /////////////////////
 // SWAGGER PARAMETERS - GRAPH
 // - keep this alphabetized
 /////////////////////
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type AppendersParam struct {
-	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
+	// Comma-separated list of Appenders to run. Available appenders: [aggregateNode, anomaly, deadNode, healthConfig, idleNode, istio, responseTime, securityPolicy, serviceEntry, sidecarsCheck, throughput].
 	//
 	// in: query
 	// required: false
-	// default: run all appenders
+	// default: run all appenders, except anomaly
 	Name string `json:"appenders"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
+type AnomalyBaselineParam struct {
+	// Used only with anomaly appender. The duration of the baseline window (e.g. 24h, 1h).
+	//
+	// in: query
+	// required: false
+	// default: 24h
+	Name string `json:"anomalyBaseline"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
+type AnomalyOffsetParam struct {
+	// Used only with anomaly appender. The baseline window ends this duration before queryTime (e.g. 7d for the same time last week).
+	//
+	// in: query
+	// required: false
+	// default: the graph duration
+	Name string `json:"anomalyOffset"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
+type AnomalyThresholdParam struct {
+	// Used only with anomaly appender. The z-score magnitude from which a metric is anomalous.
+	//
+	// in: query
+	// required: false
+	// default: 3
+	Name string `json:"anomalyThreshold"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphNamespaces graphNamespacesStream graphService graphSnapshot graphSnapshotCreate graphTrace graphWorkload
 type BoxByParam struct {
 	// Comma-separated list of desired node boxing. Available boxings: [app, cluster, namespace, none].
 	//
@@ -205,7 +464,17 @@ type BoxByParam struct {
 	Name string `json:"boxBy"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphAggregate graphAggregateByService graphApp graphAppVersion graphNamespaces graphService graphSnapshot graphSnapshotCreate graphTrace graphWorkload
+type ConfigVendorParam struct {
+	// Graph config vendor. Available vendors: [cytoscape, dot, graphml, mermaid].
+	//
+	// in: query
+	// required: false
+	// default: cytoscape
+	Name string `json:"configVendor"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type DurationGraphParam struct {
 	// Query time-range duration (Golang string duration).
 	//
@@ -215,7 +484,7 @@ type DurationGraphParam struct {
 	Name string `json:"duration"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphTrace graphWorkload
 type GraphTypeParam struct {
 	// Graph type. Available graph types: [app, service, versionedApp, workload].
 	//
@@ -225,7 +494,7 @@ type GraphTypeParam struct {
 	Name string `json:"graphType"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphSnapshotCreate graphWorkload
 type IncludeIdleEdges struct {
 	// Flag for including edges that have no request traffic for the time period.
 	//
@@ -235,7 +504,7 @@ type IncludeIdleEdges struct {
 	Name string `json:"includeIdleEdges"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphSnapshotCreate graphTrace graphWorkload
 type InjectServiceNodes struct {
 	// Flag for injecting the requested service node between source and destination nodes.
 	//
@@ -245,7 +514,7 @@ type InjectServiceNodes struct {
 	Name string `json:"injectServiceNodes"`
 }
 
-// swagger:parameters graphNamespaces
+// swagger:parameters graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphSnapshotCreate
 type NamespacesParam struct {
 	// Comma-separated list of namespaces to include in the graph. The namespaces must be accessible to the client.
 	//
@@ -254,7 +523,61 @@ type NamespacesParam struct {
 	Name string `json:"namespaces"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphSnapshotCreate
+type SnapshotNameParam struct {
+	// Optional, descriptive name for the snapshot.
+	//
+	// in: query
+	// required: false
+	Name string `json:"name"`
+}
+
+// swagger:parameters graphSnapshot graphSnapshotDelete graphSnapshotDiff
+type SnapshotParam struct {
+	// The graph snapshot ID.
+	//
+	// in: path
+	// required: true
+	Name string `json:"snapshot"`
+}
+
+// swagger:parameters graphSnapshotDiff
+type ToSnapshotParam struct {
+	// The graph snapshot ID to compare against.
+	//
+	// in: path
+	// required: true
+	Name string `json:"toSnapshot"`
+}
+
+// swagger:parameters graphBlastRadius graphHealthDrivers
+type AnalysisNodeParam struct {
+	// The cytoscape (or traffic map) ID of the analyzed node.
+	//
+	// in: query
+	// required: true
+	Name string `json:"node"`
+}
+
+// swagger:parameters graphCriticalPath
+type FromNodeParam struct {
+	// The cytoscape (or traffic map) ID of the path's first node. Default is any traffic generator (i.e. root) node.
+	//
+	// in: query
+	// required: false
+	Name string `json:"from"`
+}
+
+// swagger:parameters graphCriticalPath
+type ToNodeParam struct {
+	// The cytoscape (or traffic map) ID of the path's last node.
+	//
+	// in: query
+	// required: true
+	Name string `json:"to"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphService graphSnapshotCreate graphWorkload
 type QueryTimeParam struct {
 	// Unix time (seconds) for query such that time range is [queryTime-duration..queryTime]. Default is now.
 	//
@@ -264,7 +587,7 @@ type QueryTimeParam struct {
 	Name string `json:"queryTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type RateGrpcParam struct {
 	// How to calculate gRPC traffic rate. One of: none | received (i.e. response_messages) | requests | sent (i.e. request_messages) | total (i.e. sent+received).
 	//
@@ -274,7 +597,7 @@ type RateGrpcParam struct {
 	Name string `json:"rateGrpc"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type RateHttpParam struct {
 	// How to calculate HTTP traffic rate. One of: none | requests.
 	//
@@ -284,7 +607,7 @@ type RateHttpParam struct {
 	Name string `json:"rateHttp"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type RateTcpParam struct {
 	// How to calculate TCP traffic rate. One of: none | received (i.e. received_bytes) | sent (i.e. sent_bytes) | total (i.e. sent+received).
 	//
@@ -294,7 +617,17 @@ type RateTcpParam struct {
 	Name string `json:"rateTcp"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphNamespacesStream
+type RefreshIntervalParam struct {
+	// Duration between graph updates, at least the configured minimum.
+	//
+	// in: query
+	// required: false
+	// default: the configured refresh interval
+	Name string `json:"refreshInterval"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type ResponseTimeParam struct {
 	// Used only with responseTime appender. One of: avg | 50 | 95 | 99.
 	//
@@ -304,7 +637,17 @@ type ResponseTimeParam struct {
 	Name string `json:"responseTime"`
 }
 
-// swagger:parameters graphApp graphAppVersion graphNamespaces graphService graphWorkload
+// swagger:parameters graphAggregate graphAggregateByService graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
+type TelemetryVendorParam struct {
+	// Graph telemetry vendor. Available vendors: [istio, otel].
+	//
+	// in: query
+	// required: false
+	// default: istio
+	Name string `json:"telemetryVendor"`
+}
+
+// swagger:parameters graphApp graphAppVersion graphBlastRadius graphCriticalPath graphHealthDrivers graphNamespaces graphNamespacesStream graphService graphSnapshotCreate graphWorkload
 type ThroughputParam struct {
 	// Used only with throughput appender. One of: request | response.
 	//
@@ -348,6 +691,17 @@ type ByLabelsParam struct {
 	Name []string `json:"byLabels[]"`
 }
 
+// swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
+type ExemplarsParam struct {
+	// Flag for attaching to the histograms the exemplars having a trace ID, to the avg series or otherwise to the first
+	// requested stat. Requires the Prometheus exemplar storage.
+	//
+	// in: query
+	// required: false
+	// default: false
+	Name bool `json:"exemplars"`
+}
+
 // swagger:parameters serviceMetrics aggregateMetrics appMetrics workloadMetrics appDashboard serviceDashboard workloadDashboard
 type DirectionParam struct {
 	// Traffic direction: 'inbound' or 'outbound'.
@@ -565,6 +919,55 @@ type GraphResponse struct {
 	Body cytoscape.Config
 }
 
+// Server-sent events: an initial "snapshot" event with the graph config (see graphResponse), then "patch" events
+// swagger:response graphStreamResponse
+type GraphStreamResponse struct {
+	// in:body
+	Body stream.Patch
+}
+
+// HTTP status code 200 and graph snapshot Info in data
+// swagger:response graphSnapshotInfoResponse
+type GraphSnapshotInfoResponse struct {
+	// in:body
+	Body snapshot.Info
+}
+
+// Listing all graph snapshots accessible to the user
+// swagger:response graphSnapshotListResponse
+type GraphSnapshotListResponse struct {
+	// in:body
+	Body []snapshot.Info
+}
+
+// HTTP status code 200 and graph snapshot Diff in data
+// swagger:response graphSnapshotDiffResponse
+type GraphSnapshotDiffResponse struct {
+	// in:body
+	Body snapshot.Diff
+}
+
+// HTTP status code 200 and the node's blast radius in data
+// swagger:response graphBlastRadiusResponse
+type GraphBlastRadiusResponse struct {
+	// in:body
+	Body analysis.BlastRadius
+}
+
+// HTTP status code 200 and the highest-latency path in data
+// swagger:response graphCriticalPathResponse
+type GraphCriticalPathResponse struct {
+	// in:body
+	Body analysis.Path
+}
+
+// HTTP status code 200 and the edges degrading the node's health in data
+// swagger:response graphHealthDriversResponse
+type GraphHealthDriversResponse struct {
+	// in:body
+	Body analysis.HealthDrivers
+}
+
 // HTTP status code 200 and IstioConfigList model in data
 // swagger:response istioConfigList
 type IstioConfigResponse struct {
@@ -635,6 +1038,13 @@ type ServiceDetailsResponse struct {
 	Body models.ServiceDetails
 }
 
+// The service level objectives of a service, with their SLI, remaining error budget and burn rates
+// swagger:response serviceSLOsResponse
+type ServiceSLOsResponse struct {
+	// in:body
+	Body models.ServiceSLOs
+}
+
 // Listing all the information related to a Trace
 // swagger:response traceDetailsResponse
 type TraceDetailsResponse struct {
@@ -642,6 +1052,20 @@ type TraceDetailsResponse struct {
 	Body []jaegerModels.Trace
 }
 
+// The latency breakdown of a set of traces
+// swagger:response tracesAnalysisResponse
+type TracesAnalysisResponse struct {
+	// in:body
+	Body models.TracesAnalysis
+}
+
+// The span by span comparison of a trace with a base trace
+// swagger:response traceDiffResponse
+type TraceDiffResponse struct {
+	// in:body
+	Body models.TraceDiff
+}
+
 // Number of traces in error
 // swagger:response errorTracesResponse
 type ErrorTracesResponse struct {
@@ -663,6 +1087,22 @@ type WorkloadDetailsResponse struct {
 	Body models.Workload
 }
 
+// Logs of several containers, each entry tagged with its pod and container. In follow mode, server-sent events:
+// "logs" events with the new entries, their id positions the stream for a reconnection, "error" events for the
+// containers which logs can't be followed.
+// swagger:response logsResponse
+type LogsResponse struct {
+	// in:body
+	Body business.PodLog
+}
+
+// Analytics of the workload proxy access logs
+// swagger:response accessLogSummaryResponse
+type AccessLogSummaryResponse struct {
+	// in:body
+	Body models.AccessLogSummary
+}
+
 // Metrics response model
 // swagger:response metricsResponse
 type MetricsResponse struct {
@@ -684,6 +1124,55 @@ type IstioConfigDetailsResponse struct {
 	Body models.IstioConfigDetails
 }
 
+// Validation of an Istio config change that is not applied
+// swagger:response istioValidationsDryRunResponse
+type IstioValidationsDryRunResponse struct {
+	// in:body
+	Body models.IstioValidationsDryRun
+}
+
+// Stored revisions of an Istio object, oldest first
+// swagger:response istioConfigRevisionsResponse
+type IstioConfigRevisionsResponse struct {
+	// in:body
+	Body []models.IstioConfigRevision
+}
+
+// Field changes between two revisions of an Istio object
+// swagger:response istioConfigRevisionDiffResponse
+type IstioConfigRevisionDiffResponse struct {
+	// in:body
+	Body models.IstioConfigRevisionDiff
+}
+
+// Result of the restore of a revision of an Istio object
+// swagger:response istioConfigRevisionRestoreResponse
+type IstioConfigRevisionRestoreResponse struct {
+	// in:body
+	Body models.IstioConfigRevisionRestore
+}
+
+// Result of the apply of a set of Istio objects
+// swagger:response istioConfigBulkApplyResponse
+type IstioConfigBulkApplyResponse struct {
+	// in:body
+	Body models.IstioConfigBulkApply
+}
+
+// Routing of a service generated from a traffic intent
+// swagger:response trafficRoutingResponse
+type TrafficRoutingResponse struct {
+	// in:body
+	Body models.TrafficRouting
+}
+
+// Canary rollout of a service
+// swagger:response canaryRolloutResponse
+type CanaryRolloutResponse struct {
+	// in:body
+	Body models.CanaryRollout
+}
+
 // Detailed information of an specific app
 // swagger:response appDetails
 type AppDetailsResponse struct {
@@ -754,6 +1243,27 @@ type ConfigDumpResourceResponse struct {
 	Body map[string]interface{}
 }
 
+// Return the changes of the configuration from an envoy proxy dump to another
+// swagger:response configDumpDiff
+type ConfigDumpDiffResponse struct {
+	// in:body
+	Body models.EnvoyProxyDumpDiff
+}
+
+// Return the stored dumps of a given envoy proxy
+// swagger:response configDumpSnapshots
+type ConfigDumpSnapshotsResponse struct {
+	// in:body
+	Body []models.ConfigDumpSnapshotInfo
+}
+
+// Return the stored dump of a given envoy proxy
+// swagger:response configDumpSnapshot
+type ConfigDumpSnapshotResponse struct {
+	// in:body
+	Body models.ConfigDumpSnapshotInfo
+}
+
 //////////////////
 // SWAGGER MODELS
 //////////////////
@@ -821,3 +1331,24 @@ type ClustersResponse struct {
 	// in: body
 	Body []business.Cluster
 }
+
+// Return the current health alerts
+// swagger:response alertsResponse
+type AlertsResponse struct {
+	// in: body
+	Body models.Alerts
+}
+
+// Return the health status transitions, the most recent first
+// swagger:response alertsHistoryResponse
+type AlertsHistoryResponse struct {
+	// in: body
+	Body []models.AlertEvent
+}
+
+// Return the audit records, the most recent first
+// swagger:response auditRecordsResponse
+type AuditRecordsResponse struct {
+	// in: body
+	Body []models.AuditRecord
+}
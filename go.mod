@@ -6,6 +6,7 @@ require (
 	github.com/NYTimes/gziphandler v1.1.1
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
 	github.com/docker/spdystream v0.0.0-20181023171402-6480d4af844c // indirect
+	github.com/google/cel-go v0.6.0
 	github.com/google/gofuzz v1.2.0 // indirect
 	github.com/gorilla/mux v1.7.4
 	github.com/hashicorp/go-version v1.2.0
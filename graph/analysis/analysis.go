@@ -0,0 +1,128 @@
+// Package analysis provides dependency analysis over a graph.TrafficMap: the blast radius of a node,
+// the highest-latency path between nodes and the edges driving a node's degraded health.
+//
+// Nodes are identified by their TrafficMap ID or by their cytoscape ID, results report both so that
+// they can be matched with the nodes of a cytoscape graph config.
+package analysis
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+var (
+	// ErrNodeNotFound is returned when a requested node is not part of the traffic map
+	ErrNodeNotFound = errors.New("node not found in graph")
+	// ErrNoPath is returned when the destination node is not reachable from the source node
+	ErrNoPath = errors.New("no path between the nodes")
+)
+
+// NodeRef identifies a traffic map node
+type NodeRef struct {
+	ID             string `json:"id"`        // cytoscape node ID
+	TrafficID      string `json:"trafficId"` // traffic map node ID
+	NodeType       string `json:"nodeType"`
+	Cluster        string `json:"cluster"`
+	Namespace      string `json:"namespace"`
+	Workload       string `json:"workload,omitempty"`
+	App            string `json:"app,omitempty"`
+	Version        string `json:"version,omitempty"`
+	Service        string `json:"service,omitempty"`
+	IsInaccessible bool   `json:"isInaccessible,omitempty"`
+}
+
+func newNodeRef(n *graph.Node) NodeRef {
+	ref := NodeRef{
+		ID:        cytoscape.NodeHash(n.ID),
+		TrafficID: n.ID,
+		NodeType:  n.NodeType,
+		Cluster:   n.Cluster,
+		Namespace: n.Namespace,
+		Workload:  n.Workload,
+		App:       n.App,
+		Version:   n.Version,
+		Service:   n.Service,
+	}
+	if val, ok := n.Metadata[graph.IsInaccessible]; ok {
+		ref.IsInaccessible = val.(bool)
+	}
+	return ref
+}
+
+// findNode returns the node with the given TrafficMap or cytoscape ID
+func findNode(trafficMap graph.TrafficMap, id string) (*graph.Node, error) {
+	if n, ok := trafficMap[id]; ok {
+		return n, nil
+	}
+	for trafficID, n := range trafficMap {
+		if cytoscape.NodeHash(trafficID) == id {
+			return n, nil
+		}
+	}
+	return nil, ErrNodeNotFound
+}
+
+// sortedNodeIDs returns the traffic map node IDs, sorted to make the analysis deterministic
+func sortedNodeIDs(trafficMap graph.TrafficMap) []string {
+	ids := make([]string, 0, len(trafficMap))
+	for id := range trafficMap {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// sortedEdges returns the node's outgoing edges sorted by destination and protocol
+func sortedEdges(n *graph.Node) []*graph.Edge {
+	edges := make([]*graph.Edge, len(n.Edges))
+	copy(edges, n.Edges)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Dest.ID != edges[j].Dest.ID {
+			return edges[i].Dest.ID < edges[j].Dest.ID
+		}
+		return protocol(edges[i]) < protocol(edges[j])
+	})
+	return edges
+}
+
+// incomingEdges maps each node ID to the edges for which it is the destination
+func incomingEdges(trafficMap graph.TrafficMap) map[string][]*graph.Edge {
+	incoming := make(map[string][]*graph.Edge, len(trafficMap))
+	for _, id := range sortedNodeIDs(trafficMap) {
+		for _, e := range sortedEdges(trafficMap[id]) {
+			incoming[e.Dest.ID] = append(incoming[e.Dest.ID], e)
+		}
+	}
+	return incoming
+}
+
+func protocol(e *graph.Edge) string {
+	if p, ok := e.Metadata[graph.ProtocolKey].(string); ok {
+		return p
+	}
+	return ""
+}
+
+// requestRate returns the request rate of an HTTP or gRPC edge, 0 for other protocols
+func requestRate(e *graph.Edge) float64 {
+	p := protocol(e)
+	if p == graph.TCP.Name {
+		return 0.0
+	}
+	for _, proto := range graph.Protocols {
+		if proto.Name != p {
+			continue
+		}
+		for _, rate := range proto.EdgeRates {
+			if rate.IsTotal {
+				if val, ok := e.Metadata[rate.Name].(float64); ok {
+					return val
+				}
+			}
+		}
+	}
+	return 0.0
+}
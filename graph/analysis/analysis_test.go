@@ -0,0 +1,94 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+const (
+	ingress     = "wl_east_istio-system_istio-ingressgateway"
+	productpage = "wl_east_bookinfo_productpage-v1"
+	reviews     = "wl_east_bookinfo_reviews-v1"
+	details     = "wl_east_bookinfo_details-v1"
+	ratings     = "wl_east_bookinfo_ratings-v1"
+	mysql       = "wl_east_bookinfo_mysql-v1"
+)
+
+// buildTrafficMap returns a bookinfo-like workload graph:
+//
+//	ingress -> productpage -> reviews -> ratings -> mysql (tcp)
+//	                       -> details -> ratings
+//
+// plus a ratings -> reviews edge introducing a cycle
+func buildTrafficMap() graph.TrafficMap {
+	config.Set(config.NewConfig())
+
+	trafficMap := graph.NewTrafficMap()
+	addNode := func(namespace, workload string) *graph.Node {
+		n := graph.NewNode("east", namespace, "", namespace, workload, "", "", graph.GraphTypeWorkload)
+		trafficMap[n.ID] = &n
+		return &n
+	}
+	addEdge := func(source, dest *graph.Node, protocol string, responseTime float64, codeRates map[string]float64) {
+		e := source.AddEdge(dest)
+		e.Metadata[graph.ProtocolKey] = protocol
+		for code, rate := range codeRates {
+			graph.AddToMetadata(protocol, rate, code, "-", dest.Workload, source.Metadata, dest.Metadata, e.Metadata)
+		}
+		if responseTime > 0.0 {
+			e.Metadata[graph.ResponseTime] = responseTime
+		}
+	}
+
+	ingressNode := addNode("istio-system", "istio-ingressgateway")
+	ingressNode.Metadata[graph.IsRoot] = true
+	productpageNode := addNode("bookinfo", "productpage-v1")
+	reviewsNode := addNode("bookinfo", "reviews-v1")
+	detailsNode := addNode("bookinfo", "details-v1")
+	ratingsNode := addNode("bookinfo", "ratings-v1")
+	mysqlNode := addNode("bookinfo", "mysql-v1")
+
+	addEdge(ingressNode, productpageNode, graph.HTTP.Name, 10.0, map[string]float64{"200": 100.0})
+	addEdge(productpageNode, reviewsNode, graph.HTTP.Name, 50.0, map[string]float64{"200": 72.0, "503": 8.0})
+	addEdge(productpageNode, detailsNode, graph.HTTP.Name, 5.0, map[string]float64{"200": 19.0, "404": 1.0})
+	addEdge(reviewsNode, ratingsNode, graph.HTTP.Name, 100.0, map[string]float64{"200": 40.0})
+	addEdge(detailsNode, ratingsNode, graph.GRPC.Name, 200.0, map[string]float64{"0": 6.0, "14": 4.0})
+	addEdge(ratingsNode, reviewsNode, graph.HTTP.Name, 1.0, map[string]float64{"200": 1.0})
+	addEdge(ratingsNode, mysqlNode, graph.TCP.Name, 0.0, map[string]float64{"": 500.0})
+
+	return trafficMap
+}
+
+func TestFindNode(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := buildTrafficMap()
+
+	n, err := findNode(trafficMap, reviews)
+	assert.NoError(err)
+	assert.Equal(reviews, n.ID)
+
+	n, err = findNode(trafficMap, cytoscape.NodeHash(reviews))
+	assert.NoError(err)
+	assert.Equal(reviews, n.ID)
+
+	_, err = findNode(trafficMap, "wl_east_bookinfo_nope")
+	assert.Equal(ErrNodeNotFound, err)
+}
+
+func TestRequestRate(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := buildTrafficMap()
+	rates := map[string]float64{}
+	for _, e := range trafficMap[ratings].Edges {
+		rates[e.Dest.ID] = requestRate(e)
+	}
+	assert.Equal(map[string]float64{reviews: 1.0, mysql: 0.0}, rates)
+	assert.Equal(10.0, requestRate(trafficMap[details].Edges[0]))
+}
@@ -0,0 +1,125 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// Impact describes a node transitively depending on, or depended on by, the analyzed node
+type Impact struct {
+	Node NodeRef `json:"node"`
+	// Depth is the minimum number of hops between the analyzed node and this node
+	Depth int `json:"depth"`
+	// Rate is the request rate (requests per second) on the edges linking this node to the analyzed
+	// node's dependency chain: outgoing edges for an upstream node, incoming edges for a downstream node.
+	Rate float64 `json:"rate"`
+}
+
+// BlastRadius describes the transitive dependents (upstream) and dependencies (downstream) of a node.
+// If the node fails the upstream nodes are affected, the downstream nodes lose the node's traffic.
+type BlastRadius struct {
+	Node           NodeRef  `json:"node"`
+	Upstream       []Impact `json:"upstream"`
+	UpstreamRate   float64  `json:"upstreamRate"` // request rate into the node
+	Downstream     []Impact `json:"downstream"`
+	DownstreamRate float64  `json:"downstreamRate"` // request rate out of the node
+}
+
+// NewBlastRadius returns the BlastRadius of the node with the given TrafficMap or cytoscape ID. Impacts
+// are sorted by descending rate, then by depth.
+func NewBlastRadius(trafficMap graph.TrafficMap, nodeID string) (*BlastRadius, error) {
+	n, err := findNode(trafficMap, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	incoming := incomingEdges(trafficMap)
+
+	// upstream: follow the incoming edges, a dependent's rate is its traffic into the chain
+	upstreamDepths := traverse(n, func(node *graph.Node) []*graph.Node {
+		sources := []*graph.Node{}
+		for _, e := range incoming[node.ID] {
+			sources = append(sources, e.Source)
+		}
+		return sources
+	})
+	upstream := make([]Impact, 0, len(upstreamDepths))
+	for id, depth := range upstreamDepths {
+		impact := Impact{Node: newNodeRef(trafficMap[id]), Depth: depth}
+		for _, e := range trafficMap[id].Edges {
+			if _, inChain := upstreamDepths[e.Dest.ID]; inChain || e.Dest.ID == n.ID {
+				impact.Rate += requestRate(e)
+			}
+		}
+		upstream = append(upstream, impact)
+	}
+
+	// downstream: follow the outgoing edges, a dependency's rate is its traffic from the chain
+	downstreamDepths := traverse(n, func(node *graph.Node) []*graph.Node {
+		dests := []*graph.Node{}
+		for _, e := range sortedEdges(node) {
+			dests = append(dests, e.Dest)
+		}
+		return dests
+	})
+	downstream := make([]Impact, 0, len(downstreamDepths))
+	for id, depth := range downstreamDepths {
+		impact := Impact{Node: newNodeRef(trafficMap[id]), Depth: depth}
+		for _, e := range incoming[id] {
+			if _, inChain := downstreamDepths[e.Source.ID]; inChain || e.Source.ID == n.ID {
+				impact.Rate += requestRate(e)
+			}
+		}
+		downstream = append(downstream, impact)
+	}
+
+	br := &BlastRadius{
+		Node:       newNodeRef(n),
+		Upstream:   sortImpacts(upstream),
+		Downstream: sortImpacts(downstream),
+	}
+	for _, e := range incoming[n.ID] {
+		br.UpstreamRate += requestRate(e)
+	}
+	for _, e := range n.Edges {
+		br.DownstreamRate += requestRate(e)
+	}
+
+	return br, nil
+}
+
+// traverse performs a breadth-first traversal from the node and returns the depth of each
+// reached node, excluding the node itself.
+func traverse(n *graph.Node, next func(*graph.Node) []*graph.Node) map[string]int {
+	depths := map[string]int{}
+	visited := map[string]bool{n.ID: true}
+	queue := []*graph.Node{n}
+	for depth := 1; len(queue) > 0; depth++ {
+		nextQueue := []*graph.Node{}
+		for _, node := range queue {
+			for _, reached := range next(node) {
+				if visited[reached.ID] {
+					continue
+				}
+				visited[reached.ID] = true
+				depths[reached.ID] = depth
+				nextQueue = append(nextQueue, reached)
+			}
+		}
+		queue = nextQueue
+	}
+	return depths
+}
+
+func sortImpacts(impacts []Impact) []Impact {
+	sort.Slice(impacts, func(i, j int) bool {
+		if impacts[i].Rate != impacts[j].Rate {
+			return impacts[i].Rate > impacts[j].Rate
+		}
+		if impacts[i].Depth != impacts[j].Depth {
+			return impacts[i].Depth < impacts[j].Depth
+		}
+		return impacts[i].Node.TrafficID < impacts[j].Node.TrafficID
+	})
+	return impacts
+}
@@ -0,0 +1,63 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func impactSummary(impacts []Impact) [][]interface{} {
+	summary := [][]interface{}{}
+	for _, impact := range impacts {
+		summary = append(summary, []interface{}{impact.Node.TrafficID, impact.Depth, impact.Rate})
+	}
+	return summary
+}
+
+func TestBlastRadius(t *testing.T) {
+	assert := assert.New(t)
+
+	br, err := NewBlastRadius(buildTrafficMap(), cytoscape.NodeHash(ratings))
+	require.NoError(t, err)
+
+	assert.Equal(ratings, br.Node.TrafficID)
+	assert.Equal(cytoscape.NodeHash(ratings), br.Node.ID)
+	assert.Equal([][]interface{}{
+		{productpage, 2, 100.0},
+		{ingress, 3, 100.0},
+		{reviews, 1, 40.0},
+		{details, 1, 10.0},
+	}, impactSummary(br.Upstream))
+	assert.Equal(50.0, br.UpstreamRate)
+
+	assert.Equal([][]interface{}{
+		{reviews, 1, 1.0},
+		{mysql, 1, 0.0},
+	}, impactSummary(br.Downstream))
+	assert.Equal(1.0, br.DownstreamRate)
+}
+
+func TestBlastRadiusRoot(t *testing.T) {
+	assert := assert.New(t)
+
+	br, err := NewBlastRadius(buildTrafficMap(), ingress)
+	require.NoError(t, err)
+
+	assert.Empty(br.Upstream)
+	assert.Equal(0.0, br.UpstreamRate)
+	assert.Equal([][]interface{}{
+		{productpage, 1, 100.0},
+		{reviews, 2, 81.0},
+		{ratings, 3, 50.0},
+		{details, 2, 20.0},
+		{mysql, 4, 0.0},
+	}, impactSummary(br.Downstream))
+}
+
+func TestBlastRadiusNotFound(t *testing.T) {
+	_, err := NewBlastRadius(buildTrafficMap(), "nope")
+	assert.Equal(t, ErrNodeNotFound, err)
+}
@@ -0,0 +1,151 @@
+package analysis
+
+import (
+	"github.com/kiali/kiali/graph"
+)
+
+// PathEdge is one hop of a Path
+type PathEdge struct {
+	Source       string  `json:"source"` // cytoscape node ID
+	Target       string  `json:"target"` // cytoscape node ID
+	Protocol     string  `json:"protocol"`
+	ResponseTime float64 `json:"responseTime"` // milliseconds, 0 if not reported
+}
+
+// Path describes a path through the graph and its cumulative response time
+type Path struct {
+	Nodes        []NodeRef  `json:"nodes"`
+	Edges        []PathEdge `json:"edges"`
+	ResponseTime float64    `json:"responseTime"` // milliseconds
+}
+
+// NewCriticalPath returns the path from the source node to the destination node with the highest
+// cumulative response time, using the edge responseTime set by the responseTime appender. When
+// fromID is empty the path may start at any traffic generator (i.e. root) node.
+//
+// The longest path is only well-defined on an acyclic graph. The destination's outgoing edges can not
+// be part of a path and are ignored, other cycles are broken by ignoring the edges leading back to a
+// node already on the current depth-first traversal path. Nodes and edges are visited in ID order to
+// make the result deterministic.
+func NewCriticalPath(trafficMap graph.TrafficMap, fromID, toID string) (*Path, error) {
+	to, err := findNode(trafficMap, toID)
+	if err != nil {
+		return nil, err
+	}
+
+	var sources []*graph.Node
+	if fromID == "" {
+		for _, id := range sortedNodeIDs(trafficMap) {
+			if isRoot, ok := trafficMap[id].Metadata[graph.IsRoot].(bool); ok && isRoot {
+				sources = append(sources, trafficMap[id])
+			}
+		}
+	} else {
+		from, err := findNode(trafficMap, fromID)
+		if err != nil {
+			return nil, err
+		}
+		sources = []*graph.Node{from}
+	}
+
+	// restrict the search to the nodes that can reach the destination
+	incoming := incomingEdges(trafficMap)
+	reachesTo := traverse(to, func(node *graph.Node) []*graph.Node {
+		sources := []*graph.Node{}
+		for _, e := range incoming[node.ID] {
+			sources = append(sources, e.Source)
+		}
+		return sources
+	})
+	reachesTo[to.ID] = 0
+
+	// depth-first traversal producing a reverse topological order of the acyclic subgraph
+	var order []*graph.Node
+	state := map[string]int{} // 1 = on the traversal path, 2 = done
+	var visit func(n *graph.Node)
+	visit = func(n *graph.Node) {
+		state[n.ID] = 1
+		if n.ID != to.ID {
+			for _, e := range sortedEdges(n) {
+				if _, ok := reachesTo[e.Dest.ID]; ok && state[e.Dest.ID] == 0 {
+					visit(e.Dest)
+				}
+			}
+		}
+		state[n.ID] = 2
+		order = append(order, n)
+	}
+	for _, s := range sources {
+		if _, ok := reachesTo[s.ID]; ok && state[s.ID] == 0 {
+			visit(s)
+		}
+	}
+	if state[to.ID] == 0 {
+		return nil, ErrNoPath
+	}
+
+	// longest path, processing the nodes in topological order
+	position := make(map[string]int, len(order))
+	for i, n := range order {
+		position[n.ID] = len(order) - 1 - i
+	}
+	dist := map[string]float64{}
+	prev := map[string]*graph.Edge{}
+	for _, s := range sources {
+		if _, ok := position[s.ID]; ok {
+			dist[s.ID] = 0.0
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		d, reached := dist[n.ID]
+		if !reached || n.ID == to.ID {
+			continue
+		}
+		for _, e := range sortedEdges(n) {
+			destPosition, ok := position[e.Dest.ID]
+			if !ok || destPosition <= position[n.ID] {
+				continue // outside of the subgraph, or a back edge
+			}
+			candidate := d + responseTime(e)
+			if current, reached := dist[e.Dest.ID]; !reached || candidate > current {
+				dist[e.Dest.ID] = candidate
+				prev[e.Dest.ID] = e
+			}
+		}
+	}
+
+	var edges []*graph.Edge
+	for n := to; prev[n.ID] != nil; n = prev[n.ID].Source {
+		edges = append([]*graph.Edge{prev[n.ID]}, edges...)
+	}
+	start := to
+	if len(edges) > 0 {
+		start = edges[0].Source
+	}
+
+	path := &Path{
+		Nodes:        []NodeRef{newNodeRef(start)},
+		Edges:        []PathEdge{},
+		ResponseTime: dist[to.ID],
+	}
+	for _, e := range edges {
+		dest := newNodeRef(e.Dest)
+		path.Edges = append(path.Edges, PathEdge{
+			Source:       path.Nodes[len(path.Nodes)-1].ID,
+			Target:       dest.ID,
+			Protocol:     protocol(e),
+			ResponseTime: responseTime(e),
+		})
+		path.Nodes = append(path.Nodes, dest)
+	}
+
+	return path, nil
+}
+
+func responseTime(e *graph.Edge) float64 {
+	if val, ok := e.Metadata[graph.ResponseTime].(float64); ok {
+		return val
+	}
+	return 0.0
+}
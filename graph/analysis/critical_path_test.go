@@ -0,0 +1,72 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func pathNodes(p *Path) []string {
+	ids := []string{}
+	for _, n := range p.Nodes {
+		ids = append(ids, n.TrafficID)
+	}
+	return ids
+}
+
+func TestCriticalPath(t *testing.T) {
+	assert := assert.New(t)
+
+	p, err := NewCriticalPath(buildTrafficMap(), ingress, ratings)
+	require.NoError(t, err)
+
+	assert.Equal([]string{ingress, productpage, details, ratings}, pathNodes(p))
+	assert.Equal(215.0, p.ResponseTime)
+	require.Len(t, p.Edges, 3)
+	assert.Equal(PathEdge{
+		Source:       cytoscape.NodeHash(details),
+		Target:       cytoscape.NodeHash(ratings),
+		Protocol:     "grpc",
+		ResponseTime: 200.0,
+	}, p.Edges[2])
+}
+
+func TestCriticalPathFromRoots(t *testing.T) {
+	assert := assert.New(t)
+
+	// the longest path to reviews is the indirect one, through the ratings -> reviews edge
+	p, err := NewCriticalPath(buildTrafficMap(), "", reviews)
+	require.NoError(t, err)
+
+	assert.Equal([]string{ingress, productpage, details, ratings, reviews}, pathNodes(p))
+	assert.Equal(216.0, p.ResponseTime)
+
+	p, err = NewCriticalPath(buildTrafficMap(), productpage, mysql)
+	require.NoError(t, err)
+	assert.Equal([]string{productpage, details, ratings, mysql}, pathNodes(p))
+	assert.Equal(205.0, p.ResponseTime)
+}
+
+func TestCriticalPathSameNode(t *testing.T) {
+	p, err := NewCriticalPath(buildTrafficMap(), reviews, reviews)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{reviews}, pathNodes(p))
+	assert.Empty(t, p.Edges)
+}
+
+func TestCriticalPathErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := NewCriticalPath(buildTrafficMap(), ratings, ingress)
+	assert.Equal(ErrNoPath, err)
+
+	_, err = NewCriticalPath(buildTrafficMap(), "nope", ratings)
+	assert.Equal(ErrNodeNotFound, err)
+
+	_, err = NewCriticalPath(buildTrafficMap(), ingress, "nope")
+	assert.Equal(ErrNodeNotFound, err)
+}
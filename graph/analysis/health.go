@@ -0,0 +1,215 @@
+package analysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+)
+
+// Health statuses, in increasing severity
+const (
+	HealthHealthy  = "healthy"
+	HealthDegraded = "degraded"
+	HealthFailure  = "failure"
+)
+
+// Edge directions relative to the analyzed node, as matched by the health config tolerances
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// EdgeHealth describes an edge whose error rate degrades the analyzed node's health
+type EdgeHealth struct {
+	Source    NodeRef `json:"source"`
+	Target    NodeRef `json:"target"`
+	Direction string  `json:"direction"`
+	Protocol  string  `json:"protocol"`
+	// Code is the response code pattern of the health config tolerance that was exceeded
+	Code string `json:"code"`
+	// ErrorRate is the percentage of the edge requests matching Code
+	ErrorRate float64 `json:"errorRate"`
+	Status    string  `json:"status"`
+}
+
+// HealthDrivers describes the health of a node as determined by the error rates of its edges
+type HealthDrivers struct {
+	Node   NodeRef      `json:"node"`
+	Status string       `json:"status"`
+	Edges  []EdgeHealth `json:"edges"`
+}
+
+// NewHealthDrivers evaluates the inbound and outbound edges of the node with the given TrafficMap or
+// cytoscape ID against the health config rate tolerances. It returns the edges degrading the node's
+// health, the most severe first. The first health config rate matching the node applies. A tolerance
+// applies when its protocol and direction match, and the percentage of requests with a code matching
+// the tolerance code (where 'X' matches any digit) exceeds its degraded threshold, or reaches its
+// failure threshold.
+func NewHealthDrivers(trafficMap graph.TrafficMap, nodeID string, rates []config.Rate) (*HealthDrivers, error) {
+	n, err := findNode(trafficMap, nodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	hd := &HealthDrivers{
+		Node:   newNodeRef(n),
+		Status: HealthHealthy,
+		Edges:  []EdgeHealth{},
+	}
+
+	rate, ok := matchRate(n, rates)
+	if !ok {
+		return hd, nil
+	}
+
+	for _, e := range incomingEdges(trafficMap)[n.ID] {
+		if eh, ok := evaluateEdge(e, DirectionInbound, rate.Tolerance); ok {
+			hd.Edges = append(hd.Edges, eh)
+		}
+	}
+	for _, e := range sortedEdges(n) {
+		if eh, ok := evaluateEdge(e, DirectionOutbound, rate.Tolerance); ok {
+			hd.Edges = append(hd.Edges, eh)
+		}
+	}
+
+	sort.SliceStable(hd.Edges, func(i, j int) bool {
+		if hd.Edges[i].Status != hd.Edges[j].Status {
+			return severity(hd.Edges[i].Status) > severity(hd.Edges[j].Status)
+		}
+		return hd.Edges[i].ErrorRate > hd.Edges[j].ErrorRate
+	})
+	if len(hd.Edges) > 0 {
+		hd.Status = hd.Edges[0].Status
+	}
+
+	return hd, nil
+}
+
+// matchRate returns the first health config rate whose namespace, kind and name match the node
+func matchRate(n *graph.Node, rates []config.Rate) (config.Rate, bool) {
+	var name string
+	switch n.NodeType {
+	case graph.NodeTypeApp:
+		name = n.App
+	case graph.NodeTypeService:
+		name = n.Service
+	case graph.NodeTypeWorkload:
+		name = n.Workload
+	default:
+		return config.Rate{}, false
+	}
+
+	for _, rate := range rates {
+		if matches(rate.Namespace, n.Namespace) && matches(rate.Kind, n.NodeType) && matches(rate.Name, name) {
+			return rate, true
+		}
+	}
+	return config.Rate{}, false
+}
+
+// evaluateEdge returns the health of the edge for the most severe matching tolerance, ok is false
+// if the edge is healthy.
+func evaluateEdge(e *graph.Edge, direction string, tolerances []config.Tolerance) (eh EdgeHealth, ok bool) {
+	p := protocol(e)
+	var responses graph.Responses
+	for _, proto := range graph.Protocols {
+		if proto.Name == p {
+			responses, _ = e.Metadata[proto.EdgeResponses].(graph.Responses)
+		}
+	}
+	if len(responses) == 0 {
+		return eh, false
+	}
+
+	total := 0.0
+	codeRates := make(map[string]float64, len(responses))
+	for code, detail := range responses {
+		codeRates[code] = responseRate(detail)
+		total += codeRates[code]
+	}
+	if total == 0.0 {
+		return eh, false
+	}
+
+	for _, tolerance := range tolerances {
+		if !matches(tolerance.Protocol, p) || !matches(tolerance.Direction, direction) {
+			continue
+		}
+		codeRegexp, err := regexp.Compile(strings.NewReplacer("X", `\d`, "x", `\d`).Replace(tolerance.Code))
+		if err != nil {
+			log.Warningf("Ignoring health tolerance with invalid code [%s]: %v", tolerance.Code, err)
+			continue
+		}
+		matched := 0.0
+		for code, rate := range codeRates {
+			if codeRegexp.MatchString(code) {
+				matched += rate
+			}
+		}
+		errorRate := matched / total * 100.0
+
+		status := HealthHealthy
+		switch {
+		case errorRate >= float64(tolerance.Failure):
+			status = HealthFailure
+		case errorRate > float64(tolerance.Degraded):
+			status = HealthDegraded
+		}
+		if matched == 0.0 || severity(status) <= severity(eh.Status) {
+			continue
+		}
+		eh = EdgeHealth{
+			Source:    newNodeRef(e.Source),
+			Target:    newNodeRef(e.Dest),
+			Direction: direction,
+			Protocol:  p,
+			Code:      tolerance.Code,
+			ErrorRate: errorRate,
+			Status:    status,
+		}
+	}
+
+	return eh, eh.Status != "" && eh.Status != HealthHealthy
+}
+
+func responseRate(detail *graph.ResponseDetail) float64 {
+	rate := 0.0
+	for _, val := range detail.Flags {
+		rate += val
+	}
+	if rate == 0.0 {
+		for _, val := range detail.Hosts {
+			rate += val
+		}
+	}
+	return rate
+}
+
+// matches returns true if the value fully matches the pattern, an empty pattern matches any value
+func matches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		log.Warningf("Ignoring invalid health config pattern [%s]: %v", pattern, err)
+		return false
+	}
+	return re.MatchString(value)
+}
+
+func severity(status string) int {
+	switch status {
+	case HealthFailure:
+		return 2
+	case HealthDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,79 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+)
+
+func edgeSummary(edges []EdgeHealth) [][]interface{} {
+	summary := [][]interface{}{}
+	for _, e := range edges {
+		summary = append(summary, []interface{}{e.Source.TrafficID, e.Target.TrafficID, e.Direction, e.Code, e.ErrorRate, e.Status})
+	}
+	return summary
+}
+
+func TestHealthDrivers(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := buildTrafficMap()
+	rates := config.Get().HealthConfig.Rate
+
+	// inbound 10% 5xx is a failure, outbound 5% 404 is below the 4XX degraded threshold
+	hd, err := NewHealthDrivers(trafficMap, reviews, rates)
+	require.NoError(t, err)
+	assert.Equal(HealthFailure, hd.Status)
+	assert.Equal([][]interface{}{
+		{productpage, reviews, DirectionInbound, "5XX", 10.0, HealthFailure},
+	}, edgeSummary(hd.Edges))
+
+	// 40% gRPC unavailable on the inbound details edge, and the outbound failure
+	hd, err = NewHealthDrivers(trafficMap, ratings, rates)
+	require.NoError(t, err)
+	assert.Equal(HealthFailure, hd.Status)
+	assert.Equal([][]interface{}{
+		{details, ratings, DirectionInbound, "^[1-9]$|^1[0-6]$", 40.0, HealthFailure},
+	}, edgeSummary(hd.Edges))
+
+	hd, err = NewHealthDrivers(trafficMap, ingress, rates)
+	require.NoError(t, err)
+	assert.Equal(HealthHealthy, hd.Status)
+	assert.Empty(hd.Edges)
+}
+
+func TestHealthDriversCustomTolerance(t *testing.T) {
+	assert := assert.New(t)
+
+	rates := []config.Rate{
+		{
+			Namespace: "other",
+			Tolerance: []config.Tolerance{{Code: "5XX", Protocol: "http", Direction: ".*", Failure: 1}},
+		},
+		{
+			Namespace: "bookinfo",
+			Kind:      "workload",
+			Name:      "productpage-.*",
+			Tolerance: []config.Tolerance{
+				{Code: "4XX", Protocol: "http", Direction: "outbound", Degraded: 2, Failure: 50},
+				{Code: "5XX", Protocol: "http", Direction: "outbound", Failure: 20},
+			},
+		},
+	}
+
+	hd, err := NewHealthDrivers(buildTrafficMap(), productpage, rates)
+	require.NoError(t, err)
+	assert.Equal(HealthDegraded, hd.Status)
+	assert.Equal([][]interface{}{
+		{productpage, reviews, DirectionOutbound, "5XX", 10.0, HealthDegraded},
+		{productpage, details, DirectionOutbound, "4XX", 5.0, HealthDegraded},
+	}, edgeSummary(hd.Edges))
+
+	// no matching rate config
+	hd, err = NewHealthDrivers(buildTrafficMap(), reviews, rates)
+	require.NoError(t, err)
+	assert.Equal(HealthHealthy, hd.Status)
+}
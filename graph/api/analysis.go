@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/analysis"
+	"github.com/kiali/kiali/prometheus"
+)
+
+// Analyzer performs an analysis on a namespaces traffic map, see graph/analysis
+type Analyzer func(trafficMap graph.TrafficMap) (interface{}, error)
+
+// Analyze generates a namespaces traffic map using the provided options and returns the analyzer
+// result. Unknown nodes and missing paths are reported as http.StatusNotFound.
+func Analyze(business *business.Layer, o graph.Options, analyzer Analyzer) (code int, result interface{}) {
+	prom, err := prometheus.NewClient()
+	graph.CheckError(err)
+
+	return analyze(business, prom, o, analyzer)
+}
+
+// analyze provides a test hook that accepts mock clients
+func analyze(business *business.Layer, prom *prometheus.Client, o graph.Options, analyzer Analyzer) (code int, result interface{}) {
+	trafficMap := buildNamespacesTrafficMap(business, prom, o)
+
+	result, err := analyzer(trafficMap)
+	switch err {
+	case nil:
+		return http.StatusOK, result
+	case analysis.ErrNodeNotFound, analysis.ErrNoPath:
+		graph.Panic(err.Error(), http.StatusNotFound)
+	default:
+		graph.CheckError(err)
+	}
+	return http.StatusInternalServerError, nil
+}
@@ -7,7 +7,12 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/config/cytoscape"
-	"github.com/kiali/kiali/graph/telemetry/istio"
+	"github.com/kiali/kiali/graph/config/dot"
+	"github.com/kiali/kiali/graph/config/graphml"
+	"github.com/kiali/kiali/graph/config/mermaid"
+	"github.com/kiali/kiali/graph/snapshot"
+	_ "github.com/kiali/kiali/graph/telemetry/istio" // registers the istio telemetry vendor
+	_ "github.com/kiali/kiali/graph/telemetry/otel"  // registers the otel telemetry vendor
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/internalmetrics"
@@ -19,14 +24,9 @@ func GraphNamespaces(business *business.Layer, o graph.Options) (code int, confi
 	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
-	switch o.TelemetryVendor {
-	case graph.VendorIstio:
-		prom, err := prometheus.NewClient()
-		graph.CheckError(err)
-		code, config = graphNamespacesIstio(business, prom, o)
-	default:
-		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
-	}
+	prom, err := prometheus.NewClient()
+	graph.CheckError(err)
+	code, config = graphNamespaces(business, prom, o)
 
 	// update metrics
 	internalmetrics.SetGraphNodes(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes, 0)
@@ -34,19 +34,54 @@ func GraphNamespaces(business *business.Layer, o graph.Options) (code int, confi
 	return code, config
 }
 
-// graphNamespacesIstio provides a test hook that accepts mock clients
-func graphNamespacesIstio(business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, config interface{}) {
-
-	// Create a 'global' object to store the business. Global only to the request.
-	globalInfo := graph.NewAppenderGlobalInfo()
-	globalInfo.Business = business
-
-	trafficMap := istio.BuildNamespacesTrafficMap(o.TelemetryOptions, prom, globalInfo)
+// graphNamespaces provides a test hook that accepts mock clients
+func graphNamespaces(business *business.Layer, prom *prometheus.Client, o graph.Options) (code int, config interface{}) {
+	trafficMap := buildNamespacesTrafficMap(business, prom, o)
 	code, config = generateGraph(trafficMap, o)
 
 	return code, config
 }
 
+// SnapshotNamespaces generates a namespaces traffic map using the provided options and
+// persists it to the snapshot store. It returns the snapshot info.
+func SnapshotNamespaces(business *business.Layer, o graph.Options, name string, store snapshot.Store) (code int, info interface{}) {
+	prom, err := prometheus.NewClient()
+	graph.CheckError(err)
+
+	return snapshotNamespaces(business, prom, o, name, store)
+}
+
+// snapshotNamespaces provides a test hook that accepts mock clients
+func snapshotNamespaces(business *business.Layer, prom *prometheus.Client, o graph.Options, name string, store snapshot.Store) (code int, info interface{}) {
+	trafficMap := buildNamespacesTrafficMap(business, prom, o)
+
+	s, err := snapshot.NewSnapshot(trafficMap, name, o)
+	graph.CheckError(err)
+	graph.CheckError(store.Save(s))
+
+	return http.StatusOK, s.Info
+}
+
+func buildNamespacesTrafficMap(business *business.Layer, prom *prometheus.Client, o graph.Options) graph.TrafficMap {
+	vendor := getTelemetryVendor(o)
+
+	return buildTrafficMap(business, prom, o, func(client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+		return vendor.BuildNamespacesTrafficMap(o.TelemetryOptions, client, globalInfo)
+	})
+}
+
+// GraphSnapshot generates the graph config for a stored snapshot, the snapshot determines
+// all but the config vendor options.
+func GraphSnapshot(s *snapshot.Snapshot, o graph.Options) (code int, config interface{}) {
+	trafficMap, err := s.TrafficMap()
+	graph.CheckError(err)
+
+	o.ConfigOptions = s.ConfigOptions(o.BoxBy)
+	o.TelemetryOptions.CommonOptions = o.ConfigOptions.CommonOptions
+
+	return generateGraph(trafficMap, o)
+}
+
 // GraphNode generates a node graph using the provided options
 func GraphNode(business *business.Layer, o graph.Options) (code int, config interface{}) {
 	if len(o.Namespaces) != 1 {
@@ -57,33 +92,36 @@ func GraphNode(business *business.Layer, o graph.Options) (code int, config inte
 	promtimer := internalmetrics.GetGraphGenerationTimePrometheusTimer(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes)
 	defer promtimer.ObserveDuration()
 
-	switch o.TelemetryVendor {
-	case graph.VendorIstio:
-		prom, err := prometheus.NewClient()
-		graph.CheckError(err)
-		code, config = graphNodeIstio(business, prom, o)
-	default:
-		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
-	}
+	prom, err := prometheus.NewClient()
+	graph.CheckError(err)
+	code, config = graphNode(business, prom, o)
+
 	// update metrics
 	internalmetrics.SetGraphNodes(o.GetGraphKind(), o.TelemetryOptions.GraphType, o.InjectServiceNodes, 0)
 
 	return code, config
 }
 
-// graphNodeIstio provides a test hook that accepts mock clients
-func graphNodeIstio(business *business.Layer, client *prometheus.Client, o graph.Options) (code int, config interface{}) {
+// graphNode provides a test hook that accepts mock clients
+func graphNode(business *business.Layer, client *prometheus.Client, o graph.Options) (code int, config interface{}) {
+	vendor := getTelemetryVendor(o)
 
-	// Create a 'global' object to store the business. Global only to the request.
-	globalInfo := graph.NewAppenderGlobalInfo()
-	globalInfo.Business = business
-
-	trafficMap := istio.BuildNodeTrafficMap(o.TelemetryOptions, client, globalInfo)
+	trafficMap := buildTrafficMap(business, client, o, func(client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+		return vendor.BuildNodeTrafficMap(o.TelemetryOptions, client, globalInfo)
+	})
 	code, config = generateGraph(trafficMap, o)
 
 	return code, config
 }
 
+func getTelemetryVendor(o graph.Options) graph.TelemetryVendor {
+	vendor, ok := graph.GetTelemetryVendor(o.TelemetryVendor)
+	if !ok {
+		graph.Error(fmt.Sprintf("TelemetryVendor [%s] not supported", o.TelemetryVendor))
+	}
+	return vendor
+}
+
 func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface{}) {
 	log.Tracef("Generating config for [%s] graph...", o.ConfigVendor)
 
@@ -94,6 +132,12 @@ func generateGraph(trafficMap graph.TrafficMap, o graph.Options) (int, interface
 	switch o.ConfigVendor {
 	case graph.VendorCytoscape:
 		vendorConfig = cytoscape.NewConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorDot:
+		vendorConfig = dot.NewConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorGraphML:
+		vendorConfig = graphml.NewConfig(trafficMap, o.ConfigOptions)
+	case graph.VendorMermaid:
+		vendorConfig = mermaid.NewConfig(trafficMap, o.ConfigOptions)
 	default:
 		graph.Error(fmt.Sprintf("ConfigVendor [%s] not supported", o.ConfigVendor))
 	}
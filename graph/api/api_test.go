@@ -23,6 +23,8 @@ import (
 	"github.com/kiali/kiali/business"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/analysis"
+	"github.com/kiali/kiali/graph/snapshot"
 	"github.com/kiali/kiali/kubernetes/kubetest"
 	"github.com/kiali/kiali/prometheus"
 	"github.com/kiali/kiali/prometheus/prometheustest"
@@ -1079,7 +1081,7 @@ func TestAppGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=app&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1118,7 +1120,7 @@ func TestVersionedAppGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=versionedApp&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1157,7 +1159,7 @@ func TestServiceGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=service&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1196,7 +1198,7 @@ func TestWorkloadGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=workload&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1235,7 +1237,7 @@ func TestRatesGraphSent(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=workload&appenders&queryTime=1523364075&rateGrpc=sent&rateHttp=requests&rateTcp=sent"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1274,7 +1276,7 @@ func TestRatesGraphReceived(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=workload&appenders&queryTime=1523364075&rateGrpc=received&rateHttp=requests&rateTcp=received"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1313,7 +1315,7 @@ func TestRatesGraphTotal(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=workload&appenders&queryTime=1523364075&rateGrpc=total&rateHttp=requests&rateTcp=total"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1352,7 +1354,7 @@ func TestRatesGraphNone(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?namespaces=bookinfo&graphType=workload&appenders&queryTime=1523364075&rateGrpc=total&rateHttp=none&rateTcp=total"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1628,7 +1630,7 @@ func TestWorkloadNodeGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNodeIstio
+	fut = graphNode
 	url := ts.URL + "/api/namespaces/bookinfo/workloads/productpage-v1/graph?graphType=workload&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -1904,7 +1906,7 @@ func TestAppNodeGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNodeIstio
+	fut = graphNode
 	url := ts.URL + "/api/namespaces/bookinfo/applications/productpage/graph?graphType=versionedApp&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -2180,7 +2182,7 @@ func TestVersionedAppNodeGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNodeIstio
+	fut = graphNode
 	url := ts.URL + "/api/namespaces/bookinfo/applications/productpage/versions/v1/graph?graphType=versionedApp&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -2266,7 +2268,7 @@ func TestServiceNodeGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNodeIstio
+	fut = graphNode
 	url := ts.URL + "/api/namespaces/bookinfo/services/productpage/graph?graphType=workload&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -2633,7 +2635,7 @@ func TestRatesNodeGraphTotal(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNodeIstio
+	fut = graphNode
 	url := ts.URL + "/api/namespaces/bookinfo/workloads/productpage-v1/graph?rateGrpc=total&rateHttp=requests&rateTcp=total&graphType=workload&appenders&queryTime=1523364075"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -2794,7 +2796,7 @@ func TestRatesNodeGraphTotal(t *testing.T) {
 		ts := httptest.NewServer(mr)
 		defer ts.Close()
 
-		fut = graphNodeIstio
+		fut = graphNode
 		url := ts.URL + "/api/namespaces/bookinfo/services/productpage/graph?graphType=workload&appenders&queryTime=1523364075"
 		resp, err := http.Get(url)
 		if err != nil {
@@ -3297,7 +3299,7 @@ func TestComplexGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?graphType=versionedApp&appenders=&queryTime=1523364075&namespaces=bookinfo,tutorial,istio-system"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -3563,7 +3565,7 @@ func TestMultiClusterSourceGraph(t *testing.T) {
 	ts := httptest.NewServer(mr)
 	defer ts.Close()
 
-	fut = graphNamespacesIstio
+	fut = graphNamespaces
 	url := ts.URL + "/api/namespaces/graph?graphType=versionedApp&injectServiceNodes=true&includeIdleEdges=true&appenders=&queryTime=1523364075&namespaces=bookinfo"
 	resp, err := http.Get(url)
 	if err != nil {
@@ -3581,3 +3583,109 @@ func TestMultiClusterSourceGraph(t *testing.T) {
 	}
 	assert.Equal(t, 200, resp.StatusCode)
 }
+
+func TestSnapshotGraph(t *testing.T) {
+	client, _, err := mockNamespaceGraph(t)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	store := snapshot.NewMemoryStore(0)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/graph/snapshots", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			code, info := snapshotNamespaces(nil, client, graph.NewOptions(r.WithContext(context)), "test", store)
+			respond(w, code, info)
+		}))
+	mr.HandleFunc("/api/graph/snapshots/{snapshot}", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			s, err := store.Get(mux.Vars(r)["snapshot"])
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, config := GraphSnapshot(s, graph.NewConfigOptions(r))
+			respond(w, code, config)
+		}))
+
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	url := ts.URL + "/api/graph/snapshots?namespaces=bookinfo&graphType=app&appenders&queryTime=1523364075"
+	resp, err := http.Post(url, "application/json", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+	info := snapshot.Info{}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "test", info.Name)
+	assert.Equal(t, []string{"bookinfo"}, info.Namespaces)
+
+	// the snapshot must render exactly as the live graph did
+	resp, err = http.Get(ts.URL + "/api/graph/snapshots/" + info.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, _ := ioutil.ReadAll(resp.Body)
+	expected, _ := ioutil.ReadFile("testdata/test_app_graph.expected")
+	if runtime.GOOS == "windows" {
+		expected = bytes.Replace(expected, []byte("\r\n"), []byte("\n"), -1)
+	}
+	expected = expected[:len(expected)-1] // remove EOF byte
+
+	if !assert.Equal(t, expected, actual) {
+		fmt.Printf("\nActual:\n%v", string(actual))
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+}
+
+func TestAnalyzeBlastRadius(t *testing.T) {
+	client, _, err := mockNamespaceGraph(t)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/graph/analysis/blastradius", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			node := r.URL.Query().Get("node")
+			code, result := analyze(nil, client, graph.NewOptions(r.WithContext(context)), func(trafficMap graph.TrafficMap) (interface{}, error) {
+				return analysis.NewBlastRadius(trafficMap, node)
+			})
+			respond(w, code, result)
+		}))
+
+	ts := httptest.NewServer(mr)
+	defer ts.Close()
+
+	url := ts.URL + "/api/graph/analysis/blastradius?namespaces=bookinfo&graphType=app&injectServiceNodes=false&appenders&queryTime=1523364075"
+	resp, err := http.Get(url + "&node=app_unknown_bookinfo_productpage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 200, resp.StatusCode)
+	br := analysis.BlastRadius{}
+	if err := json.NewDecoder(resp.Body).Decode(&br); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "app_unknown_bookinfo_productpage", br.Node.TrafficID)
+	assert.NotEmpty(t, br.Upstream)
+	assert.NotEmpty(t, br.Downstream)
+
+	defer func() {
+		r := recover()
+		assert.Equal(t, graph.Response{Message: analysis.ErrNodeNotFound.Error(), Code: http.StatusNotFound}, r)
+	}()
+	o := graph.Options{TelemetryVendor: graph.VendorIstio}
+	o.Namespaces = graph.NamespaceInfoMap{}
+	analyze(nil, client, o, func(trafficMap graph.TrafficMap) (interface{}, error) {
+		return analysis.NewBlastRadius(trafficMap, "nope")
+	})
+}
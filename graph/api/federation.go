@@ -0,0 +1,172 @@
+package api
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/util"
+)
+
+// trafficMapBuilder builds a traffic map from the telemetry of a single Prometheus
+type trafficMapBuilder func(client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap
+
+// buildTrafficMap builds the traffic map from the local Prometheus and, when graph federation is enabled, merges
+// the traffic maps built from the Prometheus of each federated cluster. Each cluster's traffic map is built by
+// the same telemetry vendor, with its own global info so that appenders query that cluster's Prometheus.
+func buildTrafficMap(business *business.Layer, prom *prometheus.Client, o graph.Options, build trafficMapBuilder) graph.TrafficMap {
+	// Create a 'global' object to store the business. Global only to the request.
+	globalInfo := graph.NewAppenderGlobalInfo()
+	globalInfo.Business = business
+
+	trafficMap := build(prom, globalInfo)
+
+	clients := federatedPrometheusClients(business)
+	if len(clients) == 0 {
+		return trafficMap
+	}
+
+	clusterTrafficMaps := make(map[string]graph.TrafficMap, len(clients))
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+	wg.Add(len(clients))
+	for cluster, client := range clients {
+		go func(cluster string, client *prometheus.Client) {
+			defer wg.Done()
+			clusterGlobalInfo := graph.NewAppenderGlobalInfo()
+			clusterGlobalInfo.Business = business
+			clusterGlobalInfo.PromClient = client
+
+			if clusterTrafficMap := buildClusterTrafficMap(cluster, func() graph.TrafficMap { return build(client, clusterGlobalInfo) }); clusterTrafficMap != nil {
+				mutex.Lock()
+				clusterTrafficMaps[cluster] = clusterTrafficMap
+				mutex.Unlock()
+			}
+		}(cluster, client)
+	}
+	wg.Wait()
+
+	return federateTrafficMaps(trafficMap, clusterTrafficMaps)
+}
+
+// buildClusterTrafficMap returns nil when the traffic map of a federated cluster can't be built, an unreachable
+// cluster must not fail the whole graph.
+func buildClusterTrafficMap(cluster string, build func() graph.TrafficMap) (trafficMap graph.TrafficMap) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Warningf("Skipping the traffic of cluster [%s] in the graph: %v", cluster, r)
+			trafficMap = nil
+		}
+	}()
+	return build()
+}
+
+// federateTrafficMaps merges the traffic maps of the federated clusters into the local traffic map, cross-cluster
+// edges are stitched together from the source and destination clusters of the nodes. Traffic generators are marked
+// again, a root node of one cluster can have incoming traffic from another cluster.
+func federateTrafficMaps(trafficMap graph.TrafficMap, clusterTrafficMaps map[string]graph.TrafficMap) graph.TrafficMap {
+	clusters := make([]string, 0, len(clusterTrafficMaps))
+	for cluster := range clusterTrafficMaps {
+		clusters = append(clusters, cluster)
+	}
+	sort.Strings(clusters)
+
+	for _, cluster := range clusters {
+		telemetry.MergeClusterTrafficMaps(trafficMap, cluster, clusterTrafficMaps[cluster])
+	}
+
+	// edges of the dropped duplicates still point to the nodes of their own traffic map
+	for _, n := range trafficMap {
+		delete(n.Metadata, graph.IsRoot)
+		for _, e := range n.Edges {
+			e.Source = n
+			if dest, ok := trafficMap[e.Dest.ID]; ok {
+				e.Dest = dest
+			}
+		}
+	}
+	telemetry.MarkTrafficGenerators(trafficMap)
+
+	return trafficMap
+}
+
+// meshClustersTTL is how long the mesh clusters discovered for graph federation are reused, the discovery queries
+// the remote clusters
+const meshClustersTTL = time.Minute
+
+var (
+	meshClusters       []business.Cluster
+	meshClustersExpiry time.Time
+	meshClustersMutex  sync.Mutex
+)
+
+// federatedPrometheusClients returns the Prometheus clients of the federated clusters, by cluster name. It is
+// empty when graph federation is disabled. The clusters are the remote clusters of the mesh, discovered by the
+// mesh service from the Istio remote secrets, which have a configured Prometheus. The home cluster is never
+// federated: its telemetry is the local Prometheus.
+func federatedPrometheusClients(business *business.Layer) map[string]*prometheus.Client {
+	clients := map[string]*prometheus.Client{}
+	federation := config.Get().Graph.Federation
+	if !federation.Enabled {
+		return clients
+	}
+
+	clusters, err := getMeshClusters(business)
+	if err != nil {
+		log.Warningf("Unable to resolve the mesh clusters for graph federation: %v", err)
+		return clients
+	}
+	for name, promConfig := range federatedClusters(clusters, federation.Clusters) {
+		client, err := prometheus.NewClientForConfig(promConfig)
+		if err != nil {
+			log.Warningf("Skipping the traffic of cluster [%s] in the graph, unable to create the Prometheus client: %v", name, err)
+			continue
+		}
+		clients[name] = client
+	}
+	return clients
+}
+
+// getMeshClusters returns the clusters of the mesh, cached for meshClustersTTL
+func getMeshClusters(layer *business.Layer) ([]business.Cluster, error) {
+	meshClustersMutex.Lock()
+	defer meshClustersMutex.Unlock()
+	if now := util.Clock.Now(); now.After(meshClustersExpiry) {
+		clusters, err := layer.Mesh.GetClusters(nil)
+		if err != nil {
+			return nil, err
+		}
+		meshClusters = clusters
+		meshClustersExpiry = now.Add(meshClustersTTL)
+	}
+	return meshClusters, nil
+}
+
+// federatedClusters returns the Prometheus configs of the remote mesh clusters, by cluster name. The remote
+// clusters without a configured Prometheus are skipped, as are the configured clusters which are not part of the mesh.
+func federatedClusters(clusters []business.Cluster, configs []config.GraphFederationClusterConfig) map[string]config.PrometheusConfig {
+	promConfigs := make(map[string]config.PrometheusConfig, len(configs))
+	for _, c := range configs {
+		promConfigs[c.Name] = c.Prometheus
+	}
+
+	federated := map[string]config.PrometheusConfig{}
+	for _, cluster := range clusters {
+		if cluster.IsKialiHome {
+			continue
+		}
+		promConfig, ok := promConfigs[cluster.Name]
+		if !ok || promConfig.URL == "" {
+			log.Debugf("Skipping the traffic of cluster [%s] in the graph, no Prometheus is configured for it", cluster.Name)
+			continue
+		}
+		federated[cluster.Name] = promConfig
+	}
+	return federated
+}
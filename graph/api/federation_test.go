@@ -0,0 +1,97 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func addFederationNode(trafficMap graph.TrafficMap, cluster, namespace, workload string) *graph.Node {
+	node := graph.NewNode(cluster, namespace, "", namespace, workload, workload, "v1", graph.GraphTypeWorkload)
+	if n, ok := trafficMap[node.ID]; ok {
+		return n
+	}
+	trafficMap[node.ID] = &node
+	return &node
+}
+
+func addFederationEdge(source, dest *graph.Node, val float64) {
+	edge := source.AddEdge(dest)
+	edge.Metadata[graph.ProtocolKey] = graph.HTTP.Name
+	graph.AddToMetadata(graph.HTTP.Name, val, "200", "-", dest.Workload, source.Metadata, dest.Metadata, edge.Metadata)
+}
+
+func TestFederateTrafficMaps(t *testing.T) {
+	assert := assert.New(t)
+
+	// east telemetry: productpage (east) calls reviews (west), reported by the source proxy
+	east := graph.NewTrafficMap()
+	eastProductpage := addFederationNode(east, "east", "bookinfo", "productpage")
+	eastProductpage.Metadata[graph.IsRoot] = true
+	addFederationEdge(eastProductpage, addFederationNode(east, "west", "bookinfo", "reviews"), 10.0)
+
+	// west telemetry: the same request, reported by the destination proxy, and reviews calling ratings
+	west := graph.NewTrafficMap()
+	westReviews := addFederationNode(west, "west", "bookinfo", "reviews")
+	westReviews.Metadata[graph.IsRoot] = true
+	addFederationEdge(addFederationNode(west, "east", "bookinfo", "productpage"), westReviews, 10.0)
+	addFederationEdge(westReviews, addFederationNode(west, "west", "bookinfo", "ratings"), 5.0)
+
+	trafficMap := federateTrafficMaps(east, map[string]graph.TrafficMap{"west": west})
+
+	assert.Len(trafficMap, 3)
+	productpage := trafficMap["wl_east_bookinfo_productpage"]
+	reviews := trafficMap["wl_west_bookinfo_reviews"]
+	ratings := trafficMap["wl_west_bookinfo_ratings"]
+	assert.NotNil(productpage)
+	assert.NotNil(reviews)
+	assert.NotNil(ratings)
+
+	// the cross-cluster edge is counted once and points to the west node
+	assert.Len(productpage.Edges, 1)
+	assert.Same(reviews, productpage.Edges[0].Dest)
+	assert.Equal(10.0, productpage.Edges[0].Metadata[graph.HTTP.EdgeResponses].(graph.Responses)["200"].Flags["-"])
+	assert.Same(reviews, westReviews)
+	assert.Len(reviews.Edges, 1)
+	assert.Same(ratings, reviews.Edges[0].Dest)
+
+	// reviews has incoming traffic from east, it is no longer a traffic generator
+	assert.Equal(true, productpage.Metadata[graph.IsRoot])
+	assert.Nil(reviews.Metadata[graph.IsRoot])
+	assert.Nil(ratings.Metadata[graph.IsRoot])
+}
+
+func TestBuildClusterTrafficMapFailure(t *testing.T) {
+	assert := assert.New(t)
+
+	trafficMap := buildClusterTrafficMap("west", func() graph.TrafficMap {
+		graph.Error("Prometheus unreachable")
+		return graph.NewTrafficMap()
+	})
+	assert.Nil(trafficMap)
+
+	trafficMap = buildClusterTrafficMap("west", func() graph.TrafficMap {
+		return graph.NewTrafficMap()
+	})
+	assert.NotNil(trafficMap)
+}
+
+func TestFederatedClusters(t *testing.T) {
+	assert := assert.New(t)
+
+	clusters := []business.Cluster{{Name: "east", IsKialiHome: true}, {Name: "west"}, {Name: "north"}}
+	configs := []config.GraphFederationClusterConfig{
+		{Name: "east", Prometheus: config.PrometheusConfig{URL: "http://prometheus.east:9090"}},
+		{Name: "west", Prometheus: config.PrometheusConfig{URL: "http://prometheus.west:9090"}},
+		{Name: "south", Prometheus: config.PrometheusConfig{URL: "http://prometheus.south:9090"}},
+	}
+
+	// the home cluster, the clusters without Prometheus and the clusters out of the mesh are not federated
+	federated := federatedClusters(clusters, configs)
+	assert.Len(federated, 1)
+	assert.Equal("http://prometheus.west:9090", federated["west"].URL)
+}
@@ -0,0 +1,78 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/trace"
+)
+
+// GraphTrace generates the graph of a single trace, the workloads and services its requests went through. The
+// nodes have the same IDs as in the traffic graphs, for the trace to be overlaid on them.
+func GraphTrace(business *business.Layer, o graph.Options, traceID string) (code int, config interface{}) {
+	singleTrace, err := business.Jaeger.GetJaegerTraceDetail(traceID)
+	graph.CheckUnavailable(err)
+	if singleTrace == nil {
+		graph.Panic(fmt.Sprintf("Trace [%s] not found", traceID), http.StatusNotFound)
+	}
+
+	trafficMap := trace.BuildTrafficMap(&singleTrace.Data, trace.Options{
+		Cluster:            o.NodeOptions.Cluster,
+		GraphType:          o.TelemetryOptions.GraphType,
+		InjectServiceNodes: o.InjectServiceNodes,
+	}, workloadResolver(business))
+
+	// the graph covers the trace time span
+	var start, end uint64
+	for _, span := range singleTrace.Data.Spans {
+		if start == 0 || span.StartTime < start {
+			start = span.StartTime
+		}
+		if span.StartTime+span.Duration > end {
+			end = span.StartTime + span.Duration
+		}
+	}
+	o.ConfigOptions.QueryTime = int64(end / 1000000)
+	o.ConfigOptions.Duration = time.Duration(end-start) * time.Microsecond
+	o.TelemetryOptions.CommonOptions = o.ConfigOptions.CommonOptions
+
+	return generateGraph(trafficMap, o)
+}
+
+// workloadResolver resolves the workload of a pod from the workloads of its namespace, fetched once per namespace
+func workloadResolver(business *business.Layer) trace.WorkloadResolver {
+	type namespaceWorkloads struct {
+		names      []string
+		accessible bool
+	}
+	cache := map[string]namespaceWorkloads{}
+
+	return func(namespace, pod string) (string, bool) {
+		workloads, found := cache[namespace]
+		if !found {
+			// the trace may go through namespaces not accessible to the user
+			if _, err := business.Namespace.GetNamespace(namespace); err == nil {
+				workloads.accessible = true
+				if list, err := business.Workload.GetWorkloadList(namespace, false); err == nil {
+					for _, w := range list.Workloads {
+						workloads.names = append(workloads.names, w.Name)
+					}
+				}
+			}
+			cache[namespace] = workloads
+		}
+
+		// the pods are named after their workload, e.g. reviews-v1-545db77b95-x5l2s, the longest match wins
+		workload := ""
+		for _, name := range workloads.names {
+			if strings.HasPrefix(pod, name+"-") && len(name) > len(workload) {
+				workload = name
+			}
+		}
+		return workload, workloads.accessible
+	}
+}
@@ -8,3 +8,11 @@ type ConfigVendor interface {
 	// definitions for error handling. Refer to the Cytoscape implementation as an example.
 	NewConfig(trafficMap TrafficMap, o ConfigOptions) interface{}
 }
+
+// TextConfig is implemented by a ConfigVendor's Config when it is not meant to be
+// serialized as JSON (e.g. DOT or GraphML). The handler writes Bytes() verbatim and
+// sets the response Content-Type to ContentType().
+type TextConfig interface {
+	ContentType() string
+	Bytes() []byte
+}
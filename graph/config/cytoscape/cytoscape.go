@@ -89,6 +89,8 @@ type NodeData struct {
 	Version               string              `json:"version,omitempty"`
 	Service               string              `json:"service,omitempty"`               // requested service for NodeTypeService
 	Aggregate             string              `json:"aggregate,omitempty"`             // set like "<aggregate>=<aggregateVal>"
+	Anomalies             []string            `json:"anomalies,omitempty"`             // anomalous metrics of the incoming traffic
+	AnomalyScores         map[string]string   `json:"anomalyScores,omitempty"`         // highest z-score of the incoming traffic, per metric
 	DestServices          []graph.ServiceName `json:"destServices,omitempty"`          // requested services for [dest] node
 	Traffic               []ProtocolTraffic   `json:"traffic,omitempty"`               // traffic rates for all detected protocols
 	HasCB                 bool                `json:"hasCB,omitempty"`                 // true (has circuit breaker) | false
@@ -118,12 +120,15 @@ type EdgeData struct {
 	Target string `json:"target"` // child node ID
 
 	// App Fields (not required by Cytoscape)
-	DestPrincipal   string          `json:"destPrincipal,omitempty"`   // principal used for the edge destination
-	IsMTLS          string          `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
-	ResponseTime    string          `json:"responseTime,omitempty"`    // in millis
-	SourcePrincipal string          `json:"sourcePrincipal,omitempty"` // principal used for the edge source
-	Throughput      string          `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
-	Traffic         ProtocolTraffic `json:"traffic,omitempty"`         // traffic rates for the edge protocol
+	Anomalies       []string          `json:"anomalies,omitempty"`       // anomalous metrics: rate | errorRatio | responseTime
+	AnomalyScores   map[string]string `json:"anomalyScores,omitempty"`   // z-score against the baseline window, per metric
+	DestPrincipal   string            `json:"destPrincipal,omitempty"`   // principal used for the edge destination
+	IsError         bool              `json:"isError,omitempty"`         // true when a span of a trace graph edge failed
+	IsMTLS          string            `json:"isMTLS,omitempty"`          // set to the percentage of traffic using a mutual TLS connection
+	ResponseTime    string            `json:"responseTime,omitempty"`    // in millis
+	SourcePrincipal string            `json:"sourcePrincipal,omitempty"` // principal used for the edge source
+	Throughput      string            `json:"throughput,omitempty"`      // in bytes/sec (request or response, depends on client request)
+	Traffic         ProtocolTraffic   `json:"traffic,omitempty"`         // traffic rates for the edge protocol
 }
 
 type NodeWrapper struct {
@@ -146,7 +151,8 @@ type Config struct {
 	Elements  Elements `json:"elements"`
 }
 
-func nodeHash(id string) string {
+// NodeHash returns the cytoscape node ID for a TrafficMap node ID
+func NodeHash(id string) string {
 	return fmt.Sprintf("%x", md5.Sum([]byte(id)))
 }
 
@@ -228,7 +234,7 @@ func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) (result Confi
 
 func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*EdgeWrapper, o graph.ConfigOptions) {
 	for id, n := range trafficMap {
-		nodeID := nodeHash(id)
+		nodeID := NodeHash(id)
 
 		nd := &NodeData{
 			ID:        nodeID,
@@ -248,6 +254,14 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 			nd.HasHealthConfig = val.(map[string]string)
 		}
 
+		// node may have anomalous incoming traffic
+		if val, ok := n.Metadata[graph.Anomalies]; ok {
+			nd.Anomalies = val.([]string)
+		}
+		if val, ok := n.Metadata[graph.AnomalyScores]; ok {
+			nd.AnomalyScores = anomalyScoresToString(val.(graph.AnomalyScoresMetadata))
+		}
+
 		// node may have deployment but no pods running)
 		if val, ok := n.Metadata[graph.IsDead]; ok {
 			nd.IsDead = val.(bool)
@@ -356,8 +370,8 @@ func buildConfig(trafficMap graph.TrafficMap, nodes *[]*NodeWrapper, edges *[]*E
 		*nodes = append(*nodes, &nw)
 
 		for _, e := range n.Edges {
-			sourceIDHash := nodeHash(n.ID)
-			destIDHash := nodeHash(e.Dest.ID)
+			sourceIDHash := NodeHash(n.ID)
+			destIDHash := NodeHash(e.Dest.ID)
 			protocol := ""
 			if e.Metadata[graph.ProtocolKey] != nil {
 				protocol = e.Metadata[graph.ProtocolKey].(string)
@@ -408,6 +422,15 @@ func addNodeTelemetry(n *graph.Node, nd *NodeData) {
 }
 
 func addEdgeTelemetry(e *graph.Edge, ed *EdgeData) {
+	if val, ok := e.Metadata[graph.Anomalies]; ok {
+		ed.Anomalies = val.([]string)
+	}
+	if val, ok := e.Metadata[graph.AnomalyScores]; ok {
+		ed.AnomalyScores = anomalyScoresToString(val.(graph.AnomalyScoresMetadata))
+	}
+	if val, ok := e.Metadata[graph.IsError]; ok {
+		ed.IsError = val.(bool)
+	}
 	if val, ok := e.Metadata[graph.IsMTLS]; ok {
 		ed.IsMTLS = fmt.Sprintf("%.0f", val.(float64))
 	}
@@ -548,7 +571,7 @@ func generateBoxCompoundNodes(box map[string][]*NodeData, nodes *[]*NodeWrapper,
 	for k, members := range box {
 		if boxBy != graph.BoxByApp || len(members) > 1 {
 			// create the compound (parent) node for the member nodes
-			nodeID := nodeHash(k)
+			nodeID := NodeHash(k)
 			namespace := ""
 			app := ""
 			switch boxBy {
@@ -619,3 +642,11 @@ func calcPrecision(val float64, max int) int {
 	}
 	return precision
 }
+
+func anomalyScoresToString(scores graph.AnomalyScoresMetadata) map[string]string {
+	result := make(map[string]string, len(scores))
+	for metric, z := range scores {
+		result[metric] = fmt.Sprintf("%.2f", z)
+	}
+	return result
+}
@@ -0,0 +1,235 @@
+package cytoscape
+
+// Text.go provides helpers for config vendors that render the Cytoscape config into a
+// text-based graph format (e.g. DOT, GraphML, Mermaid).
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// Attribute is a single name/value pair describing a node or edge
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// NodeLabel returns a short, human readable label for the node
+func NodeLabel(nd *NodeData) string {
+	switch nd.NodeType {
+	case graph.NodeTypeAggregate:
+		return nd.Aggregate
+	case graph.NodeTypeApp:
+		if nd.Version != "" {
+			return fmt.Sprintf("%s %s", nd.App, nd.Version)
+		}
+		return nd.App
+	case graph.NodeTypeBox:
+		switch nd.IsBox {
+		case graph.BoxByApp:
+			return nd.App
+		case graph.BoxByNamespace:
+			return nd.Namespace
+		default:
+			return nd.Cluster
+		}
+	case graph.NodeTypeService:
+		return nd.Service
+	case graph.NodeTypeUnknown:
+		return graph.Unknown
+	default:
+		return nd.Workload
+	}
+}
+
+// EdgeLabel returns a short, human readable label for the edge traffic, e.g. "http 10.00rps 5.0%err"
+func EdgeLabel(ed *EdgeData) string {
+	protocol := ed.Traffic.Protocol
+	if protocol == "" {
+		return ""
+	}
+	for _, p := range graph.Protocols {
+		if p.Name != protocol {
+			continue
+		}
+		label := protocol
+		for _, r := range p.EdgeRates {
+			if r.IsTotal {
+				if val, ok := ed.Traffic.Rates[string(r.Name)]; ok {
+					label = fmt.Sprintf("%s %s%s", label, val, p.UnitShort)
+				}
+			}
+		}
+		if percentErr := EdgePercentErr(ed); percentErr > 0 {
+			label = fmt.Sprintf("%s %.1f%%err", label, percentErr)
+		}
+		return label
+	}
+	return protocol
+}
+
+// NodePercentErr returns the percentage of inbound requests, across all request protocols, that
+// resulted in error. It returns 0 when the node has no inbound request traffic.
+func NodePercentErr(nd *NodeData) float64 {
+	in := 0.0
+	err := 0.0
+	for _, pt := range nd.Traffic {
+		for _, p := range graph.Protocols {
+			if p.Name != pt.Protocol {
+				continue
+			}
+			for _, r := range p.NodeRates {
+				switch {
+				case r.IsIn:
+					in += parseRate(pt.Rates[string(r.Name)])
+				case r.IsErr:
+					err += parseRate(pt.Rates[string(r.Name)])
+				}
+			}
+		}
+	}
+	if in == 0 {
+		return 0
+	}
+	return err / in * 100
+}
+
+// EdgePercentErr returns the percentage of edge requests that resulted in error, or 0 when
+// not applicable to the edge protocol.
+func EdgePercentErr(ed *EdgeData) float64 {
+	for _, p := range graph.Protocols {
+		if p.Name != ed.Traffic.Protocol {
+			continue
+		}
+		for _, r := range p.EdgeRates {
+			if r.IsPercentErr {
+				return parseRate(ed.Traffic.Rates[string(r.Name)])
+			}
+		}
+	}
+	return 0
+}
+
+// NodeAttributes returns the node's identifying, traffic and health attributes, in a predictable order.
+// Unset (empty or false) values are omitted.
+func NodeAttributes(nd *NodeData) []Attribute {
+	attrs := []Attribute{}
+	add := func(name, value string) {
+		if value != "" {
+			attrs = append(attrs, Attribute{Name: name, Value: value})
+		}
+	}
+	addBool := func(name string, value bool) {
+		if value {
+			add(name, "true")
+		}
+	}
+
+	add("nodeType", nd.NodeType)
+	add("cluster", nd.Cluster)
+	add("namespace", nd.Namespace)
+	add("workload", nd.Workload)
+	add("app", nd.App)
+	add("version", nd.Version)
+	add("service", nd.Service)
+	add("aggregate", nd.Aggregate)
+	add("isBox", nd.IsBox)
+
+	for _, pt := range nd.Traffic {
+		attrs = append(attrs, rateAttributes(pt.Rates)...)
+	}
+	if percentErr := NodePercentErr(nd); percentErr > 0 {
+		add("percentErr", fmt.Sprintf("%.1f", percentErr))
+	}
+	attrs = append(attrs, anomalyAttributes(nd.Anomalies, nd.AnomalyScores)...)
+	if len(nd.HasHealthConfig) > 0 {
+		keys := make([]string, 0, len(nd.HasHealthConfig))
+		for k := range nd.HasHealthConfig {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			add(fmt.Sprintf("healthConfig.%s", k), nd.HasHealthConfig[k])
+		}
+	}
+
+	addBool("hasCB", nd.HasCB)
+	addBool("hasMissingSC", nd.HasMissingSC)
+	addBool("hasVS", nd.HasVS != nil)
+	addBool("isDead", nd.IsDead)
+	addBool("isGateway", nd.IsGateway != nil)
+	addBool("isIdle", nd.IsIdle)
+	addBool("isInaccessible", nd.IsInaccessible)
+	addBool("isOutside", nd.IsOutside)
+	addBool("isRoot", nd.IsRoot)
+	addBool("isServiceEntry", nd.IsServiceEntry != nil)
+
+	return attrs
+}
+
+// EdgeAttributes returns the edge's traffic and health attributes, in a predictable order. Unset
+// values are omitted.
+func EdgeAttributes(ed *EdgeData) []Attribute {
+	attrs := []Attribute{}
+	add := func(name, value string) {
+		if value != "" {
+			attrs = append(attrs, Attribute{Name: name, Value: value})
+		}
+	}
+
+	add("protocol", ed.Traffic.Protocol)
+	attrs = append(attrs, rateAttributes(ed.Traffic.Rates)...)
+	add("responseTime", ed.ResponseTime)
+	add("throughput", ed.Throughput)
+	attrs = append(attrs, anomalyAttributes(ed.Anomalies, ed.AnomalyScores)...)
+	add("isMTLS", ed.IsMTLS)
+	add("sourcePrincipal", ed.SourcePrincipal)
+	add("destPrincipal", ed.DestPrincipal)
+
+	return attrs
+}
+
+func anomalyAttributes(anomalies []string, scores map[string]string) []Attribute {
+	attrs := []Attribute{}
+	if len(anomalies) > 0 {
+		attrs = append(attrs, Attribute{Name: "anomalies", Value: strings.Join(anomalies, ",")})
+	}
+	metrics := make([]string, 0, len(scores))
+	for metric := range scores {
+		metrics = append(metrics, metric)
+	}
+	sort.Strings(metrics)
+	for _, metric := range metrics {
+		attrs = append(attrs, Attribute{Name: fmt.Sprintf("anomalyScore.%s", metric), Value: scores[metric]})
+	}
+	return attrs
+}
+
+func rateAttributes(rates map[string]string) []Attribute {
+	names := make([]string, 0, len(rates))
+	for name := range rates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]Attribute, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, Attribute{Name: name, Value: rates[name]})
+	}
+	return attrs
+}
+
+func parseRate(rate string) float64 {
+	if rate == "" {
+		return 0
+	}
+	val, err := strconv.ParseFloat(rate, 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
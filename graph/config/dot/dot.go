@@ -0,0 +1,126 @@
+// Package dot provides conversion from our graph to the Graphviz DOT language.
+//
+// The following links are useful for understanding DOT:
+//
+// Language:   https://graphviz.org/doc/info/lang.html
+// Attributes: https://graphviz.org/doc/info/attrs.html
+//
+// Algorithm: Generate the Cytoscape config, which gives us the decorated nodes, edges
+// and requested boxing. Then write each box as a "cluster" subgraph holding
+// its member nodes, followed by the edges. Output is sorted, so that it can
+// be diffed in source control.
+//
+// The package provides the DOT implementation of graph/ConfigVendor.
+package dot
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// ContentType is the media type for DOT content
+const ContentType = "text/vnd.graphviz"
+
+// Config is the DOT representation of the graph, it satisfies graph.TextConfig
+type Config []byte
+
+// ContentType is required by the graph.TextConfig interface
+func (c Config) ContentType() string {
+	return ContentType
+}
+
+// Bytes is required by the graph.TextConfig interface
+func (c Config) Bytes() []byte {
+	return c
+}
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) Config {
+	cyConfig := cytoscape.NewConfig(trafficMap, o)
+
+	// group nodes by parent, the nodes are already sorted with boxes first
+	children := make(map[string][]*cytoscape.NodeData)
+	for _, nw := range cyConfig.Elements.Nodes {
+		children[nw.Data.Parent] = append(children[nw.Data.Parent], nw.Data)
+	}
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "digraph %s {\n", quote("kiali"))
+	fmt.Fprintf(sb, "  graph [rankdir=LR, graphType=%s, duration=%d, timestamp=%d];\n", quote(cyConfig.GraphType), cyConfig.Duration, cyConfig.Timestamp)
+	writeNodes(sb, children, "", 1)
+	for _, ew := range cyConfig.Elements.Edges {
+		ed := ew.Data
+		attrs := []string{fmt.Sprintf("label=%s", quote(cytoscape.EdgeLabel(ed)))}
+		if cytoscape.EdgePercentErr(ed) > 0 {
+			attrs = append(attrs, "color=red")
+		}
+		attrs = append(attrs, toAttrs(cytoscape.EdgeAttributes(ed))...)
+		fmt.Fprintf(sb, "  %s -> %s [%s];\n", quote(ed.Source), quote(ed.Target), strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+
+	return Config(sb.String())
+}
+
+// writeNodes writes the nodes with the given parent, recursing into box nodes
+func writeNodes(sb *strings.Builder, children map[string][]*cytoscape.NodeData, parent string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, nd := range children[parent] {
+		if nd.NodeType == graph.NodeTypeBox {
+			// graphviz only draws subgraphs prefixed with "cluster"
+			fmt.Fprintf(sb, "%ssubgraph %s {\n", indent, quote("cluster_"+nd.ID))
+			fmt.Fprintf(sb, "%s  label=%s;\n", indent, quote(fmt.Sprintf("%s: %s", nd.IsBox, cytoscape.NodeLabel(nd))))
+			for _, attr := range toAttrs(cytoscape.NodeAttributes(nd)) {
+				fmt.Fprintf(sb, "%s  %s;\n", indent, attr)
+			}
+			writeNodes(sb, children, nd.ID, depth+1)
+			fmt.Fprintf(sb, "%s}\n", indent)
+			continue
+		}
+
+		attrs := []string{fmt.Sprintf("label=%s", quote(cytoscape.NodeLabel(nd))), fmt.Sprintf("shape=%s", shape(nd.NodeType))}
+		switch {
+		case nd.IsDead:
+			attrs = append(attrs, "style=dashed")
+		case cytoscape.NodePercentErr(nd) > 0:
+			attrs = append(attrs, "color=red")
+		}
+		attrs = append(attrs, toAttrs(cytoscape.NodeAttributes(nd))...)
+		fmt.Fprintf(sb, "%s%s [%s];\n", indent, quote(nd.ID), strings.Join(attrs, ", "))
+	}
+}
+
+// shape approximates the Kiali node shapes
+func shape(nodeType string) string {
+	switch nodeType {
+	case graph.NodeTypeAggregate:
+		return "diamond"
+	case graph.NodeTypeApp:
+		return "box"
+	case graph.NodeTypeService:
+		return "triangle"
+	case graph.NodeTypeUnknown:
+		return "doublecircle"
+	default:
+		return "ellipse"
+	}
+}
+
+func toAttrs(attributes []cytoscape.Attribute) []string {
+	attrs := make([]string, len(attributes))
+	for i, a := range attributes {
+		attrs[i] = fmt.Sprintf("%s=%s", quote(a.Name), quote(a.Value))
+	}
+	return attrs
+}
+
+// quote returns s as a DOT double-quoted string
+func quote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,68 @@
+package dot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func buildTrafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode("east", "bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	reviewsV1 := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1.Metadata[graph.IsDead] = true
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[reviewsV1.ID] = &reviewsV1
+
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 9.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 1.0, "503", "UH", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.ResponseTime] = 25.0
+
+	e = reviews.AddEdge(&reviewsV1)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 150.0, "", "-", "reviews", reviews.Metadata, reviewsV1.Metadata, e.Metadata)
+
+	return trafficMap
+}
+
+const expected = `digraph "kiali" {
+  graph [rankdir=LR, graphType="versionedApp", duration=600, timestamp=1523364075];
+  subgraph "cluster_ebaaf46a58a3e46848e9ac79a6bbaea1" {
+    label="namespace: bookinfo";
+    "nodeType"="box";
+    "cluster"="east";
+    "namespace"="bookinfo";
+    "isBox"="namespace";
+    "14a8076f0380581fe9e2fd757895a231" [label="reviews", shape=triangle, color=red, "nodeType"="service", "cluster"="east", "namespace"="bookinfo", "service"="reviews", "httpIn"="10.00", "httpIn5xx"="1.00", "tcpOut"="150.00", "percentErr"="10.0"];
+    "618cde0596062954dd7ceab6b6daf357" [label="productpage v1", shape=box, "nodeType"="app", "cluster"="east", "namespace"="bookinfo", "workload"="productpage-v1", "app"="productpage", "version"="v1", "httpOut"="10.00"];
+    "d7d2de426988db482baf04ac252f49d6" [label="reviews v1", shape=box, style=dashed, "nodeType"="app", "cluster"="east", "namespace"="bookinfo", "workload"="reviews-v1", "app"="reviews", "version"="v1", "tcpIn"="150.00", "isDead"="true"];
+  }
+  "14a8076f0380581fe9e2fd757895a231" -> "d7d2de426988db482baf04ac252f49d6" [label="tcp 150.00bps", "protocol"="tcp", "tcp"="150.00"];
+  "618cde0596062954dd7ceab6b6daf357" -> "14a8076f0380581fe9e2fd757895a231" [label="http 10.00rps 10.0%err", color=red, "protocol"="http", "http"="10.00", "http5xx"="1.00", "httpPercentErr"="10.0", "httpPercentReq"="100.0", "responseTime"="25"];
+}
+`
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.ConfigOptions{
+		BoxBy: graph.BoxByNamespace,
+		CommonOptions: graph.CommonOptions{
+			Duration:  10 * time.Minute,
+			GraphType: graph.GraphTypeVersionedApp,
+			QueryTime: 1523364075,
+		},
+	}
+	config := NewConfig(buildTrafficMap(), o)
+
+	assert.Equal(ContentType, config.ContentType())
+	assert.Equal(expected, string(config.Bytes()))
+}
@@ -0,0 +1,212 @@
+// Package graphml provides conversion from our graph to the GraphML XML format, suitable
+// for import into tools like Gephi or yEd.
+//
+// The following links are useful for understanding GraphML:
+//
+// Primer:   http://graphml.graphdrawing.org/primer/graphml-primer.html
+// Schema:   http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd
+//
+// Algorithm: Generate the Cytoscape config, which gives us the decorated nodes, edges
+// and requested boxing. Declare a typed <key> for every attribute in use and
+// then write the nodes and edges. Box nodes are written as regular nodes and
+// member nodes reference them with the "parent" attribute, because nested
+// graphs are not supported by all consumers.
+//
+// The package provides the GraphML implementation of graph/ConfigVendor.
+package graphml
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/log"
+)
+
+// ContentType is the media type for GraphML content
+const ContentType = "application/graphml+xml"
+
+const (
+	keyForEdge  string = "edge"
+	keyForGraph string = "graph"
+	keyForNode  string = "node"
+	xmlns       string = "http://graphml.graphdrawing.org/xmlns"
+)
+
+// stringKeys are the attributes holding text, most others are numeric rates or boolean flags
+var stringKeys = map[string]bool{
+	"aggregate":       true,
+	"app":             true,
+	"cluster":         true,
+	"destPrincipal":   true,
+	"graphType":       true,
+	"isBox":           true,
+	"label":           true,
+	"namespace":       true,
+	"nodeType":        true,
+	"parent":          true,
+	"protocol":        true,
+	"service":         true,
+	"sourcePrincipal": true,
+	"version":         true,
+	"workload":        true,
+}
+
+// Config is the GraphML representation of the graph, it satisfies graph.TextConfig
+type Config []byte
+
+// ContentType is required by the graph.TextConfig interface
+func (c Config) ContentType() string {
+	return ContentType
+}
+
+// Bytes is required by the graph.TextConfig interface
+func (c Config) Bytes() []byte {
+	return c
+}
+
+type graphML struct {
+	XMLName xml.Name `xml:"graphml"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	Keys    []key    `xml:"key"`
+	Graph   graphEl  `xml:"graph"`
+}
+
+type key struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphEl struct {
+	ID          string   `xml:"id,attr"`
+	EdgeDefault string   `xml:"edgedefault,attr"`
+	Data        []data   `xml:"data"`
+	Nodes       []nodeEl `xml:"node"`
+	Edges       []edgeEl `xml:"edge"`
+}
+
+type nodeEl struct {
+	ID   string `xml:"id,attr"`
+	Data []data `xml:"data"`
+}
+
+type edgeEl struct {
+	ID     string `xml:"id,attr"`
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []data `xml:"data"`
+}
+
+type data struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// keys assigns a GraphML key to each attribute name, per element type
+type keys map[string]map[string]string
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) Config {
+	cyConfig := cytoscape.NewConfig(trafficMap, o)
+
+	graphAttrs := []cytoscape.Attribute{
+		{Name: "graphType", Value: cyConfig.GraphType},
+		{Name: "duration", Value: fmt.Sprintf("%d", cyConfig.Duration)},
+		{Name: "timestamp", Value: fmt.Sprintf("%d", cyConfig.Timestamp)},
+	}
+	nodeAttrs := make([][]cytoscape.Attribute, len(cyConfig.Elements.Nodes))
+	for i, nw := range cyConfig.Elements.Nodes {
+		nd := nw.Data
+		attrs := []cytoscape.Attribute{{Name: "label", Value: cytoscape.NodeLabel(nd)}}
+		if nd.Parent != "" {
+			attrs = append(attrs, cytoscape.Attribute{Name: "parent", Value: nd.Parent})
+		}
+		nodeAttrs[i] = append(attrs, cytoscape.NodeAttributes(nd)...)
+	}
+	edgeAttrs := make([][]cytoscape.Attribute, len(cyConfig.Elements.Edges))
+	for i, ew := range cyConfig.Elements.Edges {
+		ed := ew.Data
+		attrs := []cytoscape.Attribute{{Name: "label", Value: cytoscape.EdgeLabel(ed)}}
+		edgeAttrs[i] = append(attrs, cytoscape.EdgeAttributes(ed)...)
+	}
+
+	doc := graphML{Xmlns: xmlns}
+	keys := keys{}
+	doc.Keys = append(doc.Keys, keys.declare(keyForGraph, [][]cytoscape.Attribute{graphAttrs})...)
+	doc.Keys = append(doc.Keys, keys.declare(keyForNode, nodeAttrs)...)
+	doc.Keys = append(doc.Keys, keys.declare(keyForEdge, edgeAttrs)...)
+
+	doc.Graph = graphEl{
+		ID:          "kiali",
+		EdgeDefault: "directed",
+		Data:        keys.data(keyForGraph, graphAttrs),
+	}
+	for i, nw := range cyConfig.Elements.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, nodeEl{ID: nw.Data.ID, Data: keys.data(keyForNode, nodeAttrs[i])})
+	}
+	for i, ew := range cyConfig.Elements.Edges {
+		ed := ew.Data
+		doc.Graph.Edges = append(doc.Graph.Edges, edgeEl{ID: ed.ID, Source: ed.Source, Target: ed.Target, Data: keys.data(keyForEdge, edgeAttrs[i])})
+	}
+
+	out, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		log.Errorf("Failed to marshal GraphML: %v", err)
+		graph.Error(fmt.Sprintf("Failed to marshal GraphML: %v", err))
+	}
+
+	return Config(xml.Header + string(out) + "\n")
+}
+
+// declare assigns (sorted, and therefore predictable) keys to the attribute names in use
+// for the element type, and returns the key declarations
+func (k keys) declare(keyFor string, elementAttrs [][]cytoscape.Attribute) []key {
+	names := map[string]bool{}
+	for _, attrs := range elementAttrs {
+		for _, a := range attrs {
+			names[a.Name] = true
+		}
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+
+	k[keyFor] = make(map[string]string, len(sorted))
+	declarations := make([]key, len(sorted))
+	for i, name := range sorted {
+		id := fmt.Sprintf("%s%d", keyFor[:1], i)
+		k[keyFor][name] = id
+		declarations[i] = key{ID: id, For: keyFor, AttrName: name, AttrType: attrType(name)}
+	}
+	return declarations
+}
+
+func (k keys) data(keyFor string, attrs []cytoscape.Attribute) []data {
+	result := make([]data, len(attrs))
+	for i, a := range attrs {
+		result[i] = data{Key: k[keyFor][a.Name], Value: a.Value}
+	}
+	return result
+}
+
+// attrType returns the GraphML attr.type for the attribute, typed values allow tools
+// like Gephi to size and color by rate.
+func attrType(name string) string {
+	switch {
+	case stringKeys[name] || strings.HasPrefix(name, "healthConfig."):
+		return "string"
+	case name != "isMTLS" && (strings.HasPrefix(name, "is") || strings.HasPrefix(name, "has")):
+		return "boolean"
+	case name == "duration" || name == "timestamp":
+		return "long"
+	default:
+		return "double"
+	}
+}
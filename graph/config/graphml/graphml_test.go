@@ -0,0 +1,104 @@
+package graphml
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func buildTrafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode("east", "bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	reviewsV1 := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1.Metadata[graph.IsDead] = true
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[reviewsV1.ID] = &reviewsV1
+
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 9.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 1.0, "503", "UH", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.ResponseTime] = 25.0
+
+	e = reviews.AddEdge(&reviewsV1)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 150.0, "", "-", "reviews", reviews.Metadata, reviewsV1.Metadata, e.Metadata)
+
+	return trafficMap
+}
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.ConfigOptions{
+		BoxBy: graph.BoxByNamespace,
+		CommonOptions: graph.CommonOptions{
+			Duration:  10 * time.Minute,
+			GraphType: graph.GraphTypeVersionedApp,
+			QueryTime: 1523364075,
+		},
+	}
+	config := NewConfig(buildTrafficMap(), o)
+
+	assert.Equal(ContentType, config.ContentType())
+	doc := graphML{}
+	assert.NoError(xml.Unmarshal(config.Bytes(), &doc))
+	assert.Equal(xmlns, doc.Xmlns)
+	assert.Equal("directed", doc.Graph.EdgeDefault)
+	assert.Len(doc.Graph.Nodes, 4) // 3 nodes + namespace box
+	assert.Len(doc.Graph.Edges, 2)
+
+	keyTypes := map[string]string{}
+	keyNames := map[string]string{}
+	for _, k := range doc.Keys {
+		keyTypes[k.For+"."+k.AttrName] = k.AttrType
+		keyNames[k.ID] = k.AttrName
+	}
+	assert.Equal("string", keyTypes["node.nodeType"])
+	assert.Equal("string", keyTypes["node.parent"])
+	assert.Equal("double", keyTypes["node.httpIn"])
+	assert.Equal("double", keyTypes["node.percentErr"])
+	assert.Equal("boolean", keyTypes["node.isDead"])
+	assert.Equal("double", keyTypes["edge.httpPercentErr"])
+	assert.Equal("long", keyTypes["graph.duration"])
+
+	boxID := ""
+	for _, n := range doc.Graph.Nodes {
+		attrs := map[string]string{}
+		for _, d := range n.Data {
+			attrs[keyNames[d.Key]] = d.Value
+		}
+		switch attrs["label"] {
+		case "bookinfo":
+			assert.Equal(graph.NodeTypeBox, attrs["nodeType"])
+			assert.Equal(graph.BoxByNamespace, attrs["isBox"])
+			boxID = n.ID
+		case "reviews":
+			assert.Equal(graph.NodeTypeService, attrs["nodeType"])
+			assert.Equal("10.0", attrs["percentErr"])
+			assert.Equal(boxID, attrs["parent"])
+		case "reviews v1":
+			assert.Equal("true", attrs["isDead"])
+			assert.Equal(boxID, attrs["parent"])
+		}
+	}
+
+	for _, e := range doc.Graph.Edges {
+		attrs := map[string]string{}
+		for _, d := range e.Data {
+			attrs[keyNames[d.Key]] = d.Value
+		}
+		if attrs["protocol"] == "http" {
+			assert.Equal("http 10.00rps 10.0%err", attrs["label"])
+			assert.Equal("1.00", attrs["http5xx"])
+			assert.Equal("25", attrs["responseTime"])
+		}
+	}
+}
@@ -0,0 +1,147 @@
+// Package mermaid provides conversion from our graph to a Mermaid flowchart, suitable for
+// embedding in markdown documentation.
+//
+// The following links are useful for understanding Mermaid flowcharts:
+//
+// Syntax: https://mermaid-js.github.io/mermaid/#/flowchart
+//
+// Algorithm: Generate the Cytoscape config, which gives us the decorated nodes, edges
+// and requested boxing. Then write each box as a subgraph holding its member
+// nodes, followed by the edges. Node type is reflected in the node shape and
+// health is reflected using style classes.
+//
+// The package provides the Mermaid implementation of graph/ConfigVendor.
+package mermaid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// ContentType is the media type for Mermaid content
+const ContentType = "text/vnd.mermaid"
+
+// Style classes
+const (
+	classDead     string = "dead"
+	classError    string = "error"
+	classIdle     string = "idle"
+	errorStroke   string = "stroke:#c9190b"
+	idleStroke    string = "stroke:#b8bbbe"
+	dashedOutline string = "stroke-dasharray: 5 5"
+)
+
+// Config is the Mermaid representation of the graph, it satisfies graph.TextConfig
+type Config []byte
+
+// ContentType is required by the graph.TextConfig interface
+func (c Config) ContentType() string {
+	return ContentType
+}
+
+// Bytes is required by the graph.TextConfig interface
+func (c Config) Bytes() []byte {
+	return c
+}
+
+// NewConfig is required by the graph/ConfigVendor interface
+func NewConfig(trafficMap graph.TrafficMap, o graph.ConfigOptions) Config {
+	cyConfig := cytoscape.NewConfig(trafficMap, o)
+
+	// group nodes by parent, the nodes are already sorted with boxes first
+	children := make(map[string][]*cytoscape.NodeData)
+	for _, nw := range cyConfig.Elements.Nodes {
+		children[nw.Data.Parent] = append(children[nw.Data.Parent], nw.Data)
+	}
+	classes := make(map[string][]string)
+
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%%%% kiali %s graph, duration=%ds, timestamp=%d\n", cyConfig.GraphType, cyConfig.Duration, cyConfig.Timestamp)
+	sb.WriteString("flowchart LR\n")
+	writeNodes(sb, children, classes, "", 1)
+
+	errorLinks := []string{}
+	for i, ew := range cyConfig.Elements.Edges {
+		ed := ew.Data
+		if label := cytoscape.EdgeLabel(ed); label != "" {
+			fmt.Fprintf(sb, "  %s -->|%s| %s\n", id(ed.Source), quote(label), id(ed.Target))
+		} else {
+			fmt.Fprintf(sb, "  %s --> %s\n", id(ed.Source), id(ed.Target))
+		}
+		if cytoscape.EdgePercentErr(ed) > 0 {
+			errorLinks = append(errorLinks, fmt.Sprintf("%d", i))
+		}
+	}
+
+	fmt.Fprintf(sb, "  classDef %s %s\n", classDead, dashedOutline)
+	fmt.Fprintf(sb, "  classDef %s %s\n", classError, errorStroke)
+	fmt.Fprintf(sb, "  classDef %s %s\n", classIdle, idleStroke)
+	classNames := make([]string, 0, len(classes))
+	for class := range classes {
+		classNames = append(classNames, class)
+	}
+	sort.Strings(classNames)
+	for _, class := range classNames {
+		fmt.Fprintf(sb, "  class %s %s\n", strings.Join(classes[class], ","), class)
+	}
+	if len(errorLinks) > 0 {
+		fmt.Fprintf(sb, "  linkStyle %s %s\n", strings.Join(errorLinks, ","), errorStroke)
+	}
+
+	return Config(sb.String())
+}
+
+// writeNodes writes the nodes with the given parent, recursing into box nodes
+func writeNodes(sb *strings.Builder, children map[string][]*cytoscape.NodeData, classes map[string][]string, parent string, depth int) {
+	indent := strings.Repeat("  ", depth)
+	for _, nd := range children[parent] {
+		if nd.NodeType == graph.NodeTypeBox {
+			fmt.Fprintf(sb, "%ssubgraph %s [%s]\n", indent, id(nd.ID), quote(fmt.Sprintf("%s: %s", nd.IsBox, cytoscape.NodeLabel(nd))))
+			writeNodes(sb, children, classes, nd.ID, depth+1)
+			fmt.Fprintf(sb, "%send\n", indent)
+			continue
+		}
+
+		label := cytoscape.NodeLabel(nd)
+		switch percentErr := cytoscape.NodePercentErr(nd); {
+		case nd.IsDead:
+			classes[classDead] = append(classes[classDead], id(nd.ID))
+		case percentErr > 0:
+			label = fmt.Sprintf("%s<br/>%.1f%%err", label, percentErr)
+			classes[classError] = append(classes[classError], id(nd.ID))
+		case nd.IsIdle:
+			classes[classIdle] = append(classes[classIdle], id(nd.ID))
+		}
+		fmt.Fprintf(sb, "%s%s%s\n", indent, id(nd.ID), shape(nd.NodeType, quote(label)))
+	}
+}
+
+// shape wraps the label to approximate the Kiali node shapes
+func shape(nodeType, label string) string {
+	switch nodeType {
+	case graph.NodeTypeAggregate:
+		return fmt.Sprintf("{%s}", label)
+	case graph.NodeTypeApp:
+		return fmt.Sprintf("(%s)", label)
+	case graph.NodeTypeService:
+		return fmt.Sprintf("{{%s}}", label)
+	case graph.NodeTypeUnknown:
+		return fmt.Sprintf("((%s))", label)
+	default:
+		return fmt.Sprintf("[%s]", label)
+	}
+}
+
+// id returns a Mermaid-safe identifier for the node ID
+func id(nodeID string) string {
+	return "n" + nodeID
+}
+
+// quote returns s as a Mermaid quoted string, escaping embedded quotes
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,68 @@
+package mermaid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func buildTrafficMap() graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	reviews := graph.NewNode("east", "bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	reviewsV1 := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1.Metadata[graph.IsDead] = true
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[reviewsV1.ID] = &reviewsV1
+
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 9.0, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", 1.0, "503", "UH", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.ResponseTime] = 25.0
+
+	e = reviews.AddEdge(&reviewsV1)
+	e.Metadata[graph.ProtocolKey] = "tcp"
+	graph.AddToMetadata("tcp", 150.0, "", "-", "reviews", reviews.Metadata, reviewsV1.Metadata, e.Metadata)
+
+	return trafficMap
+}
+
+const expected = `%% kiali versionedApp graph, duration=600s, timestamp=1523364075
+flowchart LR
+  subgraph nebaaf46a58a3e46848e9ac79a6bbaea1 ["namespace: bookinfo"]
+    n14a8076f0380581fe9e2fd757895a231{{"reviews<br/>10.0%err"}}
+    n618cde0596062954dd7ceab6b6daf357("productpage v1")
+    nd7d2de426988db482baf04ac252f49d6("reviews v1")
+  end
+  n14a8076f0380581fe9e2fd757895a231 -->|"tcp 150.00bps"| nd7d2de426988db482baf04ac252f49d6
+  n618cde0596062954dd7ceab6b6daf357 -->|"http 10.00rps 10.0%err"| n14a8076f0380581fe9e2fd757895a231
+  classDef dead stroke-dasharray: 5 5
+  classDef error stroke:#c9190b
+  classDef idle stroke:#b8bbbe
+  class nd7d2de426988db482baf04ac252f49d6 dead
+  class n14a8076f0380581fe9e2fd757895a231 error
+  linkStyle 1 stroke:#c9190b
+`
+
+func TestNewConfig(t *testing.T) {
+	assert := assert.New(t)
+
+	o := graph.ConfigOptions{
+		BoxBy: graph.BoxByNamespace,
+		CommonOptions: graph.CommonOptions{
+			Duration:  10 * time.Minute,
+			GraphType: graph.GraphTypeVersionedApp,
+			QueryTime: 1523364075,
+		},
+	}
+	config := NewConfig(buildTrafficMap(), o)
+
+	assert.Equal(ContentType, config.ContentType())
+	assert.Equal(expected, string(config.Bytes()))
+}
@@ -15,6 +15,8 @@ func NewMetadata() Metadata {
 const (
 	Aggregate             MetadataKey = "aggregate" // the prom attribute used for aggregation
 	AggregateValue        MetadataKey = "aggregateValue"
+	Anomalies             MetadataKey = "anomalies"     // the anomalous metrics, see AnomalyScores
+	AnomalyScores         MetadataKey = "anomalyScores" // z-scores against the baseline window
 	DestPrincipal         MetadataKey = "destPrincipal"
 	DestServices          MetadataKey = "destServices"
 	HasCB                 MetadataKey = "hasCB"
@@ -29,6 +31,7 @@ const (
 	HasWorkloadEntry      MetadataKey = "hasWorkloadEntry"
 	IsDead                MetadataKey = "isDead"
 	IsEgressCluster       MetadataKey = "isEgressCluster"  // PassthroughCluster or BlackHoleCluster
+	IsError               MetadataKey = "isError"          // a trace graph edge with a failed span
 	IsIngressGateway      MetadataKey = "isIngressGateway" // Identifies a node that is an Istio ingress gateway
 	IsIdle                MetadataKey = "isIdle"
 	IsInaccessible        MetadataKey = "isInaccessible"
@@ -56,5 +59,16 @@ func (dsm DestServicesMetadata) Add(key string, service ServiceName) DestService
 	return dsm
 }
 
+// Metrics scored by the anomaly appender
+const (
+	AnomalyErrorRatio   = "errorRatio"
+	AnomalyRate         = "rate"
+	AnomalyResponseTime = "responseTime"
+)
+
+// AnomalyScoresMetadata maps an anomaly metric to the z-score of its current value against the
+// baseline window.
+type AnomalyScoresMetadata map[string]float64
+
 type GatewaysMetadata map[string][]string
 type VirtualServicesMetadata map[string][]string
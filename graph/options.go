@@ -22,7 +22,11 @@ import (
 // The supported vendors
 const (
 	VendorCytoscape        string = "cytoscape"
+	VendorDot              string = "dot"
+	VendorGraphML          string = "graphml"
 	VendorIstio            string = "istio"
+	VendorMermaid          string = "mermaid"
+	VendorOpenTelemetry    string = "otel"
 	defaultConfigVendor    string = VendorCytoscape
 	defaultTelemetryVendor string = VendorIstio
 )
@@ -153,11 +157,7 @@ func NewOptions(r *net_http.Request) Options {
 	if cluster == "" {
 		cluster = Unknown
 	}
-	if configVendor == "" {
-		configVendor = defaultConfigVendor
-	} else if configVendor != VendorCytoscape {
-		BadRequest(fmt.Sprintf("Invalid configVendor [%s]", configVendor))
-	}
+	configVendor = parseConfigVendor(configVendor)
 	if durationString == "" {
 		duration, _ = model.ParseDuration(defaultDuration)
 	} else {
@@ -176,22 +176,7 @@ func NewOptions(r *net_http.Request) Options {
 	if app != "" && graphType != GraphTypeApp && graphType != GraphTypeVersionedApp {
 		BadRequest(fmt.Sprintf("Invalid graphType [%s]. This node detail graph supports only graphType app or versionedApp.", graphType))
 	}
-	if boxBy == "" {
-		boxBy = defaultBoxBy
-	} else {
-		for _, box := range strings.Split(boxBy, ",") {
-			switch strings.TrimSpace(box) {
-			case BoxByApp:
-				continue
-			case BoxByCluster:
-				continue
-			case BoxByNamespace:
-				continue
-			default:
-				BadRequest(fmt.Sprintf("Invalid boxBy [%s]", boxBy))
-			}
-		}
-	}
+	boxBy = parseBoxBy(boxBy)
 	if includeIdleEdgesString == "" {
 		includeIdleEdges = defaultIncludeIdleEdges
 	} else {
@@ -221,8 +206,8 @@ func NewOptions(r *net_http.Request) Options {
 	}
 	if telemetryVendor == "" {
 		telemetryVendor = defaultTelemetryVendor
-	} else if telemetryVendor != VendorIstio {
-		BadRequest(fmt.Sprintf("Invalid telemetryVendor [%s]", telemetryVendor))
+	} else if _, ok := GetTelemetryVendor(telemetryVendor); !ok {
+		BadRequest(fmt.Sprintf("Invalid telemetryVendor, expecting one of (%s). [%s]", strings.Join(TelemetryVendors(), ", "), telemetryVendor))
 	}
 
 	// Process namespaces options:
@@ -363,6 +348,102 @@ func NewOptions(r *net_http.Request) Options {
 	return options
 }
 
+// NewConfigOptions returns the Options supporting only Config Vendor processing, for
+// requests that render an existing TrafficMap (e.g. a snapshot) and therefore do not
+// require telemetry. Only the configVendor and boxBy query params are processed.
+func NewConfigOptions(r *net_http.Request) Options {
+	params := r.URL.Query()
+
+	return Options{
+		ConfigVendor: parseConfigVendor(params.Get("configVendor")),
+		ConfigOptions: ConfigOptions{
+			BoxBy: parseBoxBy(params.Get("boxBy")),
+			CommonOptions: CommonOptions{
+				Params: params,
+			},
+		},
+	}
+}
+
+// NewTraceOptions returns the Options of a trace graph, a TrafficMap built from the spans of a single trace
+// rather than from telemetry. Only the configVendor, boxBy, cluster, graphType and injectServiceNodes query
+// params are processed. The service graph type is not supported, a trace is a path between workloads.
+func NewTraceOptions(r *net_http.Request) Options {
+	params := r.URL.Query()
+	cluster := params.Get("cluster")
+	graphType := params.Get("graphType")
+	injectServiceNodesString := params.Get("injectServiceNodes")
+
+	if cluster == "" {
+		cluster = Unknown
+	}
+	if graphType == "" {
+		graphType = GraphTypeWorkload
+	} else if graphType != GraphTypeApp && graphType != GraphTypeVersionedApp && graphType != GraphTypeWorkload {
+		BadRequest(fmt.Sprintf("Invalid graphType [%s]. A trace graph supports only graphType app, versionedApp or workload.", graphType))
+	}
+	injectServiceNodes := defaultInjectServiceNodes
+	if injectServiceNodesString != "" {
+		var injectServiceNodesErr error
+		injectServiceNodes, injectServiceNodesErr = strconv.ParseBool(injectServiceNodesString)
+		if injectServiceNodesErr != nil {
+			BadRequest(fmt.Sprintf("Invalid injectServiceNodes [%s]", injectServiceNodesString))
+		}
+	}
+
+	return Options{
+		ConfigVendor: parseConfigVendor(params.Get("configVendor")),
+		ConfigOptions: ConfigOptions{
+			BoxBy: parseBoxBy(params.Get("boxBy")),
+			CommonOptions: CommonOptions{
+				GraphType: graphType,
+				Params:    params,
+			},
+		},
+		TelemetryOptions: TelemetryOptions{
+			InjectServiceNodes: injectServiceNodes,
+			CommonOptions: CommonOptions{
+				GraphType: graphType,
+				Params:    params,
+			},
+			NodeOptions: NodeOptions{
+				Cluster: cluster,
+			},
+		},
+	}
+}
+
+func parseConfigVendor(configVendor string) string {
+	switch configVendor {
+	case "":
+		return defaultConfigVendor
+	case VendorCytoscape, VendorDot, VendorGraphML, VendorMermaid:
+		return configVendor
+	default:
+		BadRequest(fmt.Sprintf("Invalid configVendor [%s]", configVendor))
+	}
+	return configVendor
+}
+
+func parseBoxBy(boxBy string) string {
+	if boxBy == "" {
+		return defaultBoxBy
+	}
+	for _, box := range strings.Split(boxBy, ",") {
+		switch strings.TrimSpace(box) {
+		case BoxByApp:
+			continue
+		case BoxByCluster:
+			continue
+		case BoxByNamespace:
+			continue
+		default:
+			BadRequest(fmt.Sprintf("Invalid boxBy [%s]", boxBy))
+		}
+	}
+	return boxBy
+}
+
 // GetGraphKind will return the kind of graph represented by the options.
 func (o *TelemetryOptions) GetGraphKind() string {
 	if o.NodeOptions.App != "" ||
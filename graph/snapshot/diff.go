@@ -0,0 +1,198 @@
+package snapshot
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kiali/kiali/graph"
+)
+
+// Diff reports what changed between two snapshots
+type Diff struct {
+	From         Info        `json:"from"`
+	To           Info        `json:"to"`
+	AddedNodes   []NodeRef   `json:"addedNodes"`
+	RemovedNodes []NodeRef   `json:"removedNodes"`
+	AddedEdges   []EdgeRef   `json:"addedEdges"`
+	RemovedEdges []EdgeRef   `json:"removedEdges"`
+	ChangedEdges []EdgeDelta `json:"changedEdges"`
+}
+
+// NodeRef identifies a node
+type NodeRef struct {
+	ID        string `json:"id"`
+	NodeType  string `json:"nodeType"`
+	Cluster   string `json:"cluster"`
+	Namespace string `json:"namespace"`
+	Workload  string `json:"workload,omitempty"`
+	App       string `json:"app,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Service   string `json:"service,omitempty"`
+}
+
+// EdgeTraffic summarizes the traffic on an edge
+type EdgeTraffic struct {
+	Rate         float64 `json:"rate"`         // requests/sec, or bytes/sec for tcp
+	ErrorRate    float64 `json:"errorRate"`    // percentage of requests in error
+	ResponseTime float64 `json:"responseTime"` // in millis, 0 if not reported
+}
+
+// EdgeRef identifies an edge and its traffic
+type EdgeRef struct {
+	Source   string      `json:"source"`
+	Dest     string      `json:"dest"`
+	Protocol string      `json:"protocol"`
+	Traffic  EdgeTraffic `json:"traffic"`
+}
+
+// Delta is the change in a single value
+type Delta struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Delta float64 `json:"delta"`
+}
+
+// EdgeDelta reports the traffic changes for an edge present in both snapshots
+type EdgeDelta struct {
+	Source       string `json:"source"`
+	Dest         string `json:"dest"`
+	Protocol     string `json:"protocol"`
+	Rate         Delta  `json:"rate"`
+	ErrorRate    Delta  `json:"errorRate"`
+	ResponseTime Delta  `json:"responseTime"`
+}
+
+// NewDiff compares the from and to snapshots. Nodes are matched by ID, and edges are
+// matched by source, dest and protocol. Only edges with traffic changes are reported
+// as changed.
+func NewDiff(from, to *Snapshot) Diff {
+	diff := Diff{
+		From:         from.Info,
+		To:           to.Info,
+		AddedNodes:   []NodeRef{},
+		RemovedNodes: []NodeRef{},
+		AddedEdges:   []EdgeRef{},
+		RemovedEdges: []EdgeRef{},
+		ChangedEdges: []EdgeDelta{},
+	}
+
+	fromNodes, fromEdges := index(from)
+	toNodes, toEdges := index(to)
+
+	for id, n := range toNodes {
+		if _, ok := fromNodes[id]; !ok {
+			diff.AddedNodes = append(diff.AddedNodes, n)
+		}
+	}
+	for id, n := range fromNodes {
+		if _, ok := toNodes[id]; !ok {
+			diff.RemovedNodes = append(diff.RemovedNodes, n)
+		}
+	}
+	for k, toEdge := range toEdges {
+		fromEdge, ok := fromEdges[k]
+		if !ok {
+			diff.AddedEdges = append(diff.AddedEdges, toEdge)
+			continue
+		}
+		if fromEdge.Traffic == toEdge.Traffic {
+			continue
+		}
+		diff.ChangedEdges = append(diff.ChangedEdges, EdgeDelta{
+			Source:       toEdge.Source,
+			Dest:         toEdge.Dest,
+			Protocol:     toEdge.Protocol,
+			Rate:         newDelta(fromEdge.Traffic.Rate, toEdge.Traffic.Rate),
+			ErrorRate:    newDelta(fromEdge.Traffic.ErrorRate, toEdge.Traffic.ErrorRate),
+			ResponseTime: newDelta(fromEdge.Traffic.ResponseTime, toEdge.Traffic.ResponseTime),
+		})
+	}
+	for k, fromEdge := range fromEdges {
+		if _, ok := toEdges[k]; !ok {
+			diff.RemovedEdges = append(diff.RemovedEdges, fromEdge)
+		}
+	}
+
+	// sort for predictable results
+	sortNodes := func(nodes []NodeRef) {
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	}
+	sortEdges := func(edges []EdgeRef) {
+		sort.Slice(edges, func(i, j int) bool {
+			return edgeKey(edges[i].Source, edges[i].Dest, edges[i].Protocol) < edgeKey(edges[j].Source, edges[j].Dest, edges[j].Protocol)
+		})
+	}
+	sortNodes(diff.AddedNodes)
+	sortNodes(diff.RemovedNodes)
+	sortEdges(diff.AddedEdges)
+	sortEdges(diff.RemovedEdges)
+	sort.Slice(diff.ChangedEdges, func(i, j int) bool {
+		ei, ej := diff.ChangedEdges[i], diff.ChangedEdges[j]
+		return edgeKey(ei.Source, ei.Dest, ei.Protocol) < edgeKey(ej.Source, ej.Dest, ej.Protocol)
+	})
+
+	return diff
+}
+
+func index(s *Snapshot) (map[string]NodeRef, map[string]EdgeRef) {
+	nodes := make(map[string]NodeRef, len(s.Nodes))
+	edges := make(map[string]EdgeRef)
+	for _, n := range s.Nodes {
+		nodes[n.ID] = NodeRef{
+			ID:        n.ID,
+			NodeType:  n.NodeType,
+			Cluster:   n.Cluster,
+			Namespace: n.Namespace,
+			Workload:  n.Workload,
+			App:       n.App,
+			Version:   n.Version,
+			Service:   n.Service,
+		}
+		for _, e := range n.Edges {
+			protocol, _ := e.Metadata[graph.ProtocolKey].(string)
+			edges[edgeKey(n.ID, e.Dest, protocol)] = EdgeRef{
+				Source:   n.ID,
+				Dest:     e.Dest,
+				Protocol: protocol,
+				Traffic:  edgeTraffic(protocol, graph.Metadata(e.Metadata)),
+			}
+		}
+	}
+	return nodes, edges
+}
+
+func edgeKey(source, dest, protocol string) string {
+	return fmt.Sprintf("%s %s %s", source, dest, protocol)
+}
+
+// edgeTraffic summarizes the edge's protocol rates, using the protocol definitions to
+// identify the total and error rates
+func edgeTraffic(protocol string, md graph.Metadata) EdgeTraffic {
+	traffic := EdgeTraffic{}
+	if val, ok := md[graph.ResponseTime].(float64); ok {
+		traffic.ResponseTime = val
+	}
+	for _, p := range graph.Protocols {
+		if p.Name != protocol {
+			continue
+		}
+		errs := 0.0
+		for _, r := range p.EdgeRates {
+			val, _ := md[r.Name].(float64)
+			switch {
+			case r.IsTotal:
+				traffic.Rate = val
+			case r.IsErr:
+				errs += val
+			}
+		}
+		if traffic.Rate > 0 {
+			traffic.ErrorRate = errs / traffic.Rate * 100
+		}
+	}
+	return traffic
+}
+
+func newDelta(from, to float64) Delta {
+	return Delta{From: from, To: to, Delta: to - from}
+}
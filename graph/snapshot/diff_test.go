@@ -0,0 +1,49 @@
+package snapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDiff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	from, err := NewSnapshot(buildTrafficMap(false, 1.0), "from", buildOptions())
+	require.NoError(err)
+	to, err := NewSnapshot(buildTrafficMap(true, 4.0), "to", buildOptions())
+	require.NoError(err)
+
+	diff := NewDiff(from, to)
+	assert.Equal("from", diff.From.Name)
+	assert.Equal("to", diff.To.Name)
+
+	require.Len(diff.AddedNodes, 1)
+	assert.Equal("reviews-v2", diff.AddedNodes[0].Workload)
+	assert.Empty(diff.RemovedNodes)
+
+	require.Len(diff.AddedEdges, 1)
+	assert.Equal("svc_east_bookinfo_reviews", diff.AddedEdges[0].Source)
+	assert.Equal(diff.AddedNodes[0].ID, diff.AddedEdges[0].Dest)
+	assert.Equal(5.0, diff.AddedEdges[0].Traffic.Rate)
+	assert.Empty(diff.RemovedEdges)
+
+	// only the productpage -> reviews edge changed, the error rate went from 10% to 40%
+	require.Len(diff.ChangedEdges, 1)
+	changed := diff.ChangedEdges[0]
+	assert.Equal("http", changed.Protocol)
+	assert.Equal("svc_east_bookinfo_reviews", changed.Dest)
+	assert.Equal(Delta{From: 10.0, To: 10.0, Delta: 0.0}, changed.Rate)
+	assert.Equal(Delta{From: 10.0, To: 40.0, Delta: 30.0}, changed.ErrorRate)
+	assert.Equal(Delta{From: 25.0, To: 25.0, Delta: 0.0}, changed.ResponseTime)
+
+	// reversed, the node and edge are removed
+	diff = NewDiff(to, from)
+	assert.Empty(diff.AddedNodes)
+	assert.Len(diff.RemovedNodes, 1)
+	assert.Empty(diff.AddedEdges)
+	assert.Len(diff.RemovedEdges, 1)
+	assert.Equal(-30.0, diff.ChangedEdges[0].ErrorRate.Delta)
+}
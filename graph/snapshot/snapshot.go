@@ -0,0 +1,243 @@
+// Package snapshot provides persistence of graph TrafficMaps, allowing a graph to be
+// fetched as it was at the time the snapshot was taken, and two snapshots to be compared.
+//
+// A TrafficMap holds pointers between nodes, so it is serialized as a flat list of nodes,
+// each holding its outgoing edges by destination node ID. Node and edge Metadata is kept
+// in full. Because Metadata values are untyped, values for the well-known structured keys
+// are restored to their graph types when the snapshot is read back.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/util"
+)
+
+// validID guards the stores from ill-formed (or malicious) snapshot IDs
+var validID = regexp.MustCompile(`^[0-9]+-[0-9a-f]+$`)
+
+// Info describes a snapshot without its graph content
+type Info struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name,omitempty"`
+	Created    time.Time `json:"created"`
+	Duration   int64     `json:"duration"` // query duration in seconds
+	GraphType  string    `json:"graphType"`
+	Namespaces []string  `json:"namespaces"`
+	Timestamp  int64     `json:"timestamp"` // query time, unix seconds
+}
+
+// Snapshot is the serializable form of a TrafficMap
+type Snapshot struct {
+	Info
+	Nodes []*Node `json:"nodes"`
+}
+
+// Node is the serializable form of a graph.Node
+type Node struct {
+	ID        string             `json:"id"`
+	NodeType  string             `json:"nodeType"`
+	Cluster   string             `json:"cluster"`
+	Namespace string             `json:"namespace"`
+	Workload  string             `json:"workload,omitempty"`
+	App       string             `json:"app,omitempty"`
+	Version   string             `json:"version,omitempty"`
+	Service   string             `json:"service,omitempty"`
+	Edges     []*Edge            `json:"edges,omitempty"`
+	Metadata  serializedMetadata `json:"metadata,omitempty"`
+}
+
+// Edge is the serializable form of a graph.Edge
+type Edge struct {
+	Dest     string             `json:"dest"` // dest node ID
+	Metadata serializedMetadata `json:"metadata,omitempty"`
+}
+
+// serializedMetadata is graph.Metadata that is restored to the expected types on unmarshal
+type serializedMetadata graph.Metadata
+
+// NewSnapshot returns a snapshot of the trafficMap, generated for the provided options
+func NewSnapshot(trafficMap graph.TrafficMap, name string, o graph.Options) (*Snapshot, error) {
+	id, err := newID(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	namespaces := make([]string, 0, len(o.Namespaces))
+	for ns := range o.Namespaces {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	s := &Snapshot{
+		Info: Info{
+			ID:         id,
+			Name:       name,
+			Created:    time.Now(),
+			Duration:   int64(o.TelemetryOptions.Duration.Seconds()),
+			GraphType:  o.TelemetryOptions.GraphType,
+			Namespaces: namespaces,
+			Timestamp:  o.TelemetryOptions.QueryTime,
+		},
+		Nodes: make([]*Node, 0, len(trafficMap)),
+	}
+
+	for _, n := range trafficMap {
+		node := &Node{
+			ID:        n.ID,
+			NodeType:  n.NodeType,
+			Cluster:   n.Cluster,
+			Namespace: n.Namespace,
+			Workload:  n.Workload,
+			App:       n.App,
+			Version:   n.Version,
+			Service:   n.Service,
+			Metadata:  serializedMetadata(n.Metadata),
+		}
+		for _, e := range n.Edges {
+			node.Edges = append(node.Edges, &Edge{Dest: e.Dest.ID, Metadata: serializedMetadata(e.Metadata)})
+		}
+		s.Nodes = append(s.Nodes, node)
+	}
+
+	// sort for a predictable serialization
+	sort.Slice(s.Nodes, func(i, j int) bool {
+		return s.Nodes[i].ID < s.Nodes[j].ID
+	})
+
+	return s, nil
+}
+
+// TrafficMap rebuilds the snapshot's TrafficMap
+func (s *Snapshot) TrafficMap() (graph.TrafficMap, error) {
+	trafficMap := graph.NewTrafficMap()
+	for _, n := range s.Nodes {
+		trafficMap[n.ID] = &graph.Node{
+			ID:        n.ID,
+			NodeType:  n.NodeType,
+			Cluster:   n.Cluster,
+			Namespace: n.Namespace,
+			Workload:  n.Workload,
+			App:       n.App,
+			Version:   n.Version,
+			Service:   n.Service,
+			Edges:     []*graph.Edge{},
+			Metadata:  toMetadata(n.Metadata),
+		}
+	}
+	for _, n := range s.Nodes {
+		source := trafficMap[n.ID]
+		for _, e := range n.Edges {
+			dest, ok := trafficMap[e.Dest]
+			if !ok {
+				return nil, fmt.Errorf("snapshot [%s] is corrupt, edge [%s]->[%s] has no dest node", s.ID, n.ID, e.Dest)
+			}
+			edge := source.AddEdge(dest)
+			edge.Metadata = toMetadata(e.Metadata)
+		}
+	}
+	return trafficMap, nil
+}
+
+// ConfigOptions returns config options reflecting the snapshot, using boxBy as requested
+func (s *Snapshot) ConfigOptions(boxBy string) graph.ConfigOptions {
+	return graph.ConfigOptions{
+		BoxBy: boxBy,
+		CommonOptions: graph.CommonOptions{
+			Duration:  time.Duration(s.Duration) * time.Second,
+			GraphType: s.GraphType,
+			QueryTime: s.Timestamp,
+		},
+	}
+}
+
+// UnmarshalJSON restores the values of the structured metadata keys to their graph types
+func (m *serializedMetadata) UnmarshalJSON(data []byte) error {
+	raw := make(map[graph.MetadataKey]json.RawMessage)
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*m = make(serializedMetadata, len(raw))
+	for k, v := range raw {
+		val, err := unmarshalMetadataValue(k, v)
+		if err != nil {
+			return fmt.Errorf("invalid metadata [%s]: %v", k, err)
+		}
+		(*m)[k] = val
+	}
+	return nil
+}
+
+func unmarshalMetadataValue(k graph.MetadataKey, data json.RawMessage) (interface{}, error) {
+	var err error
+	switch k {
+	case graph.Anomalies:
+		val := []string{}
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.AnomalyScores:
+		val := make(graph.AnomalyScoresMetadata)
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.DestServices:
+		val := graph.NewDestServicesMetadata()
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.HasHealthConfig:
+		val := make(map[string]string)
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.HasVS:
+		val := make(graph.VirtualServicesMetadata)
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.IsIngressGateway:
+		val := make(graph.GatewaysMetadata)
+		err = json.Unmarshal(data, &val)
+		return val, err
+	case graph.IsServiceEntry:
+		val := &graph.SEInfo{}
+		err = json.Unmarshal(data, val)
+		return val, err
+	}
+
+	for _, p := range graph.Protocols {
+		if k == p.EdgeResponses {
+			val := make(graph.Responses)
+			err = json.Unmarshal(data, &val)
+			return val, err
+		}
+	}
+
+	// everything else is a simple bool, float64 or string
+	var val interface{}
+	err = json.Unmarshal(data, &val)
+	return val, err
+}
+
+func toMetadata(m serializedMetadata) graph.Metadata {
+	if m == nil {
+		return graph.NewMetadata()
+	}
+	return graph.Metadata(m)
+}
+
+// newID returns a new, time-ordered, snapshot ID
+func newID(t time.Time) (string, error) {
+	suffix, err := util.CryptoRandomBytes(4)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%x", t.Unix(), suffix), nil
+}
+
+// IsValidID returns true if id is a well-formed snapshot ID
+func IsValidID(id string) bool {
+	return validID.MatchString(id)
+}
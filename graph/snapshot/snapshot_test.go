@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/graph"
+)
+
+func buildOptions() graph.Options {
+	o := graph.Options{}
+	o.TelemetryOptions.Duration = 10 * time.Minute
+	o.TelemetryOptions.GraphType = graph.GraphTypeVersionedApp
+	o.TelemetryOptions.QueryTime = 1523364075
+	o.TelemetryOptions.Namespaces = graph.NamespaceInfoMap{
+		"tutorial": graph.NamespaceInfo{Name: "tutorial"},
+		"bookinfo": graph.NamespaceInfo{Name: "bookinfo"},
+	}
+	return o
+}
+
+// buildTrafficMap returns productpage -> reviews -> reviews-v1 (+ reviews-v2 if withV2), with
+// the provided 5xx rate on the productpage -> reviews edge
+func buildTrafficMap(withV2 bool, errRate float64) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	productpage := graph.NewNode("east", "bookinfo", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	productpage.Metadata[graph.IsIngressGateway] = graph.GatewaysMetadata{"bookinfo-gateway": []string{"*"}}
+	reviews := graph.NewNode("east", "bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	reviews.Metadata[graph.HasVS] = graph.VirtualServicesMetadata{"reviews": []string{"reviews"}}
+	reviews.Metadata[graph.DestServices] = graph.NewDestServicesMetadata().Add("east bookinfo reviews", graph.ServiceName{Cluster: "east", Namespace: "bookinfo", Name: "reviews"})
+	reviewsV1 := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	reviewsV1.Metadata[graph.HasHealthConfig] = map[string]string{"health.kiali.io/rate": "400,10,20,http,inbound"}
+	reviewsV1.Metadata[graph.IsServiceEntry] = &graph.SEInfo{Hosts: []string{"reviews.com"}, Location: "MESH_EXTERNAL", Namespace: "bookinfo"}
+	trafficMap[productpage.ID] = &productpage
+	trafficMap[reviews.ID] = &reviews
+	trafficMap[reviewsV1.ID] = &reviewsV1
+
+	e := productpage.AddEdge(&reviews)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 10.0-errRate, "200", "-", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	graph.AddToMetadata("http", errRate, "503", "UH", "reviews", productpage.Metadata, reviews.Metadata, e.Metadata)
+	e.Metadata[graph.ResponseTime] = 25.0
+
+	e = reviews.AddEdge(&reviewsV1)
+	e.Metadata[graph.ProtocolKey] = "http"
+	graph.AddToMetadata("http", 5.0, "200", "-", "reviews", reviews.Metadata, reviewsV1.Metadata, e.Metadata)
+
+	if withV2 {
+		reviewsV2 := graph.NewNode("east", "bookinfo", "", "bookinfo", "reviews-v2", "reviews", "v2", graph.GraphTypeVersionedApp)
+		trafficMap[reviewsV2.ID] = &reviewsV2
+		e = reviews.AddEdge(&reviewsV2)
+		e.Metadata[graph.ProtocolKey] = "http"
+		graph.AddToMetadata("http", 5.0, "200", "-", "reviews", reviews.Metadata, reviewsV2.Metadata, e.Metadata)
+	}
+
+	return trafficMap
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	trafficMap := buildTrafficMap(false, 1.0)
+	s, err := NewSnapshot(trafficMap, "before deploy", buildOptions())
+	require.NoError(err)
+
+	assert.True(IsValidID(s.ID))
+	assert.Equal("before deploy", s.Name)
+	assert.Equal(int64(600), s.Duration)
+	assert.Equal(graph.GraphTypeVersionedApp, s.GraphType)
+	assert.Equal([]string{"bookinfo", "tutorial"}, s.Namespaces)
+	assert.Equal(int64(1523364075), s.Timestamp)
+
+	data, err := json.Marshal(s)
+	require.NoError(err)
+	restored := &Snapshot{}
+	require.NoError(json.Unmarshal(data, restored))
+
+	restoredMap, err := restored.TrafficMap()
+	require.NoError(err)
+	require.Len(restoredMap, len(trafficMap))
+
+	for id, n := range trafficMap {
+		rn, ok := restoredMap[id]
+		require.True(ok, id)
+		assert.Equal(n.NodeType, rn.NodeType)
+		assert.Equal(n.Workload, rn.Workload)
+		assert.Equal(n.Service, rn.Service)
+		// typed metadata must be restored exactly
+		assert.Equal(n.Metadata, rn.Metadata, id)
+		require.Len(rn.Edges, len(n.Edges))
+		for i, e := range n.Edges {
+			assert.Equal(e.Dest.ID, rn.Edges[i].Dest.ID)
+			assert.Same(rn.Edges[i].Dest, restoredMap[e.Dest.ID])
+			assert.Equal(e.Metadata, rn.Edges[i].Metadata)
+		}
+	}
+}
+
+func TestSnapshotCorrupt(t *testing.T) {
+	s := &Snapshot{
+		Info:  Info{ID: "1-ab"},
+		Nodes: []*Node{{ID: "a", Edges: []*Edge{{Dest: "b"}}}},
+	}
+	_, err := s.TrafficMap()
+	assert.Error(t, err)
+}
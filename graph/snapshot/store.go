@@ -0,0 +1,358 @@
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// The supported stores
+const (
+	StoreFilesystem string = "filesystem"
+	StoreMemory     string = "memory"
+)
+
+// ErrNotFound is returned when the requested snapshot does not exist in the store
+var ErrNotFound = errors.New("snapshot not found")
+
+// Store is implemented by each snapshot persistence backend
+type Store interface {
+	// Delete removes the snapshot, returns ErrNotFound if it does not exist
+	Delete(id string) error
+
+	// Get returns the snapshot, returns ErrNotFound if it does not exist
+	Get(id string) (*Snapshot, error)
+
+	// List returns the info for every stored snapshot, oldest first
+	List() ([]Info, error)
+
+	// Save stores the snapshot, removing the oldest snapshots if the store is at capacity
+	Save(s *Snapshot) error
+}
+
+var store Store
+var storeMutex sync.Mutex
+
+// GetStore returns the configured snapshot store, initializing it on first use
+func GetStore() (Store, error) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+
+	if store == nil {
+		conf := config.Get().Graph.Snapshots
+		if !conf.Enabled {
+			return nil, errors.New("graph snapshots are disabled")
+		}
+		s, err := NewStore(conf)
+		if err != nil {
+			return nil, err
+		}
+		store = s
+	}
+	return store, nil
+}
+
+// SetStore replaces the global snapshot store, it is intended for testing
+func SetStore(s Store) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	store = s
+}
+
+// NewStore returns the store described by the configuration
+func NewStore(conf config.GraphSnapshotsConfig) (Store, error) {
+	switch conf.Store {
+	case StoreFilesystem:
+		return NewFilesystemStore(conf.Path, conf.MaxSnapshots)
+	case StoreMemory:
+		return NewMemoryStore(conf.MaxSnapshots), nil
+	default:
+		return nil, fmt.Errorf("graph snapshot store [%s] not supported", conf.Store)
+	}
+}
+
+// prune deletes the oldest snapshots until no more than max remain, ids are sorted oldest first
+func prune(s Store, ids []string, max int) error {
+	if max <= 0 {
+		return nil
+	}
+	for i := 0; i < len(ids)-max; i++ {
+		log.Debugf("Removing graph snapshot [%s], snapshot limit [%d] exceeded", ids[i], max)
+		if err := s.Delete(ids[i]); err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortInfos(infos []Info) {
+	// IDs are time-ordered
+	sort.Slice(infos, func(i, j int) bool {
+		return infos[i].ID < infos[j].ID
+	})
+}
+
+// FilesystemStore keeps each snapshot as a JSON file in a single directory, next to a JSON file with its Info: the
+// snapshots are listed without reading their graph
+type FilesystemStore struct {
+	dir          string
+	maxSnapshots int
+	mutex        sync.RWMutex
+}
+
+// NewFilesystemStore returns a store using dir, which is created if necessary
+func NewFilesystemStore(dir string, maxSnapshots int) (*FilesystemStore, error) {
+	if dir == "" {
+		return nil, errors.New("graph snapshot path is not configured")
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("unable to create graph snapshot directory [%s]: %v", dir, err)
+	}
+	return &FilesystemStore{dir: dir, maxSnapshots: maxSnapshots}, nil
+}
+
+func (fs *FilesystemStore) path(id string) (string, error) {
+	if !IsValidID(id) {
+		return "", ErrNotFound
+	}
+	return filepath.Join(fs.dir, id+".json"), nil
+}
+
+func (fs *FilesystemStore) infoPath(id string) string {
+	return filepath.Join(fs.dir, id+".info.json")
+}
+
+// ids returns the IDs of the stored snapshots, from the file names, oldest first. The caller holds the mutex.
+func (fs *FilesystemStore) ids() ([]string, error) {
+	files, err := ioutil.ReadDir(fs.dir)
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, f := range files {
+		// the info files are skipped, "<id>.info" is not a valid ID
+		id := strings.TrimSuffix(f.Name(), ".json")
+		if !f.IsDir() && IsValidID(id) {
+			ids = append(ids, id)
+		}
+	}
+	// IDs are time-ordered
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// readInfo returns the Info of a snapshot, from the snapshot itself when it has no info file. The caller holds the
+// mutex.
+func (fs *FilesystemStore) readInfo(id string) (Info, error) {
+	info := Info{}
+	data, err := ioutil.ReadFile(fs.infoPath(id))
+	if os.IsNotExist(err) {
+		data, err = ioutil.ReadFile(filepath.Join(fs.dir, id+".json"))
+	}
+	if err != nil {
+		return info, err
+	}
+	err = json.Unmarshal(data, &info)
+	return info, err
+}
+
+// writeFile writes the file and then renames it, so that a partially written file is never read
+func writeFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
+
+// Delete is required by the Store interface
+func (fs *FilesystemStore) Delete(id string) error {
+	path, err := fs.path(id)
+	if err != nil {
+		return err
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if err := os.Remove(fs.infoPath(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Get is required by the Store interface
+func (fs *FilesystemStore) Get(id string) (*Snapshot, error) {
+	path, err := fs.path(id)
+	if err != nil {
+		return nil, err
+	}
+
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("unable to read graph snapshot [%s]: %v", id, err)
+	}
+	return s, nil
+}
+
+// List is required by the Store interface
+func (fs *FilesystemStore) List() ([]Info, error) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+
+	ids, err := fs.ids()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]Info, 0, len(ids))
+	for _, id := range ids {
+		info, err := fs.readInfo(id)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// deleted meanwhile
+				continue
+			}
+			log.Warningf("Skipping unreadable graph snapshot [%s]: %v", id, err)
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// Save is required by the Store interface
+func (fs *FilesystemStore) Save(s *Snapshot) error {
+	path, err := fs.path(s.ID)
+	if err != nil {
+		return fmt.Errorf("invalid graph snapshot ID [%s]", s.ID)
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	info, err := json.Marshal(s.Info)
+	if err != nil {
+		return err
+	}
+
+	fs.mutex.Lock()
+	// the info first, a listed snapshot always has one
+	if err = writeFile(fs.infoPath(s.ID), info); err == nil {
+		err = writeFile(path, data)
+	}
+	var ids []string
+	if err == nil {
+		ids, err = fs.ids()
+	}
+	fs.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return prune(fs, ids, fs.maxSnapshots)
+}
+
+// MemoryStore keeps snapshots in memory, they do not survive a restart
+type MemoryStore struct {
+	maxSnapshots int
+	mutex        sync.RWMutex
+	snapshots    map[string]memorySnapshot
+}
+
+// memorySnapshot is a snapshot kept serialized, so that callers can't alter the stored snapshot, and its Info
+type memorySnapshot struct {
+	info Info
+	data []byte
+}
+
+// NewMemoryStore returns an empty MemoryStore
+func NewMemoryStore(maxSnapshots int) *MemoryStore {
+	return &MemoryStore{maxSnapshots: maxSnapshots, snapshots: make(map[string]memorySnapshot)}
+}
+
+// Delete is required by the Store interface
+func (ms *MemoryStore) Delete(id string) error {
+	ms.mutex.Lock()
+	defer ms.mutex.Unlock()
+
+	if _, ok := ms.snapshots[id]; !ok {
+		return ErrNotFound
+	}
+	delete(ms.snapshots, id)
+	return nil
+}
+
+// Get is required by the Store interface
+func (ms *MemoryStore) Get(id string) (*Snapshot, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	stored, ok := ms.snapshots[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	s := &Snapshot{}
+	err := json.Unmarshal(stored.data, s)
+	return s, err
+}
+
+// List is required by the Store interface
+func (ms *MemoryStore) List() ([]Info, error) {
+	ms.mutex.RLock()
+	defer ms.mutex.RUnlock()
+
+	infos := make([]Info, 0, len(ms.snapshots))
+	for _, stored := range ms.snapshots {
+		infos = append(infos, stored.info)
+	}
+	sortInfos(infos)
+	return infos, nil
+}
+
+// Save is required by the Store interface
+func (ms *MemoryStore) Save(s *Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	info := s.Info
+	info.Namespaces = append([]string{}, s.Namespaces...)
+
+	ms.mutex.Lock()
+	ms.snapshots[s.ID] = memorySnapshot{info: info, data: data}
+	ids := make([]string, 0, len(ms.snapshots))
+	for id := range ms.snapshots {
+		ids = append(ids, id)
+	}
+	ms.mutex.Unlock()
+
+	// IDs are time-ordered
+	sort.Strings(ids)
+	return prune(ms, ids, ms.maxSnapshots)
+}
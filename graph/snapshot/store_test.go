@@ -0,0 +1,113 @@
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, store Store) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	infos, err := store.List()
+	require.NoError(err)
+	assert.Empty(infos)
+
+	ids := []string{"1600000001-aa", "1600000002-bb", "1600000003-cc"}
+	for _, id := range ids {
+		s, err := NewSnapshot(buildTrafficMap(false, 1.0), id, buildOptions())
+		require.NoError(err)
+		s.ID = id
+		require.NoError(store.Save(s))
+	}
+
+	// max of 2 snapshots, the oldest is pruned
+	infos, err = store.List()
+	require.NoError(err)
+	require.Len(infos, 2)
+	assert.Equal(ids[1], infos[0].ID)
+	assert.Equal(ids[2], infos[1].ID)
+
+	_, err = store.Get(ids[0])
+	assert.Equal(ErrNotFound, err)
+
+	s, err := store.Get(ids[2])
+	require.NoError(err)
+	assert.Equal(ids[2], s.Name)
+	assert.Len(s.Nodes, 3)
+
+	require.NoError(store.Delete(ids[2]))
+	assert.Equal(ErrNotFound, store.Delete(ids[2]))
+	_, err = store.Get("../../etc/passwd")
+	assert.Equal(ErrNotFound, err)
+
+	infos, err = store.List()
+	require.NoError(err)
+	assert.Len(infos, 1)
+}
+
+func TestMemoryStore(t *testing.T) {
+	testStore(t, NewMemoryStore(2))
+}
+
+func TestFilesystemStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFilesystemStore(filepath.Join(dir, "graph"), 2)
+	require.NoError(t, err)
+	testStore(t, store)
+
+	// unrelated files are ignored
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "graph", "README"), []byte("hi"), 0600))
+	infos, err := store.List()
+	require.NoError(t, err)
+	assert.Len(t, infos, 1)
+}
+
+func TestFilesystemStoreListsInfos(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+	dir, err := ioutil.TempDir("", "snapshots")
+	require.NoError(err)
+	defer os.RemoveAll(dir)
+
+	store, err := NewFilesystemStore(dir, 2)
+	require.NoError(err)
+	for _, id := range []string{"1600000001-aa", "1600000002-bb"} {
+		s, err := NewSnapshot(buildTrafficMap(false, 1.0), id, buildOptions())
+		require.NoError(err)
+		s.ID = id
+		require.NoError(store.Save(s))
+	}
+
+	// the graphs are not read to list the snapshots
+	require.NoError(ioutil.WriteFile(filepath.Join(dir, "1600000001-aa.json"), []byte("{"), 0600))
+	infos, err := store.List()
+	require.NoError(err)
+	require.Len(infos, 2)
+	assert.Equal("1600000001-aa", infos[0].Name)
+	_, err = store.Get("1600000001-aa")
+	assert.Error(err)
+
+	// a snapshot without info file is listed from its graph
+	require.NoError(os.Remove(filepath.Join(dir, "1600000002-bb.info.json")))
+	infos, err = store.List()
+	require.NoError(err)
+	require.Len(infos, 2)
+	assert.Equal("1600000002-bb", infos[1].Name)
+
+	// the info files are pruned with the snapshots
+	s, err := NewSnapshot(buildTrafficMap(false, 1.0), "1600000003-cc", buildOptions())
+	require.NoError(err)
+	s.ID = "1600000003-cc"
+	require.NoError(store.Save(s))
+	_, err = os.Stat(filepath.Join(dir, "1600000001-aa.info.json"))
+	assert.True(os.IsNotExist(err))
+}
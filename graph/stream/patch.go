@@ -0,0 +1,77 @@
+package stream
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+// Patch describes the changes between two consecutive cytoscape configs of a graph stream. Nodes and
+// edges are identified by their cytoscape IDs, which are stable across configs. Updated elements are
+// provided in full, they changed in some field (e.g. traffic rates, response time or health config).
+type Patch struct {
+	Duration     int64                    `json:"duration"`  // in seconds
+	Timestamp    int64                    `json:"timestamp"` // unix time in seconds
+	AddedNodes   []*cytoscape.NodeWrapper `json:"addedNodes,omitempty"`
+	UpdatedNodes []*cytoscape.NodeWrapper `json:"updatedNodes,omitempty"`
+	RemovedNodes []string                 `json:"removedNodes,omitempty"`
+	AddedEdges   []*cytoscape.EdgeWrapper `json:"addedEdges,omitempty"`
+	UpdatedEdges []*cytoscape.EdgeWrapper `json:"updatedEdges,omitempty"`
+	RemovedEdges []string                 `json:"removedEdges,omitempty"`
+}
+
+// NewPatch returns the Patch turning the from config into the to config. Added and updated elements
+// keep the to config order, so that compound (box) nodes precede their children.
+func NewPatch(from, to cytoscape.Config) Patch {
+	p := Patch{
+		Duration:  to.Duration,
+		Timestamp: to.Timestamp,
+	}
+
+	fromNodes := make(map[string]*cytoscape.NodeData, len(from.Elements.Nodes))
+	for _, n := range from.Elements.Nodes {
+		fromNodes[n.Data.ID] = n.Data
+	}
+	for _, n := range to.Elements.Nodes {
+		fromNode, found := fromNodes[n.Data.ID]
+		switch {
+		case !found:
+			p.AddedNodes = append(p.AddedNodes, n)
+		case !reflect.DeepEqual(fromNode, n.Data):
+			p.UpdatedNodes = append(p.UpdatedNodes, n)
+		}
+		delete(fromNodes, n.Data.ID)
+	}
+	for id := range fromNodes {
+		p.RemovedNodes = append(p.RemovedNodes, id)
+	}
+	sort.Strings(p.RemovedNodes)
+
+	fromEdges := make(map[string]*cytoscape.EdgeData, len(from.Elements.Edges))
+	for _, e := range from.Elements.Edges {
+		fromEdges[e.Data.ID] = e.Data
+	}
+	for _, e := range to.Elements.Edges {
+		fromEdge, found := fromEdges[e.Data.ID]
+		switch {
+		case !found:
+			p.AddedEdges = append(p.AddedEdges, e)
+		case !reflect.DeepEqual(fromEdge, e.Data):
+			p.UpdatedEdges = append(p.UpdatedEdges, e)
+		}
+		delete(fromEdges, e.Data.ID)
+	}
+	for id := range fromEdges {
+		p.RemovedEdges = append(p.RemovedEdges, id)
+	}
+	sort.Strings(p.RemovedEdges)
+
+	return p
+}
+
+// IsEmpty returns true if the graph elements did not change
+func (p Patch) IsEmpty() bool {
+	return len(p.AddedNodes) == 0 && len(p.UpdatedNodes) == 0 && len(p.RemovedNodes) == 0 &&
+		len(p.AddedEdges) == 0 && len(p.UpdatedEdges) == 0 && len(p.RemovedEdges) == 0
+}
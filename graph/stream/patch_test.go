@@ -0,0 +1,79 @@
+package stream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+func node(id, rate string) *cytoscape.NodeWrapper {
+	nd := &cytoscape.NodeData{ID: id, NodeType: "workload", Namespace: "bookinfo", Workload: id}
+	if rate != "" {
+		nd.Traffic = []cytoscape.ProtocolTraffic{{Protocol: "http", Rates: map[string]string{"httpIn": rate}}}
+	}
+	return &cytoscape.NodeWrapper{Data: nd}
+}
+
+func edge(id, source, target, rate string) *cytoscape.EdgeWrapper {
+	return &cytoscape.EdgeWrapper{Data: &cytoscape.EdgeData{
+		ID:      id,
+		Source:  source,
+		Target:  target,
+		Traffic: cytoscape.ProtocolTraffic{Protocol: "http", Rates: map[string]string{"http": rate}},
+	}}
+}
+
+func config(timestamp int64, nodes []*cytoscape.NodeWrapper, edges []*cytoscape.EdgeWrapper) cytoscape.Config {
+	return cytoscape.Config{
+		Timestamp: timestamp,
+		Duration:  60,
+		GraphType: "workload",
+		Elements:  cytoscape.Elements{Nodes: nodes, Edges: edges},
+	}
+}
+
+func TestNewPatch(t *testing.T) {
+	assert := assert.New(t)
+
+	from := config(100,
+		[]*cytoscape.NodeWrapper{node("productpage", ""), node("reviews", "1.00"), node("details", "1.00")},
+		[]*cytoscape.EdgeWrapper{edge("e0", "productpage", "reviews", "1.00"), edge("e1", "productpage", "details", "1.00")})
+	to := config(115,
+		[]*cytoscape.NodeWrapper{node("productpage", ""), node("reviews", "2.00"), node("ratings", "1.00")},
+		[]*cytoscape.EdgeWrapper{edge("e0", "productpage", "reviews", "2.00"), edge("e2", "reviews", "ratings", "1.00")})
+
+	p := NewPatch(from, to)
+	assert.Equal(int64(115), p.Timestamp)
+	assert.Equal(int64(60), p.Duration)
+	assert.False(p.IsEmpty())
+
+	assert.Len(p.AddedNodes, 1)
+	assert.Equal("ratings", p.AddedNodes[0].Data.ID)
+	assert.Len(p.UpdatedNodes, 1)
+	assert.Equal("reviews", p.UpdatedNodes[0].Data.ID)
+	assert.Equal([]string{"details"}, p.RemovedNodes)
+
+	assert.Len(p.AddedEdges, 1)
+	assert.Equal("e2", p.AddedEdges[0].Data.ID)
+	assert.Len(p.UpdatedEdges, 1)
+	assert.Equal("e0", p.UpdatedEdges[0].Data.ID)
+	assert.Equal("2.00", p.UpdatedEdges[0].Data.Traffic.Rates["http"])
+	assert.Equal([]string{"e1"}, p.RemovedEdges)
+}
+
+func TestNewPatchUnchanged(t *testing.T) {
+	assert := assert.New(t)
+
+	from := config(100,
+		[]*cytoscape.NodeWrapper{node("productpage", ""), node("reviews", "1.00")},
+		[]*cytoscape.EdgeWrapper{edge("e0", "productpage", "reviews", "1.00")})
+	to := config(115,
+		[]*cytoscape.NodeWrapper{node("productpage", ""), node("reviews", "1.00")},
+		[]*cytoscape.EdgeWrapper{edge("e0", "productpage", "reviews", "1.00")})
+
+	p := NewPatch(from, to)
+	assert.True(p.IsEmpty())
+	assert.Equal(int64(115), p.Timestamp)
+}
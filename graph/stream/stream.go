@@ -0,0 +1,67 @@
+// Package stream provides live graph updates: an initial cytoscape config followed by periodic
+// patches holding only the changed graph elements.
+package stream
+
+import (
+	"context"
+	"time"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/log"
+)
+
+// Stream event names
+const (
+	EventError    = "error"    // data: ErrorData
+	EventPatch    = "patch"    // data: Patch
+	EventSnapshot = "snapshot" // data: cytoscape.Config
+)
+
+// ErrorData is the data of an EventError
+type ErrorData struct {
+	Error string `json:"error"`
+}
+
+// Generator returns the current graph config
+type Generator func() (cytoscape.Config, error)
+
+// Sender sends a stream event to the client
+type Sender func(event string, data interface{}) error
+
+// Stream generates the graph config every interval and sends it to the client until the context
+// is done or a send fails. The first config is sent in full (EventSnapshot), the next ones as a
+// Patch against the previously sent config (EventPatch). A patch is sent even when empty, letting
+// the client know the graph is current. A failure to generate the config is sent as an EventError,
+// the stream continues with the next interval.
+func Stream(ctx context.Context, interval time.Duration, generate Generator, send Sender) error {
+	var last *cytoscape.Config
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		config, generateErr := generate()
+		switch {
+		case generateErr != nil:
+			log.Debugf("Graph stream failed to generate config: %v", generateErr)
+			err = send(EventError, ErrorData{Error: generateErr.Error()})
+		case last == nil:
+			err = send(EventSnapshot, config)
+		default:
+			err = send(EventPatch, NewPatch(*last, config))
+		}
+		if err != nil {
+			return err
+		}
+		if generateErr == nil {
+			last = &config
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/graph/config/cytoscape"
+)
+
+type sentEvent struct {
+	event string
+	data  interface{}
+}
+
+func TestStream(t *testing.T) {
+	assert := assert.New(t)
+
+	configs := []cytoscape.Config{
+		config(100, []*cytoscape.NodeWrapper{node("productpage", "")}, nil),
+		{},
+		config(130, []*cytoscape.NodeWrapper{node("productpage", ""), node("reviews", "1.00")}, nil),
+	}
+	calls := 0
+	generate := func() (cytoscape.Config, error) {
+		defer func() { calls++ }()
+		if calls == 1 {
+			return cytoscape.Config{}, errors.New("prometheus unavailable")
+		}
+		return configs[calls], nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := []sentEvent{}
+	send := func(event string, data interface{}) error {
+		events = append(events, sentEvent{event: event, data: data})
+		if len(events) == len(configs) {
+			cancel()
+		}
+		return nil
+	}
+
+	err := Stream(ctx, time.Millisecond, generate, send)
+	assert.NoError(err)
+	assert.Len(events, 3)
+
+	assert.Equal(EventSnapshot, events[0].event)
+	assert.Equal(configs[0], events[0].data)
+
+	assert.Equal(EventError, events[1].event)
+	assert.Equal(ErrorData{Error: "prometheus unavailable"}, events[1].data)
+
+	// the patch is against the last sent config
+	assert.Equal(EventPatch, events[2].event)
+	p := events[2].data.(Patch)
+	assert.Equal(int64(130), p.Timestamp)
+	assert.Len(p.AddedNodes, 1)
+	assert.Equal("reviews", p.AddedNodes[0].Data.ID)
+}
+
+func TestStreamSendFailure(t *testing.T) {
+	sendErr := errors.New("broken pipe")
+	generate := func() (cytoscape.Config, error) {
+		return config(100, nil, nil), nil
+	}
+	send := func(event string, data interface{}) error {
+		return sendErr
+	}
+
+	err := Stream(context.Background(), time.Millisecond, generate, send)
+	assert.Equal(t, sendErr, err)
+}
@@ -1,6 +1,9 @@
 package graph
 
 import (
+	"sort"
+	"sync"
+
 	"github.com/kiali/kiali/prometheus"
 )
 
@@ -16,4 +19,45 @@ type TelemetryVendor interface {
 	// TrafficMap for the requested node, It is recommended to use the graph/util.go definitions for
 	// error handling. It should be modeled after the Istio implementation.
 	BuildNodeTrafficMap(o TelemetryOptions, client *prometheus.Client, globalInfo *AppenderGlobalInfo) TrafficMap
+
+	// ParseAppenders is required by the TelemetryVendor interface. It returns, in execution order,
+	// the vendor appenders requested by the provided options.  Invalid appender names should be
+	// reported with graph.BadRequest.
+	ParseAppenders(o TelemetryOptions) []Appender
+}
+
+var (
+	telemetryVendors      = map[string]TelemetryVendor{}
+	telemetryVendorsMutex sync.RWMutex
+)
+
+// RegisterTelemetryVendor makes a TelemetryVendor available by name, typically from the
+// vendor package's init(). Registering the same name twice replaces the previous vendor.
+func RegisterTelemetryVendor(name string, vendor TelemetryVendor) {
+	telemetryVendorsMutex.Lock()
+	defer telemetryVendorsMutex.Unlock()
+
+	telemetryVendors[name] = vendor
+}
+
+// GetTelemetryVendor returns the TelemetryVendor registered with the given name
+func GetTelemetryVendor(name string) (TelemetryVendor, bool) {
+	telemetryVendorsMutex.RLock()
+	defer telemetryVendorsMutex.RUnlock()
+
+	vendor, ok := telemetryVendors[name]
+	return vendor, ok
+}
+
+// TelemetryVendors returns the sorted names of the registered telemetry vendors
+func TelemetryVendors() []string {
+	telemetryVendorsMutex.RLock()
+	defer telemetryVendorsMutex.RUnlock()
+
+	names := make([]string, 0, len(telemetryVendors))
+	for name := range telemetryVendors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
@@ -18,10 +18,25 @@ import (
 //   ns1 graph: unknown -> ns1:A -> ns2:B
 //   ns2 graph:   ns1:A -> ns2:B -> ns2:C
 func MergeTrafficMaps(trafficMap graph.TrafficMap, ns string, nsTrafficMap graph.TrafficMap) {
-	for nsID, nsNode := range nsTrafficMap {
+	mergeTrafficMaps(trafficMap, nsTrafficMap, func(n *graph.Node) bool { return n.Namespace == ns })
+}
+
+// MergeClusterTrafficMaps combines the traffic map built from the telemetry of a federated cluster. Node IDs
+// include the cluster, so a cross-cluster edge, reported by the source proxy to the source cluster and by the
+// destination proxy to the destination cluster, is found in both traffic maps. As for namespaces, duplicate nodes
+// are removed, preferring the instance from the cluster owning the node, and duplicate edges are dropped so that
+// their traffic is counted once.
+func MergeClusterTrafficMaps(trafficMap graph.TrafficMap, cluster string, clusterTrafficMap graph.TrafficMap) {
+	mergeTrafficMaps(trafficMap, clusterTrafficMap, func(n *graph.Node) bool { return n.Cluster == cluster })
+}
+
+// mergeTrafficMaps merges mergedTrafficMap into trafficMap, isPreferred returns true for the merged-in nodes
+// replacing their duplicate.
+func mergeTrafficMaps(trafficMap graph.TrafficMap, mergedTrafficMap graph.TrafficMap, isPreferred func(n *graph.Node) bool) {
+	for nsID, nsNode := range mergedTrafficMap {
 		if node, isDup := trafficMap[nsID]; isDup {
-			if nsNode.Namespace == ns {
-				// prefer nsNode (see above comment), so do a swap
+			if isPreferred(nsNode) {
+				// prefer nsNode (see MergeTrafficMaps), so do a swap
 				trafficMap[nsID] = nsNode
 				temp := node
 				node = nsNode
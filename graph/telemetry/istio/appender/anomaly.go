@@ -0,0 +1,307 @@
+package appender
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/util"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+)
+
+const (
+	// AnomalyAppenderName uniquely identifies the appender: anomaly
+	AnomalyAppenderName = "anomaly"
+
+	defaultAnomalyBaselineWindow = 24 * time.Hour
+	defaultAnomalyThreshold      = 3.0
+
+	// a baseline deviation is at least this fraction of the baseline mean, so that an (almost) flat
+	// baseline does not turn every small variation into an anomaly.
+	anomalyMinRelativeDeviation = 0.1
+)
+
+// anomalyMinDeviation is the minimum baseline deviation for each metric, used when the baseline mean is ~0
+var anomalyMinDeviation = map[string]float64{
+	graph.AnomalyErrorRatio:   0.01, // 1% of the requests
+	graph.AnomalyRate:         0.01, // req/sec
+	graph.AnomalyResponseTime: 1.0,  // millis
+}
+
+// AnomalyAppender is responsible for scoring the HTTP and gRPC request traffic of the graph edges against
+// a baseline window. For each edge the current request rate, error ratio (requests with a 4xx/5xx response
+// code or a non-OK gRPC status) and p95 response time are compared with the mean and standard deviation of
+// the same metric over the baseline window, giving a z-score. The baseline window ends BaselineOffset before
+// the query time (e.g. a trailing 24h window, or the same hour last week with BaselineWindow=1h and
+// BaselineOffset=7d).  A metric is anomalous when its z-score reaches the threshold: in either direction for
+// the request rate, only increases for the error ratio and response time.
+//
+// Edges are set with the AnomalyScores and, if any metric is anomalous, the Anomalies metadata. Destination
+// nodes aggregate their incoming edges: the highest z-score magnitude for each metric, and all of the anomalous
+// metrics. When service nodes are injected only the service-to-workload edges are scored.
+// Name: anomaly
+type AnomalyAppender struct {
+	BaselineOffset     time.Duration // 0 for a baseline window ending at the start of the graph duration
+	BaselineWindow     time.Duration
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	QueryTime          int64 // unix time in seconds
+	Rates              graph.RequestedRates
+	Threshold          float64 // minimum z-score magnitude of an anomaly
+}
+
+// anomalyStats holds, for one metric, the current value and the baseline stats of each edge
+type anomalyStats struct {
+	current map[string]float64
+	mean    map[string]float64
+	stddev  map[string]float64
+}
+
+// Name implements Appender
+func (a AnomalyAppender) Name() string {
+	return AnomalyAppenderName
+}
+
+// AppendGraph implements Appender
+func (a AnomalyAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	// Anomalies only apply to request traffic
+	if a.Rates.Grpc != graph.RateRequests && a.Rates.Http != graph.RateRequests {
+		return
+	}
+
+	if globalInfo.PromClient == nil {
+		var err error
+		globalInfo.PromClient, err = prometheus.NewClient()
+		graph.CheckError(err)
+	}
+
+	a.appendGraph(trafficMap, namespaceInfo.Namespace, globalInfo.PromClient)
+}
+
+func (a AnomalyAppender) appendGraph(trafficMap graph.TrafficMap, namespace string, client *prometheus.Client) {
+	duration := a.Namespaces[namespace].Duration
+	offset := a.BaselineOffset
+	if offset == 0 {
+		offset = duration
+	}
+	log.Tracef("Generating anomalies; namespace = %v, baseline = %v offset %v", namespace, a.BaselineWindow, offset)
+
+	groupBy := "source_cluster,source_workload_namespace,source_workload,source_canonical_service,source_canonical_revision,destination_cluster,destination_service_namespace,destination_service,destination_service_name,destination_workload_namespace,destination_workload,destination_canonical_service,destination_canonical_revision,request_protocol"
+	rangeDuration := int(duration.Seconds())
+
+	metrics := map[string]func(selector string) string{
+		graph.AnomalyRate: func(selector string) string {
+			return fmt.Sprintf(`sum(rate(istio_requests_total{%s}[%vs])) by (%s)`, selector, rangeDuration, groupBy)
+		},
+		// an edge without successful requests has no series for the numerator, fall back to 0
+		graph.AnomalyErrorRatio: func(selector string) string {
+			total := fmt.Sprintf(`sum(rate(istio_requests_total{%s}[%vs])) by (%s)`, selector, rangeDuration, groupBy)
+			return fmt.Sprintf(`1 - (sum(rate(istio_requests_total{%s,response_code!~"[45][0-9][0-9]",grpc_response_status=~"0|"}[%vs])) by (%s) or 0 * %s) / %s`,
+				selector, rangeDuration, groupBy, total, total)
+		},
+		graph.AnomalyResponseTime: func(selector string) string {
+			return fmt.Sprintf(`histogram_quantile(0.95, sum(rate(istio_request_duration_milliseconds_bucket{%s}[%vs])) by (le,%s))`, selector, rangeDuration, groupBy)
+		},
+	}
+
+	stats := make(map[string]*anomalyStats, len(metrics))
+	for metric, expr := range metrics {
+		// Incoming traffic uses destination telemetry and outgoing traffic uses source telemetry. The query
+		// order is important as both may report edges within the namespace, 'or' prefers the incoming result.
+		query := fmt.Sprintf(`(%s) or (%s)`,
+			expr(fmt.Sprintf(`reporter="destination",destination_service_namespace="%s"`, namespace)),
+			expr(fmt.Sprintf(`reporter="source",source_workload_namespace="%s"`, namespace)))
+		subquery := fmt.Sprintf(`(%s)[%vs:%vs] offset %vs`, query, int(a.BaselineWindow.Seconds()), rangeDuration, int(offset.Seconds()))
+
+		s := &anomalyStats{
+			current: make(map[string]float64),
+			mean:    make(map[string]float64),
+			stddev:  make(map[string]float64),
+		}
+		for _, q := range []struct {
+			query  string
+			values map[string]float64
+		}{
+			{query: query, values: s.current},
+			{query: fmt.Sprintf(`avg_over_time(%s)`, subquery), values: s.mean},
+			{query: fmt.Sprintf(`stddev_over_time(%s)`, subquery), values: s.stddev},
+		} {
+			vector := promQuery(q.query, time.Unix(a.QueryTime, 0), client.GetContext(), client.API(), a)
+			a.populateAnomalyMap(q.values, &vector)
+		}
+		stats[metric] = s
+	}
+
+	a.applyAnomalies(trafficMap, stats)
+}
+
+func (a AnomalyAppender) applyAnomalies(trafficMap graph.TrafficMap, stats map[string]*anomalyStats) {
+	for _, n := range trafficMap {
+		for _, e := range n.Edges {
+			key := fmt.Sprintf("%s %s %s", e.Source.ID, e.Dest.ID, e.Metadata[graph.ProtocolKey].(string))
+
+			scores := graph.AnomalyScoresMetadata{}
+			anomalies := []string{}
+			for metric, s := range stats {
+				z, ok := s.zScore(metric, key)
+				if !ok {
+					continue
+				}
+				scores[metric] = z
+				if z >= a.Threshold || (metric == graph.AnomalyRate && z <= -a.Threshold) {
+					anomalies = append(anomalies, metric)
+				}
+			}
+			if len(scores) == 0 {
+				continue
+			}
+			sort.Strings(anomalies)
+
+			e.Metadata[graph.AnomalyScores] = scores
+			if len(anomalies) > 0 {
+				e.Metadata[graph.Anomalies] = anomalies
+			}
+			addNodeAnomalies(e.Dest, scores, anomalies)
+		}
+	}
+}
+
+// zScore returns the z-score of the edge's current value, ok is false if the edge has no current
+// value or no baseline.
+func (s *anomalyStats) zScore(metric, key string) (z float64, ok bool) {
+	current, currentOk := s.current[key]
+	mean, meanOk := s.mean[key]
+	if !currentOk || !meanOk {
+		return 0, false
+	}
+	deviation := math.Max(s.stddev[key], math.Max(anomalyMinDeviation[metric], anomalyMinRelativeDeviation*math.Abs(mean)))
+	return (current - mean) / deviation, true
+}
+
+// addNodeAnomalies aggregates the scores and anomalies of an incoming edge into the node metadata
+func addNodeAnomalies(n *graph.Node, scores graph.AnomalyScoresMetadata, anomalies []string) {
+	nodeScores, ok := n.Metadata[graph.AnomalyScores].(graph.AnomalyScoresMetadata)
+	if !ok {
+		nodeScores = graph.AnomalyScoresMetadata{}
+		n.Metadata[graph.AnomalyScores] = nodeScores
+	}
+	for metric, z := range scores {
+		if nodeZ, found := nodeScores[metric]; !found || math.Abs(z) > math.Abs(nodeZ) {
+			nodeScores[metric] = z
+		}
+	}
+
+	if len(anomalies) == 0 {
+		return
+	}
+	nodeAnomalies, _ := n.Metadata[graph.Anomalies].([]string)
+	for _, anomaly := range anomalies {
+		found := false
+		for _, nodeAnomaly := range nodeAnomalies {
+			if found = nodeAnomaly == anomaly; found {
+				break
+			}
+		}
+		if !found {
+			nodeAnomalies = append(nodeAnomalies, anomaly)
+		}
+	}
+	sort.Strings(nodeAnomalies)
+	n.Metadata[graph.Anomalies] = nodeAnomalies
+}
+
+func (a AnomalyAppender) populateAnomalyMap(anomalyMap map[string]float64, vector *model.Vector) {
+	skipRequestsGrpc := a.Rates.Grpc != graph.RateRequests
+	skipRequestsHttp := a.Rates.Http != graph.RateRequests
+
+	for _, s := range *vector {
+		m := s.Metric
+		lSourceCluster, sourceClusterOk := m["source_cluster"]
+		lSourceWlNs, sourceWlNsOk := m["source_workload_namespace"]
+		lSourceWl, sourceWlOk := m["source_workload"]
+		lSourceApp, sourceAppOk := m["source_canonical_service"]
+		lSourceVer, sourceVerOk := m["source_canonical_revision"]
+		lDestCluster, destClusterOk := m["destination_cluster"]
+		lDestSvcNs, destSvcNsOk := m["destination_service_namespace"]
+		lDestSvc, destSvcOk := m["destination_service"]
+		lDestSvcName, destSvcNameOk := m["destination_service_name"]
+		lDestWlNs, destWlNsOk := m["destination_workload_namespace"]
+		lDestWl, destWlOk := m["destination_workload"]
+		lDestApp, destAppOk := m["destination_canonical_service"]
+		lDestVer, destVerOk := m["destination_canonical_revision"]
+		lProtocol, protocolOk := m["request_protocol"]
+
+		if !sourceWlNsOk || !sourceWlOk || !sourceAppOk || !sourceVerOk || !destSvcNsOk || !destSvcNameOk || !destSvcOk || !destWlNsOk || !destWlOk || !destAppOk || !destVerOk || !protocolOk {
+			log.Warningf("populateAnomalyMap: Skipping %s, missing expected labels", m.String())
+			continue
+		}
+
+		sourceWlNs := string(lSourceWlNs)
+		sourceWl := string(lSourceWl)
+		sourceApp := string(lSourceApp)
+		sourceVer := string(lSourceVer)
+		destSvc := string(lDestSvc)
+		protocol := string(lProtocol)
+
+		if (skipRequestsHttp && protocol == graph.HTTP.Name) || (skipRequestsGrpc && protocol == graph.GRPC.Name) || protocol == graph.TCP.Name {
+			continue
+		}
+
+		// handle clusters
+		sourceCluster, destCluster := util.HandleClusters(lSourceCluster, sourceClusterOk, lDestCluster, destClusterOk)
+
+		if util.IsBadSourceTelemetry(sourceCluster, sourceClusterOk, sourceWlNs, sourceWl, sourceApp) {
+			continue
+		}
+
+		val := float64(s.Value)
+
+		// handle unusual destinations
+		destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, _ := util.HandleDestination(sourceCluster, sourceWlNs, sourceWl, destCluster, string(lDestSvcNs), string(lDestSvc), string(lDestSvcName), string(lDestWlNs), string(lDestWl), string(lDestApp), string(lDestVer))
+
+		if util.IsBadDestTelemetry(destCluster, destClusterOk, destSvcNs, destSvc, destSvcName, destWl) {
+			continue
+		}
+
+		// an edge without requests (or without a baseline) has a NaN ratio or quantile, just skip it
+		if math.IsNaN(val) || math.IsInf(val, 0) {
+			continue
+		}
+
+		// don't inject a service node if destSvcName is not set or the dest node is already a service node.
+		inject := false
+		if a.InjectServiceNodes && graph.IsOK(destSvcName) {
+			_, destNodeType := graph.Id(destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer, a.GraphType)
+			inject = (graph.NodeTypeService != destNodeType)
+		}
+
+		// With an injected service node only score the outgoing edge, the incoming edge aggregates the
+		// traffic to several destinations and can't validly be scored against any one of them.
+		if inject {
+			a.addAnomalyValue(anomalyMap, val, protocol, destCluster, destSvcNs, destSvcName, "", "", "", destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		} else {
+			a.addAnomalyValue(anomalyMap, val, protocol, sourceCluster, sourceWlNs, "", sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvcName, destWlNs, destWl, destApp, destVer)
+		}
+	}
+}
+
+func (a AnomalyAppender) addAnomalyValue(anomalyMap map[string]float64, val float64, protocol, sourceCluster, sourceNs, sourceSvc, sourceWl, sourceApp, sourceVer, destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer string) {
+	sourceID, _ := graph.Id(sourceCluster, sourceNs, sourceSvc, sourceNs, sourceWl, sourceApp, sourceVer, a.GraphType)
+	destID, _ := graph.Id(destCluster, destSvcNs, destSvc, destWlNs, destWl, destApp, destVer, a.GraphType)
+	key := fmt.Sprintf("%s %s %s", sourceID, destID, protocol)
+
+	// The query already prefers the incoming (destination) telemetry, keep the first reported value
+	if _, found := anomalyMap[key]; !found {
+		anomalyMap[key] = val
+	}
+}
@@ -0,0 +1,169 @@
+package appender
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+)
+
+func anomalyTestMetric(sourceWl, sourceApp, destSvc, destWl, destApp string) model.Metric {
+	return model.Metric{
+		"source_cluster":                 business.DefaultClusterID,
+		"source_workload_namespace":      "bookinfo",
+		"source_workload":                model.LabelValue(sourceWl),
+		"source_canonical_service":       model.LabelValue(sourceApp),
+		"source_canonical_revision":      "v1",
+		"destination_cluster":            business.DefaultClusterID,
+		"destination_service_namespace":  "bookinfo",
+		"destination_service":            model.LabelValue(destSvc + ".bookinfo.svc.cluster.local"),
+		"destination_service_name":       model.LabelValue(destSvc),
+		"destination_workload_namespace": "bookinfo",
+		"destination_workload":           model.LabelValue(destWl),
+		"destination_canonical_service":  model.LabelValue(destApp),
+		"destination_canonical_revision": "v1",
+		"request_protocol":               "http"}
+}
+
+func TestAnomaly(t *testing.T) {
+	assert := assert.New(t)
+
+	reviews := anomalyTestMetric("productpage-v1", "productpage", "reviews", "reviews-v1", "reviews")
+	ratings := anomalyTestMetric("reviews-v1", "reviews", "ratings", "ratings-v1", "ratings")
+
+	// values by stat and metric: [reviews, ratings], a negative value means no series
+	values := map[string]map[string][]float64{
+		"current": {
+			graph.AnomalyRate:         {10.0, 1.0},
+			graph.AnomalyErrorRatio:   {0.5, -1},
+			graph.AnomalyResponseTime: {100.0, 20.0},
+		},
+		"avg_over_time": {
+			graph.AnomalyRate:         {10.0, 10.0},
+			graph.AnomalyErrorRatio:   {0.01, 0.0},
+			graph.AnomalyResponseTime: {100.0, -1},
+		},
+		"stddev_over_time": {
+			graph.AnomalyRate:         {1.0, 1.0},
+			graph.AnomalyErrorRatio:   {0.01, 0.0},
+			graph.AnomalyResponseTime: {0.0, -1},
+		},
+	}
+
+	client, api, err := setupMocked()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for stat, metricValues := range values {
+		for metric, vals := range metricValues {
+			stat, metric := stat, metric
+			vector := model.Vector{}
+			for i, m := range []model.Metric{reviews, ratings} {
+				if vals[i] >= 0 {
+					vector = append(vector, &model.Sample{Metric: m, Value: model.SampleValue(vals[i])})
+				}
+			}
+			api.On("Query", mock.Anything, mock.MatchedBy(func(query string) bool {
+				return anomalyTestStat(query) == stat && anomalyTestMetricName(query) == metric
+			}), mock.AnythingOfType("time.Time")).Return(vector, nil)
+		}
+	}
+
+	trafficMap := responseThroughputTestTraffic()
+
+	duration, _ := time.ParseDuration("60s")
+	appender := AnomalyAppender{
+		BaselineWindow:     24 * time.Hour,
+		GraphType:          graph.GraphTypeVersionedApp,
+		InjectServiceNodes: true,
+		Namespaces: map[string]graph.NamespaceInfo{
+			"bookinfo": {
+				Name:     "bookinfo",
+				Duration: duration,
+			},
+		},
+		QueryTime: time.Now().Unix(),
+		Rates: graph.RequestedRates{
+			Grpc: graph.RateRequests,
+			Http: graph.RateRequests,
+			Tcp:  graph.RateTotal,
+		},
+		Threshold: 3.0,
+	}
+
+	appender.appendGraph(trafficMap, "bookinfo", client)
+
+	reviewsServiceID, _ := graph.Id(business.DefaultClusterID, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeVersionedApp)
+	reviewsService := trafficMap[reviewsServiceID]
+	reviewsEdge := reviewsService.Edges[0]
+	assert.Equal("reviews-v1", reviewsEdge.Dest.Workload)
+	assert.Equal(graph.AnomalyScoresMetadata{
+		graph.AnomalyErrorRatio:   49.0,
+		graph.AnomalyRate:         0.0,
+		graph.AnomalyResponseTime: 0.0,
+	}, reviewsEdge.Metadata[graph.AnomalyScores])
+	assert.Equal([]string{graph.AnomalyErrorRatio}, reviewsEdge.Metadata[graph.Anomalies])
+	assert.Equal([]string{graph.AnomalyErrorRatio}, reviewsEdge.Dest.Metadata[graph.Anomalies])
+	_, ok := reviewsService.Edges[1].Metadata[graph.AnomalyScores]
+	assert.False(ok)
+
+	// the service node's incoming edge is not scored
+	productpageID, _ := graph.Id(business.DefaultClusterID, "bookinfo", "productpage", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeVersionedApp)
+	_, ok = trafficMap[productpageID].Edges[0].Metadata[graph.AnomalyScores]
+	assert.False(ok)
+	_, ok = reviewsService.Metadata[graph.AnomalyScores]
+	assert.False(ok)
+
+	ratingsServiceID, _ := graph.Id(business.DefaultClusterID, "bookinfo", "ratings", "", "", "", "", graph.GraphTypeVersionedApp)
+	ratingsEdge := trafficMap[ratingsServiceID].Edges[0]
+	assert.Equal(graph.AnomalyScoresMetadata{
+		graph.AnomalyRate: -9.0,
+	}, ratingsEdge.Metadata[graph.AnomalyScores])
+	assert.Equal([]string{graph.AnomalyRate}, ratingsEdge.Metadata[graph.Anomalies])
+	assert.Equal(graph.AnomalyScoresMetadata{
+		graph.AnomalyRate: -9.0,
+	}, ratingsEdge.Dest.Metadata[graph.AnomalyScores])
+}
+
+func TestAnomalyNodeAggregation(t *testing.T) {
+	assert := assert.New(t)
+
+	n := graph.NewNode(business.DefaultClusterID, "bookinfo", "reviews", "bookinfo", "reviews-v1", "reviews", "v1", graph.GraphTypeVersionedApp)
+	addNodeAnomalies(&n, graph.AnomalyScoresMetadata{graph.AnomalyRate: 4.0, graph.AnomalyErrorRatio: 1.0}, []string{graph.AnomalyRate})
+	addNodeAnomalies(&n, graph.AnomalyScoresMetadata{graph.AnomalyRate: -5.0, graph.AnomalyResponseTime: 3.5}, []string{graph.AnomalyRate, graph.AnomalyResponseTime})
+	addNodeAnomalies(&n, graph.AnomalyScoresMetadata{graph.AnomalyErrorRatio: 0.5}, []string{})
+
+	assert.Equal(graph.AnomalyScoresMetadata{
+		graph.AnomalyErrorRatio:   1.0,
+		graph.AnomalyRate:         -5.0,
+		graph.AnomalyResponseTime: 3.5,
+	}, n.Metadata[graph.AnomalyScores])
+	assert.Equal([]string{graph.AnomalyRate, graph.AnomalyResponseTime}, n.Metadata[graph.Anomalies])
+}
+
+func anomalyTestStat(query string) string {
+	for _, stat := range []string{"avg_over_time", "stddev_over_time"} {
+		if strings.HasPrefix(query, "round("+stat+"(") {
+			return stat
+		}
+	}
+	return "current"
+}
+
+func anomalyTestMetricName(query string) string {
+	switch {
+	case strings.Contains(query, "histogram_quantile(0.95"):
+		return graph.AnomalyResponseTime
+	case strings.Contains(query, "1 - ("):
+		return graph.AnomalyErrorRatio
+	default:
+		return graph.AnomalyRate
+	}
+}
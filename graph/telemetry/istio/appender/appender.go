@@ -2,6 +2,10 @@ package appender
 
 import (
 	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/common/model"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/graph"
@@ -23,6 +27,8 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 			switch appenderName {
 			case AggregateNodeAppenderName:
 				requestedAppenders[AggregateNodeAppenderName] = true
+			case AnomalyAppenderName:
+				requestedAppenders[AnomalyAppenderName] = true
 			case DeadNodeAppenderName:
 				requestedAppenders[DeadNodeAppenderName] = true
 			case HealthConfigAppenderName:
@@ -58,6 +64,7 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 	// - lazily inject aggregate nodes so other decorations can influence the new nodes/edges, if necessary
 	// Add orphan (idle) services
 	// Run remaining appenders
+	// The anomaly appender is expensive (baseline subqueries) and only runs when explicitly requested
 	var appenders []graph.Appender
 
 	if _, ok := requestedAppenders[ServiceEntryAppenderName]; ok || o.Appenders.All {
@@ -133,6 +140,42 @@ func ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
 		}
 		appenders = append(appenders, a)
 	}
+	if _, ok := requestedAppenders[AnomalyAppenderName]; ok {
+		baselineWindow := defaultAnomalyBaselineWindow
+		if baselineString := o.Params.Get("anomalyBaseline"); baselineString != "" {
+			baseline, err := model.ParseDuration(baselineString)
+			if err != nil || baseline <= 0 {
+				graph.BadRequest(fmt.Sprintf("Invalid anomalyBaseline, expecting a positive duration like 24h or 1h. [%s]", baselineString))
+			}
+			baselineWindow = time.Duration(baseline)
+		}
+		var baselineOffset time.Duration
+		if offsetString := o.Params.Get("anomalyOffset"); offsetString != "" {
+			offset, err := model.ParseDuration(offsetString)
+			if err != nil {
+				graph.BadRequest(fmt.Sprintf("Invalid anomalyOffset, expecting a duration like 7d. [%s]", offsetString))
+			}
+			baselineOffset = time.Duration(offset)
+		}
+		threshold := defaultAnomalyThreshold
+		if thresholdString := o.Params.Get("anomalyThreshold"); thresholdString != "" {
+			var err error
+			if threshold, err = strconv.ParseFloat(thresholdString, 64); err != nil || threshold <= 0 {
+				graph.BadRequest(fmt.Sprintf("Invalid anomalyThreshold, expecting a positive number. [%s]", thresholdString))
+			}
+		}
+		a := AnomalyAppender{
+			BaselineOffset:     baselineOffset,
+			BaselineWindow:     baselineWindow,
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			QueryTime:          o.QueryTime,
+			Rates:              o.Rates,
+			Threshold:          threshold,
+		}
+		appenders = append(appenders, a)
+	}
 	if _, ok := requestedAppenders[AggregateNodeAppenderName]; ok || o.Appenders.All {
 		aggregate := o.NodeOptions.Aggregate
 		if aggregate == "" {
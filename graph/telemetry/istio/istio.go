@@ -44,11 +44,37 @@ var (
 	grpcMetric = regexp.MustCompile(`istio_.*_messages`)
 )
 
+func init() {
+	graph.RegisterTelemetryVendor(graph.VendorIstio, Vendor{})
+}
+
+// Vendor is the Istio graph.TelemetryVendor
+type Vendor struct{}
+
 // BuildNamespacesTrafficMap is required by the graph/TelemtryVendor interface
-func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+func (v Vendor) BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	return BuildNamespacesTrafficMap(o, client, globalInfo, v.ParseAppenders(o))
+}
+
+// BuildNodeTrafficMap is required by the graph/TelemtryVendor interface
+func (v Vendor) BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	return BuildNodeTrafficMap(o, client, globalInfo, v.ParseAppenders(o))
+}
+
+// ParseAppenders is required by the graph/TelemtryVendor interface. The aggregate node graphs always run the
+// aggregate node appender.
+func (Vendor) ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
+	if o.NodeOptions.Aggregate != "" && !o.Appenders.All {
+		names := make([]string, 0, len(o.Appenders.AppenderNames)+1)
+		o.Appenders.AppenderNames = append(append(names, o.Appenders.AppenderNames...), appender.AggregateNodeAppenderName)
+	}
+	return appender.ParseAppenders(o)
+}
+
+// BuildNamespacesTrafficMap builds the namespaces graph and runs the appenders on the graph of each namespace
+func BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo, appenders []graph.Appender) graph.TrafficMap {
 	log.Tracef("Build [%s] graph for [%d] namespaces [%v]", o.GraphType, len(o.Namespaces), o.Namespaces)
 
-	appenders := appender.ParseAppenders(o)
 	trafficMap := graph.NewTrafficMap()
 
 	for _, namespace := range o.Namespaces {
@@ -412,17 +438,16 @@ func timeSeriesHash(cluster, serviceNs, service, workloadNs, workload, app, vers
 	return fmt.Sprintf("%x", md5.Sum([]byte(fmt.Sprintf("%s:%s:%s:%s:%s:%s:%s", cluster, serviceNs, service, workloadNs, workload, app, version))))
 }
 
-// BuildNodeTrafficMap is required by the graph/TelemtryVendor interface
-func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+// BuildNodeTrafficMap builds the node graph and runs the appenders on it
+func BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo, appenders []graph.Appender) graph.TrafficMap {
 	if o.NodeOptions.Aggregate != "" {
-		return handleAggregateNodeTrafficMap(o, client, globalInfo)
+		return handleAggregateNodeTrafficMap(o, client, globalInfo, appenders)
 	}
 
 	n := graph.NewNode(o.NodeOptions.Cluster, o.NodeOptions.Namespace, o.NodeOptions.Service, o.NodeOptions.Namespace, o.NodeOptions.Workload, o.NodeOptions.App, o.NodeOptions.Version, o.GraphType)
 
 	log.Tracef("Build graph for node [%+v]", n)
 
-	appenders := appender.ParseAppenders(o)
 	trafficMap := buildNodeTrafficMap(o.Cluster, o.NodeOptions.Namespace, n, o, client)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
@@ -797,15 +822,11 @@ func buildNodeTrafficMap(cluster, namespace string, n graph.Node, o graph.Teleme
 	return trafficMap
 }
 
-func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+func handleAggregateNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo, appenders []graph.Appender) graph.TrafficMap {
 	n := graph.NewAggregateNode(o.NodeOptions.Cluster, o.NodeOptions.Namespace, o.NodeOptions.Aggregate, o.NodeOptions.AggregateValue, o.NodeOptions.Service, o.NodeOptions.App)
 
 	log.Tracef("Build graph for aggregate node [%+v]", n)
 
-	if !o.Appenders.All {
-		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, appender.AggregateNodeAppenderName)
-	}
-	appenders := appender.ParseAppenders(o)
 	trafficMap := buildAggregateNodeTrafficMap(o.NodeOptions.Namespace, n, o, client)
 
 	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
@@ -0,0 +1,35 @@
+package istio
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry/istio/appender"
+)
+
+func TestParseAppenders(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	names := func(appenders []graph.Appender) []string {
+		result := []string{}
+		for _, a := range appenders {
+			result = append(result, a.Name())
+		}
+		return result
+	}
+
+	o := graph.TelemetryOptions{}
+	o.Appenders = graph.RequestedAppenders{AppenderNames: make([]string, 1, 2)}
+	o.Appenders.AppenderNames[0] = appender.DeadNodeAppenderName
+	assert.Equal([]string{appender.DeadNodeAppenderName}, names(Vendor{}.ParseAppenders(o)))
+
+	// the aggregate node graphs run the aggregate node appender, the requested names are unchanged
+	o.NodeOptions.Aggregate = "request_operation"
+	assert.Equal([]string{appender.DeadNodeAppenderName, appender.AggregateNodeAppenderName}, names(Vendor{}.ParseAppenders(o)))
+	assert.Equal([]string{appender.DeadNodeAppenderName}, o.Appenders.AppenderNames)
+	assert.Equal("", o.Appenders.AppenderNames[:2][1])
+}
@@ -0,0 +1,445 @@
+// Package otel provides the OpenTelemetry span-metrics implementation of graph/TelemetryVendor.
+package otel
+
+// Otel.go is responsible for generating TrafficMaps using the span-metrics produced by the OpenTelemetry
+// collector (spanmetrics connector or processor). It lets services without an Envoy proxy show up in the
+// graph.  It implements the TelemetryVendor interface.
+//
+// Span-metrics have no notion of a destination, edges are built from client spans that report a peer
+// service (the peer.service span attribute must be configured as a spanmetrics dimension):
+//   - the reporting service is the source node
+//   - the peer service is the destination node, its namespace is resolved from the server spans reported
+//     by the peer itself. A peer that reports no server spans (e.g. a database) becomes a service node in
+//     the "unknown" namespace.
+// Instrumented services are represented as workloads, using the reported service name for both the
+// workload and the app. The response code is the HTTP or gRPC status code dimension when available,
+// otherwise the span status is mapped to 200 or 500.
+//
+// Supports one vendor-specific query parameter:
+//   responseTime: Must be one of: avg | 50 | 95 | 99
+//
+// The series and labels used are defined by the graph.span_metrics configuration.
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/telemetry"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/internalmetrics"
+)
+
+const (
+	serviceNamespacesKey = "serviceNamespacesKey" // global vendor info map[service][]namespace
+	spanKindClient       = "SPAN_KIND_CLIENT"
+	spanKindServer       = "SPAN_KIND_SERVER"
+	statusCodeError      = "STATUS_CODE_ERROR"
+)
+
+func init() {
+	graph.RegisterTelemetryVendor(graph.VendorOpenTelemetry, Vendor{})
+}
+
+// Vendor is the OpenTelemetry span-metrics graph.TelemetryVendor
+type Vendor struct{}
+
+// spanService identifies one end of a span-metrics edge
+type spanService struct {
+	instrumented bool // false for a peer service not reporting its own spans
+	name         string
+	namespace    string
+	version      string
+}
+
+// serviceNamespaces maps a service name to the namespaces in which it reports server spans
+type serviceNamespaces map[string][]string
+
+// BuildNamespacesTrafficMap is required by the graph/TelemtryVendor interface
+func (v Vendor) BuildNamespacesTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	log.Tracef("Build [%s] span-metrics graph for [%d] namespaces [%v]", o.GraphType, len(o.Namespaces), o.Namespaces)
+
+	if globalInfo.PromClient == nil {
+		globalInfo.PromClient = client
+	}
+
+	appenders := v.ParseAppenders(o)
+	trafficMap := graph.NewTrafficMap()
+
+	for _, namespace := range o.Namespaces {
+		log.Tracef("Build span-metrics traffic map for namespace [%v]", namespace)
+		ns := namespace.Name
+		namespaceTrafficMap := buildTrafficMap(ns, o, globalInfo, func(source, dest spanService) bool {
+			return source.namespace == ns || dest.namespace == ns
+		})
+		namespaceInfo := graph.NewAppenderNamespaceInfo(ns)
+		for _, a := range appenders {
+			appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
+			a.AppendGraph(namespaceTrafficMap, globalInfo, namespaceInfo)
+			appenderTimer.ObserveDuration()
+		}
+		telemetry.MergeTrafficMaps(trafficMap, ns, namespaceTrafficMap)
+	}
+
+	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
+	telemetry.MarkTrafficGenerators(trafficMap)
+
+	if graph.GraphTypeService == o.GraphType {
+		trafficMap = telemetry.ReduceToServiceGraph(trafficMap)
+	}
+
+	return trafficMap
+}
+
+// BuildNodeTrafficMap is required by the graph/TelemtryVendor interface
+func (v Vendor) BuildNodeTrafficMap(o graph.TelemetryOptions, client *prometheus.Client, globalInfo *graph.AppenderGlobalInfo) graph.TrafficMap {
+	if o.NodeOptions.Aggregate != "" {
+		graph.BadRequest(fmt.Sprintf("Aggregate node graphs are not supported by the [%s] telemetry vendor", graph.VendorOpenTelemetry))
+	}
+
+	if globalInfo.PromClient == nil {
+		globalInfo.PromClient = client
+	}
+
+	n := graph.NewNode(o.NodeOptions.Cluster, o.NodeOptions.Namespace, o.NodeOptions.Service, o.NodeOptions.Namespace, o.NodeOptions.Workload, o.NodeOptions.App, o.NodeOptions.Version, o.GraphType)
+
+	log.Tracef("Build span-metrics graph for node [%+v]", n)
+
+	appenders := v.ParseAppenders(o)
+	trafficMap := buildTrafficMap(o.NodeOptions.Namespace, o, globalInfo, func(source, dest spanService) bool {
+		return isNode(n, source) || isNode(n, dest)
+	})
+
+	namespaceInfo := graph.NewAppenderNamespaceInfo(o.NodeOptions.Namespace)
+
+	for _, a := range appenders {
+		appenderTimer := internalmetrics.GetGraphAppenderTimePrometheusTimer(a.Name())
+		a.AppendGraph(trafficMap, globalInfo, namespaceInfo)
+		appenderTimer.ObserveDuration()
+	}
+
+	telemetry.MarkOutsideOrInaccessible(trafficMap, o)
+	telemetry.MarkTrafficGenerators(trafficMap)
+
+	return trafficMap
+}
+
+// ParseAppenders is required by the graph/TelemtryVendor interface. Only the appenders that apply to
+// span-metrics are supported, requesting another appender is a bad request.
+func (Vendor) ParseAppenders(o graph.TelemetryOptions) []graph.Appender {
+	requestedAppenders := make(map[string]bool)
+	if !o.Appenders.All {
+		for _, appenderName := range o.Appenders.AppenderNames {
+			switch appenderName {
+			case ResponseTimeAppenderName:
+				requestedAppenders[ResponseTimeAppenderName] = true
+			case "":
+				// skip
+			default:
+				graph.BadRequest(fmt.Sprintf("Invalid appender [%s], not supported by the [%s] telemetry vendor", appenderName, graph.VendorOpenTelemetry))
+			}
+		}
+	}
+
+	var appenders []graph.Appender
+
+	if _, ok := requestedAppenders[ResponseTimeAppenderName]; ok || o.Appenders.All {
+		quantile := defaultQuantile
+		responseTimeString := o.Params.Get("responseTime")
+		if responseTimeString != "" {
+			switch responseTimeString {
+			case "avg":
+				quantile = 0.0
+			case "50":
+				quantile = 0.5
+			case "95":
+				quantile = 0.95
+			case "99":
+				quantile = 0.99
+			default:
+				graph.BadRequest(fmt.Sprintf(`Invalid responseTime, must be one of: avg | 50 | 95 | 99: [%s]`, responseTimeString))
+			}
+		}
+		a := ResponseTimeAppender{
+			GraphType:          o.GraphType,
+			InjectServiceNodes: o.InjectServiceNodes,
+			Namespaces:         o.Namespaces,
+			Quantile:           quantile,
+			QueryTime:          o.QueryTime,
+			Rates:              o.Rates,
+		}
+		appenders = append(appenders, a)
+	}
+
+	return appenders
+}
+
+// buildTrafficMap returns a map of the client span traffic accepted by the include func (key=id). The
+// namespace determines the query duration.
+func buildTrafficMap(namespace string, o graph.TelemetryOptions, globalInfo *graph.AppenderGlobalInfo, include func(source, dest spanService) bool) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	// span-metrics only provide request traffic
+	if o.Rates.Http != graph.RateRequests && o.Rates.Grpc != graph.RateRequests {
+		return trafficMap
+	}
+
+	conf := config.Get().Graph.SpanMetrics
+	duration := o.Namespaces[namespace].Duration
+	queryTime := time.Unix(o.QueryTime, 0)
+	idleCondition := "> 0"
+	if o.IncludeIdleEdges {
+		idleCondition = ""
+	}
+
+	namespaces := getServiceNamespaces(duration, queryTime, globalInfo)
+
+	groupBy := joinLabels(conf.NamespaceLabel, conf.ServiceLabel, conf.ServiceVersionLabel, conf.PeerServiceLabel, conf.StatusCodeLabel, conf.HTTPStatusCodeLabel, conf.GrpcStatusCodeLabel)
+	query := fmt.Sprintf(`sum(rate(%s{%s="%s",%s!=""}[%vs])) by (%s) %s`,
+		conf.CallsMetric,
+		conf.SpanKindLabel,
+		spanKindClient,
+		conf.PeerServiceLabel,
+		int(duration.Seconds()), // range duration for the query
+		groupBy,
+		idleCondition)
+	vector := promQuery(query, queryTime, globalInfo.PromClient.API())
+	populateTrafficMap(trafficMap, &vector, namespaces, include, o)
+
+	return trafficMap
+}
+
+func populateTrafficMap(trafficMap graph.TrafficMap, vector *model.Vector, namespaces serviceNamespaces, include func(source, dest spanService) bool, o graph.TelemetryOptions) {
+	conf := config.Get().Graph.SpanMetrics
+
+	for _, s := range *vector {
+		val := float64(s.Value)
+		m := s.Metric
+
+		source, dest, ok := parseEdge(m, namespaces)
+		if !ok {
+			log.Warningf("Skipping %s, missing expected span-metrics labels", m.String())
+			continue
+		}
+		if !include(source, dest) {
+			continue
+		}
+
+		protocol, code := parseResponse(m, conf)
+		if protocol == graph.GRPC.Name && o.Rates.Grpc != graph.RateRequests || protocol == graph.HTTP.Name && o.Rates.Http != graph.RateRequests {
+			continue
+		}
+
+		for _, e := range edgeNodes(trafficMap, source, dest, o) {
+			addEdgeTraffic(val, protocol, code, dest.name, e.source, e.dest)
+		}
+	}
+}
+
+// parseEdge returns the source and destination of a client span series. The destination namespace
+// is resolved using the namespaces in which the peer reports server spans, preferring the source
+// namespace when the peer name is ambiguous.
+func parseEdge(m model.Metric, namespaces serviceNamespaces) (source, dest spanService, ok bool) {
+	conf := config.Get().Graph.SpanMetrics
+
+	lSourceNs, sourceNsOk := m[model.LabelName(conf.NamespaceLabel)]
+	lSource, sourceOk := m[model.LabelName(conf.ServiceLabel)]
+	lPeer, peerOk := m[model.LabelName(conf.PeerServiceLabel)]
+	if !sourceNsOk || !sourceOk || !peerOk || lSource == "" || lPeer == "" {
+		return source, dest, false
+	}
+
+	source = spanService{
+		instrumented: true,
+		name:         string(lSource),
+		namespace:    string(lSourceNs),
+		version:      labelValue(m, conf.ServiceVersionLabel),
+	}
+	if !graph.IsOK(source.namespace) {
+		source.namespace = graph.Unknown
+	}
+
+	dest = spanService{name: string(lPeer), namespace: graph.Unknown}
+	if peerNamespaces := namespaces[dest.name]; len(peerNamespaces) > 0 {
+		dest.instrumented = true
+		dest.namespace = peerNamespaces[0]
+		for _, ns := range peerNamespaces {
+			if ns == source.namespace {
+				dest.namespace = ns
+				break
+			}
+		}
+	}
+
+	return source, dest, true
+}
+
+// parseResponse returns the protocol and response code of a span series, preferring the status code
+// dimensions over the span status.
+func parseResponse(m model.Metric, conf config.GraphSpanMetricsConfig) (protocol, code string) {
+	if code = labelValue(m, conf.GrpcStatusCodeLabel); code != "" {
+		return graph.GRPC.Name, code
+	}
+	if code = labelValue(m, conf.HTTPStatusCodeLabel); code != "" {
+		return graph.HTTP.Name, code
+	}
+	if labelValue(m, conf.StatusCodeLabel) == statusCodeError {
+		return graph.HTTP.Name, "500"
+	}
+	return graph.HTTP.Name, "200"
+}
+
+type nodePair struct {
+	source *graph.Node
+	dest   *graph.Node
+}
+
+// edgeNodes adds the nodes for the source and destination to the traffic map, injecting a service node
+// when requested, and returns the node pairs that should receive the traffic.
+func edgeNodes(trafficMap graph.TrafficMap, source, dest spanService, o graph.TelemetryOptions) []nodePair {
+	sourceNode := addNode(trafficMap, source, o.GraphType)
+	destNode := addNode(trafficMap, dest, o.GraphType)
+
+	if o.InjectServiceNodes && dest.instrumented {
+		serviceNode := addServiceNode(trafficMap, dest, o.GraphType)
+		return []nodePair{{source: sourceNode, dest: serviceNode}, {source: serviceNode, dest: destNode}}
+	}
+	return []nodePair{{source: sourceNode, dest: destNode}}
+}
+
+func addEdgeTraffic(val float64, protocol, code, host string, source, dest *graph.Node) {
+	var edge *graph.Edge
+	for _, e := range source.Edges {
+		if dest.ID == e.Dest.ID && e.Metadata[graph.ProtocolKey] == protocol {
+			edge = e
+			break
+		}
+	}
+	if nil == edge {
+		edge = source.AddEdge(dest)
+		edge.Metadata[graph.ProtocolKey] = protocol
+	}
+	graph.AddToMetadata(protocol, val, code, "-", host, source.Metadata, dest.Metadata, edge.Metadata)
+}
+
+// addNode adds the node for the service, an instrumented service is represented as a workload (and app)
+// while a peer service is represented only as a service.
+func addNode(trafficMap graph.TrafficMap, s spanService, graphType string) *graph.Node {
+	if !s.instrumented {
+		return addServiceNode(trafficMap, s, graphType)
+	}
+	return getOrAddNode(trafficMap, graph.NewNode(graph.Unknown, "", "", s.namespace, s.name, s.name, s.version, graphType))
+}
+
+func addServiceNode(trafficMap graph.TrafficMap, s spanService, graphType string) *graph.Node {
+	return getOrAddNode(trafficMap, graph.NewNode(graph.Unknown, s.namespace, s.name, "", "", "", "", graphType))
+}
+
+func getOrAddNode(trafficMap graph.TrafficMap, n graph.Node) *graph.Node {
+	if node, found := trafficMap[n.ID]; found {
+		return node
+	}
+	trafficMap[n.ID] = &n
+	return &n
+}
+
+// isNode returns true if the span service is represented by the node graph's target node
+func isNode(n graph.Node, s spanService) bool {
+	if n.Namespace != s.namespace {
+		return false
+	}
+	switch n.NodeType {
+	case graph.NodeTypeApp:
+		return n.App == s.name && (!graph.IsOKVersion(n.Version) || n.Version == s.version)
+	case graph.NodeTypeService:
+		return n.Service == s.name
+	case graph.NodeTypeWorkload:
+		return n.Workload == s.name
+	default:
+		return false
+	}
+}
+
+// getServiceNamespaces returns the namespaces in which each service reports server spans, the result
+// is cached for the duration of the graph request.
+func getServiceNamespaces(duration time.Duration, queryTime time.Time, globalInfo *graph.AppenderGlobalInfo) serviceNamespaces {
+	if namespaces, ok := globalInfo.Vendor[serviceNamespacesKey]; ok {
+		return namespaces.(serviceNamespaces)
+	}
+
+	conf := config.Get().Graph.SpanMetrics
+	query := fmt.Sprintf(`sum(rate(%s{%s="%s"}[%vs])) by (%s)`,
+		conf.CallsMetric,
+		conf.SpanKindLabel,
+		spanKindServer,
+		int(duration.Seconds()), // range duration for the query
+		joinLabels(conf.NamespaceLabel, conf.ServiceLabel))
+	vector := promQuery(query, queryTime, globalInfo.PromClient.API())
+
+	namespaces := serviceNamespaces{}
+	for _, s := range vector {
+		service := labelValue(s.Metric, conf.ServiceLabel)
+		namespace := labelValue(s.Metric, conf.NamespaceLabel)
+		if service == "" || !graph.IsOK(namespace) {
+			continue
+		}
+		namespaces[service] = append(namespaces[service], namespace)
+	}
+	for _, serviceNamespaces := range namespaces {
+		sort.Strings(serviceNamespaces)
+	}
+
+	globalInfo.Vendor[serviceNamespacesKey] = namespaces
+	return namespaces
+}
+
+func labelValue(m model.Metric, label string) string {
+	if label == "" {
+		return ""
+	}
+	return string(m[model.LabelName(label)])
+}
+
+// joinLabels returns the comma-separated labels, ignoring the unset optional labels
+func joinLabels(labels ...string) string {
+	set := make([]string, 0, len(labels))
+	for _, l := range labels {
+		if l != "" {
+			set = append(set, l)
+		}
+	}
+	return strings.Join(set, ",")
+}
+
+func promQuery(query string, queryTime time.Time, api prom_v1.API) model.Vector {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// wrap with a round() to be in line with metrics api
+	query = fmt.Sprintf("round(%s,0.001)", query)
+	log.Tracef("Graph query:\n%s@time=%v (now=%v, %v)\n", query, queryTime.Format(graph.TF), time.Now().Format(graph.TF), queryTime.Unix())
+
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Graph-Generation")
+	value, warnings, err := api.Query(ctx, query, queryTime)
+	if len(warnings) > 0 {
+		log.Warningf("promQuery. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	graph.CheckUnavailable(err)
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+
+	switch t := value.Type(); t {
+	case model.ValVector: // Instant Vector
+		return value.(model.Vector)
+	default:
+		graph.Error(fmt.Sprintf("No handling for type %v!\n", t))
+	}
+
+	return nil
+}
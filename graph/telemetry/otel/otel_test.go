@@ -0,0 +1,222 @@
+package otel
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/prometheus"
+	"github.com/kiali/kiali/prometheus/prometheustest"
+)
+
+const (
+	serverQuery       = `round(sum(rate(traces_spanmetrics_calls_total{span_kind="SPAN_KIND_SERVER"}[600s])) by (service_namespace,service_name),0.001)`
+	clientQuery       = `round(sum(rate(traces_spanmetrics_calls_total{span_kind="SPAN_KIND_CLIENT",peer_service!=""}[600s])) by (service_namespace,service_name,service_version,peer_service,status_code,http_status_code,rpc_grpc_status_code) > 0,0.001)`
+	responseTimeQuery = `round(histogram_quantile(0.95, sum(rate(traces_spanmetrics_duration_milliseconds_bucket{span_kind="SPAN_KIND_CLIENT",peer_service!=""}[600s])) by (le,service_namespace,service_name,service_version,peer_service)) > 0,0.001)`
+)
+
+func setupMocked(t *testing.T) *prometheus.Client {
+	config.Set(config.NewConfig())
+
+	api := new(prometheustest.PromAPIMock)
+	client, err := prometheus.NewClient()
+	require.NoError(t, err)
+	client.Inject(api)
+
+	server := model.Vector{
+		server("shop", "frontend", 10),
+		server("shop", "checkout", 10),
+		server("billing", "payments", 3),
+	}
+	spans := model.Vector{
+		clientSpan("frontend", "checkout", "STATUS_CODE_UNSET", "200", "", 9),
+		clientSpan("frontend", "checkout", "STATUS_CODE_ERROR", "503", "", 1),
+		clientSpan("frontend", "postgres", "STATUS_CODE_UNSET", "", "", 5),
+		clientSpan("checkout", "payments", "STATUS_CODE_UNSET", "", "0", 3),
+	}
+	responseTime := model.Vector{
+		latency("frontend", "checkout", 120),
+		latency("checkout", "payments", 40),
+	}
+
+	api.On("Query", mock.Anything, serverQuery, mock.AnythingOfType("time.Time")).Return(server, nil)
+	api.On("Query", mock.Anything, clientQuery, mock.AnythingOfType("time.Time")).Return(spans, nil)
+	api.On("Query", mock.Anything, responseTimeQuery, mock.AnythingOfType("time.Time")).Return(responseTime, nil)
+
+	return client
+}
+
+func server(namespace, service string, val float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"service_namespace": model.LabelValue(namespace),
+			"service_name":      model.LabelValue(service),
+		},
+		Value: model.SampleValue(val),
+	}
+}
+
+func clientSpan(service, peer, status, httpCode, grpcCode string, val float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"service_namespace":    "shop",
+			"service_name":         model.LabelValue(service),
+			"service_version":      "v1",
+			"peer_service":         model.LabelValue(peer),
+			"status_code":          model.LabelValue(status),
+			"http_status_code":     model.LabelValue(httpCode),
+			"rpc_grpc_status_code": model.LabelValue(grpcCode),
+		},
+		Value: model.SampleValue(val),
+	}
+}
+
+func latency(service, peer string, val float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			"service_namespace": "shop",
+			"service_name":      model.LabelValue(service),
+			"service_version":   "v1",
+			"peer_service":      model.LabelValue(peer),
+		},
+		Value: model.SampleValue(val),
+	}
+}
+
+func telemetryOptions(graphType string, injectServiceNodes bool) graph.TelemetryOptions {
+	o := graph.TelemetryOptions{
+		AccessibleNamespaces: map[string]time.Time{"billing": time.Now(), "shop": time.Now()},
+		Appenders:            graph.RequestedAppenders{All: true},
+		InjectServiceNodes:   injectServiceNodes,
+		Namespaces: graph.NamespaceInfoMap{
+			"shop": graph.NamespaceInfo{Name: "shop", Duration: 10 * time.Minute},
+		},
+		Rates: graph.RequestedRates{Grpc: graph.RateRequests, Http: graph.RateRequests, Tcp: graph.RateSent},
+	}
+	o.GraphType = graphType
+	o.Params = url.Values{}
+	o.QueryTime = time.Now().Unix()
+	return o
+}
+
+func findEdge(trafficMap graph.TrafficMap, sourceID, destID string) *graph.Edge {
+	if source, ok := trafficMap[sourceID]; ok {
+		for _, e := range source.Edges {
+			if e.Dest.ID == destID {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+func TestNamespacesGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	client := setupMocked(t)
+	trafficMap := Vendor{}.BuildNamespacesTrafficMap(telemetryOptions(graph.GraphTypeWorkload, false), client, graph.NewAppenderGlobalInfo())
+
+	assert.Len(trafficMap, 4)
+	frontend := "wl_unknown_shop_frontend"
+	checkout := "wl_unknown_shop_checkout"
+	payments := "wl_unknown_billing_payments"
+	postgres := "svc_unknown_unknown_postgres"
+	for _, id := range []string{frontend, checkout, payments, postgres} {
+		assert.Contains(trafficMap, id)
+	}
+
+	e := findEdge(trafficMap, frontend, checkout)
+	require.NotNil(t, e)
+	assert.Equal(graph.HTTP.Name, e.Metadata[graph.ProtocolKey])
+	assert.Equal(10.0, e.Metadata["http"])
+	assert.Equal(1.0, e.Metadata["http5xx"])
+	assert.Equal(120.0, e.Metadata[graph.ResponseTime])
+
+	e = findEdge(trafficMap, frontend, postgres)
+	require.NotNil(t, e)
+	assert.Equal(5.0, e.Metadata["http"])
+	assert.Nil(e.Metadata[graph.ResponseTime])
+
+	e = findEdge(trafficMap, checkout, payments)
+	require.NotNil(t, e)
+	assert.Equal(graph.GRPC.Name, e.Metadata[graph.ProtocolKey])
+	assert.Equal(3.0, e.Metadata["grpc"])
+	assert.Equal(40.0, e.Metadata[graph.ResponseTime])
+
+	assert.Equal(true, trafficMap[frontend].Metadata[graph.IsRoot])
+	assert.Equal(true, trafficMap[payments].Metadata[graph.IsOutside])
+	assert.Nil(trafficMap[checkout].Metadata[graph.IsOutside])
+}
+
+func TestNamespacesGraphWithServiceInjection(t *testing.T) {
+	assert := assert.New(t)
+
+	client := setupMocked(t)
+	trafficMap := Vendor{}.BuildNamespacesTrafficMap(telemetryOptions(graph.GraphTypeVersionedApp, true), client, graph.NewAppenderGlobalInfo())
+
+	frontend := "vapp_unknown_shop_frontend"
+	checkoutSvc := "svc_unknown_shop_checkout"
+	checkout := "vapp_unknown_shop_checkout"
+
+	assert.Len(trafficMap, 6)
+	e := findEdge(trafficMap, frontend, checkoutSvc)
+	require.NotNil(t, e)
+	assert.Equal(10.0, e.Metadata["http"])
+	assert.Equal(120.0, e.Metadata[graph.ResponseTime])
+
+	e = findEdge(trafficMap, checkoutSvc, checkout)
+	require.NotNil(t, e)
+	assert.Equal(10.0, e.Metadata["http"])
+	assert.Equal(120.0, e.Metadata[graph.ResponseTime])
+
+	// uninstrumented peers are already service nodes
+	assert.NotNil(findEdge(trafficMap, frontend, "svc_unknown_unknown_postgres"))
+}
+
+func TestNodeGraph(t *testing.T) {
+	assert := assert.New(t)
+
+	client := setupMocked(t)
+	o := telemetryOptions(graph.GraphTypeWorkload, false)
+	o.NodeOptions = graph.NodeOptions{Cluster: graph.Unknown, Namespace: "shop", Workload: "checkout"}
+	trafficMap := Vendor{}.BuildNodeTrafficMap(o, client, graph.NewAppenderGlobalInfo())
+
+	assert.Len(trafficMap, 3)
+	assert.NotNil(findEdge(trafficMap, "wl_unknown_shop_frontend", "wl_unknown_shop_checkout"))
+	assert.NotNil(findEdge(trafficMap, "wl_unknown_shop_checkout", "wl_unknown_billing_payments"))
+	assert.NotContains(trafficMap, "svc_unknown_unknown_postgres")
+}
+
+func TestParseAppenders(t *testing.T) {
+	assert := assert.New(t)
+
+	o := telemetryOptions(graph.GraphTypeWorkload, false)
+	o.Appenders = graph.RequestedAppenders{AppenderNames: []string{"responseTime", ""}}
+	o.Params.Set("responseTime", "avg")
+	appenders := Vendor{}.ParseAppenders(o)
+
+	require.Len(t, appenders, 1)
+	assert.Equal(ResponseTimeAppenderName, appenders[0].Name())
+	assert.Equal(0.0, appenders[0].(ResponseTimeAppender).Quantile)
+
+	o.Params.Set("responseTime", "42")
+	assert.Panics(func() { Vendor{}.ParseAppenders(o) })
+
+	// the appenders of the istio vendor are not supported
+	o.Params.Set("responseTime", "avg")
+	o.Appenders = graph.RequestedAppenders{AppenderNames: []string{"deadNode", "responseTime"}}
+	assert.Panics(func() { Vendor{}.ParseAppenders(o) })
+}
+
+func TestRegistered(t *testing.T) {
+	vendor, ok := graph.GetTelemetryVendor(graph.VendorOpenTelemetry)
+	assert.True(t, ok)
+	assert.IsType(t, Vendor{}, vendor)
+}
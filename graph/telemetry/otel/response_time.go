@@ -0,0 +1,94 @@
+package otel
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/log"
+)
+
+const (
+	// ResponseTimeAppenderName uniquely identifies the appender: responseTime
+	ResponseTimeAppenderName = "responseTime"
+
+	defaultQuantile = 0.95
+)
+
+// ResponseTimeAppender is responsible for adding responseTime information to the graph using the
+// span-metrics latency histogram. ResponseTime is represented as a percentile value, or the average
+// when Quantile is 0. ResponseTime values are reported in milliseconds. Client span latency is used,
+// and so it includes network latency.
+// Name: responseTime
+type ResponseTimeAppender struct {
+	GraphType          string
+	InjectServiceNodes bool
+	Namespaces         graph.NamespaceInfoMap
+	Quantile           float64
+	QueryTime          int64 // unix time in seconds
+	Rates              graph.RequestedRates
+}
+
+// Name implements Appender
+func (a ResponseTimeAppender) Name() string {
+	return ResponseTimeAppenderName
+}
+
+// AppendGraph implements Appender
+func (a ResponseTimeAppender) AppendGraph(trafficMap graph.TrafficMap, globalInfo *graph.AppenderGlobalInfo, namespaceInfo *graph.AppenderNamespaceInfo) {
+	if len(trafficMap) == 0 {
+		return
+	}
+
+	if a.Rates.Grpc != graph.RateRequests && a.Rates.Http != graph.RateRequests {
+		return
+	}
+
+	conf := config.Get().Graph.SpanMetrics
+	duration := a.Namespaces[namespaceInfo.Namespace].Duration
+	queryTime := time.Unix(a.QueryTime, 0)
+	selector := fmt.Sprintf(`{%s="%s",%s!=""}[%vs]`, conf.SpanKindLabel, spanKindClient, conf.PeerServiceLabel, int(duration.Seconds()))
+	groupBy := joinLabels(conf.NamespaceLabel, conf.ServiceLabel, conf.ServiceVersionLabel, conf.PeerServiceLabel)
+
+	var query string
+	if a.Quantile == 0.0 {
+		log.Tracef("Generating average span-metrics responseTime; namespace = %v", namespaceInfo.Namespace)
+		query = fmt.Sprintf(`sum(rate(%s_sum%s)) by (%s) / sum(rate(%s_count%s)) by (%s) > 0`,
+			conf.LatencyMetric, selector, groupBy,
+			conf.LatencyMetric, selector, groupBy)
+	} else {
+		log.Tracef("Generating responseTime using quantile [%.2f]; namespace = %v", a.Quantile, namespaceInfo.Namespace)
+		query = fmt.Sprintf(`histogram_quantile(%.2f, sum(rate(%s_bucket%s)) by (le,%s)) > 0`,
+			a.Quantile, conf.LatencyMetric, selector, groupBy)
+	}
+
+	namespaces := getServiceNamespaces(duration, queryTime, globalInfo)
+	vector := promQuery(query, queryTime, globalInfo.PromClient.API())
+
+	// the response time applies to every edge of the (possibly service-injected) path
+	o := graph.TelemetryOptions{InjectServiceNodes: a.InjectServiceNodes}
+	o.GraphType = a.GraphType
+	for _, s := range vector {
+		val := float64(s.Value)
+		if math.IsNaN(val) {
+			continue
+		}
+		source, dest, ok := parseEdge(s.Metric, namespaces)
+		if !ok {
+			continue
+		}
+		for _, pair := range edgeNodes(graph.NewTrafficMap(), source, dest, o) {
+			n, found := trafficMap[pair.source.ID]
+			if !found {
+				continue
+			}
+			for _, e := range n.Edges {
+				if e.Dest.ID == pair.dest.ID {
+					e.Metadata[graph.ResponseTime] = val
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Package trace builds a TrafficMap from the spans of a single trace, showing the path of one request through
+// the mesh with the same node IDs as the traffic graph.
+//
+// Only the spans reported by the Istio proxies, identified by their node_id tag, are used, the spans of the
+// instrumented apps are skipped:
+//   - the proxy of a client span is the source workload, its upstream_cluster tag the destination service
+//   - the proxy of a server span, child of the client span, is the destination workload
+//
+// Each edge carries the span duration as its response time and whether the span failed. An edge traversed
+// several times (e.g. retries, or several calls from the same workload) keeps the slowest duration and is
+// failed if any of its spans failed.
+package trace
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/kubernetes"
+)
+
+const (
+	spanKindClient = "client"
+	spanKindServer = "server"
+)
+
+// replicaSetHash matches the pod template hash of the pods names of a Deployment
+var replicaSetHash = regexp.MustCompile(`^[a-z0-9]{8,10}$`)
+
+// Options are the trace graph options
+type Options struct {
+	Cluster            string
+	GraphType          string
+	InjectServiceNodes bool
+}
+
+// WorkloadResolver returns the workload of a pod, "" when unresolved, and false when its namespace is not
+// accessible
+type WorkloadResolver func(namespace, pod string) (workload string, accessible bool)
+
+// proxySpan is a span reported by an Istio proxy
+type proxySpan struct {
+	span      *jaegerModels.Span
+	parentID  jaegerModels.SpanID
+	namespace string
+	pod       string
+	app       string
+	version   string
+	kind      string
+	upstream  string // the Envoy upstream cluster, e.g. outbound|9080|v1|reviews.bookinfo.svc.cluster.local
+	protocol  string
+	isError   bool
+}
+
+// BuildTrafficMap returns the TrafficMap of the requests of the trace
+func BuildTrafficMap(trace *jaegerModels.Trace, o Options, resolve WorkloadResolver) graph.TrafficMap {
+	trafficMap := graph.NewTrafficMap()
+
+	// the spans are processed in the trace order, for a stable edge order
+	ordered := []*proxySpan{}
+	spans := map[jaegerModels.SpanID]*proxySpan{}
+	for i := range trace.Spans {
+		if ps, ok := parseProxySpan(&trace.Spans[i]); ok {
+			ordered = append(ordered, ps)
+			spans[ps.span.SpanID] = ps
+		}
+	}
+	children := map[jaegerModels.SpanID][]*proxySpan{}
+	for _, ps := range ordered {
+		if ps.kind == spanKindServer && ps.parentID != "" {
+			children[ps.parentID] = append(children[ps.parentID], ps)
+		}
+	}
+
+	for _, ps := range ordered {
+		parent, hasParent := spans[ps.parentID]
+		switch ps.kind {
+		case spanKindClient:
+			source := addWorkloadNode(trafficMap, ps, o, resolve)
+			if ps.parentID == "" {
+				source.Metadata[graph.IsRoot] = true
+			}
+			service := addServiceNode(trafficMap, ps, o)
+			servers := children[ps.span.SpanID]
+			for _, server := range servers {
+				dest := addWorkloadNode(trafficMap, server, o, resolve)
+				if o.InjectServiceNodes && service != nil {
+					addEdge(source, service, ps)
+					addEdge(service, dest, server)
+				} else {
+					addEdge(source, dest, ps)
+				}
+			}
+			if len(servers) == 0 && service != nil {
+				// the destination is not in the mesh, or its span was not reported
+				addEdge(source, service, ps)
+			}
+		case spanKindServer:
+			// the server spans of client spans are handled with their client span
+			if !hasParent || parent.kind != spanKindClient {
+				dest := addWorkloadNode(trafficMap, ps, o, resolve)
+				if ps.parentID == "" {
+					dest.Metadata[graph.IsRoot] = true
+				}
+			}
+		}
+	}
+	return trafficMap
+}
+
+// parseProxySpan returns the proxy info of a span, and false when not reported by an Istio proxy
+func parseProxySpan(span *jaegerModels.Span) (*proxySpan, bool) {
+	ps := &proxySpan{span: span, protocol: "tcp"}
+	for _, ref := range span.References {
+		if ref.RefType == jaegerModels.ChildOf {
+			ps.parentID = ref.SpanID
+			break
+		}
+	}
+	if ps.parentID == "" {
+		ps.parentID = span.ParentSpanID
+	}
+
+	nodeID := ""
+	isHTTP, isGRPC := false, false
+	for _, tag := range span.Tags {
+		value := fmt.Sprintf("%v", tag.Value)
+		switch tag.Key {
+		case "node_id":
+			nodeID = value
+		case "istio.canonical_service":
+			ps.app = value
+		case "istio.canonical_revision":
+			ps.version = value
+		case "istio.namespace":
+			ps.namespace = value
+		case "span.kind":
+			ps.kind = value
+		case "upstream_cluster":
+			ps.upstream = value
+		case "error":
+			ps.isError, _ = strconv.ParseBool(value)
+		case "grpc.status_code":
+			isGRPC = true
+		case "http.method", "http.status_code":
+			isHTTP = true
+		}
+	}
+	switch {
+	case isGRPC:
+		ps.protocol = "grpc"
+	case isHTTP:
+		ps.protocol = "http"
+	}
+
+	// For a workload named "ai-locals", node_id is like:
+	// sidecar~172.17.0.20~ai-locals-6d8996bff-ztg6z.default~default.svc.cluster.local
+	parts := strings.Split(nodeID, "~")
+	if len(parts) < 3 {
+		return nil, false
+	}
+	podNamespace := parts[2]
+	dot := strings.LastIndex(podNamespace, ".")
+	if dot < 0 {
+		return nil, false
+	}
+	ps.pod = podNamespace[:dot]
+	if ps.namespace == "" {
+		ps.namespace = podNamespace[dot+1:]
+	}
+	return ps, ps.kind == spanKindClient || ps.kind == spanKindServer
+}
+
+// podWorkload guesses the workload of a pod from its name: without the pod suffix, and the pod template hash for
+// the pods of a Deployment
+func podWorkload(pod string) string {
+	parts := strings.Split(pod, "-")
+	if len(parts) < 2 {
+		return pod
+	}
+	parts = parts[:len(parts)-1]
+	if len(parts) > 1 && replicaSetHash.MatchString(parts[len(parts)-1]) && strings.ContainsAny(parts[len(parts)-1], "0123456789") {
+		parts = parts[:len(parts)-1]
+	}
+	return strings.Join(parts, "-")
+}
+
+func addNode(trafficMap graph.TrafficMap, node graph.Node) *graph.Node {
+	if n, found := trafficMap[node.ID]; found {
+		return n
+	}
+	trafficMap[node.ID] = &node
+	return &node
+}
+
+func addWorkloadNode(trafficMap graph.TrafficMap, ps *proxySpan, o Options, resolve WorkloadResolver) *graph.Node {
+	workload, accessible := resolve(ps.namespace, ps.pod)
+	if workload == "" {
+		workload = podWorkload(ps.pod)
+	}
+	app, version := ps.app, ps.version
+	if app == "" {
+		app = graph.Unknown
+	}
+	if version == "" {
+		version = graph.Unknown
+	}
+	node := addNode(trafficMap, graph.NewNode(o.Cluster, "", "", ps.namespace, workload, app, version, o.GraphType))
+	if !accessible {
+		node.Metadata[graph.IsInaccessible] = true
+	}
+	return node
+}
+
+// addServiceNode adds the service of the upstream cluster of a client span, it returns nil when unknown
+func addServiceNode(trafficMap graph.TrafficMap, ps *proxySpan, o Options) *graph.Node {
+	upstream := strings.Split(ps.upstream, "|")
+	var service, namespace string
+	switch {
+	case len(upstream) == 4 && upstream[3] != "":
+		host := kubernetes.ParseHost(upstream[3], "", "")
+		service, namespace = host.Service, graph.Unknown
+		if host.CompleteInput && host.Namespace != "" {
+			namespace = host.Namespace
+		}
+	case ps.upstream == "PassthroughCluster" || ps.upstream == "BlackHoleCluster":
+		service, namespace = ps.upstream, ps.namespace
+	default:
+		return nil
+	}
+	return addNode(trafficMap, graph.NewNode(o.Cluster, namespace, service, "", "", "", "", o.GraphType))
+}
+
+func addEdge(source, dest *graph.Node, ps *proxySpan) {
+	responseTime := float64(ps.span.Duration) / 1000.0
+	for _, e := range source.Edges {
+		if e.Dest.ID == dest.ID && e.Metadata[graph.ProtocolKey] == ps.protocol {
+			if responseTime > e.Metadata[graph.ResponseTime].(float64) {
+				e.Metadata[graph.ResponseTime] = responseTime
+			}
+			if ps.isError {
+				e.Metadata[graph.IsError] = true
+			}
+			return
+		}
+	}
+	e := source.AddEdge(dest)
+	e.Metadata[graph.ProtocolKey] = ps.protocol
+	e.Metadata[graph.ResponseTime] = responseTime
+	if ps.isError {
+		e.Metadata[graph.IsError] = true
+	}
+}
@@ -0,0 +1,161 @@
+package trace
+
+import (
+	"testing"
+
+	jaegerModels "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+)
+
+func proxySpanTags(pod, ns, app, version, kind string, tags ...jaegerModels.KeyValue) []jaegerModels.KeyValue {
+	return append([]jaegerModels.KeyValue{
+		{Key: "node_id", Type: jaegerModels.StringType, Value: "sidecar~172.17.0.20~" + pod + "." + ns + "~" + ns + ".svc.cluster.local"},
+		{Key: "istio.canonical_service", Type: jaegerModels.StringType, Value: app},
+		{Key: "istio.canonical_revision", Type: jaegerModels.StringType, Value: version},
+		{Key: "span.kind", Type: jaegerModels.StringType, Value: kind},
+	}, tags...)
+}
+
+func tag(key string, value interface{}) jaegerModels.KeyValue {
+	return jaegerModels.KeyValue{Key: key, Type: jaegerModels.StringType, Value: value}
+}
+
+func childOf(spanID jaegerModels.SpanID) []jaegerModels.Reference {
+	return []jaegerModels.Reference{{RefType: jaegerModels.ChildOf, TraceID: "t1", SpanID: spanID}}
+}
+
+// productpage -> reviews (retried, once failed), reviews -> ratings (gRPC) and productpage -> an external host
+func bookinfoTrace() *jaegerModels.Trace {
+	return &jaegerModels.Trace{
+		TraceID: "t1",
+		Spans: []jaegerModels.Span{
+			{
+				SpanID: "s1", StartTime: 1000000, Duration: 50000,
+				Tags: proxySpanTags("productpage-v1-6b746f74dc-9stvs", "bookinfo", "productpage", "v1", "server", tag("http.method", "GET")),
+			},
+			{
+				SpanID: "s2", References: childOf("s1"), StartTime: 1001000, Duration: 30000,
+				Tags: proxySpanTags("productpage-v1-6b746f74dc-9stvs", "bookinfo", "productpage", "v1", "client",
+					tag("http.method", "GET"), tag("upstream_cluster", "outbound|9080||reviews.bookinfo.svc.cluster.local"), tag("error", true)),
+			},
+			{
+				SpanID: "s3", References: childOf("s2"), StartTime: 1002000, Duration: 28000,
+				Tags: proxySpanTags("reviews-v2-7bf8c9648f-f8dxq", "bookinfo", "reviews", "v2", "server",
+					tag("http.method", "GET"), tag("error", "true")),
+			},
+			{
+				SpanID: "s4", References: childOf("s1"), StartTime: 1032000, Duration: 15000,
+				Tags: proxySpanTags("productpage-v1-6b746f74dc-9stvs", "bookinfo", "productpage", "v1", "client",
+					tag("http.method", "GET"), tag("upstream_cluster", "outbound|9080||reviews.bookinfo.svc.cluster.local")),
+			},
+			{
+				SpanID: "s5", References: childOf("s4"), StartTime: 1033000, Duration: 12000,
+				Tags: proxySpanTags("reviews-v2-7bf8c9648f-f8dxq", "bookinfo", "reviews", "v2", "server", tag("http.method", "GET")),
+			},
+			{
+				SpanID: "s6", References: childOf("s5"), StartTime: 1034000, Duration: 5000,
+				Tags: proxySpanTags("reviews-v2-7bf8c9648f-f8dxq", "bookinfo", "reviews", "v2", "client",
+					tag("grpc.status_code", "0"), tag("upstream_cluster", "outbound|9080||ratings.bookinfo.svc.cluster.local")),
+			},
+			{
+				SpanID: "s7", References: childOf("s6"), StartTime: 1035000, Duration: 3000,
+				Tags: proxySpanTags("ratings-v1-b6994bb9-gnndr", "bookinfo", "ratings", "v1", "server", tag("grpc.status_code", "0")),
+			},
+			{
+				SpanID: "s8", References: childOf("s1"), StartTime: 1047000, Duration: 2000,
+				Tags: proxySpanTags("productpage-v1-6b746f74dc-9stvs", "bookinfo", "productpage", "v1", "client",
+					tag("http.method", "GET"), tag("upstream_cluster", "outbound|443||www.example.com")),
+			},
+			{
+				// an app span, not reported by a proxy
+				SpanID: "s9", References: childOf("s1"), StartTime: 1001000, Duration: 1000,
+				Tags: []jaegerModels.KeyValue{tag("span.kind", "client")},
+			},
+		},
+	}
+}
+
+func resolver(namespace, pod string) (string, bool) {
+	if pod == "ratings-v1-b6994bb9-gnndr" {
+		// an inaccessible namespace, the workload is guessed from the pod name
+		return "", false
+	}
+	return "", true
+}
+
+func TestBuildTrafficMap(t *testing.T) {
+	config.Set(config.NewConfig())
+	assert := assert.New(t)
+
+	o := Options{Cluster: graph.Unknown, GraphType: graph.GraphTypeWorkload}
+	trafficMap := BuildTrafficMap(bookinfoTrace(), o, resolver)
+
+	productpage, _ := graph.Id(graph.Unknown, "", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews, _ := graph.Id(graph.Unknown, "", "", "bookinfo", "reviews-v2", "reviews", "v2", graph.GraphTypeWorkload)
+	ratings, _ := graph.Id(graph.Unknown, "", "", "bookinfo", "ratings-v1", "ratings", "v1", graph.GraphTypeWorkload)
+	external, _ := graph.Id(graph.Unknown, graph.Unknown, "www.example.com", "", "", "", "", graph.GraphTypeWorkload)
+	reviewsService, _ := graph.Id(graph.Unknown, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeWorkload)
+
+	require.Len(t, trafficMap, 6)
+	assert.Equal(true, trafficMap[productpage].Metadata[graph.IsRoot])
+	assert.Equal(true, trafficMap[ratings].Metadata[graph.IsInaccessible])
+	assert.NotContains(trafficMap[reviews].Metadata, graph.IsInaccessible)
+
+	// the retried edge keeps the slowest span, and failed
+	require.Len(t, trafficMap[productpage].Edges, 2)
+	e := trafficMap[productpage].Edges[0]
+	assert.Equal(reviews, e.Dest.ID)
+	assert.Equal("http", e.Metadata[graph.ProtocolKey])
+	assert.Equal(30.0, e.Metadata[graph.ResponseTime])
+	assert.Equal(true, e.Metadata[graph.IsError])
+
+	// no server span, the edge ends at the service
+	e = trafficMap[productpage].Edges[1]
+	assert.Equal(external, e.Dest.ID)
+	assert.Equal(2.0, e.Metadata[graph.ResponseTime])
+
+	require.Len(t, trafficMap[reviews].Edges, 1)
+	e = trafficMap[reviews].Edges[0]
+	assert.Equal(ratings, e.Dest.ID)
+	assert.Equal("grpc", e.Metadata[graph.ProtocolKey])
+	assert.Equal(5.0, e.Metadata[graph.ResponseTime])
+	assert.NotContains(e.Metadata, graph.IsError)
+
+	// without service node injection the service node is present but unused
+	assert.Empty(trafficMap[reviewsService].Edges)
+}
+
+func TestBuildTrafficMapInjectServiceNodes(t *testing.T) {
+	config.Set(config.NewConfig())
+	assert := assert.New(t)
+
+	o := Options{Cluster: graph.Unknown, GraphType: graph.GraphTypeWorkload, InjectServiceNodes: true}
+	trafficMap := BuildTrafficMap(bookinfoTrace(), o, resolver)
+
+	productpage, _ := graph.Id(graph.Unknown, "", "", "bookinfo", "productpage-v1", "productpage", "v1", graph.GraphTypeWorkload)
+	reviews, _ := graph.Id(graph.Unknown, "", "", "bookinfo", "reviews-v2", "reviews", "v2", graph.GraphTypeWorkload)
+	reviewsService, _ := graph.Id(graph.Unknown, "bookinfo", "reviews", "", "", "", "", graph.GraphTypeWorkload)
+
+	require.Len(t, trafficMap[productpage].Edges, 2)
+	assert.Equal(reviewsService, trafficMap[productpage].Edges[0].Dest.ID)
+	assert.Equal(30.0, trafficMap[productpage].Edges[0].Metadata[graph.ResponseTime])
+
+	require.Len(t, trafficMap[reviewsService].Edges, 1)
+	e := trafficMap[reviewsService].Edges[0]
+	assert.Equal(reviews, e.Dest.ID)
+	assert.Equal(28.0, e.Metadata[graph.ResponseTime])
+	assert.Equal(true, e.Metadata[graph.IsError])
+}
+
+func TestPodWorkload(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("reviews-v2", podWorkload("reviews-v2-7bf8c9648f-f8dxq"))
+	assert.Equal("ratings-v1", podWorkload("ratings-v1-b6994bb9-gnndr"))
+	assert.Equal("mysql", podWorkload("mysql-0"))
+	assert.Equal("standalone", podWorkload("standalone"))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/kiali/kiali/alerting"
+)
+
+// Alerts is a REST http.HandlerFunc returning the current health alerts of the namespaces accessible to the user.
+// The optional "namespaces" query param filters the namespaces.
+func Alerts(w http.ResponseWriter, r *http.Request) {
+	alerter := alerting.Get()
+	if alerter == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Health alerting is disabled")
+		return
+	}
+	namespaces, ok := alertsNamespaces(w, r)
+	if !ok {
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, alerter.Alerts(namespaces))
+}
+
+// AlertsHistory is a REST http.HandlerFunc returning the health status transitions of the namespaces accessible
+// to the user. The optional "namespaces" query param filters the namespaces.
+func AlertsHistory(w http.ResponseWriter, r *http.Request) {
+	alerter := alerting.Get()
+	if alerter == nil {
+		RespondWithError(w, http.StatusServiceUnavailable, "Health alerting is disabled")
+		return
+	}
+	namespaces, ok := alertsNamespaces(w, r)
+	if !ok {
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, alerter.History(namespaces))
+}
+
+// alertsNamespaces returns the requested namespaces accessible to the user, it responds with the error when
+// the namespaces can't be listed.
+func alertsNamespaces(w http.ResponseWriter, r *http.Request) (map[string]bool, bool) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return nil, false
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return nil, false
+	}
+
+	requested := map[string]bool{}
+	if param := r.URL.Query().Get("namespaces"); param != "" {
+		for _, ns := range strings.Split(param, ",") {
+			requested[strings.TrimSpace(ns)] = true
+		}
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if len(requested) == 0 || requested[ns.Name] {
+			accessible[ns.Name] = true
+		}
+	}
+	return accessible, true
+}
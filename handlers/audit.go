@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/audit"
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+)
+
+// AuditRecords is a REST http.HandlerFunc returning the audit records of the namespaces accessible to the user, the
+// most recent first. The records are filtered by the optional query params.
+func AuditRecords(w http.ResponseWriter, r *http.Request) {
+	store, err := audit.GetStore()
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+
+	params := r.URL.Query()
+	q := audit.Query{
+		User:       params.Get("user"),
+		Operation:  params.Get("operation"),
+		ObjectType: params.Get("objectType"),
+		Name:       params.Get("name"),
+	}
+	if from := params.Get("from"); from != "" {
+		if q.From, err = time.Parse(time.RFC3339, from); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid from time: "+err.Error())
+			return
+		}
+	}
+	if to := params.Get("to"); to != "" {
+		if q.To, err = time.Parse(time.RFC3339, to); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid to time: "+err.Error())
+			return
+		}
+	}
+	if limit := params.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil || q.Limit < 0 {
+			RespondWithError(w, http.StatusBadRequest, "Invalid limit: "+limit)
+			return
+		}
+	}
+
+	if q.Namespaces, err = auditNamespaces(r); err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	records, err := store.Query(q)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Unable to query audit records: "+err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, records)
+}
+
+// auditNamespaces returns the requested namespaces accessible to the user
+func auditNamespaces(r *http.Request) (map[string]bool, error) {
+	business, err := getBusiness(r)
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	requested := map[string]bool{}
+	if param := r.URL.Query().Get("namespaces"); param != "" {
+		for _, ns := range strings.Split(param, ",") {
+			requested[strings.TrimSpace(ns)] = true
+		}
+	}
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		if len(requested) == 0 || requested[ns.Name] {
+			accessible[ns.Name] = true
+		}
+	}
+	return accessible, nil
+}
+
+// recordAudit records a successful write operation performed by the user of the request
+func recordAudit(r *http.Request, record models.AuditRecord) {
+	record.User = auditUser(r)
+	audit.Record(record)
+}
+
+// auditUser returns the user performing the request. The authentication handler sets the verified subject of the
+// session in the request context, the token of the header strategy is resolved with a TokenReview.
+func auditUser(r *http.Request) string {
+	if user, ok := r.Context().Value("kialiUser").(string); ok && user != "" {
+		return user
+	}
+	if config.Get().Auth.Strategy != config.AuthStrategyHeader {
+		return ""
+	}
+
+	authInfo, err := getAuthInfo(r)
+	if err != nil {
+		log.Warningf("Unable to resolve the audited user: %v", err)
+		return ""
+	}
+	kialiToken, err := kubernetes.GetKialiToken()
+	if err != nil {
+		log.Warningf("Unable to resolve the audited user: %v", err)
+		return ""
+	}
+	layer, err := business.Get(&api.AuthInfo{Token: kialiToken})
+	if err != nil {
+		log.Warningf("Unable to resolve the audited user: %v", err)
+		return ""
+	}
+	// The impersonation headers are not trusted, the audited user is the owner of the token
+	subject, err := layer.TokenReview.GetTokenSubject(&api.AuthInfo{Token: authInfo.Token})
+	if err != nil {
+		log.Warningf("Unable to resolve the audited user: %v", err)
+		return ""
+	}
+	return strings.TrimPrefix(subject, "system:serviceaccount:")
+}
+
+// auditEnabled returns true when the audit trail is stored, the state of the audited objects is only read then
+func auditEnabled() bool {
+	return config.Get().Audit.Enabled
+}
+
+// auditState returns the JSON state of an audited object, nil when the object could not be read
+func auditState(object interface{}, err error) json.RawMessage {
+	if err != nil {
+		log.Debugf("Audited object state not available: %v", err)
+		return nil
+	}
+	state, err := json.Marshal(object)
+	if err != nil {
+		log.Debugf("Audited object state not available: %v", err)
+		return nil
+	}
+	return state
+}
+
+// auditServiceState returns the JSON state of the Kubernetes service, without the health and the metrics of the
+// service details
+func auditServiceState(layer *business.Layer, namespace, service string) json.RawMessage {
+	if !auditEnabled() {
+		return nil
+	}
+	details, err := layer.Svc.GetServiceDefinition(namespace, service)
+	if err != nil {
+		return auditState(nil, err)
+	}
+	return auditState(details.Service, nil)
+}
+
+// objectName returns the metadata.name of a Kubernetes object body
+func objectName(body []byte) string {
+	object := struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}{}
+	_ = json.Unmarshal(body, &object)
+	return object.Metadata.Name
+}
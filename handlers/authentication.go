@@ -608,6 +608,9 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 		var authInfo *api.AuthInfo
 		var token string
 
+		// The Kiali-User header is only set by the session checks, a value sent by the client is never trusted
+		r.Header.Del("Kiali-User")
+
 		switch conf.Auth.Strategy {
 		case config.AuthStrategyOpenshift:
 			statusCode, token = checkOpenshiftSession(w, r)
@@ -645,8 +648,14 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 				http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 				log.Errorf("No authInfo: %v", http.StatusBadRequest)
 			}
-			context := context.WithValue(r.Context(), "authInfo", authInfo)
-			next.ServeHTTP(w, r.WithContext(context))
+			ctx := context.WithValue(r.Context(), "authInfo", authInfo)
+			// The verified subject of the session, the audit trail resolves the users of the header strategy
+			if user := r.Header.Get("Kiali-User"); user != "" {
+				ctx = context.WithValue(ctx, "kialiUser", user)
+			} else if conf.Auth.Strategy == config.AuthStrategyAnonymous {
+				ctx = context.WithValue(ctx, "kialiUser", config.AuthStrategyAnonymous)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
 		case http.StatusUnauthorized:
 			deleteTokenCookies(w, r)
 			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
@@ -659,6 +668,7 @@ func (aHandler AuthenticationHandler) Handle(next http.Handler) http.Handler {
 
 func (aHandler AuthenticationHandler) HandleUnauthenticated(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Header.Del("Kiali-User")
 		context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: ""})
 		next.ServeHTTP(w, r.WithContext(context))
 	})
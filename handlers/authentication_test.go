@@ -596,3 +596,30 @@ func IsValidUUID(uuid string) bool {
 	r := regexp.MustCompile("^[0-9a-f]{8}-[0-9a-f]{4}-[0-5][0-9a-f]{3}-[089ab][0-9a-f]{3}-[0-9a-f]{12}$")
 	return r.MatchString(uuid)
 }
+
+type auditUserHandler struct {
+	user string
+}
+
+func (h *auditUserHandler) ServeHTTP(_ http.ResponseWriter, r *http.Request) {
+	h.user = auditUser(r)
+}
+
+// TestAuditUserIgnoresClientHeader checks that a Kiali-User header sent by the client is not audited
+func TestAuditUserIgnoresClientHeader(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Auth.Strategy = config.AuthStrategyAnonymous
+	config.Set(cfg)
+
+	request := httptest.NewRequest("POST", "http://kiali/api/foo", nil)
+	request.Header.Set("Kiali-User", "admin")
+
+	next := &auditUserHandler{}
+	handler := AuthenticationHandler{saToken: "kiali"}.Handle(next)
+	responseRecorder := httptest.NewRecorder()
+	handler.ServeHTTP(responseRecorder, request)
+
+	assert.Equal(t, http.StatusOK, responseRecorder.Code)
+	assert.Equal(t, config.AuthStrategyAnonymous, next.user)
+	assert.Empty(t, request.Header.Get("Kiali-User"))
+}
@@ -42,6 +42,12 @@ func RespondWithDetailedError(w http.ResponseWriter, code int, message, detail s
 	RespondWithJSON(w, code, responseError{Error: message, Detail: detail})
 }
 
+func RespondWithContent(w http.ResponseWriter, code int, contentType string, content []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(code)
+	_, _ = w.Write(content)
+}
+
 func RespondWithCode(w http.ResponseWriter, code int) {
 	w.WriteHeader(code)
 }
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+)
+
+// CanaryRollout is the API handler returning the canary rollout of a service
+func CanaryRollout(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	rollout, err := business.Canary.GetCanaryRollout(params["namespace"], params["service"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// CanaryRolloutStart is the API handler starting the canary rollout of a service, the canary controller advances
+// it. A routing introducing validation errors is only applied with force=true.
+func CanaryRolloutStart(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	spec := models.CanarySpec{}
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Canary rollout could not be read: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	rollout, err := business.Canary.StartCanaryRollout(namespace, service, spec, force, auditUser(r))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditCreate,
+		Namespace:  namespace,
+		ObjectType: "canaryrollouts",
+		Name:       service,
+		After:      auditState(rollout, nil),
+	})
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
+
+// CanaryRolloutAbort is the API handler aborting the progressing canary rollout of a service, the traffic is routed
+// back to the baseline
+func CanaryRolloutAbort(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.Canary.GetCanaryRollout(namespace, service))
+	}
+	rollout, err := business.Canary.AbortCanaryRollout(namespace, service)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditDelete,
+		Namespace:  namespace,
+		ObjectType: "canaryrollouts",
+		Name:       service,
+		Before:     before,
+		After:      auditState(rollout, nil),
+	})
+	RespondWithJSON(w, http.StatusOK, rollout)
+}
@@ -25,6 +25,10 @@ func handleErrorResponse(w http.ResponseWriter, err error, extraMesg ...string)
 		RespondWithError(w, http.StatusNotFound, errorMsg)
 	} else if errors.IsServiceUnavailable(err) {
 		RespondWithError(w, http.StatusServiceUnavailable, errorMsg)
+	} else if errors.IsBadRequest(err) {
+		RespondWithError(w, http.StatusBadRequest, errorMsg)
+	} else if errors.IsAlreadyExists(err) {
+		RespondWithError(w, http.StatusConflict, errorMsg)
 	} else if statusError, isStatus := err.(*errors.StatusError); isStatus {
 		errorMsg = statusError.ErrStatus.Message
 		RespondWithError(w, http.StatusInternalServerError, errorMsg)
@@ -18,13 +18,13 @@ package handlers
 //
 // The handlers accept the following query parameters (see notes below)
 //   appenders:       Comma-separated list of TelemetryVendor-specific appenders to run. (default: all)
-//   configVendor:    default: cytoscape
+//   configVendor:    cytoscape | dot | graphml | mermaid (default: cytoscape)
 //   duration:        time.Duration indicating desired query range duration, (default: 10m)
 //   graphType:       Determines how to present the telemetry data. app | service | versionedApp | workload (default: workload)
 //   boxBy:           If supported by vendor, visually box by a specified node attribute (default: none)
 //   namespaces:      Comma-separated list of namespace names to use in the graph. Will override namespace path param
 //   queryTime:       Unix time (seconds) for query such that range is queryTime-duration..queryTime (default now)
-//   TelemetryVendor: default: istio
+//   telemetryVendor: istio | otel (default: istio)
 //
 //  Note: some handlers may ignore some query parameters.
 //  Note: vendors may support additional, vendor-specific query parameters.
@@ -34,6 +34,8 @@ import (
 	"net/http"
 	"runtime/debug"
 
+	"github.com/gorilla/mux"
+
 	"github.com/kiali/kiali/graph"
 	"github.com/kiali/kiali/graph/api"
 	"github.com/kiali/kiali/log"
@@ -65,23 +67,22 @@ func GraphNode(w http.ResponseWriter, r *http.Request) {
 	respond(w, code, payload)
 }
 
+// GraphTrace is a REST http.HandlerFunc handling the graph of a single trace, the path of its requests.
+func GraphTrace(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewTraceOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.GraphTrace(business, o, mux.Vars(r)["traceID"])
+	respond(w, code, payload)
+}
+
 func handlePanic(w http.ResponseWriter) {
-	code := http.StatusInternalServerError
 	if r := recover(); r != nil {
-		var message string
-		switch err := r.(type) {
-		case string:
-			message = err
-		case error:
-			message = err.Error()
-		case func() string:
-			message = err()
-		case graph.Response:
-			message = err.Message
-			code = err.Code
-		default:
-			message = fmt.Sprintf("%v", r)
-		}
+		message, code := panicMessage(r)
 		if code == http.StatusInternalServerError {
 			stack := debug.Stack()
 			log.Errorf("%s: %s", message, stack)
@@ -92,8 +93,31 @@ func handlePanic(w http.ResponseWriter) {
 	}
 }
 
+// panicMessage returns the message and http status code of a recovered graph panic
+func panicMessage(r interface{}) (message string, code int) {
+	code = http.StatusInternalServerError
+	switch err := r.(type) {
+	case string:
+		message = err
+	case error:
+		message = err.Error()
+	case func() string:
+		message = err()
+	case graph.Response:
+		message = err.Message
+		code = err.Code
+	default:
+		message = fmt.Sprintf("%v", r)
+	}
+	return message, code
+}
+
 func respond(w http.ResponseWriter, code int, payload interface{}) {
 	if code == http.StatusOK {
+		if textConfig, ok := payload.(graph.TextConfig); ok {
+			RespondWithContent(w, code, textConfig.ContentType(), textConfig.Bytes())
+			return
+		}
 		RespondWithJSONIndent(w, code, payload)
 		return
 	}
@@ -0,0 +1,79 @@
+package handlers
+
+// Graph_analysis.go provides handlers analyzing the namespaces graph. They accept the same query
+// params as GraphNamespaces, plus the analysis-specific params. Nodes are identified by their
+// cytoscape (or traffic map) ID.
+
+import (
+	"net/http"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/analysis"
+	"github.com/kiali/kiali/graph/api"
+)
+
+// GraphBlastRadius is a REST http.HandlerFunc returning the upstream dependents and the downstream
+// dependencies of the "node" query param.
+func GraphBlastRadius(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewOptions(r)
+	node := requiredQueryParam(r, "node")
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.Analyze(business, o, func(trafficMap graph.TrafficMap) (interface{}, error) {
+		return analysis.NewBlastRadius(trafficMap, node)
+	})
+	respond(w, code, payload)
+}
+
+// GraphCriticalPath is a REST http.HandlerFunc returning the highest-latency path from the optional
+// "from" query param (default: any root node) to the "to" query param. The responseTime appender
+// is always run.
+func GraphCriticalPath(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewOptions(r)
+	from := r.URL.Query().Get("from")
+	to := requiredQueryParam(r, "to")
+	if !o.Appenders.All {
+		o.Appenders.AppenderNames = append(o.Appenders.AppenderNames, "responseTime")
+	}
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.Analyze(business, o, func(trafficMap graph.TrafficMap) (interface{}, error) {
+		return analysis.NewCriticalPath(trafficMap, from, to)
+	})
+	respond(w, code, payload)
+}
+
+// GraphHealthDrivers is a REST http.HandlerFunc returning the edges whose error rate degrades the
+// health of the "node" query param, evaluated with the configured health tolerances.
+func GraphHealthDrivers(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewOptions(r)
+	node := requiredQueryParam(r, "node")
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	rates := config.Get().HealthConfig.Rate
+	code, payload := api.Analyze(business, o, func(trafficMap graph.TrafficMap) (interface{}, error) {
+		return analysis.NewHealthDrivers(trafficMap, node, rates)
+	})
+	respond(w, code, payload)
+}
+
+func requiredQueryParam(r *http.Request, name string) string {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		graph.BadRequest("Missing required query param [" + name + "]")
+	}
+	return value
+}
@@ -0,0 +1,160 @@
+package handlers
+
+// Graph_snapshots.go provides handlers for persisting namespace graphs and for retrieving
+// and comparing the persisted snapshots. A user can access a snapshot only if every
+// namespace in the snapshot is accessible to the user.
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/api"
+	"github.com/kiali/kiali/graph/snapshot"
+)
+
+// GraphSnapshotCreate is a REST http.HandlerFunc generating a namespaces graph and persisting it as a snapshot.
+// It accepts the same query params as GraphNamespaces, plus an optional snapshot "name".
+func GraphSnapshotCreate(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	store, err := snapshot.GetStore()
+	graph.CheckUnavailable(err)
+
+	o := graph.NewOptions(r)
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	code, payload := api.SnapshotNamespaces(business, o, r.URL.Query().Get("name"), store)
+	respond(w, code, payload)
+}
+
+// GraphSnapshotList is a REST http.HandlerFunc listing the snapshots accessible to the user
+func GraphSnapshotList(w http.ResponseWriter, r *http.Request) {
+	store, err := snapshot.GetStore()
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	namespaces, err := business.Namespace.GetNamespaces()
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	infos, err := store.List()
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Unable to list graph snapshots: "+err.Error())
+		return
+	}
+
+	accessible := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		accessible[ns.Name] = true
+	}
+	result := []snapshot.Info{}
+	for _, info := range infos {
+		isAccessible := true
+		for _, ns := range info.Namespaces {
+			isAccessible = isAccessible && accessible[ns]
+		}
+		if isAccessible {
+			result = append(result, info)
+		}
+	}
+	RespondWithJSON(w, http.StatusOK, result)
+}
+
+// GraphSnapshot is a REST http.HandlerFunc returning the graph config for a snapshot. Only the configVendor
+// and boxBy query params are supported.
+func GraphSnapshot(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	o := graph.NewConfigOptions(r)
+
+	s, ok := getAccessibleSnapshot(w, r, mux.Vars(r)["snapshot"])
+	if !ok {
+		return
+	}
+
+	code, payload := api.GraphSnapshot(s, o)
+	respond(w, code, payload)
+}
+
+// GraphSnapshotDelete is a REST http.HandlerFunc removing a snapshot
+func GraphSnapshotDelete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["snapshot"]
+	if _, ok := getAccessibleSnapshot(w, r, id); !ok {
+		return
+	}
+
+	store, _ := snapshot.GetStore()
+	if err := store.Delete(id); err != nil && err != snapshot.ErrNotFound {
+		RespondWithError(w, http.StatusInternalServerError, "Unable to delete graph snapshot: "+err.Error())
+		return
+	}
+	RespondWithCode(w, http.StatusOK)
+}
+
+// GraphSnapshotDiff is a REST http.HandlerFunc comparing two snapshots
+func GraphSnapshotDiff(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	from, ok := getAccessibleSnapshot(w, r, params["snapshot"])
+	if !ok {
+		return
+	}
+	to, ok := getAccessibleSnapshot(w, r, params["toSnapshot"])
+	if !ok {
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, snapshot.NewDiff(from, to))
+}
+
+// getAccessibleSnapshot fetches the snapshot and ensures the user can access it. If not ok
+// the error response has already been written.
+func getAccessibleSnapshot(w http.ResponseWriter, r *http.Request, id string) (*snapshot.Snapshot, bool) {
+	store, err := snapshot.GetStore()
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return nil, false
+	}
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return nil, false
+	}
+
+	s, err := store.Get(id)
+	if err == snapshot.ErrNotFound {
+		RespondWithError(w, http.StatusNotFound, "Graph snapshot ["+id+"] not found")
+		return nil, false
+	} else if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Unable to read graph snapshot: "+err.Error())
+		return nil, false
+	}
+
+	if err := checkSnapshotAccess(layer, s); err != nil {
+		// do not reveal the existence of the snapshot
+		RespondWithError(w, http.StatusNotFound, "Graph snapshot ["+id+"] not found")
+		return nil, false
+	}
+	return s, true
+}
+
+func checkSnapshotAccess(layer *business.Layer, s *snapshot.Snapshot) error {
+	for _, ns := range s.Namespaces {
+		if _, err := layer.Namespace.GetNamespace(ns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
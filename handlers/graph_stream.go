@@ -0,0 +1,142 @@
+package handlers
+
+// Graph_stream.go provides the handler streaming live namespaces graph updates as server-sent events.
+// It accepts the same query params as GraphNamespaces (queryTime excluded, the graph is always current,
+// and only the cytoscape configVendor), plus:
+//   refreshInterval: time.Duration between graph updates (default: graph streaming config)
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/graph"
+	"github.com/kiali/kiali/graph/api"
+	"github.com/kiali/kiali/graph/config/cytoscape"
+	"github.com/kiali/kiali/graph/stream"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// GraphNamespacesStream is a REST http.HandlerFunc streaming the namespaces graph: a "snapshot" event with the
+// cytoscape config, then every refresh interval a "patch" event with the changed nodes and edges.
+func GraphNamespacesStream(w http.ResponseWriter, r *http.Request) {
+	defer handlePanic(w)
+
+	conf := config.Get().Graph.Streaming
+	if !conf.Enabled {
+		RespondWithError(w, http.StatusServiceUnavailable, "Graph streaming is disabled")
+		return
+	}
+
+	interval := time.Duration(conf.RefreshInterval) * time.Second
+	if intervalString := r.URL.Query().Get("refreshInterval"); intervalString != "" {
+		refreshInterval, err := model.ParseDuration(intervalString)
+		if err != nil {
+			graph.BadRequest(fmt.Sprintf("Invalid refreshInterval [%s]", intervalString))
+		}
+		interval = time.Duration(refreshInterval)
+	}
+	if minInterval := time.Duration(conf.MinRefreshInterval) * time.Second; interval < minInterval {
+		graph.BadRequest(fmt.Sprintf("Invalid refreshInterval, must be at least %v. [%v]", minInterval, interval))
+	}
+
+	// the graph is always generated for the current time
+	query := r.URL.Query()
+	query.Del("queryTime")
+	r.URL.RawQuery = query.Encode()
+
+	o := graph.NewOptions(r)
+	if o.ConfigVendor != graph.VendorCytoscape {
+		graph.BadRequest(fmt.Sprintf("Invalid configVendor, graph streaming only supports [%s]. [%s]", graph.VendorCytoscape, o.ConfigVendor))
+	}
+
+	business, err := getBusiness(r)
+	graph.CheckError(err)
+
+	generate := func() (config cytoscape.Config, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				message, _ := panicMessage(r)
+				err = fmt.Errorf("%s", message)
+			}
+		}()
+		// re-parse the options so that the query time, and namespace durations, are current
+		_, payload := api.GraphNamespaces(business, graph.NewOptions(r))
+		return payload.(cytoscape.Config), nil
+	}
+
+	// the next event is written within the refresh interval
+	sse, err := newSSEWriter(w, r, interval+httputil.WriteTimeout)
+	if err != nil {
+		graph.Error(err.Error())
+	}
+
+	if err := stream.Stream(r.Context(), interval, generate, sse.send); err != nil {
+		log.Debugf("Graph stream closed: %v", err)
+	}
+}
+
+// sseWriter writes server-sent events to the response, each event is flushed to the client. Each write extends the
+// write deadline of the connection by the timeout, instead of the server write timeout ending the stream. The stream
+// ends with the request context. On HTTP/2 connections, which can't extend their write deadline, the stream still
+// ends with the server write timeout, the client event source then reconnects.
+type sseWriter struct {
+	writer  http.ResponseWriter
+	flusher http.Flusher
+	request *http.Request
+	timeout time.Duration
+}
+
+func newSSEWriter(w http.ResponseWriter, r *http.Request, timeout time.Duration) (*sseWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported by the connection")
+	}
+	sse := &sseWriter{
+		writer:  w,
+		flusher: flusher,
+		request: r,
+		timeout: timeout,
+	}
+	sse.extendWriteDeadline()
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return sse, nil
+}
+
+func (sse *sseWriter) extendWriteDeadline() {
+	if !httputil.ExtendWriteDeadline(sse.request, sse.timeout) {
+		log.Tracef("Write deadline of the event stream [%s] not extended", sse.request.URL.Path)
+	}
+}
+
+func (sse *sseWriter) send(event string, data interface{}) error {
+	return sse.sendWithID(event, "", data)
+}
+
+// sendWithID sends an event with an id, sent back by a reconnecting client as the Last-Event-ID header, no id when
+// empty
+func (sse *sseWriter) sendWithID(event, id string, data interface{}) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	sse.extendWriteDeadline()
+	if id != "" {
+		if _, err := fmt.Fprintf(sse.writer, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(sse.writer, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	sse.flusher.Flush()
+	return nil
+}
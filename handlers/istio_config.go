@@ -1,16 +1,17 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/business"
-	"github.com/kiali/kiali/config"
-	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/models"
 )
 
@@ -162,12 +163,22 @@ func IstioConfigDelete(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
 		return
 	}
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.IstioConfig.GetIstioConfigDetails(namespace, objectType, object))
+	}
 	err = business.IstioConfig.DeleteIstioConfigDetail(api, namespace, objectType, object)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	} else {
-		audit(r, "DELETE on Namespace: "+namespace+" Type: "+objectType+" Name: "+object)
+		recordAudit(r, models.AuditRecord{
+			Operation:  models.AuditDelete,
+			Namespace:  namespace,
+			ObjectType: objectType,
+			Name:       object,
+			Before:     before,
+		})
 		RespondWithCode(w, http.StatusOK)
 	}
 }
@@ -196,6 +207,21 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Update request with bad update patch: "+err.Error())
 	}
 	jsonPatch := string(body)
+
+	if isDryRun(r) {
+		dryRun, err := business.IstioConfig.DryRunUpdateIstioConfigDetail(namespace, objectType, object, jsonPatch)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, dryRun)
+		return
+	}
+
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.IstioConfig.GetIstioConfigDetails(namespace, objectType, object))
+	}
 	updatedConfigDetails, err := business.IstioConfig.UpdateIstioConfigDetail(api, namespace, objectType, object, jsonPatch)
 
 	if err != nil {
@@ -203,7 +229,15 @@ func IstioConfigUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audit(r, "UPDATE on Namespace: "+namespace+" Type: "+objectType+" Name: "+object+" Patch: "+jsonPatch)
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: objectType,
+		Name:       object,
+		Patch:      jsonPatch,
+		Before:     before,
+		After:      auditState(updatedConfigDetails, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, updatedConfigDetails)
 }
 
@@ -231,25 +265,217 @@ func IstioConfigCreate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Create request could not be read: "+err.Error())
 	}
 
+	if isDryRun(r) {
+		dryRun, err := business.IstioConfig.DryRunCreateIstioConfigDetail(namespace, objectType, body)
+		if err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+		RespondWithJSON(w, http.StatusOK, dryRun)
+		return
+	}
+
 	createdConfigDetails, err := business.IstioConfig.CreateIstioConfigDetail(api, namespace, objectType, body)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	}
 
-	audit(r, "CREATE on Namespace: "+namespace+" Type: "+objectType+" Object: "+string(body))
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditCreate,
+		Namespace:  namespace,
+		ObjectType: objectType,
+		Name:       objectName(body),
+		Patch:      string(body),
+		After:      auditState(createdConfigDetails, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, createdConfigDetails)
 }
 
-func checkObjectType(objectType string) bool {
-	return business.GetIstioAPI(objectType) != ""
+// IstioConfigRevisions is the API handler listing the stored revisions of an Istio object, oldest first
+func IstioConfigRevisions(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	revisions, err := business.IstioConfig.GetIstioConfigRevisions(params["namespace"], params["object_type"], params["object"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, revisions)
 }
 
-func audit(r *http.Request, message string) {
-	if config.Get().Server.AuditLog {
-		user := r.Header.Get("Kiali-User")
-		log.Infof("AUDIT User [%s] Msg [%s]", user, message)
+// IstioConfigRevisionsDiff is the API handler comparing the "from" revision of an Istio object with the "to"
+// revision, or with the current object when "to" is not set
+func IstioConfigRevisionsDiff(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+	from, err := parseRevision(query.Get("from"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
 	}
+	to := 0
+	if query.Get("to") != "" {
+		if to, err = parseRevision(query.Get("to")); err != nil {
+			RespondWithError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	diff, err := business.IstioConfig.DiffIstioConfigRevisions(params["namespace"], params["object_type"], params["object"], from, to)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, diff)
+}
+
+// IstioConfigRevisionRestore is the API handler restoring a revision of an Istio object. The revision is validated
+// first, a revision introducing validation errors is only applied with force=true.
+func IstioConfigRevisionRestore(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	objectType := params["object_type"]
+	object := params["object"]
+
+	api := business.GetIstioAPI(objectType)
+	if api == "" {
+		RespondWithError(w, http.StatusBadRequest, "Object type not managed: "+objectType)
+		return
+	}
+	revision, err := parseRevision(params["revision"])
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.IstioConfig.GetIstioConfigDetails(namespace, objectType, object))
+	}
+	restore, err := business.IstioConfig.RestoreIstioConfigRevision(api, namespace, objectType, object, revision, force)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	if restore.Applied {
+		recordAudit(r, models.AuditRecord{
+			Operation:  models.AuditRestore,
+			Namespace:  namespace,
+			ObjectType: objectType,
+			Name:       object,
+			Patch:      "revision " + strconv.Itoa(revision),
+			Before:     before,
+			After:      auditState(restore.Details, nil),
+		})
+	}
+	RespondWithJSON(w, http.StatusOK, restore)
+}
+
+// IstioConfigBulkApply is the API handler applying a set of Istio objects of a namespace, from yaml or json
+// manifests. The objects are validated together, objects introducing validation errors are only applied with
+// force=true. The applied objects are rolled back when an object fails.
+func IstioConfigBulkApply(w http.ResponseWriter, r *http.Request) {
+	namespace := mux.Vars(r)["namespace"]
+	force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Apply request could not be read: "+err.Error())
+		return
+	}
+
+	result, err := business.IstioConfig.ApplyIstioConfigBulk(namespace, body, force, isDryRun(r))
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	recordBulkApplyAudit(r, namespace, result)
+	RespondWithJSON(w, bulkApplyStatus(result), result)
+}
+
+// bulkApplyStatus returns the response status of a bulk apply: a failed apply is a conflict when it is rolled back,
+// an internal error when the rollback failed too. The response body still describes each object.
+func bulkApplyStatus(result *models.IstioConfigBulkApply) int {
+	if result.Error == "" {
+		return http.StatusOK
+	}
+	for _, object := range result.Objects {
+		if object.Status == models.BulkApplyRollbackFailed {
+			return http.StatusInternalServerError
+		}
+	}
+	return http.StatusConflict
+}
+
+// recordBulkApplyAudit records the objects written by a bulk apply. The objects rolled back after a failure are
+// recorded with their rollback.
+func recordBulkApplyAudit(r *http.Request, namespace string, result *models.IstioConfigBulkApply) {
+	for _, object := range result.Objects {
+		if object.Status == models.BulkApplySkipped || object.Status == models.BulkApplyFailed {
+			continue
+		}
+		recordAudit(r, models.AuditRecord{
+			Operation:  object.Operation,
+			Namespace:  namespace,
+			ObjectType: object.ObjectType,
+			Name:       object.Name,
+			Patch:      string(object.Object),
+			Before:     object.Previous,
+			After:      object.Object,
+		})
+		if object.Status == models.BulkApplyRolledBack {
+			recordAudit(r, models.AuditRecord{
+				Operation:  models.AuditRollback,
+				Namespace:  namespace,
+				ObjectType: object.ObjectType,
+				Name:       object.Name,
+				Before:     object.Object,
+				After:      object.Previous,
+			})
+		}
+	}
+}
+
+func parseRevision(revision string) (int, error) {
+	r, err := strconv.Atoi(revision)
+	if err != nil || r <= 0 {
+		return 0, fmt.Errorf("invalid revision [%s]", revision)
+	}
+	return r, nil
+}
+
+// isDryRun returns true if the request only validates the Istio config change, without applying it
+func isDryRun(r *http.Request) bool {
+	dryRun, _ := strconv.ParseBool(r.URL.Query().Get("dryRun"))
+	return dryRun
+}
+
+func checkObjectType(objectType string) bool {
+	return business.GetIstioAPI(objectType) != ""
 }
 
 func IstioConfigPermissions(w http.ResponseWriter, r *http.Request) {
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"strings"
@@ -8,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
 )
 
 func Iter8Status(w http.ResponseWriter, r *http.Request) {
@@ -133,6 +135,14 @@ func Iter8ExperimentCreate(w http.ResponseWriter, r *http.Request) {
 		handleErrorResponse(w, err)
 		return
 	}
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditCreate,
+		Namespace:  namespace,
+		ObjectType: "experiments",
+		Name:       experiment.ExperimentItem.Name,
+		Patch:      string(body),
+		After:      auditState(experiment, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, experiment)
 }
 
@@ -151,11 +161,24 @@ func Iter8ExperimentUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.Iter8.GetIter8Experiment(namespace, name))
+	}
 	experiment, err := business.Iter8.UpdateIter8Experiment(namespace, name, body)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	}
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: "experiments",
+		Name:       name,
+		Patch:      string(body),
+		Before:     before,
+		After:      auditState(experiment, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, experiment)
 }
 
@@ -168,11 +191,22 @@ func Iter8ExperimentDelete(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
 		return
 	}
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.Iter8.GetIter8Experiment(namespace, name))
+	}
 	err = business.Iter8.DeleteIter8Experiment(namespace, name)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	}
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditDelete,
+		Namespace:  namespace,
+		ObjectType: "experiments",
+		Name:       name,
+		Before:     before,
+	})
 	RespondWithCode(w, http.StatusOK)
 }
 
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	"k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/models"
@@ -148,6 +149,47 @@ func TraceDetails(w http.ResponseWriter, r *http.Request) {
 	RespondWithJSON(w, http.StatusOK, trace)
 }
 
+// AppTracesAnalysis is the API handler to fetch the latency breakdown of the traces of an app
+func AppTracesAnalysis(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Traces analysis initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	q, err := readQuery(r.URL.Query())
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	analysis, err := business.Jaeger.GetAppTracesAnalysis(params["namespace"], params["app"], q)
+	if err != nil {
+		RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, analysis)
+}
+
+// TracesComparison is the API handler to compare a trace with a base trace span by span
+func TracesComparison(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Traces comparison initialization error: "+err.Error())
+		return
+	}
+	params := mux.Vars(r)
+	diff, err := business.Jaeger.CompareTraces(params["baseTraceID"], params["traceID"])
+	if err != nil {
+		if errors.IsNotFound(err) {
+			RespondWithError(w, http.StatusNotFound, err.Error())
+		} else {
+			RespondWithError(w, http.StatusServiceUnavailable, err.Error())
+		}
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, diff)
+}
+
 // AppSpans is the API handler to fetch Jaeger spans of a specific app
 func AppSpans(w http.ResponseWriter, r *http.Request) {
 	business, err := getBusiness(r)
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// logFollowInterval is the time between two sends of the followed logs
+const logFollowInterval = 2 * time.Second
+
+var logSeverities = map[string]bool{"ERROR": true, "WARN": true, "INFO": true, "DEBUG": true, "TRACE": true}
+
+// logsPods returns the pods whose logs are aggregated
+type logsPods func(layer *business.Layer) (models.Pods, error)
+
+// WorkloadLogs is the API handler to fetch the merged logs of all the containers of all the pods of a workload
+func WorkloadLogs(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	workloadType := r.URL.Query().Get("type")
+	aggregatedLogs(w, r, params["namespace"], func(layer *business.Layer) (models.Pods, error) {
+		return layer.Workload.GetWorkloadLogsPods(params["namespace"], params["workload"], workloadType)
+	})
+}
+
+// AppLogs is the API handler to fetch the merged logs of all the containers of all the pods of an app
+func AppLogs(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	aggregatedLogs(w, r, params["namespace"], func(layer *business.Layer) (models.Pods, error) {
+		return layer.App.GetAppLogsPods(params["namespace"], params["app"])
+	})
+}
+
+// aggregatedLogs responds with the fetched logs, or streams them as server-sent events in follow mode
+func aggregatedLogs(w http.ResponseWriter, r *http.Request, namespace string, getPods logsPods) {
+	queryParams := r.URL.Query()
+
+	// Get business layer
+	layer, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Logs initialization error: "+err.Error())
+		return
+	}
+
+	filter := business.LogFilter{}
+	if expr := queryParams.Get("filter"); expr != "" {
+		if filter.Regexp, err = regexp.Compile(expr); err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid filter ["+expr+"]: "+err.Error())
+			return
+		}
+	}
+	if severities := queryParams.Get("severity"); severities != "" {
+		filter.Severities = map[string]bool{}
+		for _, severity := range strings.Split(severities, ",") {
+			severity = strings.ToUpper(strings.TrimSpace(severity))
+			if !logSeverities[severity] {
+				RespondWithError(w, http.StatusBadRequest, "Invalid severity ["+severity+"], expecting ERROR, WARN, INFO, DEBUG or TRACE")
+				return
+			}
+			filter.Severities[severity] = true
+		}
+	}
+
+	// the container and proxy options are set for each container
+	opts, err := layer.Workload.BuildLogOptionsCriteria(
+		"",
+		queryParams.Get("duration"),
+		"false",
+		queryParams.Get("sinceTime"),
+		queryParams.Get("tailLines"))
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// in follow mode too, the first fetch errors are a regular response, e.g. on a missing workload
+	pods, err := getPods(layer)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	follow := queryParams.Get("follow") == "true"
+
+	// a reconnecting client resumes the stream after its last event
+	if lastEventID := r.Header.Get("Last-Event-ID"); follow && lastEventID != "" {
+		cursor, err := business.ParseLogCursor(lastEventID)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid Last-Event-ID: "+err.Error())
+			return
+		}
+		followLogs(w, r, layer, namespace, pods, nil, cursor, filter)
+		return
+	}
+
+	fetchTime := util.Clock.Now()
+	logs, err := layer.Workload.GetAggregatedLogs(namespace, pods, opts, filter)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	if !follow {
+		RespondWithJSON(w, http.StatusOK, logs)
+		return
+	}
+	followLogs(w, r, layer, namespace, pods, logs, business.NewLogCursor(fetchTime, logs.Entries), filter)
+}
+
+// followLogs streams the logs from the cursor, after sending the fetched logs when not nil. The pods are not
+// resolved again: the client reconnects to follow the new pods.
+func followLogs(w http.ResponseWriter, r *http.Request, layer *business.Layer, namespace string, pods models.Pods, fetched *business.PodLog, cursor business.LogCursor, filter business.LogFilter) {
+	// the deadline is extended on each event, sent at least every follow interval
+	sse, err := newSSEWriter(w, r, logFollowInterval+httputil.WriteTimeout)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if fetched != nil {
+		if err := sse.sendWithID(business.LogEventEntries, cursor.String(), fetched); err != nil {
+			log.Debugf("Log stream closed: %v", err)
+			return
+		}
+	}
+	if err := layer.Workload.FollowLogs(r.Context(), logFollowInterval, namespace, pods, cursor, filter, sse.sendWithID); err != nil {
+		log.Debugf("Log stream closed: %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/kubernetes/kubetest"
+	"github.com/kiali/kiali/util"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+type logsEvent struct {
+	id    string
+	event string
+	data  string
+}
+
+// readLogsEvent reads the next server-sent event
+func readLogsEvent(reader *bufio.Reader) (logsEvent, error) {
+	event := logsEvent{}
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return event, err
+		}
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && event.event != "":
+			return event, nil
+		case strings.HasPrefix(line, "id: "):
+			event.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			event.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			event.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+// readLogsMessages reads the logs events until one has entries
+func readLogsMessages(t *testing.T, reader *bufio.Reader) (string, []string) {
+	for {
+		event, err := readLogsEvent(reader)
+		require.NoError(t, err)
+		require.Equal(t, business.LogEventEntries, event.event)
+		logs := business.PodLog{}
+		require.NoError(t, json.Unmarshal([]byte(event.data), &logs))
+		if len(logs.Entries) > 0 {
+			messages := []string{}
+			for _, entry := range logs.Entries {
+				messages = append(messages, entry.Message)
+			}
+			return event.id, messages
+		}
+	}
+}
+
+// TestFollowAppLogs follows the logs through a server which write timeout is shorter than the follow interval, then
+// resumes them from the last event id
+func TestFollowAppLogs(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	conf := config.NewConfig()
+	conf.KubernetesConfig.CacheEnabled = false
+	config.Set(conf)
+	util.Clock = util.ClockMock{Time: time.Date(2021, 3, 1, 10, 0, 20, 0, time.UTC)}
+
+	k8s := kubetest.NewK8SClientMock()
+	k8s.On("GetPods", "bookinfo", "app=reviews").Return([]core_v1.Pod{{
+		ObjectMeta: meta_v1.ObjectMeta{Name: "reviews-v1-1", Namespace: "bookinfo"},
+		Spec:       core_v1.PodSpec{Containers: []core_v1.Container{{Name: "reviews"}}},
+	}}, nil)
+	k8s.On("GetPodLogs", "bookinfo", "reviews-v1-1", mock.Anything).Return(&kubernetes.PodLogs{
+		Logs: "2021-03-01T10:00:10Z INFO a\n2021-03-01T10:00:11Z INFO b\n",
+	}, nil).Once()
+	// the streams restart at the second of the last sent entry
+	k8s.On("StreamPodLogs", "bookinfo", "reviews-v1-1", mock.Anything).Return(ioutil.NopCloser(strings.NewReader(
+		"2021-03-01T10:00:11Z INFO b\n2021-03-01T10:00:12Z INFO c\n",
+	)), nil).Once()
+	k8s.On("StreamPodLogs", "bookinfo", "reviews-v1-1", mock.Anything).Return(ioutil.NopCloser(strings.NewReader(
+		"2021-03-01T10:00:12Z INFO c\n2021-03-01T10:00:13Z INFO d\n",
+	)), nil).Once()
+	business.SetWithBackends(kubetest.NewK8SClientFactoryMock(k8s), nil)
+
+	mr := mux.NewRouter()
+	mr.HandleFunc("/api/namespaces/{namespace}/apps/{app}/logs", http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			context := context.WithValue(r.Context(), "authInfo", &api.AuthInfo{Token: "test"})
+			AppLogs(w, r.WithContext(context))
+		}))
+	ts := httptest.NewUnstartedServer(mr)
+	ts.Config.WriteTimeout = logFollowInterval / 4
+	ts.Config.ConnContext = httputil.ConnContext
+	ts.Start()
+	defer ts.Close()
+
+	url := ts.URL + "/api/namespaces/bookinfo/apps/reviews/logs?follow=true"
+	resp, err := http.Get(url)
+	require.NoError(err)
+	reader := bufio.NewReader(resp.Body)
+
+	id, messages := readLogsMessages(t, reader)
+	assert.Equal([]string{"INFO a", "INFO b"}, messages)
+	assert.Equal(fmt.Sprintf("%d;reviews-v1-1/reviews=1", time.Date(2021, 3, 1, 10, 0, 11, 0, time.UTC).Unix()), id)
+
+	// sent after a follow interval, past the server write timeout
+	id, messages = readLogsMessages(t, reader)
+	assert.Equal([]string{"INFO c"}, messages)
+	resp.Body.Close()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(err)
+	req.Header.Set("Last-Event-ID", id)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(err)
+	defer resp.Body.Close()
+
+	_, messages = readLogsMessages(t, bufio.NewReader(resp.Body))
+	assert.Equal([]string{"INFO d"}, messages)
+	k8s.AssertNumberOfCalls(t, "GetPodLogs", 1)
+}
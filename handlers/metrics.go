@@ -213,6 +213,12 @@ func extractIstioMetricsQueryParams(r *http.Request, q *models.IstioMetricsQuery
 		}
 		q.Reporter = reporter
 	}
+	if exemplars := queryParams.Get("exemplars"); exemplars != "" {
+		var err error
+		if q.Exemplars, err = strconv.ParseBool(exemplars); err != nil {
+			return errors.New("bad request, cannot parse query parameter 'exemplars'")
+		}
+	}
 	return extractBaseMetricsQueryParams(queryParams, &q.RangeQuery, namespaceInfo)
 }
 
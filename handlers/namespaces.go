@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 
@@ -69,11 +70,23 @@ func NamespaceUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 	jsonPatch := string(body)
 
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.Namespace.GetNamespace(namespace))
+	}
 	ns, err := business.Namespace.UpdateNamespace(namespace, jsonPatch)
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	}
-	audit(r, "UPDATE on Namespace: "+namespace+" Patch: "+jsonPatch)
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: "namespaces",
+		Name:       namespace,
+		Patch:      jsonPatch,
+		Before:     before,
+		After:      auditState(ns, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, ns)
 }
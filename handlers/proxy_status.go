@@ -50,3 +50,78 @@ func ConfigDumpResourceEntries(w http.ResponseWriter, r *http.Request) {
 
 	RespondWithJSON(w, http.StatusOK, dump)
 }
+
+// ConfigDumpDiff is a REST http.HandlerFunc comparing the Envoy config of the pod with the one of another pod, given
+// by the "pod" and optional "namespace" query params, or with a stored dump given by the "snapshot" query param
+func ConfigDumpDiff(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	namespace := params["namespace"]
+	pod := params["pod"]
+	otherNamespace := query.Get("namespace")
+	if otherNamespace == "" {
+		otherNamespace = namespace
+	}
+	otherPod := query.Get("pod")
+	snapshot := query.Get("snapshot")
+	if (otherPod == "") == (snapshot == "") {
+		RespondWithError(w, http.StatusBadRequest, "Either the pod or the snapshot query param is required")
+		return
+	}
+
+	diff, err := business.ProxyStatus.DiffConfigDumps(namespace, pod, otherNamespace, otherPod, snapshot)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, diff)
+}
+
+// ConfigDumpSnapshots is a REST http.HandlerFunc listing the stored config dumps of the pod
+func ConfigDumpSnapshots(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	infos, err := business.ProxyStatus.ListConfigDumps(params["namespace"], params["pod"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, infos)
+}
+
+// ConfigDumpSnapshotCreate is a REST http.HandlerFunc storing the current config dump of the pod
+func ConfigDumpSnapshotCreate(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	info, err := business.ProxyStatus.SaveConfigDump(params["namespace"], params["pod"])
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusCreated, info)
+}
@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"sync"
 
 	"github.com/gorilla/mux"
@@ -138,6 +140,7 @@ func ServiceUpdate(w http.ResponseWriter, r *http.Request) {
 		}()
 	}
 
+	before := auditServiceState(business, namespace, service)
 	serviceDetails, err := business.Svc.UpdateService(namespace, service, rateInterval, queryTime, jsonPatch)
 
 	if includeValidations && err == nil {
@@ -151,6 +154,75 @@ func ServiceUpdate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	audit(r, "UPDATE on Namespace: "+namespace+" Service name: "+service+" Patch: "+jsonPatch)
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: "services",
+		Name:       service,
+		Patch:      jsonPatch,
+		Before:     before,
+		After:      auditServiceState(business, namespace, service),
+	})
 	RespondWithJSON(w, http.StatusOK, serviceDetails)
 }
+
+// ServiceSLOs is a REST http.HandlerFunc returning the service level objectives of a service, with their service
+// level indicator, remaining error budget and burn rates
+func ServiceSLOs(w http.ResponseWriter, r *http.Request) {
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	params := mux.Vars(r)
+	slos, err := business.SLO.GetServiceSLOs(params["namespace"], params["service"], util.Clock.Now())
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+	RespondWithJSON(w, http.StatusOK, slos)
+}
+
+// ServiceTrafficRouting is the API handler generating the VirtualService and the DestinationRule of a service from a
+// traffic intent. With apply=true the routing is applied, objects introducing validation errors are only applied
+// with force=true. With dryRun=true the routing is only validated.
+func ServiceTrafficRouting(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	namespace := params["namespace"]
+	service := params["service"]
+	query := r.URL.Query()
+	apply, _ := strconv.ParseBool(query.Get("apply"))
+	force, _ := strconv.ParseBool(query.Get("force"))
+	dryRun := isDryRun(r)
+
+	intent := models.TrafficIntent{}
+	if err := json.NewDecoder(r.Body).Decode(&intent); err != nil {
+		RespondWithError(w, http.StatusBadRequest, "Traffic intent could not be read: "+err.Error())
+		return
+	}
+
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Services initialization error: "+err.Error())
+		return
+	}
+
+	var routing *models.TrafficRouting
+	if apply || dryRun {
+		routing, err = business.TrafficRouting.ApplyTrafficRouting(namespace, service, intent, force, dryRun)
+	} else {
+		routing, err = business.TrafficRouting.GenerateTrafficRouting(namespace, service, intent)
+	}
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	status := http.StatusOK
+	if routing.Apply != nil {
+		recordBulkApplyAudit(r, namespace, routing.Apply)
+		status = bulkApplyStatus(routing.Apply)
+	}
+	RespondWithJSON(w, status, routing)
+}
@@ -1,10 +1,21 @@
 package handlers
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
+
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util"
+)
+
+const (
+	defaultAccessLogDuration = "10m"
+	defaultAccessLogLimit    = 10
 )
 
 // WorkloadList is the API handler to fetch all the workloads to be displayed, related to a single namespace
@@ -51,9 +62,70 @@ func WorkloadDetails(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The proxy checks fetch the config dump of every pod, they are only computed on demand
+	if _, found := query["proxyChecks"]; found {
+		if workloadDetails.ProxyChecks, err = business.ProxyStatus.GetWorkloadProxyChecks(namespace, workloadDetails); err != nil {
+			handleErrorResponse(w, err)
+			return
+		}
+	}
+
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
+// WorkloadAccessLogSummary is the API handler to aggregate the proxy access logs of all the pods of a workload
+func WorkloadAccessLogSummary(w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	query := r.URL.Query()
+
+	// Get business layer
+	business, err := getBusiness(r)
+	if err != nil {
+		RespondWithError(w, http.StatusInternalServerError, "Workloads initialization error: "+err.Error())
+		return
+	}
+	namespace := params["namespace"]
+	workload := params["workload"]
+	workloadType := query.Get("type")
+
+	limit := defaultAccessLogLimit
+	if strLimit := query.Get("limit"); strLimit != "" {
+		if limit, err = strconv.Atoi(strLimit); err != nil || limit <= 0 {
+			RespondWithError(w, http.StatusBadRequest, "Cannot parse parameter 'limit', expecting a positive integer: "+strLimit)
+			return
+		}
+	}
+
+	// Without start time, the window is the last duration
+	duration := query.Get("duration")
+	sinceTime := query.Get("sinceTime")
+	if sinceTime == "" {
+		if duration == "" {
+			duration = defaultAccessLogDuration
+		}
+		d, err := time.ParseDuration(duration)
+		if err != nil {
+			RespondWithError(w, http.StatusBadRequest, "Invalid duration ["+duration+"]: "+err.Error())
+			return
+		}
+		sinceTime = strconv.FormatInt(util.Clock.Now().Add(-d).Unix(), 10)
+		duration = ""
+	}
+	opts, err := business.Workload.BuildLogOptionsCriteria("istio-proxy", duration, "true", sinceTime, "")
+	if err != nil {
+		RespondWithError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	summary, err := business.Workload.GetAccessLogSummary(namespace, workload, workloadType, opts, limit)
+	if err != nil {
+		handleErrorResponse(w, err)
+		return
+	}
+
+	RespondWithJSON(w, http.StatusOK, summary)
+}
+
 // WorkloadUpdate is the API to perform a patch on a Workload configuration
 func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -75,13 +147,25 @@ func WorkloadUpdate(w http.ResponseWriter, r *http.Request) {
 		RespondWithError(w, http.StatusBadRequest, "Update request with bad update patch: "+err.Error())
 	}
 	jsonPatch := string(body)
+	var before json.RawMessage
+	if auditEnabled() {
+		before = auditState(business.Workload.GetWorkload(namespace, workload, workloadType, true))
+	}
 	workloadDetails, err := business.Workload.UpdateWorkload(namespace, workload, workloadType, true, jsonPatch)
 
 	if err != nil {
 		handleErrorResponse(w, err)
 		return
 	}
-	audit(r, "UPDATE on Namespace: "+namespace+" Workload name: "+workload+" Type: "+workloadType+" Patch: "+jsonPatch)
+	recordAudit(r, models.AuditRecord{
+		Operation:  models.AuditUpdate,
+		Namespace:  namespace,
+		ObjectType: "workloads",
+		Name:       workload,
+		Patch:      jsonPatch,
+		Before:     before,
+		After:      auditState(workloadDetails, nil),
+	})
 	RespondWithJSON(w, http.StatusOK, workloadDetails)
 }
 
@@ -43,6 +43,39 @@ type Client struct {
 	ctx        context.Context
 }
 
+// NewTracingClient returns the client of the tracing backend set in the config
+func NewTracingClient(token string) (ClientInterface, error) {
+	switch provider := config.Get().ExternalServices.Tracing.Provider; provider {
+	case "", config.TracingProviderJaeger:
+		client, err := NewClient(token)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	case config.TracingProviderTempo:
+		client, err := NewTempoClient(token)
+		if err != nil {
+			return nil, err
+		}
+		return client, nil
+	default:
+		return nil, fmt.Errorf("tracing provider [%s] not supported", provider)
+	}
+}
+
+// tracingURL returns the URL of the tracing backend, the in-cluster one when running in the cluster
+func tracingURL(cfg *config.Config) (*url.URL, error) {
+	u, err := url.Parse(cfg.ExternalServices.Tracing.InClusterURL)
+	if !cfg.InCluster {
+		u, err = url.Parse(cfg.ExternalServices.Tracing.URL)
+	}
+	if err != nil {
+		log.Errorf("Error parsing tracing URL: %s", err)
+		return nil, err
+	}
+	return u, nil
+}
+
 func NewClient(token string) (*Client, error) {
 	cfg := config.Get()
 	cfgTracing := cfg.ExternalServices.Tracing
@@ -56,12 +89,8 @@ func NewClient(token string) (*Client, error) {
 		}
 		ctx := context.Background()
 
-		u, errParse := url.Parse(cfgTracing.InClusterURL)
-		if !cfg.InCluster {
-			u, errParse = url.Parse(cfgTracing.URL)
-		}
+		u, errParse := tracingURL(cfg)
 		if errParse != nil {
-			log.Errorf("Error parsing Jaeger URL: %s", errParse)
 			return nil, errParse
 		}
 
@@ -0,0 +1,409 @@
+package jaeger
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jaegertracing/jaeger/model"
+	jsonModel "github.com/jaegertracing/jaeger/model/json"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+	"github.com/kiali/kiali/models"
+	"github.com/kiali/kiali/util/httputil"
+)
+
+// tempoMaxConcurrentFetches bounds the traces fetched in parallel after a search
+const tempoMaxConcurrentFetches = 10
+
+// TempoClient is the client of the Grafana Tempo HTTP API. Tempo searches return only the trace IDs, the traces are
+// then fetched by ID. The OTLP traces are converted to the Jaeger model, for the Kiali API to be the same with
+// both backends.
+type TempoClient struct {
+	httpClient http.Client
+	baseURL    *url.URL
+}
+
+func NewTempoClient(token string) (*TempoClient, error) {
+	cfg := config.Get()
+	cfgTracing := cfg.ExternalServices.Tracing
+
+	if !cfgTracing.Enabled {
+		return nil, errors.New("tracing is not enabled")
+	}
+	auth := cfgTracing.Auth
+	if auth.UseKialiToken {
+		auth.Token = token
+	}
+	u, err := tracingURL(cfg)
+	if err != nil {
+		return nil, err
+	}
+	log.Tracef("Using HTTP client for Tempo: url=%v, auth.type=%s", u, auth.Type)
+	timeout := time.Duration(5000 * time.Millisecond)
+	transport, err := httputil.CreateTransport(&auth, &http.Transport{}, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &TempoClient{httpClient: http.Client{Transport: transport, Timeout: timeout}, baseURL: u}, nil
+}
+
+// tempoSearchResponse is the response of /api/search
+type tempoSearchResponse struct {
+	Traces []struct {
+		TraceID string `json:"traceID"`
+	} `json:"traces"`
+}
+
+// GetAppTraces searches the traces of an app then fetches them
+func (in *TempoClient) GetAppTraces(namespace, app string, q models.TracingQuery) (*JaegerResponse, error) {
+	serviceName := buildJaegerServiceName(namespace, app)
+	traceIDs, err := in.search(serviceName, q)
+	if err != nil {
+		return nil, err
+	}
+
+	traces := make([]*jsonModel.Trace, len(traceIDs))
+	wg := sync.WaitGroup{}
+	wg.Add(len(traceIDs))
+	errChan := make(chan error, len(traceIDs))
+	semaphore := make(chan bool, tempoMaxConcurrentFetches)
+	for i, traceID := range traceIDs {
+		go func(i int, traceID string) {
+			defer wg.Done()
+			semaphore <- true
+			defer func() { <-semaphore }()
+			trace, err := in.getTrace(traceID)
+			if err != nil {
+				errChan <- err
+				return
+			}
+			traces[i] = trace
+		}(i, traceID)
+	}
+	wg.Wait()
+	if len(errChan) != 0 {
+		return nil, <-errChan
+	}
+
+	r := JaegerResponse{
+		Data:              []jsonModel.Trace{},
+		JaegerServiceName: serviceName,
+	}
+	for _, trace := range traces {
+		// a trace found by the search may not be readable yet
+		if trace != nil {
+			r.Data = append(r.Data, *trace)
+		}
+	}
+	return &r, nil
+}
+
+// GetTraceDetail fetches a specific trace from its ID
+func (in *TempoClient) GetTraceDetail(traceID string) (*JaegerSingleTrace, error) {
+	trace, err := in.getTrace(traceID)
+	if err != nil || trace == nil {
+		return nil, err
+	}
+	return &JaegerSingleTrace{Data: *trace}, nil
+}
+
+// GetErrorTraces fetches number of traces in error for the given app
+func (in *TempoClient) GetErrorTraces(ns, app string, duration time.Duration) (int, error) {
+	now := time.Now()
+	query := models.TracingQuery{
+		Start: now.Add(-duration),
+		End:   now,
+		Tags:  map[string]string{"status.code": "error"},
+	}
+	traceIDs, err := in.search(buildJaegerServiceName(ns, app), query)
+	if err != nil {
+		return 0, err
+	}
+	return len(traceIDs), nil
+}
+
+func (in *TempoClient) GetServiceStatus() (bool, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/echo")
+	_, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("Tempo status error [code: %d]", code)
+	}
+	return err == nil, err
+}
+
+// search returns the IDs of the traces matching the query
+func (in *TempoClient) search(serviceName string, q models.TracingQuery) ([]string, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/search")
+	prepareTempoQuery(&u, serviceName, q)
+
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("Tempo search error [code: %d]: %s", code, string(resp))
+	}
+	if err != nil {
+		log.Errorf("Tempo query error: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	var response tempoSearchResponse
+	if err := json.Unmarshal(resp, &response); err != nil {
+		log.Errorf("Error unmarshalling Tempo response: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	traceIDs := make([]string, 0, len(response.Traces))
+	for _, trace := range response.Traces {
+		traceIDs = append(traceIDs, trace.TraceID)
+	}
+	return traceIDs, nil
+}
+
+func prepareTempoQuery(u *url.URL, serviceName string, query models.TracingQuery) {
+	// the tags are in logfmt, e.g. service.name=reviews.bookinfo http.status_code=500
+	tags := []string{"service.name=" + logfmtValue(serviceName)}
+	keys := make([]string, 0, len(query.Tags))
+	for key := range query.Tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		tags = append(tags, key+"="+logfmtValue(query.Tags[key]))
+	}
+
+	q := url.Values{}
+	q.Set("tags", strings.Join(tags, " "))
+	if !query.Start.IsZero() {
+		q.Set("start", strconv.FormatInt(query.Start.Unix(), 10))
+	}
+	if !query.End.IsZero() {
+		q.Set("end", strconv.FormatInt(query.End.Unix(), 10))
+	}
+	if query.MinDuration > 0 {
+		q.Set("minDuration", query.MinDuration.String())
+	}
+	if query.Limit > 0 {
+		q.Set("limit", strconv.Itoa(query.Limit))
+	}
+	u.RawQuery = q.Encode()
+	log.Debugf("Prepared Tempo query: %v", u)
+}
+
+func logfmtValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+// getTrace fetches a trace by ID, nil when not found
+func (in *TempoClient) getTrace(traceID string) (*jsonModel.Trace, error) {
+	u := *in.baseURL
+	u.Path = path.Join(u.Path, "/api/traces/"+traceID)
+	resp, code, err := makeRequest(in.httpClient, u.String(), nil)
+	if err == nil && code == http.StatusNotFound {
+		return nil, nil
+	}
+	if err == nil && code != http.StatusOK {
+		err = fmt.Errorf("Tempo trace error [code: %d]: %s", code, string(resp))
+	}
+	if err != nil {
+		log.Errorf("Tempo query error: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	var trace otlpTrace
+	if err := json.Unmarshal(resp, &trace); err != nil {
+		log.Errorf("Error unmarshalling Tempo trace: %s [URL: %v]", err, u)
+		return nil, err
+	}
+	converted := trace.toJaeger()
+	if len(converted.Spans) == 0 {
+		return nil, nil
+	}
+	return converted, nil
+}
+
+// otlpTrace is an OTLP JSON trace, "batches" for the Tempo v1 API, "resourceSpans" for OTLP
+type otlpTrace struct {
+	Batches       []otlpResourceSpans `json:"batches"`
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource struct {
+		Attributes []otlpKeyValue `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans                  []otlpScopeSpans `json:"scopeSpans"`
+	InstrumentationLibrarySpans []otlpScopeSpans `json:"instrumentationLibrarySpans"` // before OTLP 0.15
+}
+
+type otlpScopeSpans struct {
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId"`
+	Name              string         `json:"name"`
+	Kind              otlpEnum       `json:"kind"`
+	StartTimeUnixNano otlpUint64     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   otlpUint64     `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes"`
+	Status            struct {
+		Code    otlpEnum `json:"code"`
+		Message string   `json:"message"`
+	} `json:"status"`
+}
+
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string     `json:"stringValue"`
+		BoolValue   *bool       `json:"boolValue"`
+		IntValue    *otlpUint64 `json:"intValue"`
+		DoubleValue *float64    `json:"doubleValue"`
+	} `json:"value"`
+}
+
+// otlpEnum is an enum, either by name or by number
+type otlpEnum string
+
+func (e *otlpEnum) UnmarshalJSON(data []byte) error {
+	*e = otlpEnum(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// otlpUint64 is a 64 bits integer, either a string or a number
+type otlpUint64 uint64
+
+func (i *otlpUint64) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	*i = otlpUint64(value)
+	return err
+}
+
+var otlpSpanKinds = map[otlpEnum]string{
+	"SPAN_KIND_SERVER":   "server",
+	"2":                  "server",
+	"SPAN_KIND_CLIENT":   "client",
+	"3":                  "client",
+	"SPAN_KIND_PRODUCER": "producer",
+	"4":                  "producer",
+	"SPAN_KIND_CONSUMER": "consumer",
+	"5":                  "consumer",
+}
+
+// otlpID returns the hexadecimal form of a base64 or hexadecimal ID
+func otlpID(id string) string {
+	if _, err := hex.DecodeString(id); err == nil && (len(id) == 16 || len(id) == 32) {
+		return id
+	}
+	if bytes, err := base64.StdEncoding.DecodeString(id); err == nil {
+		return hex.EncodeToString(bytes)
+	}
+	return id
+}
+
+func (kv otlpKeyValue) toJaeger() jsonModel.KeyValue {
+	v := kv.Value
+	switch {
+	case v.BoolValue != nil:
+		return jsonModel.KeyValue{Key: kv.Key, Type: jsonModel.BoolType, Value: *v.BoolValue}
+	case v.IntValue != nil:
+		return jsonModel.KeyValue{Key: kv.Key, Type: jsonModel.Int64Type, Value: int64(*v.IntValue)}
+	case v.DoubleValue != nil:
+		return jsonModel.KeyValue{Key: kv.Key, Type: jsonModel.Float64Type, Value: *v.DoubleValue}
+	case v.StringValue != nil:
+		return jsonModel.KeyValue{Key: kv.Key, Type: jsonModel.StringType, Value: *v.StringValue}
+	default:
+		// arrays, maps and bytes are not supported by the Jaeger model
+		return jsonModel.KeyValue{Key: kv.Key, Type: jsonModel.StringType, Value: ""}
+	}
+}
+
+// toJaeger converts the trace to the Jaeger model, with a process per resource and the IDs in the Jaeger format.
+// The span kind and the error status are converted to the span.kind and error tags.
+func (t otlpTrace) toJaeger() *jsonModel.Trace {
+	trace := &jsonModel.Trace{
+		Spans:     []jsonModel.Span{},
+		Processes: map[jsonModel.ProcessID]jsonModel.Process{},
+	}
+	for i, resourceSpans := range append(t.Batches, t.ResourceSpans...) {
+		processID := jsonModel.ProcessID(fmt.Sprintf("p%d", i+1))
+		process := jsonModel.Process{Tags: []jsonModel.KeyValue{}}
+		for _, attribute := range resourceSpans.Resource.Attributes {
+			if attribute.Key == "service.name" && attribute.Value.StringValue != nil {
+				process.ServiceName = *attribute.Value.StringValue
+			} else {
+				process.Tags = append(process.Tags, attribute.toJaeger())
+			}
+		}
+		trace.Processes[processID] = process
+
+		for _, scopeSpans := range append(resourceSpans.ScopeSpans, resourceSpans.InstrumentationLibrarySpans...) {
+			for _, span := range scopeSpans.Spans {
+				converted := span.toJaeger(processID)
+				trace.TraceID = converted.TraceID
+				trace.Spans = append(trace.Spans, converted)
+			}
+		}
+	}
+	return trace
+}
+
+func (s otlpSpan) toJaeger(processID jsonModel.ProcessID) jsonModel.Span {
+	traceID := otlpID(s.TraceID)
+	if id, err := model.TraceIDFromString(traceID); err == nil {
+		traceID = id.String()
+	}
+	span := jsonModel.Span{
+		TraceID:       jsonModel.TraceID(traceID),
+		SpanID:        jaegerSpanID(s.SpanID),
+		OperationName: s.Name,
+		References:    []jsonModel.Reference{},
+		StartTime:     uint64(s.StartTimeUnixNano) / 1000,
+		ProcessID:     processID,
+		Tags:          []jsonModel.KeyValue{},
+		Logs:          []jsonModel.Log{},
+	}
+	if s.EndTimeUnixNano > s.StartTimeUnixNano {
+		span.Duration = uint64(s.EndTimeUnixNano-s.StartTimeUnixNano) / 1000
+	}
+	if s.ParentSpanID != "" {
+		span.References = append(span.References, jsonModel.Reference{
+			RefType: jsonModel.ChildOf,
+			TraceID: span.TraceID,
+			SpanID:  jaegerSpanID(s.ParentSpanID),
+		})
+	}
+	for _, attribute := range s.Attributes {
+		span.Tags = append(span.Tags, attribute.toJaeger())
+	}
+	if kind, ok := otlpSpanKinds[s.Kind]; ok {
+		span.Tags = append(span.Tags, jsonModel.KeyValue{Key: "span.kind", Type: jsonModel.StringType, Value: kind})
+	}
+	if s.Status.Code == "STATUS_CODE_ERROR" || s.Status.Code == "2" {
+		span.Tags = append(span.Tags, jsonModel.KeyValue{Key: "error", Type: jsonModel.BoolType, Value: true})
+	}
+	return span
+}
+
+func jaegerSpanID(otlpSpanID string) jsonModel.SpanID {
+	spanID := otlpID(otlpSpanID)
+	if id, err := model.SpanIDFromString(spanID); err == nil {
+		spanID = id.String()
+	}
+	return jsonModel.SpanID(spanID)
+}
@@ -0,0 +1,161 @@
+package jaeger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jsonModel "github.com/jaegertracing/jaeger/model/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/models"
+)
+
+// a Tempo v1 trace, the IDs are base64 encoded and the 64 bits integers are strings
+const tempoTrace = `{
+  "batches": [{
+    "resource": {"attributes": [
+      {"key": "service.name", "value": {"stringValue": "reviews.bookinfo"}},
+      {"key": "hostname", "value": {"stringValue": "reviews-v2-7bf8c9648f-f8dxq"}}
+    ]},
+    "instrumentationLibrarySpans": [{"spans": [{
+      "traceId": "AAAAAAAAAAAT3Q8HGU7cSg==",
+      "spanId": "E90PBxlO3Eo=",
+      "name": "reviews.bookinfo.svc.cluster.local:9080/*",
+      "kind": "SPAN_KIND_SERVER",
+      "startTimeUnixNano": "1600000000000000000",
+      "endTimeUnixNano": "1600000000025000000",
+      "attributes": [
+        {"key": "node_id", "value": {"stringValue": "sidecar~172.17.0.20~reviews-v2-7bf8c9648f-f8dxq.bookinfo~bookinfo.svc.cluster.local"}},
+        {"key": "http.status_code", "value": {"intValue": "503"}}
+      ],
+      "status": {"code": 2}
+    }]}]
+  }, {
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "ratings.bookinfo"}}]},
+    "scopeSpans": [{"spans": [{
+      "traceId": "000000000000000013dd0f07194edc4a",
+      "spanId": "1a2b3c4d5e6f7a8b",
+      "parentSpanId": "E90PBxlO3Eo=",
+      "name": "ratings.bookinfo.svc.cluster.local:9080/*",
+      "kind": 3,
+      "startTimeUnixNano": 1600000000005000000,
+      "endTimeUnixNano": 1600000000015000000,
+      "attributes": [{"key": "retry", "value": {"boolValue": true}}]
+    }]}]
+  }]
+}`
+
+func setupTempo(t *testing.T, handler http.HandlerFunc) *TempoClient {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	conf := config.NewConfig()
+	conf.InCluster = false
+	conf.ExternalServices.Tracing.Provider = config.TracingProviderTempo
+	conf.ExternalServices.Tracing.URL = server.URL
+	config.Set(conf)
+
+	client, err := NewTracingClient("")
+	require.NoError(t, err)
+	return client.(*TempoClient)
+}
+
+func TestTempoTraceDetail(t *testing.T) {
+	assert := assert.New(t)
+
+	client := setupTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/traces/13dd0f07194edc4a" {
+			_, _ = w.Write([]byte(tempoTrace))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	trace, err := client.GetTraceDetail("13dd0f07194edc4a")
+	require.NoError(t, err)
+	require.NotNil(t, trace)
+	require.Len(t, trace.Data.Spans, 2)
+	assert.Equal("reviews.bookinfo", trace.Data.Processes["p1"].ServiceName)
+	assert.Equal("ratings.bookinfo", trace.Data.Processes["p2"].ServiceName)
+
+	reviews := trace.Data.Spans[0]
+	assert.Equal(jsonModel.TraceID("13dd0f07194edc4a"), reviews.TraceID)
+	assert.Equal(jsonModel.SpanID("13dd0f07194edc4a"), reviews.SpanID)
+	assert.Equal(uint64(1600000000000000), reviews.StartTime)
+	assert.Equal(uint64(25000), reviews.Duration)
+	assert.Empty(reviews.References)
+	assert.Contains(reviews.Tags, jsonModel.KeyValue{Key: "http.status_code", Type: jsonModel.Int64Type, Value: int64(503)})
+	assert.Contains(reviews.Tags, jsonModel.KeyValue{Key: "span.kind", Type: jsonModel.StringType, Value: "server"})
+	assert.Contains(reviews.Tags, jsonModel.KeyValue{Key: "error", Type: jsonModel.BoolType, Value: true})
+
+	ratings := trace.Data.Spans[1]
+	assert.Equal(jsonModel.ProcessID("p2"), ratings.ProcessID)
+	assert.Equal([]jsonModel.Reference{{RefType: jsonModel.ChildOf, TraceID: "13dd0f07194edc4a", SpanID: "13dd0f07194edc4a"}}, ratings.References)
+	assert.Equal(uint64(10000), ratings.Duration)
+	assert.Contains(ratings.Tags, jsonModel.KeyValue{Key: "span.kind", Type: jsonModel.StringType, Value: "client"})
+	assert.NotContains(ratings.Tags, jsonModel.KeyValue{Key: "error", Type: jsonModel.BoolType, Value: true})
+
+	trace, err = client.GetTraceDetail("123")
+	assert.NoError(err)
+	assert.Nil(trace)
+}
+
+func TestTempoAppTraces(t *testing.T) {
+	assert := assert.New(t)
+
+	var search *http.Request
+	client := setupTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/search":
+			search = r
+			_, _ = w.Write([]byte(`{"traces": [{"traceID": "13dd0f07194edc4a"}, {"traceID": "2f"}]}`))
+		case "/api/traces/13dd0f07194edc4a":
+			_, _ = w.Write([]byte(tempoTrace))
+		default:
+			// not readable yet
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	end := time.Unix(1600000600, 0)
+	r, err := client.GetAppTraces("bookinfo", "reviews", models.TracingQuery{
+		Start:       end.Add(-10 * time.Minute),
+		End:         end,
+		Tags:        map[string]string{"http.status_code": "503", "error": "true"},
+		MinDuration: 100 * time.Millisecond,
+		Limit:       20,
+	})
+	require.NoError(t, err)
+	assert.Equal("reviews.bookinfo", r.JaegerServiceName)
+	require.Len(t, r.Data, 1)
+	assert.Equal(jsonModel.TraceID("13dd0f07194edc4a"), r.Data[0].TraceID)
+
+	query := search.URL.Query()
+	assert.Equal("service.name=reviews.bookinfo error=true http.status_code=503", query.Get("tags"))
+	assert.Equal("1600000000", query.Get("start"))
+	assert.Equal("1600000600", query.Get("end"))
+	assert.Equal("100ms", query.Get("minDuration"))
+	assert.Equal("20", query.Get("limit"))
+}
+
+func TestTempoSearchError(t *testing.T) {
+	client := setupTempo(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("invalid tags"))
+	})
+
+	_, err := client.GetErrorTraces("bookinfo", "reviews", time.Minute)
+	assert.EqualError(t, err, "Tempo search error [code: 400]: invalid tags")
+}
+
+func TestLogfmtValue(t *testing.T) {
+	assert := assert.New(t)
+
+	assert.Equal("reviews.bookinfo", logfmtValue("reviews.bookinfo"))
+	assert.Equal(`"GET /reviews"`, logfmtValue("GET /reviews"))
+	assert.Equal(`""`, logfmtValue(""))
+}
@@ -54,6 +54,11 @@ func main() {
 	log.InitializeLogger()
 	util.Clock = util.RealClock{}
 
+	// the validate command validates Istio config manifests offline, instead of starting the server
+	if len(os.Args) > 1 && os.Args[1] == "validate" {
+		os.Exit(runValidate(os.Args[2:], os.Stdout, os.Stderr))
+	}
+
 	// process command line
 	flag.Parse()
 	validateFlags()
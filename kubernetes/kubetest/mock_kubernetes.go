@@ -1,6 +1,8 @@
 package kubetest
 
 import (
+	"io"
+
 	apps_v1 "k8s.io/api/apps/v1"
 	auth_v1 "k8s.io/api/authorization/v1"
 	batch_v1 "k8s.io/api/batch/v1"
@@ -91,6 +93,11 @@ func (o *K8SClientMock) GetPodLogs(namespace, name string, opts *core_v1.PodLogO
 	return args.Get(0).(*kubernetes.PodLogs), args.Error(1)
 }
 
+func (o *K8SClientMock) StreamPodLogs(namespace, name string, opts *core_v1.PodLogOptions) (io.ReadCloser, error) {
+	args := o.Called(namespace, name, opts)
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
 func (o *K8SClientMock) GetPodPortForwarder(namespace, name, portMap string) (*httputil.PortForwarder, error) {
 	args := o.Called(namespace, name, portMap)
 	return args.Get(0).(*httputil.PortForwarder), args.Error(1)
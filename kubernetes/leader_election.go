@@ -0,0 +1,75 @@
+package kubernetes
+
+import (
+	"context"
+	"os"
+	"time"
+
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kube "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// NewLeaseLock returns a Lease lock held with the Kiali service account, the replicas compete for it with their
+// host name
+func NewLeaseLock(namespace, name string) (resourcelock.Interface, error) {
+	clientConfig, err := ConfigClient()
+	if err != nil {
+		return nil, err
+	}
+	token := ""
+	if config.Get().InCluster {
+		if token, err = GetKialiToken(); err != nil {
+			return nil, err
+		}
+	}
+	clientset, err := kube.NewForConfig(&rest.Config{
+		Host:            clientConfig.Host,
+		TLSClientConfig: clientConfig.TLSClientConfig,
+		QPS:             clientConfig.QPS,
+		Burst:           clientConfig.Burst,
+		BearerToken:     token,
+	})
+	if err != nil {
+		return nil, err
+	}
+	identity, err := os.Hostname()
+	if err != nil {
+		return nil, err
+	}
+	return &resourcelock.LeaseLock{
+		LeaseMeta:  meta_v1.ObjectMeta{Name: name, Namespace: namespace},
+		Client:     clientset.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}, nil
+}
+
+// RunLeader runs lead while the replica holds the Lease, and competes for the Lease again when it is lost, until ctx
+// is done. The context of lead is done when the Lease is lost, lead must return then. The Lease is released when ctx
+// is done. The name of the leading task is logged.
+func RunLeader(ctx context.Context, name string, lock resourcelock.Interface, lead func(leading context.Context)) {
+	for ctx.Err() == nil {
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			Name:            name,
+			LeaseDuration:   30 * time.Second,
+			RenewDeadline:   20 * time.Second,
+			RetryPeriod:     5 * time.Second,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(leading context.Context) {
+					log.Infof("%s is leading, Lease [%s] acquired", name, lock.Describe())
+					lead(leading)
+				},
+				OnStoppedLeading: func() {
+					log.Infof("%s is not leading, Lease [%s] released", name, lock.Describe())
+				},
+			},
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package kubernetes
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+func TestRunLeader(t *testing.T) {
+	assert := assert.New(t)
+
+	clientset := fake.NewSimpleClientset()
+	newLock := func(identity string) resourcelock.Interface {
+		return &resourcelock.LeaseLock{
+			LeaseMeta:  meta_v1.ObjectMeta{Name: "kiali-test", Namespace: "istio-system"},
+			Client:     clientset.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leading := int32(0)
+	started := make(chan struct{}, 2)
+	lead := func(leadingCtx context.Context) {
+		atomic.AddInt32(&leading, 1)
+		started <- struct{}{}
+		<-leadingCtx.Done()
+		atomic.AddInt32(&leading, -1)
+	}
+	go RunLeader(ctx, "Test", newLock("kiali-1"), lead)
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the first replica did not lead")
+	}
+
+	// the Lease is held: the second replica doesn't lead
+	go RunLeader(ctx, "Test", newLock("kiali-2"), lead)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(int32(1), atomic.LoadInt32(&leading))
+
+	lease, err := clientset.CoordinationV1().Leases("istio-system").Get(context.Background(), "kiali-test", meta_v1.GetOptions{})
+	assert.NoError(err)
+	assert.Equal("kiali-1", *lease.Spec.HolderIdentity)
+}
@@ -0,0 +1,216 @@
+package kubernetes
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
+	batch_v1beta1 "k8s.io/api/batch/v1beta1"
+	core_v1 "k8s.io/api/core/v1"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Manifests groups the objects decoded from yaml or json manifests, e.g. the files of a GitOps repository,
+// used to validate Istio config offline. Kinds not listed here are ignored, they are only counted in Ignored.
+type Manifests struct {
+	IstioObjects           []IstioObject
+	ConfigMaps             []core_v1.ConfigMap
+	CronJobs               []batch_v1beta1.CronJob
+	DaemonSets             []apps_v1.DaemonSet
+	Deployments            []apps_v1.Deployment
+	Jobs                   []batch_v1.Job
+	Namespaces             []core_v1.Namespace
+	Pods                   []core_v1.Pod
+	ReplicaSets            []apps_v1.ReplicaSet
+	ReplicationControllers []core_v1.ReplicationController
+	Services               []core_v1.Service
+	StatefulSets           []apps_v1.StatefulSet
+	Ignored                int
+}
+
+// istioKinds are the Istio config kinds, taken from the supported networking and security types
+var istioKinds = func() map[string]bool {
+	kinds := map[string]bool{}
+	for _, t := range networkingTypes {
+		kinds[t.objectKind] = true
+	}
+	for _, t := range securityTypes {
+		kinds[t.objectKind] = true
+	}
+	return kinds
+}()
+
+// Decode appends the objects of a stream of yaml documents, or json objects, to the manifests. Objects without
+// namespace are set in the defaultNamespace. Kubernetes lists (kind: List) and json arrays are expanded.
+func (m *Manifests) Decode(r io.Reader, defaultNamespace string) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(r, 4096)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := m.add(raw, defaultNamespace); err != nil {
+			return err
+		}
+	}
+}
+
+// Merge appends the objects of other manifests
+func (m *Manifests) Merge(other Manifests) {
+	m.IstioObjects = append(m.IstioObjects, other.IstioObjects...)
+	m.ConfigMaps = append(m.ConfigMaps, other.ConfigMaps...)
+	m.CronJobs = append(m.CronJobs, other.CronJobs...)
+	m.DaemonSets = append(m.DaemonSets, other.DaemonSets...)
+	m.Deployments = append(m.Deployments, other.Deployments...)
+	m.Jobs = append(m.Jobs, other.Jobs...)
+	m.Namespaces = append(m.Namespaces, other.Namespaces...)
+	m.Pods = append(m.Pods, other.Pods...)
+	m.ReplicaSets = append(m.ReplicaSets, other.ReplicaSets...)
+	m.ReplicationControllers = append(m.ReplicationControllers, other.ReplicationControllers...)
+	m.Services = append(m.Services, other.Services...)
+	m.StatefulSets = append(m.StatefulSets, other.StatefulSets...)
+	m.Ignored += other.Ignored
+}
+
+// Count returns the number of decoded objects, ignored objects included
+func (m *Manifests) Count() int {
+	return len(m.IstioObjects) + len(m.ConfigMaps) + len(m.CronJobs) + len(m.DaemonSets) + len(m.Deployments) +
+		len(m.Jobs) + len(m.Namespaces) + len(m.Pods) + len(m.ReplicaSets) + len(m.ReplicationControllers) +
+		len(m.Services) + len(m.StatefulSets) + m.Ignored
+}
+
+func (m *Manifests) add(raw json.RawMessage, defaultNamespace string) error {
+	// empty yaml documents
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+
+	// json arrays of objects
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		items := []json.RawMessage{}
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := m.add(item, defaultNamespace); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	typeMeta := meta_v1.TypeMeta{}
+	if err := json.Unmarshal(raw, &typeMeta); err != nil {
+		return err
+	}
+
+	if istioKinds[typeMeta.Kind] && strings.Contains(typeMeta.APIVersion, "istio.io/") {
+		object := &GenericIstioObject{}
+		if err := unmarshalManifest(raw, object, &object.ObjectMeta, defaultNamespace); err != nil {
+			return err
+		}
+		m.IstioObjects = append(m.IstioObjects, object)
+		return nil
+	}
+
+	var err error
+	switch typeMeta.Kind {
+	case "List":
+		list := struct {
+			Items []json.RawMessage `json:"items"`
+		}{}
+		if err = json.Unmarshal(raw, &list); err == nil {
+			for _, item := range list.Items {
+				if err = m.add(item, defaultNamespace); err != nil {
+					break
+				}
+			}
+		}
+	case "Namespace":
+		object := core_v1.Namespace{}
+		if err = json.Unmarshal(raw, &object); err == nil {
+			m.Namespaces = append(m.Namespaces, object)
+		}
+	case ConfigMapType:
+		object := core_v1.ConfigMap{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.ConfigMaps = append(m.ConfigMaps, object)
+		}
+	case CronJobType:
+		object := batch_v1beta1.CronJob{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.CronJobs = append(m.CronJobs, object)
+		}
+	case DaemonSetType:
+		object := apps_v1.DaemonSet{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.DaemonSets = append(m.DaemonSets, object)
+		}
+	case DeploymentType:
+		object := apps_v1.Deployment{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.Deployments = append(m.Deployments, object)
+		}
+	case JobType:
+		object := batch_v1.Job{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.Jobs = append(m.Jobs, object)
+		}
+	case PodType:
+		object := core_v1.Pod{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.Pods = append(m.Pods, object)
+		}
+	case ReplicaSetType:
+		object := apps_v1.ReplicaSet{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.ReplicaSets = append(m.ReplicaSets, object)
+		}
+	case ReplicationControllerType:
+		object := core_v1.ReplicationController{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.ReplicationControllers = append(m.ReplicationControllers, object)
+		}
+	case ServiceType:
+		object := core_v1.Service{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.Services = append(m.Services, object)
+		}
+	case StatefulSetType:
+		object := apps_v1.StatefulSet{}
+		if err = unmarshalManifest(raw, &object, &object.ObjectMeta, defaultNamespace); err == nil {
+			m.StatefulSets = append(m.StatefulSets, object)
+		}
+	default:
+		m.Ignored++
+	}
+	return err
+}
+
+func unmarshalManifest(raw json.RawMessage, object interface{}, meta *meta_v1.ObjectMeta, defaultNamespace string) error {
+	if err := json.Unmarshal(raw, object); err != nil {
+		return err
+	}
+	if meta.Name == "" {
+		return fmt.Errorf("manifest without metadata.name: %s", abbreviate(string(raw), 80))
+	}
+	if meta.Namespace == "" {
+		meta.Namespace = defaultNamespace
+	}
+	return nil
+}
+
+func abbreviate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+	return s[:length] + "..."
+}
@@ -0,0 +1,118 @@
+package kubernetes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testManifests = `
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: bookinfo
+---
+# empty documents are ignored
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: reviews
+spec:
+  ports:
+  - name: http
+    port: 9080
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reviews-v1
+  namespace: bookinfo
+spec:
+  template:
+    metadata:
+      labels:
+        app: reviews
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: security.istio.io/v1beta1
+  kind: PeerAuthentication
+  metadata:
+    name: default
+    namespace: istio-system
+  spec:
+    mtls:
+      mode: STRICT
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: istio
+    namespace: istio-system
+---
+apiVersion: example.com/v1
+kind: VirtualService
+metadata:
+  name: not-istio
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: ignored
+`
+
+func TestDecodeManifests(t *testing.T) {
+	assert := assert.New(t)
+
+	manifests := Manifests{}
+	assert.NoError(manifests.Decode(strings.NewReader(testManifests), "default"))
+
+	assert.Len(manifests.Namespaces, 1)
+	assert.Len(manifests.Services, 1)
+	assert.Equal("default", manifests.Services[0].Namespace)
+	assert.Len(manifests.Deployments, 1)
+	assert.Equal("reviews", manifests.Deployments[0].Spec.Template.Labels["app"])
+	assert.Len(manifests.ConfigMaps, 1)
+
+	assert.Len(manifests.IstioObjects, 2)
+	vs := manifests.IstioObjects[0]
+	assert.Equal(VirtualServiceType, vs.GetTypeMeta().Kind)
+	assert.Equal("bookinfo", vs.GetObjectMeta().Namespace)
+	assert.Equal([]interface{}{"reviews"}, vs.GetSpec()["hosts"])
+	assert.Equal(PeerAuthenticationsType, manifests.IstioObjects[1].GetTypeMeta().Kind)
+	assert.Equal(2, manifests.Ignored)
+	assert.Equal(8, manifests.Count())
+
+	json := Manifests{}
+	assert.NoError(json.Decode(strings.NewReader(`{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "ratings"}}`), "bookinfo"))
+	assert.Len(json.Services, 1)
+
+	manifests.Merge(json)
+	assert.Len(manifests.Services, 2)
+
+	array := Manifests{}
+	assert.NoError(array.Decode(strings.NewReader(`[
+		{"apiVersion": "networking.istio.io/v1alpha3", "kind": "Gateway", "metadata": {"name": "bookinfo-gateway"}},
+		{"apiVersion": "networking.istio.io/v1alpha3", "kind": "VirtualService", "metadata": {"name": "bookinfo"}}
+	]`), "bookinfo"))
+	assert.Len(array.IstioObjects, 2)
+	assert.Equal(GatewayType, array.IstioObjects[0].GetTypeMeta().Kind)
+	assert.Equal("bookinfo", array.IstioObjects[1].GetObjectMeta().Namespace)
+}
+
+func TestDecodeInvalidManifests(t *testing.T) {
+	manifests := Manifests{}
+	assert.Error(t, manifests.Decode(strings.NewReader("kind: Service\nmetadata: {}\n"), "default"))
+	assert.Error(t, manifests.Decode(strings.NewReader("kind: [Service\n"), "default"))
+}
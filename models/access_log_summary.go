@@ -0,0 +1,69 @@
+package models
+
+import "time"
+
+// AccessLogSummary aggregates the proxy access logs of the pods of a workload over a time window
+// swagger:model AccessLogSummary
+type AccessLogSummary struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Workload string `json:"workload"`
+
+	// The pods whose proxy logs are aggregated
+	// required: true
+	Pods []string `json:"pods"`
+
+	// required: true
+	StartTime time.Time `json:"startTime"`
+
+	// required: true
+	EndTime time.Time `json:"endTime"`
+
+	// The number of parsed access log entries
+	// required: true
+	Requests int `json:"requests"`
+
+	// The most requested paths, most requested first
+	// required: true
+	TopPaths []AccessLogCount `json:"topPaths"`
+
+	// The requests by response code, most frequent first
+	// required: true
+	StatusCodes []AccessLogCount `json:"statusCodes"`
+
+	// The requests by Envoy response flag (UH, UF, NR...), most frequent first. A request may have several flags.
+	// required: true
+	ResponseFlags []AccessLogCount `json:"responseFlags"`
+
+	// The requests by upstream host, most frequent first
+	// required: true
+	UpstreamHosts []AccessLogCount `json:"upstreamHosts"`
+
+	// The slowest requests, slowest first
+	// required: true
+	SlowestRequests []AccessLogRequest `json:"slowestRequests"`
+}
+
+// AccessLogCount is the number of requests having a value
+type AccessLogCount struct {
+	// required: true
+	Value string `json:"value"`
+	// required: true
+	Count int `json:"count"`
+}
+
+// AccessLogRequest is a single request of the access logs
+type AccessLogRequest struct {
+	Pod           string    `json:"pod"`
+	Timestamp     time.Time `json:"timestamp"`
+	Method        string    `json:"method,omitempty"`
+	Path          string    `json:"path,omitempty"`
+	StatusCode    string    `json:"statusCode"`
+	ResponseFlags string    `json:"responseFlags,omitempty"`
+	UpstreamHost  string    `json:"upstreamHost,omitempty"`
+	RequestID     string    `json:"requestId,omitempty"`
+	// The request duration, in milliseconds
+	Duration int64 `json:"duration"`
+}
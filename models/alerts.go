@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// AlertKey identifies the app or service whose health is watched
+type AlertKey struct {
+	// The namespace of the app or service
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The kind: app | service
+	// required: true
+	Kind string `json:"kind"`
+
+	// The name of the app or service
+	// required: true
+	Name string `json:"name"`
+}
+
+// Alert is a degraded, or failing, app or service health
+// swagger:model Alert
+type Alert struct {
+	AlertKey
+	HealthStatus
+
+	// The time of the last health status transition
+	// required: true
+	Since time.Time `json:"since"`
+}
+
+// AlertEvent is a health status transition of an app or service
+// swagger:model AlertEvent
+type AlertEvent struct {
+	AlertKey
+
+	// The previous health status
+	// required: true
+	From string `json:"from"`
+
+	// The new health status
+	// required: true
+	To string `json:"to"`
+
+	// The cause of the new health status, empty when healthy
+	Reason string `json:"reason,omitempty"`
+
+	// The time of the transition
+	// required: true
+	Time time.Time `json:"time"`
+}
+
+// Alerts holds the current alerts, the most severe first
+// swagger:model Alerts
+type Alerts struct {
+	// The time of the last health evaluation, zero before the first evaluation
+	// required: true
+	LastEvaluation time.Time `json:"lastEvaluation"`
+
+	// required: true
+	Alerts []Alert `json:"alerts"`
+}
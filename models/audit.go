@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited operations
+const (
+	AuditCreate   = "create"
+	AuditDelete   = "delete"
+	AuditRestore  = "restore"
+	AuditRollback = "rollback"
+	AuditUpdate   = "update"
+)
+
+// AuditRecord is a write operation performed through the Kiali API
+// swagger:model AuditRecord
+type AuditRecord struct {
+	// The record identifier, records with a greater ID are more recent
+	// required: true
+	ID string `json:"id"`
+
+	// The time of the operation
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// The user performing the operation
+	// required: true
+	User string `json:"user"`
+
+	// The operation: create | update | delete | restore | rollback
+	// required: true
+	Operation string `json:"operation"`
+
+	// The namespace of the object, the namespace itself for namespace objects
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The object type, e.g. virtualservices, workloads, services, namespaces or experiments
+	// required: true
+	ObjectType string `json:"objectType"`
+
+	// The object name
+	// required: true
+	Name string `json:"name"`
+
+	// The JSON patch of an update, the body of a create, or the restored revision
+	Patch string `json:"patch,omitempty"`
+
+	// The object before the operation, empty for a create
+	Before json.RawMessage `json:"before,omitempty"`
+
+	// The object after the operation, empty for a delete
+	After json.RawMessage `json:"after,omitempty"`
+}
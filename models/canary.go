@@ -0,0 +1,115 @@
+package models
+
+import "time"
+
+// Canary rollout phases
+const (
+	CanaryAborted     = "Aborted"
+	CanaryProgressing = "Progressing"
+	CanaryRolledBack  = "RolledBack"
+	CanarySucceeded   = "Succeeded"
+)
+
+// CanaryRollout shifts the traffic of a service step by step from a baseline version to a canary version. Each
+// step is held for the interval, the rollout advances to the next step while the canary meets the success
+// criteria over the interval and is rolled back to the baseline otherwise. After the last step the canary is
+// promoted, it gets all the traffic.
+// swagger:model CanaryRollout
+type CanaryRollout struct {
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// required: true
+	Service string `json:"service"`
+
+	// required: true
+	Spec CanarySpec `json:"spec"`
+
+	// required: true
+	Status CanaryStatus `json:"status"`
+
+	// The user starting the rollout. The canary controller advances it with the Kiali service account.
+	StartedBy string `json:"startedBy,omitempty"`
+}
+
+// CanarySpec defines a canary rollout
+// swagger:model CanarySpec
+type CanarySpec struct {
+	// The version receiving the traffic not routed to the canary
+	// required: true
+	// example: v1
+	Baseline string `json:"baseline"`
+
+	// The version rolled out
+	// required: true
+	// example: v2
+	Canary string `json:"canary"`
+
+	// The canary weights of the steps, increasing and between 1 and 99
+	// required: true
+	// example: [10, 25, 50]
+	Steps []int `json:"steps"`
+
+	// The time each step is held, it's also the interval of the metrics checked, e.g. "5m"
+	// required: true
+	// example: 5m
+	Interval string `json:"interval"`
+
+	// The maximum percentage of failed canary requests
+	// required: true
+	// example: 1
+	MaxErrorRate float64 `json:"maxErrorRate"`
+
+	// The maximum 95th percentile of the canary request duration, in milliseconds. 0 means no latency criteria.
+	// example: 500
+	MaxLatencyP95 float64 `json:"maxLatencyP95,omitempty"`
+
+	// The maximum time a step is held while the canary gets no request, the rollout is rolled back after it, e.g.
+	// "1h". Default: 10 intervals.
+	// example: 1h
+	MaxHold string `json:"maxHold,omitempty"`
+}
+
+// CanaryStatus is the progress of a canary rollout
+// swagger:model CanaryStatus
+type CanaryStatus struct {
+	// Progressing | Succeeded | RolledBack | Aborted
+	// required: true
+	Phase string `json:"phase"`
+
+	// The index of the current step, the number of steps once promoted
+	// required: true
+	Step int `json:"step"`
+
+	// The current canary weight
+	// required: true
+	Weight int `json:"weight"`
+
+	// The start of the current step
+	// required: true
+	StepStarted time.Time `json:"stepStarted"`
+
+	// The start of the hold of the current step, while the canary gets no request
+	HeldSince *time.Time `json:"heldSince,omitempty"`
+
+	// The last check of the success criteria
+	LastCheck *CanaryCheck `json:"lastCheck,omitempty"`
+
+	// The reason of the last change
+	Message string `json:"message,omitempty"`
+}
+
+// CanaryCheck is a check of the canary success criteria over the interval of a step
+type CanaryCheck struct {
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// The percentage of failed canary requests, empty when the canary had no request
+	ErrorRate *float64 `json:"errorRate,omitempty"`
+
+	// The 95th percentile of the canary request duration, in milliseconds, when checked
+	LatencyP95 *float64 `json:"latencyP95,omitempty"`
+
+	// required: true
+	Passed bool `json:"passed"`
+}
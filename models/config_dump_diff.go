@@ -0,0 +1,250 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+// ConfigDumpSnapshot is an Envoy config dump of a pod, stored to be compared later
+type ConfigDumpSnapshot struct {
+	ConfigDumpSnapshotInfo
+	ConfigDump *kubernetes.ConfigDump `json:"config_dump"`
+}
+
+// ConfigDumpSnapshotInfo describes a stored config dump
+// swagger:model ConfigDumpSnapshotInfo
+type ConfigDumpSnapshotInfo struct {
+	// required: true
+	ID string `json:"id"`
+	// required: true
+	Namespace string `json:"namespace"`
+	// required: true
+	Pod string `json:"pod"`
+	// required: true
+	Created time.Time `json:"created"`
+}
+
+// ConfigDumpRef identifies a compared config dump: a pod's current dump, or a stored snapshot
+type ConfigDumpRef struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	// The stored snapshot ID, empty for the current dump
+	Snapshot string `json:"snapshot,omitempty"`
+	// The time of the dump
+	Created time.Time `json:"created"`
+}
+
+// EnvoyProxyDumpDiff holds the changes of the parsed Envoy config from a dump to another
+// swagger:model EnvoyProxyDumpDiff
+type EnvoyProxyDumpDiff struct {
+	From      ConfigDumpRef `json:"from"`
+	To        ConfigDumpRef `json:"to"`
+	Bootstrap ResourceDiff  `json:"bootstrap"`
+	Clusters  ResourceDiff  `json:"clusters"`
+	Listeners ResourceDiff  `json:"listeners"`
+	Routes    ResourceDiff  `json:"routes"`
+}
+
+// ResourceDiff holds the added, removed and changed entries of a resource, sorted by key
+type ResourceDiff struct {
+	Added   []DiffEntry `json:"added"`
+	Removed []DiffEntry `json:"removed"`
+	Changed []DiffEntry `json:"changed"`
+}
+
+// DiffEntry is an added or removed entry, with its value, or a changed entry, with its field changes
+type DiffEntry struct {
+	Key    string        `json:"key"`
+	Value  interface{}   `json:"value,omitempty"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+// FieldChange is the change of a field, by its path in the entry (e.g. "metadata.ISTIO_VERSION")
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// DiffConfigDumps parses both dumps and returns the changes from the first to the second. The routes domains are
+// resolved against the namespaces.
+func DiffConfigDumps(from, to *kubernetes.ConfigDump, namespaces []string) (*EnvoyProxyDumpDiff, error) {
+	var fromSummary, toSummary EnvoyProxyDump
+	for _, s := range []struct {
+		dump    *kubernetes.ConfigDump
+		summary *EnvoyProxyDump
+	}{{from, &fromSummary}, {to, &toSummary}} {
+		s.summary.Bootstrap, s.summary.Clusters, s.summary.Listeners, s.summary.Routes = &Bootstrap{}, &Clusters{}, &Listeners{}, &Routes{}
+		if err := s.summary.Bootstrap.Parse(s.dump); err != nil {
+			return nil, err
+		}
+		if err := s.summary.Clusters.Parse(s.dump); err != nil {
+			return nil, err
+		}
+		if err := s.summary.Listeners.Parse(s.dump); err != nil {
+			return nil, err
+		}
+		if err := s.summary.Routes.Parse(s.dump, namespaces); err != nil {
+			return nil, err
+		}
+	}
+
+	return &EnvoyProxyDumpDiff{
+		Bootstrap: diffEntries(fromSummary.Bootstrap.entries(), toSummary.Bootstrap.entries()),
+		Clusters:  diffEntries(fromSummary.Clusters.entries(), toSummary.Clusters.entries()),
+		Listeners: diffEntries(fromSummary.Listeners.entries(), toSummary.Listeners.entries()),
+		Routes:    diffEntries(fromSummary.Routes.entries(), toSummary.Routes.entries()),
+	}, nil
+}
+
+// keyedEntries holds the entries of a resource by key
+type keyedEntries map[string]interface{}
+
+// add keeps entries sharing a key apart, suffixing the key with the occurrence number
+func (ke keyedEntries) add(key string, value interface{}) {
+	unique := key
+	for i := 2; ; i++ {
+		if _, found := ke[unique]; !found {
+			break
+		}
+		unique = fmt.Sprintf("%s #%d", key, i)
+	}
+	ke[unique] = value
+}
+
+// entries are the top level bootstrap sections (node, static_resources...)
+func (bd *Bootstrap) entries() keyedEntries {
+	ke := keyedEntries{}
+	sections, ok := bd.Bootstrap["bootstrap"].(map[string]interface{})
+	if !ok {
+		return ke
+	}
+	for key, value := range sections {
+		ke.add(key, value)
+	}
+	return ke
+}
+
+// entries are keyed by Envoy cluster name
+func (css *Clusters) entries() keyedEntries {
+	ke := keyedEntries{}
+	for _, cs := range *css {
+		key := cs.ServiceFQDN.String()
+		if cs.Direction != "" {
+			key = fmt.Sprintf("%s|%d|%s|%s", cs.Direction, cs.Port, cs.Subset, key)
+		}
+		ke.add(key, cs)
+	}
+	return ke
+}
+
+// entries are keyed by address, port and filter chain match
+func (ls *Listeners) entries() keyedEntries {
+	ke := keyedEntries{}
+	for _, l := range *ls {
+		ke.add(fmt.Sprintf("%s:%v %s", l.Address, l.Port, l.Match), l)
+	}
+	return ke
+}
+
+// entries are keyed by route config name, domains and match
+func (rs *Routes) entries() keyedEntries {
+	ke := keyedEntries{}
+	for _, r := range *rs {
+		ke.add(fmt.Sprintf("%s %s %s", r.Name, r.Domains.String(), r.Match), r)
+	}
+	return ke
+}
+
+func diffEntries(from, to keyedEntries) ResourceDiff {
+	diff := ResourceDiff{Added: []DiffEntry{}, Removed: []DiffEntry{}, Changed: []DiffEntry{}}
+	for key, fromValue := range from {
+		toValue, found := to[key]
+		if !found {
+			diff.Removed = append(diff.Removed, DiffEntry{Key: key, Value: fromValue})
+			continue
+		}
+		if fields := diffFields(fromValue, toValue); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, DiffEntry{Key: key, Fields: fields})
+		}
+	}
+	for key, toValue := range to {
+		if _, found := from[key]; !found {
+			diff.Added = append(diff.Added, DiffEntry{Key: key, Value: toValue})
+		}
+	}
+
+	for _, entries := range [][]DiffEntry{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Key < entries[j].Key
+		})
+	}
+	return diff
+}
+
+// diffFields returns the changed leaf fields of two entries, compared by their JSON representation
+func diffFields(from, to interface{}) []FieldChange {
+	fromFields, toFields := map[string]interface{}{}, map[string]interface{}{}
+	flatten("", toJSONValue(from), fromFields)
+	flatten("", toJSONValue(to), toFields)
+
+	changes := []FieldChange{}
+	for field, fromValue := range fromFields {
+		toValue, found := toFields[field]
+		if !found || !reflect.DeepEqual(fromValue, toValue) {
+			changes = append(changes, FieldChange{Field: field, From: fromValue, To: toValue})
+		}
+	}
+	for field, toValue := range toFields {
+		if _, found := fromFields[field]; !found {
+			changes = append(changes, FieldChange{Field: field, To: toValue})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Field < changes[j].Field
+	})
+	return changes
+}
+
+func toJSONValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	var result interface{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return string(data)
+	}
+	return result
+}
+
+// flatten collects the leaf values by path: object fields are dot separated, array items indexed
+func flatten(path string, value interface{}, fields map[string]interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			fields[path] = v
+		}
+		for key, item := range v {
+			if path == "" {
+				flatten(key, item, fields)
+			} else {
+				flatten(path+"."+key, item, fields)
+			}
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = v
+		}
+		for i, item := range v {
+			flatten(fmt.Sprintf("%s[%d]", path, i), item, fields)
+		}
+	default:
+		fields[path] = v
+	}
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kiali/kiali/kubernetes"
+)
+
+func configDump(t *testing.T, istioVersion, reviewsDR, reviewsVS string, ratings bool) *kubernetes.ConfigDump {
+	clusters := []interface{}{
+		map[string]interface{}{"cluster": map[string]interface{}{
+			"name": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local",
+			"type": "EDS",
+			"metadata": map[string]interface{}{"filter_metadata": map[string]interface{}{"istio": map[string]interface{}{
+				"config": "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/destination-rule/" + reviewsDR,
+			}}},
+		}},
+	}
+	if ratings {
+		clusters = append(clusters, map[string]interface{}{"cluster": map[string]interface{}{
+			"name": "outbound|9080||ratings.bookinfo.svc.cluster.local",
+			"type": "EDS",
+		}})
+	}
+
+	dump := map[string]interface{}{
+		"configs": []interface{}{
+			map[string]interface{}{
+				"@type": "type.googleapis.com/envoy.admin.v3.BootstrapConfigDump",
+				"bootstrap": map[string]interface{}{
+					"node": map[string]interface{}{"id": "sidecar~10.0.0.1", "metadata": map[string]interface{}{"ISTIO_VERSION": istioVersion}},
+				},
+			},
+			map[string]interface{}{
+				"@type":                   "type.googleapis.com/envoy.admin.v3.ClustersConfigDump",
+				"dynamic_active_clusters": clusters,
+			},
+			map[string]interface{}{
+				"@type": "type.googleapis.com/envoy.admin.v3.RoutesConfigDump",
+				"dynamic_route_configs": []interface{}{
+					map[string]interface{}{"route_config": map[string]interface{}{
+						"name": "9080",
+						"virtual_hosts": []interface{}{map[string]interface{}{
+							"domains": []interface{}{"reviews.bookinfo.svc.cluster.local"},
+							"routes": []interface{}{map[string]interface{}{
+								"match": map[string]interface{}{"prefix": "/"},
+								"route": map[string]interface{}{"cluster": "outbound|9080|v1|reviews.bookinfo.svc.cluster.local"},
+								"metadata": map[string]interface{}{"filter_metadata": map[string]interface{}{"istio": map[string]interface{}{
+									"config": "/apis/networking.istio.io/v1alpha3/namespaces/bookinfo/virtual-service/" + reviewsVS,
+								}}},
+							}},
+						}},
+					}},
+				},
+			},
+		},
+	}
+
+	// round trip, as read from the proxy
+	data, err := json.Marshal(dump)
+	require.NoError(t, err)
+	cd := &kubernetes.ConfigDump{}
+	require.NoError(t, json.Unmarshal(data, cd))
+	return cd
+}
+
+func TestDiffConfigDumps(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	from := configDump(t, "1.9.0", "reviews", "reviews", false)
+	to := configDump(t, "1.9.1", "reviews-canary", "reviews", true)
+
+	diff, err := DiffConfigDumps(from, to, []string{"bookinfo"})
+	require.NoError(err)
+
+	assert.Empty(diff.Listeners.Added)
+	assert.Empty(diff.Listeners.Removed)
+	assert.Empty(diff.Listeners.Changed)
+	assert.Empty(diff.Routes.Changed)
+
+	assert.Empty(diff.Bootstrap.Added)
+	require.Len(diff.Bootstrap.Changed, 1)
+	assert.Equal(DiffEntry{Key: "node", Fields: []FieldChange{{Field: "metadata.ISTIO_VERSION", From: "1.9.0", To: "1.9.1"}}}, diff.Bootstrap.Changed[0])
+
+	require.Len(diff.Clusters.Added, 1)
+	assert.Equal("outbound|9080||ratings.bookinfo.svc.cluster.local", diff.Clusters.Added[0].Key)
+	assert.Empty(diff.Clusters.Removed)
+	require.Len(diff.Clusters.Changed, 1)
+	assert.Equal("outbound|9080|v1|reviews.bookinfo.svc.cluster.local", diff.Clusters.Changed[0].Key)
+	assert.Equal([]FieldChange{{Field: "destination_rule", From: "reviews.bookinfo", To: "reviews-canary.bookinfo"}}, diff.Clusters.Changed[0].Fields)
+
+	// the same dump has no changes
+	diff, err = DiffConfigDumps(to, to, []string{"bookinfo"})
+	require.NoError(err)
+	assert.Empty(diff.Bootstrap.Changed)
+	assert.Empty(diff.Clusters.Added)
+	assert.Empty(diff.Clusters.Changed)
+	assert.Empty(diff.Routes.Changed)
+}
@@ -0,0 +1,217 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/log"
+)
+
+// Health statuses, in increasing severity
+const (
+	HealthStatusHealthy  = "healthy"
+	HealthStatusDegraded = "degraded"
+	HealthStatusFailure  = "failure"
+)
+
+// Health kinds, as matched by the health config rates
+const (
+	HealthKindApp      = "app"
+	HealthKindService  = "service"
+	HealthKindWorkload = "workload"
+)
+
+// HealthStatus is the health status of an app, service or workload, evaluated against the health config
+// tolerances. Reason explains the most severe cause, it is empty when healthy.
+type HealthStatus struct {
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// HealthSeverity orders the health statuses, the higher the more severe
+func HealthSeverity(status string) int {
+	switch status {
+	case HealthStatusFailure:
+		return 2
+	case HealthStatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (in HealthStatus) worst(other HealthStatus) HealthStatus {
+	if HealthSeverity(other.Status) > HealthSeverity(in.Status) {
+		return other
+	}
+	return in
+}
+
+// Status evaluates the app health: its workloads statuses and its request error rates
+func (in AppHealth) Status(namespace, app string) HealthStatus {
+	status := in.Requests.Status(namespace, HealthKindApp, app)
+	for _, ws := range in.WorkloadStatuses {
+		status = status.worst(ws.Status())
+	}
+	return status
+}
+
+// Status evaluates the service health: its request error rates
+func (in ServiceHealth) Status(namespace, service string) HealthStatus {
+	return in.Requests.Status(namespace, HealthKindService, service)
+}
+
+// Status evaluates the workload health: its replicas and its request error rates
+func (in WorkloadHealth) Status(namespace, workload string) HealthStatus {
+	status := in.Requests.Status(namespace, HealthKindWorkload, workload)
+	if in.WorkloadStatus != nil {
+		status = status.worst(in.WorkloadStatus.Status())
+	}
+	return status
+}
+
+// Status evaluates the workload replicas: a workload without available replicas fails, a workload with
+// less available replicas, or synced proxies, than desired is degraded. A workload scaled to zero is healthy.
+func (in WorkloadStatus) Status() HealthStatus {
+	switch {
+	case in.DesiredReplicas == 0:
+		return HealthStatus{Status: HealthStatusHealthy}
+	case in.AvailableReplicas == 0:
+		return HealthStatus{Status: HealthStatusFailure, Reason: fmt.Sprintf("workload %s has no available replicas, %d desired", in.Name, in.DesiredReplicas)}
+	case in.AvailableReplicas < in.DesiredReplicas:
+		return HealthStatus{Status: HealthStatusDegraded, Reason: fmt.Sprintf("workload %s has %d available replicas, %d desired", in.Name, in.AvailableReplicas, in.DesiredReplicas)}
+	case in.SyncedProxies >= 0 && in.SyncedProxies < in.AvailableReplicas:
+		return HealthStatus{Status: HealthStatusDegraded, Reason: fmt.Sprintf("workload %s has %d synced proxies, %d available replicas", in.Name, in.SyncedProxies, in.AvailableReplicas)}
+	}
+	return HealthStatus{Status: HealthStatusHealthy}
+}
+
+// Status evaluates the request error rates against the tolerances of the health.kiali.io/rate annotation, if
+// set, otherwise of the first health config rate matching the namespace, kind and name. A tolerance applies
+// when its protocol and direction match, and the percentage of requests with a code matching the tolerance
+// code (where 'X' matches any digit) exceeds its degraded threshold, or reaches its failure threshold.
+func (in RequestHealth) Status(namespace, kind, name string) HealthStatus {
+	tolerances := in.tolerances(namespace, kind, name)
+	status := HealthStatus{Status: HealthStatusHealthy}
+	status = status.worst(evaluateRequests("inbound", in.Inbound, tolerances))
+	status = status.worst(evaluateRequests("outbound", in.Outbound, tolerances))
+	return status
+}
+
+func (in RequestHealth) tolerances(namespace, kind, name string) []config.Tolerance {
+	if annotation, ok := in.HealthAnnotations[string(RateHealthAnnotation)]; ok {
+		tolerances, err := ParseRateHealthAnnotation(annotation)
+		if err == nil {
+			return tolerances
+		}
+		log.Warningf("Ignoring invalid [%s] annotation of %s [%s/%s]: %v", RateHealthAnnotation, kind, namespace, name, err)
+	}
+	for _, rate := range config.Get().HealthConfig.Rate {
+		if healthMatches(rate.Namespace, namespace) && healthMatches(rate.Kind, kind) && healthMatches(rate.Name, name) {
+			return rate.Tolerance
+		}
+	}
+	return []config.Tolerance{}
+}
+
+// ParseRateHealthAnnotation parses the tolerances of a health.kiali.io/rate annotation, a semicolon separated
+// list of "<code>,<degraded>,<failure>,<protocol>,<direction>" tolerances
+func ParseRateHealthAnnotation(annotation string) ([]config.Tolerance, error) {
+	tolerances := []config.Tolerance{}
+	for _, value := range strings.Split(annotation, ";") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		fields := strings.Split(value, ",")
+		if len(fields) != 5 {
+			return nil, fmt.Errorf("tolerance [%s] does not have the format <code>,<degraded>,<failure>,<protocol>,<direction>", value)
+		}
+		degraded, err := strconv.ParseFloat(strings.TrimSpace(fields[1]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("tolerance [%s] has an invalid degraded threshold: %v", value, err)
+		}
+		failure, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 32)
+		if err != nil {
+			return nil, fmt.Errorf("tolerance [%s] has an invalid failure threshold: %v", value, err)
+		}
+		tolerances = append(tolerances, config.Tolerance{
+			Code:      strings.TrimSpace(fields[0]),
+			Degraded:  float32(degraded),
+			Failure:   float32(failure),
+			Protocol:  strings.TrimSpace(fields[3]),
+			Direction: strings.TrimSpace(fields[4]),
+		})
+	}
+	return tolerances, nil
+}
+
+// evaluateRequests returns the most severe status of the protocols request rates, by code, for the direction
+func evaluateRequests(direction string, requests map[string]map[string]float64, tolerances []config.Tolerance) HealthStatus {
+	status := HealthStatus{Status: HealthStatusHealthy}
+
+	protocols := make([]string, 0, len(requests))
+	for protocol := range requests {
+		protocols = append(protocols, protocol)
+	}
+	sort.Strings(protocols)
+
+	for _, protocol := range protocols {
+		total := 0.0
+		for _, rate := range requests[protocol] {
+			total += rate
+		}
+		if total == 0.0 {
+			continue
+		}
+
+		for _, tolerance := range tolerances {
+			if !healthMatches(tolerance.Protocol, protocol) || !healthMatches(tolerance.Direction, direction) {
+				continue
+			}
+			codeRegexp, err := regexp.Compile(strings.NewReplacer("X", `\d`, "x", `\d`).Replace(tolerance.Code))
+			if err != nil {
+				log.Warningf("Ignoring health tolerance with invalid code [%s]: %v", tolerance.Code, err)
+				continue
+			}
+			matched := 0.0
+			for code, rate := range requests[protocol] {
+				if codeRegexp.MatchString(code) {
+					matched += rate
+				}
+			}
+			if matched == 0.0 {
+				continue
+			}
+
+			errorRate := matched / total * 100.0
+			switch {
+			case errorRate >= float64(tolerance.Failure):
+				status = status.worst(HealthStatus{Status: HealthStatusFailure, Reason: requestsReason(direction, protocol, tolerance.Code, errorRate, tolerance.Failure)})
+			case errorRate > float64(tolerance.Degraded):
+				status = status.worst(HealthStatus{Status: HealthStatusDegraded, Reason: requestsReason(direction, protocol, tolerance.Code, errorRate, tolerance.Degraded)})
+			}
+		}
+	}
+	return status
+}
+
+func requestsReason(direction, protocol, code string, errorRate float64, threshold float32) string {
+	return fmt.Sprintf("%s %s requests with code %s at %.2f%%, threshold %.2f%%", direction, protocol, code, errorRate, threshold)
+}
+
+// healthMatches returns true if the value fully matches the pattern, an empty pattern matches any value
+func healthMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		log.Warningf("Ignoring invalid health config pattern [%s]: %v", pattern, err)
+		return false
+	}
+	return re.MatchString(value)
+}
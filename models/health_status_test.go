@@ -0,0 +1,91 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestRequestHealthStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	health := NewEmptyRequestHealth()
+	assert.Equal(HealthStatusHealthy, health.Status("bookinfo", HealthKindService, "reviews").Status)
+
+	// 4XX: degraded above 10%, failure from 20%
+	health.Inbound["http"] = map[string]float64{"200": 85, "404": 15}
+	status := health.Status("bookinfo", HealthKindService, "reviews")
+	assert.Equal(HealthStatusDegraded, status.Status)
+	assert.Equal("inbound http requests with code 4XX at 15.00%, threshold 10.00%", status.Reason)
+
+	// 5XX: failure from 10%
+	health.Outbound["http"] = map[string]float64{"200": 90, "503": 10}
+	status = health.Status("bookinfo", HealthKindService, "reviews")
+	assert.Equal(HealthStatusFailure, status.Status)
+	assert.Equal("outbound http requests with code 5XX at 10.00%, threshold 10.00%", status.Reason)
+
+	// the annotation replaces the health config tolerances
+	health.HealthAnnotations[string(RateHealthAnnotation)] = "4XX,20,30,http,inbound"
+	assert.Equal(HealthStatusHealthy, health.Status("bookinfo", HealthKindService, "reviews").Status)
+}
+
+func TestRequestHealthStatusConfigRate(t *testing.T) {
+	assert := assert.New(t)
+	conf := config.NewConfig()
+	conf.HealthConfig.Rate = []config.Rate{
+		{
+			Namespace: "bookinfo",
+			Kind:      "app",
+			Name:      "ratings|reviews",
+			Tolerance: []config.Tolerance{{Code: "5XX", Protocol: "http", Direction: "inbound", Degraded: 30, Failure: 50}},
+		},
+	}
+	config.Set(conf)
+
+	health := NewEmptyRequestHealth()
+	health.Inbound["http"] = map[string]float64{"200": 80, "500": 20}
+	assert.Equal(HealthStatusHealthy, health.Status("bookinfo", HealthKindApp, "reviews").Status)
+	assert.Equal(HealthStatusFailure, health.Status("bookinfo", HealthKindApp, "details").Status)
+	assert.Equal(HealthStatusFailure, health.Status("bookinfo", HealthKindService, "reviews").Status)
+}
+
+func TestParseRateHealthAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	tolerances, err := ParseRateHealthAnnotation("4XX,10,20,http,inbound;5XX, 0, 5, grpc|http, .*")
+	assert.NoError(err)
+	assert.Equal([]config.Tolerance{
+		{Code: "4XX", Degraded: 10, Failure: 20, Protocol: "http", Direction: "inbound"},
+		{Code: "5XX", Degraded: 0, Failure: 5, Protocol: "grpc|http", Direction: ".*"},
+	}, tolerances)
+
+	_, err = ParseRateHealthAnnotation("4XX,10,20,http")
+	assert.Error(err)
+	_, err = ParseRateHealthAnnotation("4XX,ten,20,http,inbound")
+	assert.Error(err)
+}
+
+func TestAppHealthStatus(t *testing.T) {
+	assert := assert.New(t)
+	config.Set(config.NewConfig())
+
+	health := EmptyAppHealth()
+	health.WorkloadStatuses = []*WorkloadStatus{
+		{Name: "reviews-v1", DesiredReplicas: 1, CurrentReplicas: 1, AvailableReplicas: 1, SyncedProxies: 1},
+		{Name: "reviews-v2", DesiredReplicas: 0, CurrentReplicas: 0, AvailableReplicas: 0, SyncedProxies: -1},
+	}
+	assert.Equal(HealthStatusHealthy, health.Status("bookinfo", "reviews").Status)
+
+	health.WorkloadStatuses = append(health.WorkloadStatuses, &WorkloadStatus{Name: "reviews-v3", DesiredReplicas: 2, CurrentReplicas: 2, AvailableReplicas: 1, SyncedProxies: 1})
+	status := health.Status("bookinfo", "reviews")
+	assert.Equal(HealthStatusDegraded, status.Status)
+	assert.Equal("workload reviews-v3 has 1 available replicas, 2 desired", status.Reason)
+
+	health.WorkloadStatuses[0].AvailableReplicas = 0
+	status = health.Status("bookinfo", "reviews")
+	assert.Equal(HealthStatusFailure, status.Status)
+	assert.Equal("workload reviews-v1 has no available replicas, 1 desired", status.Reason)
+}
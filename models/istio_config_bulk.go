@@ -0,0 +1,66 @@
+package models
+
+import "encoding/json"
+
+// Operations of a bulk apply on each object
+const (
+	BulkApplyCreate = "create"
+	BulkApplyUpdate = "update"
+)
+
+// Status of each object of a bulk apply
+const (
+	BulkApplyApplied        = "applied"
+	BulkApplyFailed         = "failed"
+	BulkApplyRolledBack     = "rolledBack"
+	BulkApplyRollbackFailed = "rollbackFailed"
+	BulkApplySkipped        = "skipped"
+)
+
+// IstioConfigBulkApply is the result of the apply of a set of Istio objects. The objects are validated together
+// and applied in dependency order, the applied objects are rolled back when an object fails.
+// swagger:model IstioConfigBulkApply
+type IstioConfigBulkApply struct {
+	// True when all the objects are applied. Objects introducing validation errors are only applied when forced.
+	// required: true
+	Applied bool `json:"applied"`
+
+	// The validation of the objects against the current Istio config
+	// required: true
+	Validations IstioValidationsBulkDryRun `json:"validations"`
+
+	// The objects, in the order they are applied
+	// required: true
+	Objects []IstioConfigBulkApplyObject `json:"objects"`
+
+	// The error of the failed object, when the apply is rolled back
+	Error string `json:"error,omitempty"`
+}
+
+// IstioConfigBulkApplyObject is the apply of an object of a bulk apply
+// swagger:model IstioConfigBulkApplyObject
+type IstioConfigBulkApplyObject struct {
+	// required: true
+	ObjectType string `json:"objectType"`
+
+	// required: true
+	Name string `json:"name"`
+
+	// The object: its name, namespace, labels and annotations, and its spec
+	// required: true
+	Object json.RawMessage `json:"object"`
+
+	// The object before the apply, empty for a create
+	Previous json.RawMessage `json:"previous,omitempty"`
+
+	// create, when the object does not exist, or update
+	// required: true
+	Operation string `json:"operation"`
+
+	// applied | failed | rolledBack | rollbackFailed | skipped
+	// required: true
+	Status string `json:"status"`
+
+	// The error of a failed apply or rollback
+	Error string `json:"error,omitempty"`
+}
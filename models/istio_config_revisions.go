@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Operations replacing a revision
+const (
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionUpdate  = "update"
+)
+
+// IstioConfigRevision is a previous state of an Istio object, stored before Kiali changed the object
+// swagger:model IstioConfigRevision
+type IstioConfigRevision struct {
+	// The revision number, increasing with each change of the object
+	// required: true
+	Revision int `json:"revision"`
+
+	// The time the object was changed
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// The change replacing this state: update, delete or restore
+	// required: true
+	Operation string `json:"operation"`
+
+	// The object: its name, namespace, labels and annotations, and its spec
+	// required: true
+	Object json.RawMessage `json:"object"`
+}
+
+// IstioConfigRevisionDiff holds the field changes from a revision of an Istio object to another revision, or to
+// the current object
+// swagger:model IstioConfigRevisionDiff
+type IstioConfigRevisionDiff struct {
+	// required: true
+	From int `json:"from"`
+
+	// The compared revision, 0 for the current object
+	// required: true
+	To int `json:"to"`
+
+	// The changed fields, by their path in the object (e.g. "spec.http[0].route[0].weight")
+	// required: true
+	Fields []FieldChange `json:"fields"`
+}
+
+// IstioConfigRevisionRestore is the result of the restore of a revision
+// swagger:model IstioConfigRevisionRestore
+type IstioConfigRevisionRestore struct {
+	// True when the revision is applied. A revision introducing validation errors is only applied when forced.
+	// required: true
+	Applied bool `json:"applied"`
+
+	// The validation of the revision against the current Istio config
+	// required: true
+	Validations IstioValidationsDryRun `json:"validations"`
+
+	// The restored object, when applied
+	Details *IstioConfigDetails `json:"details,omitempty"`
+}
+
+// DiffIstioConfigRevisions returns the changed fields from an object to another, sorted by path
+func DiffIstioConfigRevisions(from, to json.RawMessage) []FieldChange {
+	return diffFields(from, to)
+}
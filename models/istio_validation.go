@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
@@ -84,6 +85,69 @@ type IstioCheck struct {
 	Path string `json:"path"`
 }
 
+// IstioValidationChange is a check that an Istio config change introduces, or resolves, on an Istio object
+type IstioValidationChange struct {
+	IstioValidationKey
+
+	// The introduced, or resolved, check
+	// required: true
+	Check *IstioCheck `json:"check"`
+
+	// Related objects of the checked object
+	References []IstioValidationKey `json:"references"`
+}
+
+// IstioValidationsDryRun is the validation of an Istio config change that is not applied
+// swagger:model
+type IstioValidationsDryRun struct {
+	// Validation of the proposed object
+	// required: true
+	Validation *IstioValidation `json:"validation"`
+
+	// Checks the change introduces, on the proposed object or on the objects related to it
+	// required: true
+	Introduced []IstioValidationChange `json:"introduced"`
+
+	// Checks the change resolves, on the current object or on the objects related to it
+	// required: true
+	Resolved []IstioValidationChange `json:"resolved"`
+}
+
+// IntroducesErrors returns true if the change introduces error checks
+func (dryRun IstioValidationsDryRun) IntroducesErrors() bool {
+	return hasErrorChecks(dryRun.Introduced)
+}
+
+// IstioValidationsBulkDryRun is the validation of a set of Istio config changes that are not applied
+// swagger:model
+type IstioValidationsBulkDryRun struct {
+	// Validations of the proposed objects, in the order of the objects
+	// required: true
+	Validations []*IstioValidation `json:"validations"`
+
+	// Checks the changes introduce, on the proposed objects or on the objects related to them
+	// required: true
+	Introduced []IstioValidationChange `json:"introduced"`
+
+	// Checks the changes resolve, on the current objects or on the objects related to them
+	// required: true
+	Resolved []IstioValidationChange `json:"resolved"`
+}
+
+// IntroducesErrors returns true if the changes introduce error checks
+func (dryRun IstioValidationsBulkDryRun) IntroducesErrors() bool {
+	return hasErrorChecks(dryRun.Introduced)
+}
+
+func hasErrorChecks(changes []IstioValidationChange) bool {
+	for _, change := range changes {
+		if change.Check != nil && change.Check.Severity == ErrorSeverity {
+			return true
+		}
+	}
+	return false
+}
+
 type SeverityLevel string
 
 const (
@@ -306,6 +370,26 @@ var checkDescriptors = map[string]IstioCheck{
 		Message:  "Subset not found",
 		Severity: WarningSeverity,
 	},
+	"proxy.virtualservice.routemissing": {
+		Code:     "KIA1501",
+		Message:  "VirtualService routes are missing from the proxy routes",
+		Severity: WarningSeverity,
+	},
+	"proxy.destinationrule.subsetmissing": {
+		Code:     "KIA1502",
+		Message:  "DestinationRule subset has no matching cluster in the proxy",
+		Severity: WarningSeverity,
+	},
+	"proxy.sidecar.listenernotexcluded": {
+		Code:     "KIA1503",
+		Message:  "Proxy has a listener on a port not included in the Sidecar egress",
+		Severity: WarningSeverity,
+	},
+	"proxy.status.stale": {
+		Code:     "KIA1504",
+		Message:  "Proxy config is stale, the last config sent by istiod was not acknowledged",
+		Severity: WarningSeverity,
+	},
 	"validation.unable.cross-namespace": {
 		Code:     "KIA0001",
 		Message:  "Unable to verify the validity, cross-namespace validation is not supported for this field",
@@ -411,6 +495,43 @@ func (iv IstioValidations) MergeValidations(validations IstioValidations) IstioV
 	return iv
 }
 
+// ChecksNotIn returns the checks of iv not found, with the same code, path and severity, on the same object in
+// validations. Changes are sorted by object type, namespace, name and check code.
+func (iv IstioValidations) ChecksNotIn(validations IstioValidations) []IstioValidationChange {
+	changes := []IstioValidationChange{}
+	for key, validation := range iv {
+	NextCheck:
+		for _, check := range validation.Checks {
+			if other, found := validations[key]; found {
+				for _, otherCheck := range other.Checks {
+					if check.Code == otherCheck.Code && check.Path == otherCheck.Path && check.Severity == otherCheck.Severity {
+						continue NextCheck
+					}
+				}
+			}
+			changes = append(changes, IstioValidationChange{
+				IstioValidationKey: key,
+				Check:              check,
+				References:         validation.References,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		ki, kj := changes[i].IstioValidationKey, changes[j].IstioValidationKey
+		if ki.ObjectType != kj.ObjectType {
+			return ki.ObjectType < kj.ObjectType
+		}
+		if ki.Namespace != kj.Namespace {
+			return ki.Namespace < kj.Namespace
+		}
+		if ki.Name != kj.Name {
+			return ki.Name < kj.Name
+		}
+		return changes[i].Check.Code < changes[j].Check.Code
+	})
+	return changes
+}
+
 func (iv IstioValidations) MergeReferences(validations IstioValidations) IstioValidations {
 	for _, currentValidations := range iv {
 		if currentValidations.References == nil {
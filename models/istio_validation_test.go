@@ -84,3 +84,45 @@ func TestSummarizeValidations(t *testing.T) {
 	assert.Equal(1, summary.Warnings)
 	assert.Equal(1, summary.Errors)
 }
+
+func TestChecksNotIn(t *testing.T) {
+	assert := assert.New(t)
+
+	vsKey := IstioValidationKey{ObjectType: "virtualservice", Namespace: "bookinfo", Name: "reviews"}
+	drKey := IstioValidationKey{ObjectType: "destinationrule", Namespace: "bookinfo", Name: "reviews"}
+	before := IstioValidations{
+		vsKey: &IstioValidation{Checks: []*IstioCheck{
+			{Code: "KIA1106", Severity: WarningSeverity, Path: "spec/hosts"},
+		}},
+		drKey: &IstioValidation{Checks: []*IstioCheck{
+			{Code: "KIA0203", Severity: ErrorSeverity, Path: "spec/subsets[0]"},
+		}},
+	}
+	after := IstioValidations{
+		vsKey: &IstioValidation{
+			Checks: []*IstioCheck{
+				{Code: "KIA1106", Severity: WarningSeverity, Path: "spec/hosts"},
+				{Code: "KIA1101", Severity: ErrorSeverity, Path: "spec/http[0]/route[0]/destination/host"},
+			},
+			References: []IstioValidationKey{drKey},
+		},
+		drKey: &IstioValidation{Checks: []*IstioCheck{
+			{Code: "KIA0203", Severity: Unknown, Path: "spec/subsets[0]"},
+		}},
+	}
+
+	introduced := after.ChecksNotIn(before)
+	assert.Len(introduced, 2)
+	assert.Equal(drKey, introduced[0].IstioValidationKey)
+	assert.Equal(Unknown, introduced[0].Check.Severity)
+	assert.Equal(vsKey, introduced[1].IstioValidationKey)
+	assert.Equal("KIA1101", introduced[1].Check.Code)
+	assert.Equal([]IstioValidationKey{drKey}, introduced[1].References)
+
+	resolved := before.ChecksNotIn(after)
+	assert.Len(resolved, 1)
+	assert.Equal(drKey, resolved[0].IstioValidationKey)
+	assert.Equal(ErrorSeverity, resolved[0].Check.Severity)
+
+	assert.Empty(after.ChecksNotIn(after))
+}
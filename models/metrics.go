@@ -28,6 +28,7 @@ type IstioMetricsQuery struct {
 	Reporter        string // source | destination, defaults to source if not provided
 	Aggregate       string
 	AggregateValue  string
+	Exemplars       bool // attach the exemplars, linking the histograms to traces
 }
 
 // FillDefaults fills the struct with default parameters
@@ -116,10 +117,21 @@ func (t *Target) GenKey() string {
 type Metric struct {
 	Labels     map[string]string `json:"labels"`
 	Datapoints []Datapoint       `json:"datapoints"`
+	Exemplars  []Exemplar        `json:"exemplars,omitempty"`
 	Stat       string            `json:"stat,omitempty"`
 	Name       string            `json:"name"`
 }
 
+// Exemplar is an observation of a histogram series linked to a trace, the trace details are available from
+// /api/traces/{traceId}
+type Exemplar struct {
+	Timestamp pmod.Time `json:"timestamp"` // unix time in seconds, with a millisecond precision, as the datapoints
+	Value     float64   `json:"value"`
+	TraceID   string    `json:"traceId"`
+	// The other exemplar labels, e.g. span_id
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 type Datapoint struct {
 	Timestamp int64
 	Value     float64
@@ -237,3 +249,76 @@ func convertSamplePair(from *pmod.SamplePair, scale float64) Datapoint {
 		Value:     scale * float64(from.Value),
 	}
 }
+
+// exemplarTraceIDLabels are the exemplar labels holding the trace ID, by order of preference
+var exemplarTraceIDLabels = []pmod.LabelName{"trace_id", "traceID", "traceId"}
+
+// AttachExemplars attaches to the histogram series the exemplars of the bucket series they aggregate, i.e. having
+// the same values for the series labels. The exemplars are attached once, to the series of a single stat: avg
+// when requested, otherwise the first stat. The exemplars without a trace ID are skipped.
+func AttachExemplars(metrics []Metric, from []prometheus.ExemplarSeries, scale float64) {
+	if len(metrics) == 0 {
+		return
+	}
+	stat := metrics[0].Stat
+	for _, metric := range metrics {
+		if metric.Stat == "avg" {
+			stat = metric.Stat
+			break
+		}
+	}
+
+	for i := range metrics {
+		metric := &metrics[i]
+		if metric.Stat != stat {
+			continue
+		}
+		for _, series := range from {
+			if !seriesMatches(metric.Labels, series.SeriesLabels) {
+				continue
+			}
+			for _, e := range series.Exemplars {
+				if exemplar, ok := convertExemplar(e, scale); ok {
+					metric.Exemplars = append(metric.Exemplars, exemplar)
+				}
+			}
+		}
+		sort.SliceStable(metric.Exemplars, func(i, j int) bool {
+			return metric.Exemplars[i].Timestamp < metric.Exemplars[j].Timestamp
+		})
+	}
+}
+
+func seriesMatches(labels map[string]string, seriesLabels pmod.LabelSet) bool {
+	for name, value := range labels {
+		if string(seriesLabels[pmod.LabelName(name)]) != value {
+			return false
+		}
+	}
+	return true
+}
+
+func convertExemplar(from prometheus.Exemplar, scale float64) (Exemplar, bool) {
+	exemplar := Exemplar{
+		Timestamp: from.Timestamp,
+		Value:     scale * float64(from.Value),
+	}
+	var traceIDLabel pmod.LabelName
+	for _, name := range exemplarTraceIDLabels {
+		if traceID, ok := from.Labels[name]; ok {
+			exemplar.TraceID = string(traceID)
+			traceIDLabel = name
+			break
+		}
+	}
+	for name, value := range from.Labels {
+		if name == traceIDLabel {
+			continue
+		}
+		if exemplar.Labels == nil {
+			exemplar.Labels = map[string]string{}
+		}
+		exemplar.Labels[string(name)] = string(value)
+	}
+	return exemplar, exemplar.TraceID != ""
+}
@@ -55,6 +55,7 @@ type ServiceDetails struct {
 	Validations       IstioValidations  `json:"validations"`
 	NamespaceMTLS     MTLSStatus        `json:"namespaceMTLS"`
 	AdditionalDetails []AdditionalItem  `json:"additionalDetails"`
+	SLOs              *ServiceSLOs      `json:"slos,omitempty"`
 }
 
 type Services []*Service
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/kiali/kiali/config"
+)
+
+// SLOAnnotation sets the objectives of a service, replacing the configured ones
+const SLOAnnotation AnnotationKey = "slo.kiali.io/objectives"
+
+// SLOLatencyBuckets are the buckets of the Istio request duration histogram, in milliseconds. A latency objective
+// counts the requests of the bucket of its threshold, the threshold must be one of them.
+var SLOLatencyBuckets = []float64{0.5, 1, 5, 10, 25, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 30000, 60000, 300000, 600000, 1800000, 3600000}
+
+// SLO sources
+const (
+	SLOSourceAnnotation = "annotation"
+	SLOSourceConfig     = "config"
+)
+
+// ServiceSLOs holds the service level objectives of a service and their error budget
+// swagger:model ServiceSLOs
+type ServiceSLOs struct {
+	// The namespace of the service
+	// required: true
+	Namespace string `json:"namespace"`
+
+	// The service name
+	// required: true
+	Service string `json:"service"`
+
+	// Where the objectives are defined: annotation | config
+	// required: true
+	Source string `json:"source"`
+
+	// required: true
+	Objectives []SLOStatus `json:"objectives"`
+}
+
+// SLOStatus is an objective with its service level indicator and error budget. The percentages are nil when the
+// service had no request over the window.
+type SLOStatus struct {
+	config.SLO
+
+	// The percentage of good requests over the window
+	SLI *float64 `json:"sli"`
+
+	// The percentage of the error budget remaining over the window, negative when exhausted
+	ErrorBudgetRemaining *float64 `json:"errorBudgetRemaining"`
+
+	// The error budget burn rates over the configured windows
+	// required: true
+	BurnRates []BurnRate `json:"burnRates"`
+}
+
+// BurnRate is the error budget burn rate over a long and a short window: the ratio of the bad requests to the
+// error budget, 1 consuming exactly the budget over the objective window. Firing is true when both reach the
+// threshold.
+type BurnRate struct {
+	config.BurnRateWindow
+
+	LongBurnRate  *float64 `json:"longBurnRate"`
+	ShortBurnRate *float64 `json:"shortBurnRate"`
+
+	// required: true
+	Firing bool `json:"firing"`
+}
+
+// ParseSLOAnnotation parses the objectives of a slo.kiali.io/objectives annotation, a semicolon separated list
+// of "<type>,<target>[,<window>[,<threshold>]]" objectives, the threshold being required for latency objectives
+func ParseSLOAnnotation(annotation string) ([]config.SLO, error) {
+	objectives := []config.SLO{}
+	for _, value := range strings.Split(annotation, ";") {
+		if strings.TrimSpace(value) == "" {
+			continue
+		}
+		fields := strings.Split(value, ",")
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("objective [%s] does not have the format <type>,<target>[,<window>[,<threshold>]]", value)
+		}
+		slo := config.SLO{Name: fields[0], Type: fields[0]}
+		target, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("objective [%s] has an invalid target: %v", value, err)
+		}
+		slo.Target = target
+		if len(fields) > 2 {
+			slo.Window = fields[2]
+		}
+		if len(fields) > 3 {
+			if slo.Threshold, err = strconv.ParseFloat(fields[3], 64); err != nil {
+				return nil, fmt.Errorf("objective [%s] has an invalid threshold: %v", value, err)
+			}
+		}
+		if err := ValidateSLO(slo); err != nil {
+			return nil, fmt.Errorf("objective [%s]: %v", value, err)
+		}
+		objectives = append(objectives, slo)
+	}
+	return objectives, nil
+}
+
+// ValidateSLO checks the objective type, target, window and latency threshold
+func ValidateSLO(slo config.SLO) error {
+	switch slo.Type {
+	case config.SLOAvailability:
+	case config.SLOLatency:
+		if slo.Threshold <= 0 {
+			return fmt.Errorf("latency objective [%s] requires a positive threshold", slo.Name)
+		}
+		if !isSLOLatencyBucket(slo.Threshold) {
+			return fmt.Errorf("latency objective [%s] has an invalid threshold [%v], expecting an Istio request duration bucket %v", slo.Name, slo.Threshold, SLOLatencyBuckets)
+		}
+	default:
+		return fmt.Errorf("objective [%s] has an invalid type [%s], expecting availability or latency", slo.Name, slo.Type)
+	}
+	if slo.Target <= 0 || slo.Target >= 100 {
+		return fmt.Errorf("objective [%s] has an invalid target [%v], expecting a percentage between 0 and 100 excluded", slo.Name, slo.Target)
+	}
+	if slo.Window != "" {
+		if window, err := model.ParseDuration(slo.Window); err != nil || window <= 0 {
+			return fmt.Errorf("objective [%s] has an invalid window [%s], expecting a duration such as 30d", slo.Name, slo.Window)
+		}
+	}
+	return nil
+}
+
+func isSLOLatencyBucket(threshold float64) bool {
+	for _, bucket := range SLOLatencyBuckets {
+		if threshold == bucket {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/kiali/kiali/config"
+)
+
+func TestParseSLOAnnotation(t *testing.T) {
+	assert := assert.New(t)
+
+	objectives, err := ParseSLOAnnotation("availability,99.9; latency, 99, 7d, 250 ;")
+	assert.NoError(err)
+	assert.Equal([]config.SLO{
+		{Name: "availability", Type: config.SLOAvailability, Target: 99.9},
+		{Name: "latency", Type: config.SLOLatency, Target: 99, Window: "7d", Threshold: 250},
+	}, objectives)
+
+	_, err = ParseSLOAnnotation("availability")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("availability,high")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("availability,100")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("latency,99,30d")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("throughput,99")
+	assert.Error(err)
+	// the threshold must be a bucket of the request duration histogram
+	_, err = ParseSLOAnnotation("latency,99,30d,300")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("availability,99,a month")
+	assert.Error(err)
+	_, err = ParseSLOAnnotation("availability,99,-1d")
+	assert.Error(err)
+}
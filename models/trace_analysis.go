@@ -0,0 +1,124 @@
+package models
+
+// TracesAnalysis is the latency breakdown of a set of traces. All the times are in milliseconds.
+// swagger:model TracesAnalysis
+type TracesAnalysis struct {
+	// The number of analyzed traces
+	// required: true
+	Traces int `json:"traces"`
+
+	// The time spent in each service, by decreasing self time
+	// required: true
+	Services []ServiceLatency `json:"services"`
+
+	// The duration statistics of each span operation, by decreasing p95
+	// required: true
+	Operations []OperationLatency `json:"operations"`
+
+	// The time each span operation spends on the critical path of the traces, by decreasing time
+	// required: true
+	CriticalPath []CriticalPathLatency `json:"criticalPath"`
+}
+
+// ServiceLatency splits the time spent in the spans of a service between the service itself and its children
+type ServiceLatency struct {
+	// required: true
+	Service string `json:"service"`
+	// The number of spans of the service
+	// required: true
+	Spans int `json:"spans"`
+	// The time spent in the service, not covered by a child span
+	// required: true
+	SelfTime float64 `json:"selfTime"`
+	// The time spent waiting for the child spans
+	// required: true
+	ChildTime float64 `json:"childTime"`
+}
+
+// OperationLatency holds the duration percentiles of the spans of an operation
+type OperationLatency struct {
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	Operation string `json:"operation"`
+	// required: true
+	Count int `json:"count"`
+	// required: true
+	Min float64 `json:"min"`
+	// required: true
+	Max float64 `json:"max"`
+	// required: true
+	Mean float64 `json:"mean"`
+	// required: true
+	P50 float64 `json:"p50"`
+	// required: true
+	P90 float64 `json:"p90"`
+	// required: true
+	P95 float64 `json:"p95"`
+	// required: true
+	P99 float64 `json:"p99"`
+}
+
+// CriticalPathLatency is the time an operation spends on the critical path of the traces: the time the traces would
+// be shorter without it
+type CriticalPathLatency struct {
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	Operation string `json:"operation"`
+	// The total time on the critical path of the traces
+	// required: true
+	Time float64 `json:"time"`
+	// The share of the total duration of the traces, between 0 and 1
+	// required: true
+	Share float64 `json:"share"`
+}
+
+// TraceDiff compares a trace with a base trace span by span, to show where the extra latency came from. All the
+// times are in milliseconds, the deltas are the trace values minus the base trace values.
+// swagger:model TraceDiff
+type TraceDiff struct {
+	// required: true
+	BaseTraceID string `json:"baseTraceID"`
+	// required: true
+	TraceID string `json:"traceID"`
+	// required: true
+	BaseDuration float64 `json:"baseDuration"`
+	// required: true
+	Duration float64 `json:"duration"`
+	// required: true
+	DurationDelta float64 `json:"durationDelta"`
+
+	// The spans of both traces, by decreasing absolute self time delta
+	// required: true
+	Spans []SpanDiff `json:"spans"`
+}
+
+// SpanDiff statuses
+const (
+	SpanDiffMatched = "matched" // the span is in both traces
+	SpanDiffAdded   = "added"   // the span is only in the trace
+	SpanDiffRemoved = "removed" // the span is only in the base trace
+)
+
+// SpanDiff compares a span with the span at the same position of the base trace
+type SpanDiff struct {
+	// The service:operation path of the span from the root span, e.g. productpage:GET /productpage > reviews:GET /reviews
+	// required: true
+	Path string `json:"path"`
+	// required: true
+	Service string `json:"service"`
+	// required: true
+	Operation string `json:"operation"`
+	// matched, added or removed
+	// required: true
+	Status       string  `json:"status"`
+	BaseDuration float64 `json:"baseDuration"`
+	Duration     float64 `json:"duration"`
+	// required: true
+	DurationDelta float64 `json:"durationDelta"`
+	BaseSelfTime  float64 `json:"baseSelfTime"`
+	SelfTime      float64 `json:"selfTime"`
+	// required: true
+	SelfTimeDelta float64 `json:"selfTimeDelta"`
+}
@@ -0,0 +1,91 @@
+package models
+
+import "github.com/kiali/kiali/kubernetes"
+
+// TrafficIntent is a high level change of the routing of a service, translated into its VirtualService and
+// DestinationRule. At least one intent must be set.
+// swagger:model TrafficIntent
+type TrafficIntent struct {
+	// Shift a percentage of the service traffic to a version
+	Shift *TrafficShift `json:"shift,omitempty"`
+
+	// Mirror a percentage of the service traffic to a version
+	Mirror *TrafficMirror `json:"mirror,omitempty"`
+
+	// Inject faults in the service traffic
+	Fault *TrafficFault `json:"fault,omitempty"`
+
+	// The timeout of the service requests, e.g. "2s"
+	Timeout string `json:"timeout,omitempty"`
+
+	// Retry the failed service requests
+	Retries *TrafficRetries `json:"retries,omitempty"`
+}
+
+// TrafficShift routes Weight percent of the traffic to Version. The rest of the traffic is split between the other
+// versions, keeping their current proportions, or evenly when they are not weighted yet.
+type TrafficShift struct {
+	// required: true
+	// example: v3
+	Version string `json:"version"`
+
+	// required: true
+	// example: 20
+	Weight int `json:"weight"`
+}
+
+// TrafficMirror mirrors Percentage percent of the traffic to Version
+type TrafficMirror struct {
+	// required: true
+	// example: v2
+	Version string `json:"version"`
+
+	// required: true
+	// example: 10
+	Percentage float64 `json:"percentage"`
+}
+
+// TrafficFault aborts with HttpStatus, and/or delays by Delay, Percentage percent of the requests. With a Path, only
+// the requests with this path prefix are affected.
+type TrafficFault struct {
+	// example: /api
+	Path string `json:"path,omitempty"`
+
+	// required: true
+	// example: 5
+	Percentage float64 `json:"percentage"`
+
+	// example: 503
+	HttpStatus int `json:"httpStatus,omitempty"`
+
+	// example: 2s
+	Delay string `json:"delay,omitempty"`
+}
+
+// TrafficRetries retries the failed requests Attempts times
+type TrafficRetries struct {
+	// required: true
+	// example: 3
+	Attempts int `json:"attempts"`
+
+	// example: 2s
+	PerTryTimeout string `json:"perTryTimeout,omitempty"`
+
+	// example: 5xx,connect-failure
+	RetryOn string `json:"retryOn,omitempty"`
+}
+
+// TrafficRouting is the routing of a service generated from a TrafficIntent
+// swagger:model TrafficRouting
+type TrafficRouting struct {
+	// The VirtualService, the current one merged with the intent when it exists
+	// required: true
+	VirtualService kubernetes.IstioObject `json:"virtualService"`
+
+	// The DestinationRule defining the subsets of the versions, the current one merged with them when it exists. Null
+	// when no subset is added, e.g. for a timeout
+	DestinationRule kubernetes.IstioObject `json:"destinationRule"`
+
+	// The apply of the routing, when requested
+	Apply *IstioConfigBulkApply `json:"apply,omitempty"`
+}
@@ -123,6 +123,9 @@ type Workload struct {
 
 	// Additional details to display, such as configured annotations
 	AdditionalDetails []AdditionalItem `json:"additionalDetails"`
+
+	// Checks of the pods proxy config against the Istio config, only set when requested
+	ProxyChecks []*IstioCheck `json:"proxyChecks,omitempty"`
 }
 
 type Workloads []*Workload
@@ -29,6 +29,7 @@ var (
 type ClientInterface interface {
 	FetchHistogramRange(metricName, labels, grouping string, q *RangeQuery) Histogram
 	FetchHistogramValues(metricName, labels, grouping, rateInterval string, avg bool, quantiles []string, queryTime time.Time) (map[string]model.Vector, error)
+	FetchExemplars(metricName, labels string, q *RangeQuery) ([]ExemplarSeries, error)
 	FetchRange(metricName, labels, grouping, aggregator string, q *RangeQuery) Metric
 	FetchRateRange(metricName string, labels []string, grouping string, q *RangeQuery) Metric
 	GetAllRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
@@ -37,6 +38,7 @@ type ClientInterface interface {
 	GetFlags() (prom_v1.FlagsResult, error)
 	GetNamespaceServicesRequestRates(namespace, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetServiceRequestRates(namespace, service, ratesInterval string, queryTime time.Time) (model.Vector, error)
+	GetServiceRequestDurationRates(namespace, service, threshold, ratesInterval string, queryTime time.Time) (model.Vector, error)
 	GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error)
 	GetMetricsForLabels(labels []string) ([]string, error)
 }
@@ -186,6 +188,15 @@ func (in *Client) GetServiceRequestRates(namespace, service, ratesInterval strin
 	return result, nil
 }
 
+// GetServiceRequestDurationRates queries Prometheus to fetch the inbound request duration histogram rates over a
+// time interval for a given service, grouped by "le": the rate of the requests served within the threshold
+// milliseconds (threshold must be a histogram bucket), and the rate of all the requests (le="+Inf").
+// Only the destination reporter is considered.
+func (in *Client) GetServiceRequestDurationRates(namespace, service, threshold, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	log.Tracef("GetServiceRequestDurationRates [namespace: %s] [service: %s] [threshold: %s] [ratesInterval: %s] [queryTime: %s]", namespace, service, threshold, ratesInterval, queryTime.String())
+	return getServiceRequestDurationRates(in.ctx, in.api, namespace, service, threshold, queryTime, ratesInterval)
+}
+
 // GetAppRequestRates queries Prometheus to fetch request counters rates over a time interval
 // for a given app, both in and out. Note that it does not discriminate on "reporter", so rates can
 // be inflated due to duplication, and therefore should be used mainly for calculating ratios
@@ -255,6 +266,12 @@ func (in *Client) FetchHistogramValues(metricName, labels, grouping, rateInterva
 	return fetchHistogramValues(in.ctx, in.api, metricName, labels, grouping, rateInterval, avg, quantiles, queryTime)
 }
 
+// FetchExemplars fetches the exemplars of the buckets of a histogram over the query range. It requires Prometheus
+// 2.26+ with the exemplar storage enabled.
+func (in *Client) FetchExemplars(metricName, labels string, q *RangeQuery) ([]ExemplarSeries, error) {
+	return fetchExemplars(in.ctx, in.p8s, metricName, labels, q.Range)
+}
+
 // API returns the Prometheus V1 HTTP API for performing calls not supported natively by this client
 func (in *Client) API() prom_v1.API {
 	return in.api
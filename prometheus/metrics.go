@@ -2,10 +2,14 @@ package prometheus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/api"
 	prom_v1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -110,6 +114,44 @@ func fetchRange(ctx context.Context, api prom_v1.API, query string, bounds prom_
 	return Metric{Err: fmt.Errorf("invalid query, matrix expected: %s", query)}
 }
 
+// exemplarsResponse is the response of the query_exemplars API, not supported by the Prometheus client library
+type exemplarsResponse struct {
+	Status    string           `json:"status"`
+	Data      []ExemplarSeries `json:"data"`
+	ErrorType string           `json:"errorType"`
+	Error     string           `json:"error"`
+}
+
+func fetchExemplars(ctx context.Context, client api.Client, metricName, labels string, bounds prom_v1.Range) ([]ExemplarSeries, error) {
+	query := fmt.Sprintf("%s_bucket%s", metricName, labels)
+	log.Tracef("[Prom] fetchExemplars: %s", query)
+
+	u := client.URL("/api/v1/query_exemplars", nil)
+	q := u.Query()
+	q.Set("query", query)
+	q.Set("start", strconv.FormatFloat(float64(bounds.Start.UnixNano())/1e9, 'f', -1, 64))
+	q.Set("end", strconv.FormatFloat(float64(bounds.End.UnixNano())/1e9, 'f', -1, 64))
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, body, err := client.Do(ctx, req)
+	if err != nil {
+		return nil, errors.NewServiceUnavailable(err.Error())
+	}
+	var result exemplarsResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		// e.g. a 404 page of a Prometheus not supporting exemplars
+		return nil, fmt.Errorf("cannot parse the exemplars of %s [code: %d]: %v", metricName, resp.StatusCode, err)
+	}
+	if result.Status != "success" {
+		return nil, fmt.Errorf("exemplars query error [%s]: %s", result.ErrorType, result.Error)
+	}
+	return result.Data, nil
+}
+
 // getAllRequestRates retrieves traffic rates for requests entering, internal to, or exiting the namespace.
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
@@ -156,6 +198,24 @@ func getServiceRequestRates(ctx context.Context, api prom_v1.API, namespace, ser
 	return in, nil
 }
 
+// getServiceRequestDurationRates retrieves the rates of the requests entering a specific service name, served within
+// the threshold milliseconds, and of all those requests, grouped by "le"
+func getServiceRequestDurationRates(ctx context.Context, api prom_v1.API, namespace, service, threshold string, queryTime time.Time, ratesInterval string) (model.Vector, error) {
+	query := fmt.Sprintf(`sum(rate(istio_request_duration_milliseconds_bucket{reporter="destination",destination_service_name="%s",destination_service_namespace="%s",le=~"%s|\\+Inf"}[%s])) by (le)`,
+		service, namespace, threshold, ratesInterval)
+	log.Tracef("[Prom] getServiceRequestDurationRates: %s", query)
+	promtimer := internalmetrics.GetPrometheusProcessingTimePrometheusTimer("Metrics-GetRequestDurationRates")
+	result, warnings, err := api.Query(ctx, query, queryTime)
+	if warnings != nil && len(warnings) > 0 {
+		log.Warningf("getServiceRequestDurationRates. Prometheus Warnings: [%s]", strings.Join(warnings, ","))
+	}
+	if err != nil {
+		return model.Vector{}, errors.NewServiceUnavailable(err.Error())
+	}
+	promtimer.ObserveDuration() // notice we only collect metrics for successful prom queries
+	return result.(model.Vector), nil
+}
+
 // getItemRequestRates retrieves traffic rates for requests entering, internal to, or exiting the namespace, for a specific destinatation_<itemLabelSuffix> value
 // Note that it does not discriminate on "reporter", so rates can be inflated due to duplication, and therefore
 // should be used mainly for calculating ratios (e.g total rates / error rates)
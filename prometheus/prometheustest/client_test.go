@@ -1,6 +1,9 @@
 package prometheustest
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 	"time"
 
@@ -144,3 +147,55 @@ func mockConfig(api *PromAPIMock, ret prom_v1.ConfigResult) {
 func mockFlags(api *PromAPIMock, ret prom_v1.FlagsResult) {
 	api.On("Flags", mock.AnythingOfType("*context.emptyCtx")).Return(ret, nil)
 }
+
+func TestFetchExemplars(t *testing.T) {
+	assert := assert.New(t)
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal("/api/v1/query_exemplars", r.URL.Path)
+		query = r.URL.Query()
+		_, _ = w.Write([]byte(`{
+			"status": "success",
+			"data": [{
+				"seriesLabels": {"__name__": "istio_request_duration_milliseconds_bucket", "le": "50"},
+				"exemplars": [{"labels": {"trace_id": "13dd0f07194edc4a"}, "value": "42", "timestamp": 1600000000.5}]
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := prometheus.NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(err)
+	q := prometheus.RangeQuery{}
+	q.Start = time.Unix(1600000000, 0)
+	q.End = time.Unix(1600000600, 0)
+
+	series, err := client.FetchExemplars("istio_request_duration_milliseconds", `{reporter="source"}`, &q)
+
+	assert.NoError(err)
+	assert.Equal(`istio_request_duration_milliseconds_bucket{reporter="source"}`, query.Get("query"))
+	assert.Equal("1600000000", query.Get("start"))
+	assert.Equal("1600000600", query.Get("end"))
+	assert.Equal([]prometheus.ExemplarSeries{{
+		SeriesLabels: model.LabelSet{"__name__": "istio_request_duration_milliseconds_bucket", "le": "50"},
+		Exemplars: []prometheus.Exemplar{{
+			Labels:    model.LabelSet{"trace_id": "13dd0f07194edc4a"},
+			Value:     42,
+			Timestamp: model.Time(1600000000500),
+		}},
+	}}, series)
+}
+
+func TestFetchExemplarsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"status": "error", "errorType": "bad_data", "error": "invalid parameter"}`))
+	}))
+	defer server.Close()
+
+	client, err := prometheus.NewClientForConfig(config.PrometheusConfig{URL: server.URL})
+	assert.NoError(t, err)
+	_, err = client.FetchExemplars("istio_request_duration_milliseconds", "", &prometheus.RangeQuery{})
+
+	assert.EqualError(t, err, "exemplars query error [bad_data]: invalid parameter")
+}
@@ -296,6 +296,11 @@ func (o *PromClientMock) GetServiceRequestRates(namespace, service, ratesInterva
 	return args.Get(0).(model.Vector), args.Error(1)
 }
 
+func (o *PromClientMock) GetServiceRequestDurationRates(namespace, service, threshold, ratesInterval string, queryTime time.Time) (model.Vector, error) {
+	args := o.Called(namespace, service, threshold, ratesInterval, queryTime)
+	return args.Get(0).(model.Vector), args.Error(1)
+}
+
 func (o *PromClientMock) GetWorkloadRequestRates(namespace, workload, ratesInterval string, queryTime time.Time) (model.Vector, model.Vector, error) {
 	args := o.Called(namespace, workload, ratesInterval, queryTime)
 	return args.Get(0).(model.Vector), args.Get(1).(model.Vector), args.Error(2)
@@ -321,6 +326,11 @@ func (o *PromClientMock) FetchHistogramValues(metricName, labels, grouping, rate
 	return args.Get(0).(map[string]model.Vector), args.Error((1))
 }
 
+func (o *PromClientMock) FetchExemplars(metricName, labels string, q *prometheus.RangeQuery) ([]prometheus.ExemplarSeries, error) {
+	args := o.Called(metricName, labels, q)
+	return args.Get(0).([]prometheus.ExemplarSeries), args.Error(1)
+}
+
 func (o *PromClientMock) GetMetricsForLabels(labels []string) ([]string, error) {
 	args := o.Called(labels)
 	return args.Get(0).([]string), args.Error(1)
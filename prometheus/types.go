@@ -41,3 +41,16 @@ type Metric struct {
 
 // Histogram contains Metric objects for several histogram-kind statistics
 type Histogram = map[string]Metric
+
+// ExemplarSeries holds the exemplars of a histogram bucket series
+type ExemplarSeries struct {
+	SeriesLabels model.LabelSet `json:"seriesLabels"`
+	Exemplars    []Exemplar     `json:"exemplars"`
+}
+
+// Exemplar is an observation of a histogram, with the labels linking it to a trace (e.g. trace_id)
+type Exemplar struct {
+	Labels    model.LabelSet    `json:"labels"`
+	Value     model.SampleValue `json:"value"`
+	Timestamp model.Time        `json:"timestamp"`
+}
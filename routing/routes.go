@@ -287,6 +287,7 @@ func NewRoutes() (r *Routes) {
 		// swagger:route PATCH /namespaces/{namespace}/istio/{object_type}/{object} config istioConfigUpdate
 		// ---
 		// Endpoint to update the Istio Config of an Istio object used for templates and adapters using Json Merge Patch strategy.
+		// With dryRun=true the update is only validated, the response is an istioValidationsDryRunResponse.
 		//
 		//     Consumes:
 		//	   - application/json
@@ -309,9 +310,103 @@ func NewRoutes() (r *Routes) {
 			handlers.IstioConfigUpdate,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/revisions config istioConfigRevisions
+		// ---
+		// Endpoint to list the revisions of an Istio object, i.e. its states before it was changed through Kiali, oldest first.
+		// The revisions are kept in memory, the last 10 of up to 1000 objects, and are lost when Kiali restarts.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigRevisionsResponse
+		//
+		{
+			"IstioConfigRevisions",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions",
+			handlers.IstioConfigRevisions,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/istio/{object_type}/{object}/revisions/diff config istioConfigRevisionsDiff
+		// ---
+		// Endpoint to compare two revisions of an Istio object, or a revision with the current object.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigRevisionDiffResponse
+		//
+		{
+			"IstioConfigRevisionsDiff",
+			"GET",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions/diff",
+			handlers.IstioConfigRevisionsDiff,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio/{object_type}/{object}/revisions/{revision}/restore config istioConfigRevisionRestore
+		// ---
+		// Endpoint to restore a revision of an Istio object. The revision is validated against the current Istio config first,
+		// a revision introducing validation errors is not applied unless forced.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: istioConfigRevisionRestoreResponse
+		//
+		{
+			"IstioConfigRevisionRestore",
+			"POST",
+			"/api/namespaces/{namespace}/istio/{object_type}/{object}/revisions/{revision}/restore",
+			handlers.IstioConfigRevisionRestore,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/istio config istioConfigBulkApply
+		// ---
+		// Endpoint to apply a set of Istio objects, from yaml or json manifests. The objects are validated together
+		// and applied in dependency order, the applied objects are rolled back when an object fails.
+		// Objects introducing validation errors are only applied with force=true, with dryRun=true they are only validated.
+		// A failed apply responds 409 when it is rolled back, 500 when the rollback failed, with the status of each object.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: istioConfigBulkApplyResponse
+		//      500: internalError
+		//      200: istioConfigBulkApplyResponse
+		//
+		{
+			"IstioConfigBulkApply",
+			"POST",
+			"/api/namespaces/{namespace}/istio",
+			handlers.IstioConfigBulkApply,
+			true,
+		},
 		// swagger:route POST /namespaces/{namespace}/istio/{object_type} config istioConfigCreate
 		// ---
 		// Endpoint to create an Istio object by using an Istio Config item
+		// With dryRun=true the creation is only validated, the response is an istioValidationsDryRunResponse.
 		//
 		//     Produces:
 		//     - application/json
@@ -373,6 +468,28 @@ func NewRoutes() (r *Routes) {
 			handlers.ServiceDetails,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/slo services serviceSLOs
+		// ---
+		// Endpoint to get the service level objectives of a given service, with their SLI, remaining error budget and burn rates
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: serviceSLOsResponse
+		//
+		{
+			"ServiceSLOs",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/slo",
+			handlers.ServiceSLOs,
+			true,
+		},
 		// swagger:route PATCH /namespaces/{namespace}/services/{service} services serviceUpdate
 		// ---
 		// Endpoint to update the Service configuration using Json Merge Patch strategy.
@@ -398,6 +515,106 @@ func NewRoutes() (r *Routes) {
 			handlers.ServiceUpdate,
 			true,
 		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/traffic services serviceTrafficRouting
+		// ---
+		// Endpoint to generate the VirtualService and the DestinationRule of a service from a traffic intent: a traffic
+		// shift, a mirror, a fault injection, a timeout or retries. The current VirtualService and DestinationRule are
+		// merged with the intent. With apply=true the routing is applied, with dryRun=true it is only validated. A failed
+		// apply responds 409 when it is rolled back, 500 when the rollback failed.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: trafficRoutingResponse
+		//      500: internalError
+		//      200: trafficRoutingResponse
+		//
+		{
+			"ServiceTrafficRouting",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/traffic",
+			handlers.ServiceTrafficRouting,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/services/{service}/canary services canaryRollout
+		// ---
+		// Endpoint to get the canary rollout of a service
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRollout",
+			"GET",
+			"/api/namespaces/{namespace}/services/{service}/canary",
+			handlers.CanaryRollout,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/services/{service}/canary services canaryRolloutStart
+		// ---
+		// Endpoint to start the canary rollout of a service: its traffic is shifted step by step from the baseline
+		// version to the canary version while the canary meets the success criteria, and rolled back otherwise.
+		// A routing introducing validation errors is only applied with force=true.
+		//
+		//     Consumes:
+		//	   - application/json
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      409: conflictError
+		//      500: internalError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRolloutStart",
+			"POST",
+			"/api/namespaces/{namespace}/services/{service}/canary",
+			handlers.CanaryRolloutStart,
+			true,
+		},
+		// swagger:route DELETE /namespaces/{namespace}/services/{service}/canary services canaryRolloutAbort
+		// ---
+		// Endpoint to abort the progressing canary rollout of a service, its traffic is routed back to the baseline
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: canaryRolloutResponse
+		//
+		{
+			"CanaryRolloutAbort",
+			"DELETE",
+			"/api/namespaces/{namespace}/services/{service}/canary",
+			handlers.CanaryRolloutAbort,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/spans traces appSpans
 		// ---
 		// Endpoint to get Jaeger spans for a given app
@@ -518,6 +735,29 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadTraces,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/traces/analysis traces appTracesAnalysis
+		// ---
+		// Endpoint to get the latency breakdown of the traces of a given app: self and child time per service,
+		// duration percentiles per span operation and time on the critical path per span operation
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: tracesAnalysisResponse
+		//
+		{
+			"AppTracesAnalysis",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/traces/analysis",
+			handlers.AppTracesAnalysis,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/apps/{app}/errortraces traces errorTraces
 		// ---
 		// Endpoint to get the number of traces in error for a given service
@@ -560,6 +800,28 @@ func NewRoutes() (r *Routes) {
 			handlers.TraceDetails,
 			true,
 		},
+		// swagger:route GET /traces/{traceID}/compare/{baseTraceID} traces tracesComparison
+		// ---
+		// Endpoint to compare a trace with a base trace span by span, to show where the extra latency came from
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: traceDiffResponse
+		//
+		{
+			"TracesComparison",
+			"GET",
+			"/api/traces/{traceID}/compare/{baseTraceID}",
+			handlers.TracesComparison,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/workloads workloads workloadList
 		// ---
 		// Endpoint to get the list of workloads for a namespace
@@ -601,6 +863,52 @@ func NewRoutes() (r *Routes) {
 			handlers.WorkloadDetails,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/accesslogs/summary workloads workloadAccessLogSummary
+		// ---
+		// Endpoint to get the analytics of the proxy access logs of all the workload pods over a time window
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      404: notFoundError
+		//      200: accessLogSummaryResponse
+		//
+		{
+			"WorkloadAccessLogSummary",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/accesslogs/summary",
+			handlers.WorkloadAccessLogSummary,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/workloads/{workload}/logs workloads workloadLogs
+		// ---
+		// Endpoint to get the logs of all the containers of all the workload pods, merged in timestamp order. In follow
+		// mode, server-sent events with the new log entries.
+		//
+		//     Produces:
+		//     - application/json
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      404: notFoundError
+		//      200: logsResponse
+		//
+		{
+			"WorkloadLogs",
+			"GET",
+			"/api/namespaces/{namespace}/workloads/{workload}/logs",
+			handlers.WorkloadLogs,
+			true,
+		},
 		// swagger:route PATCH /namespaces/{namespace}/workloads/{workload} workloads workloadUpdate
 		// ---
 		// Endpoint to update the Workload configuration using Json Merge Patch strategy.
@@ -667,6 +975,30 @@ func NewRoutes() (r *Routes) {
 			handlers.AppDetails,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/apps/{app}/logs apps appLogs
+		// ---
+		// Endpoint to get the logs of all the containers of all the app pods, merged in timestamp order. In follow mode,
+		// server-sent events with the new log entries.
+		//
+		//     Produces:
+		//     - application/json
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      404: notFoundError
+		//      200: logsResponse
+		//
+		{
+			"AppLogs",
+			"GET",
+			"/api/namespaces/{namespace}/apps/{app}/logs",
+			handlers.AppLogs,
+			true,
+		},
 		// swagger:route GET /namespaces namespaces namespaceList
 		// ---
 		// Endpoint to get the list of the available namespaces
@@ -1075,6 +1407,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1090,12 +1425,65 @@ func NewRoutes() (r *Routes) {
 			handlers.GraphNamespaces,
 			true,
 		},
+		// swagger:route GET /traces/{traceID}/graph graphs graphTrace
+		// ---
+		// The backing JSON for the graph of a single trace: the workloads and services its requests went through,
+		// each edge with the span duration and error status.
+		//
+		//     Produces:
+		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: graphResponse
+		//
+		{
+			"GraphTrace",
+			"GET",
+			"/api/traces/{traceID}/graph",
+			handlers.GraphTrace,
+			true,
+		},
+		// swagger:route GET /namespaces/graph/stream graphs graphNamespacesStream
+		// ---
+		// Server-sent events for a live namespaces graph: a snapshot event with the cytoscape config, then a patch
+		// event with the changed nodes and edges every refresh interval.
+		//
+		//     Produces:
+		//     - text/event-stream
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: graphStreamResponse
+		//
+		{
+			"GraphNamespacesStream",
+			"GET",
+			"/api/namespaces/graph/stream",
+			handlers.GraphNamespacesStream,
+			true,
+		},
 		// swagger:route GET /namespaces/{namespace}/aggregates/{aggregate}/{aggregateValue}/graph graphs graphAggregate
 		// ---
 		// The backing JSON for an aggregate node detail graph. (supported graphTypes: app | versionedApp | workload)
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1118,6 +1506,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1140,6 +1531,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1162,6 +1556,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1183,6 +1580,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1204,6 +1604,9 @@ func NewRoutes() (r *Routes) {
 		//
 		//     Produces:
 		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
 		//
 		//     Schemes: http, https
 		//
@@ -1219,6 +1622,179 @@ func NewRoutes() (r *Routes) {
 			handlers.GraphNode,
 			true,
 		},
+		// swagger:route GET /graph/snapshots graphs graphSnapshotList
+		// ---
+		// The list of graph snapshots accessible to the user.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: graphSnapshotListResponse
+		//
+		{
+			"GraphSnapshotList",
+			"GET",
+			"/api/graph/snapshots",
+			handlers.GraphSnapshotList,
+			true,
+		},
+		// swagger:route POST /graph/snapshots graphs graphSnapshotCreate
+		// ---
+		// Generates a namespaces graph and persists it as a snapshot.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: graphSnapshotInfoResponse
+		//
+		{
+			"GraphSnapshotCreate",
+			"POST",
+			"/api/graph/snapshots",
+			handlers.GraphSnapshotCreate,
+			true,
+		},
+		// swagger:route GET /graph/snapshots/{snapshot} graphs graphSnapshot
+		// ---
+		// The backing JSON for a graph snapshot.
+		//
+		//     Produces:
+		//     - application/json
+		//     - application/graphml+xml
+		//     - text/vnd.graphviz
+		//     - text/vnd.mermaid
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphResponse
+		//
+		{
+			"GraphSnapshot",
+			"GET",
+			"/api/graph/snapshots/{snapshot}",
+			handlers.GraphSnapshot,
+			true,
+		},
+		// swagger:route DELETE /graph/snapshots/{snapshot} graphs graphSnapshotDelete
+		// ---
+		// Deletes a graph snapshot.
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200
+		//
+		{
+			"GraphSnapshotDelete",
+			"DELETE",
+			"/api/graph/snapshots/{snapshot}",
+			handlers.GraphSnapshotDelete,
+			true,
+		},
+		// swagger:route GET /graph/snapshots/{snapshot}/diff/{toSnapshot} graphs graphSnapshotDiff
+		// ---
+		// The nodes and edges added or removed, and the edge traffic changes, between two graph snapshots.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphSnapshotDiffResponse
+		//
+		{
+			"GraphSnapshotDiff",
+			"GET",
+			"/api/graph/snapshots/{snapshot}/diff/{toSnapshot}",
+			handlers.GraphSnapshotDiff,
+			true,
+		},
+		// swagger:route GET /graph/analysis/blastradius graphs graphBlastRadius
+		// ---
+		// The transitive upstream dependents and downstream dependencies of a node, weighted by request rate.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphBlastRadiusResponse
+		//
+		{
+			"GraphBlastRadius",
+			"GET",
+			"/api/graph/analysis/blastradius",
+			handlers.GraphBlastRadius,
+			true,
+		},
+		// swagger:route GET /graph/analysis/criticalpath graphs graphCriticalPath
+		// ---
+		// The highest-latency path between two nodes, using the edge response times.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphCriticalPathResponse
+		//
+		{
+			"GraphCriticalPath",
+			"GET",
+			"/api/graph/analysis/criticalpath",
+			handlers.GraphCriticalPath,
+			true,
+		},
+		// swagger:route GET /graph/analysis/health graphs graphHealthDrivers
+		// ---
+		// The edges whose error rate degrades a node's health.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      404: notFoundError
+		//      500: internalError
+		//      200: graphHealthDriversResponse
+		//
+		{
+			"GraphHealthDrivers",
+			"GET",
+			"/api/graph/analysis/health",
+			handlers.GraphHealthDrivers,
+			true,
+		},
 		// swagger:route GET /grafana integrations grafanaInfo
 		// ---
 		// Get the grafana URL and other descriptors
@@ -1346,6 +1922,71 @@ func NewRoutes() (r *Routes) {
 			handlers.ConfigDumpResourceEntries,
 			true,
 		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump_diff pods podProxyDumpDiff
+		// ---
+		// Endpoint to compare the pod proxy config with the one of another pod, or with a stored dump
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      404: notFoundError
+		//      200: configDumpDiff
+		//
+		{
+			"PodConfigDumpDiff",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/config_dump_diff",
+			handlers.ConfigDumpDiff,
+			true,
+		},
+		// swagger:route GET /namespaces/{namespace}/pods/{pod}/config_dump_snapshots pods podProxyDumpSnapshots
+		// ---
+		// Endpoint to list the stored pod proxy dumps
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      200: configDumpSnapshots
+		//
+		{
+			"PodConfigDumpSnapshots",
+			"GET",
+			"/api/namespaces/{namespace}/pods/{pod}/config_dump_snapshots",
+			handlers.ConfigDumpSnapshots,
+			true,
+		},
+		// swagger:route POST /namespaces/{namespace}/pods/{pod}/config_dump_snapshots pods podProxyDumpSnapshotCreate
+		// ---
+		// Endpoint to store the current pod proxy dump, to compare it later. The last 5 dumps of each pod are kept in
+		// memory, within a 64MB budget shared by all the pods.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      404: notFoundError
+		//      201: configDumpSnapshot
+		//
+		{
+			"PodConfigDumpSnapshotCreate",
+			"POST",
+			"/api/namespaces/{namespace}/pods/{pod}/config_dump_snapshots",
+			handlers.ConfigDumpSnapshotCreate,
+			true,
+		},
 		// swagger:route GET /iter8
 		// ---
 		// Endpoint to check if iter8 adapter is present in the cluster and if user can write adapter config
@@ -1540,6 +2181,70 @@ func NewRoutes() (r *Routes) {
 			handlers.GetClusters,
 			true,
 		},
+		// swagger:route GET /alerts alerts alerts
+		// ---
+		// The current health alerts, i.e. the degraded or failing apps and services, of the namespaces accessible to the user.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: alertsResponse
+		//
+		{
+			"Alerts",
+			"GET",
+			"/api/alerts",
+			handlers.Alerts,
+			true,
+		},
+		// swagger:route GET /alerts/history alerts alertsHistory
+		// ---
+		// The health status transitions of the apps and services of the namespaces accessible to the user, the most recent first.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: alertsHistoryResponse
+		//
+		{
+			"AlertsHistory",
+			"GET",
+			"/api/alerts/history",
+			handlers.AlertsHistory,
+			true,
+		},
+		// swagger:route GET /audit audit auditRecords
+		// ---
+		// The audit trail of the write operations on the namespaces accessible to the user, the most recent first.
+		//
+		//     Produces:
+		//     - application/json
+		//
+		//     Schemes: http, https
+		//
+		// responses:
+		//      400: badRequestError
+		//      500: internalError
+		//      503: serviceUnavailableError
+		//      200: auditRecordsResponse
+		//
+		{
+			"AuditRecords",
+			"GET",
+			"/api/audit",
+			handlers.AuditRecords,
+			true,
+		},
 	}
 
 	return
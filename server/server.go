@@ -9,10 +9,13 @@ import (
 	"github.com/NYTimes/gziphandler"
 	"github.com/gorilla/mux"
 
+	"github.com/kiali/kiali/alerting"
 	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/canary"
 	"github.com/kiali/kiali/config"
 	"github.com/kiali/kiali/log"
 	"github.com/kiali/kiali/routing"
+	"github.com/kiali/kiali/util/httputil"
 )
 
 type Server struct {
@@ -54,7 +57,9 @@ func NewServer() *Server {
 		Addr:         fmt.Sprintf("%v:%v", conf.Server.Address, conf.Server.Port),
 		TLSConfig:    tlsConfig,
 		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: httputil.WriteTimeout,
+		// the server-sent events extend the write timeout of their connection
+		ConnContext: httputil.ConnContext,
 	}
 
 	// return our new Server
@@ -87,11 +92,19 @@ func (s *Server) Start() {
 	if conf.Server.MetricsEnabled {
 		StartMetricsServer()
 	}
+
+	// Start watching the health, when alerting is enabled
+	alerting.Start()
+
+	// Start advancing the canary rollouts, when the canary controller is enabled
+	canary.Start()
 }
 
 // Stop the HTTP server
 func (s *Server) Stop() {
 	StopMetricsServer()
+	alerting.Stop()
+	canary.Stop()
 	business.Stop()
 	log.Infof("Server endpoint will stop at [%v]", s.httpServer.Addr)
 	s.httpServer.Close()
@@ -0,0 +1,33 @@
+package httputil
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// WriteTimeout is the server timeout for writing a response
+const WriteTimeout = 30 * time.Second
+
+type connContextKey struct{}
+
+// ConnContext is an http.Server ConnContext keeping the connection in the context of its requests, so that long
+// running responses can extend their write deadline with ExtendWriteDeadline
+func ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// ExtendWriteDeadline replaces the server write timeout of the response: it must now be written within the
+// timeout, e.g. the next event of a server-sent events stream. It is only supported by HTTP/1 connections, it
+// returns false when the write deadline is unchanged, HTTP/2 streams end with the server write timeout.
+func ExtendWriteDeadline(r *http.Request, timeout time.Duration) bool {
+	if r.ProtoMajor != 1 {
+		return false
+	}
+	conn, ok := r.Context().Value(connContextKey{}).(net.Conn)
+	if !ok {
+		return false
+	}
+	return conn.SetWriteDeadline(time.Now().Add(timeout)) == nil
+}
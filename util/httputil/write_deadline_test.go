@@ -0,0 +1,47 @@
+package httputil
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtendWriteDeadline(t *testing.T) {
+	// the response is written over 3 times the server write timeout
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 6; i++ {
+			if !ExtendWriteDeadline(r, 200*time.Millisecond) {
+				t.Error("write deadline not extended")
+			}
+			fmt.Fprintf(w, "line %d\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(50 * time.Millisecond)
+		}
+	}))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Config.ConnContext = ConnContext
+	server.Start()
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	lines := 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines++
+	}
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, 6, lines)
+}
+
+func TestExtendWriteDeadlineUnknownConnection(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	assert.False(t, ExtendWriteDeadline(r, time.Second))
+}
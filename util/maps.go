@@ -1,5 +1,7 @@
 package util
 
+import "reflect"
+
 func RemoveNilValues(root interface{}) {
 	if mRoot, isMap := root.(map[string]interface{}); isMap {
 		for k, v := range mRoot {
@@ -12,3 +14,49 @@ func RemoveNilValues(root interface{}) {
 		}
 	}
 }
+
+// MergePatch applies a JSON merge patch (RFC 7386) to a target, both as unmarshalled json. The target maps are modified.
+func MergePatch(target, patch interface{}) interface{} {
+	mPatch, isMap := patch.(map[string]interface{})
+	if !isMap {
+		return patch
+	}
+	mTarget, isMap := target.(map[string]interface{})
+	if !isMap {
+		mTarget = map[string]interface{}{}
+	}
+	for k, v := range mPatch {
+		if v == nil {
+			delete(mTarget, k)
+		} else {
+			mTarget[k] = MergePatch(mTarget[k], v)
+		}
+	}
+	return mTarget
+}
+
+// CreateMergePatch returns the JSON merge patch (RFC 7386) turning from into to, both as unmarshalled json
+func CreateMergePatch(from, to interface{}) interface{} {
+	mFrom, isMap := from.(map[string]interface{})
+	if !isMap {
+		return to
+	}
+	mTo, isMap := to.(map[string]interface{})
+	if !isMap {
+		return to
+	}
+	patch := map[string]interface{}{}
+	for k, v := range mFrom {
+		if toV, found := mTo[k]; !found {
+			patch[k] = nil
+		} else if !reflect.DeepEqual(v, toV) {
+			patch[k] = CreateMergePatch(v, toV)
+		}
+	}
+	for k, v := range mTo {
+		if _, found := mFrom[k]; !found {
+			patch[k] = v
+		}
+	}
+	return patch
+}
@@ -39,3 +39,65 @@ func TestRemoveNilValues(t *testing.T) {
 	assert.True(t, k3k1)
 	assert.True(t, k3k3k1)
 }
+
+func TestMergePatch(t *testing.T) {
+	target := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{
+			"d": "e",
+			"f": "g",
+		},
+		"h": []interface{}{"i"},
+	}
+	patch := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"f": nil,
+		},
+		"h": []interface{}{"j", "k"},
+		"l": map[string]interface{}{"m": nil, "n": "o"},
+	}
+
+	expected := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"d": "e",
+		},
+		"h": []interface{}{"j", "k"},
+		"l": map[string]interface{}{"n": "o"},
+	}
+	assert.Equal(t, expected, MergePatch(target, patch))
+	assert.Equal(t, "x", MergePatch(target, "x"))
+}
+
+func TestCreateMergePatch(t *testing.T) {
+	from := map[string]interface{}{
+		"a": "b",
+		"c": map[string]interface{}{
+			"d": "e",
+			"f": "g",
+		},
+		"h": []interface{}{"i"},
+		"p": "q",
+	}
+	to := map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"d": "e",
+		},
+		"h": []interface{}{"j", "k"},
+		"l": map[string]interface{}{"n": "o"},
+		"p": "q",
+	}
+
+	patch := CreateMergePatch(from, to)
+	assert.Equal(t, map[string]interface{}{
+		"a": "z",
+		"c": map[string]interface{}{
+			"f": nil,
+		},
+		"h": []interface{}{"j", "k"},
+		"l": map[string]interface{}{"n": "o"},
+	}, patch)
+	assert.Equal(t, to, MergePatch(from, patch))
+}
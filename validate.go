@@ -0,0 +1,350 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/kiali/kiali/business"
+	"github.com/kiali/kiali/config"
+	"github.com/kiali/kiali/kubernetes"
+	"github.com/kiali/kiali/models"
+)
+
+// Validate command output formats
+const (
+	validateOutputJSON  = "json"
+	validateOutputSARIF = "sarif"
+	validateOutputText  = "text"
+)
+
+// Validate command exit codes
+const (
+	validateExitOK     = 0 // no validation errors
+	validateExitFailed = 1 // validation errors, or warnings with -fail-on-warnings
+	validateExitError  = 2 // invalid arguments, or manifests that can't be read
+)
+
+const validateUsage = `Usage: kiali validate [flags] <file or directory>...
+
+Validates the Istio config of yaml, or json, manifests without a cluster: the Istio objects are checked
+against the other Istio objects, services and workloads of the manifests. Directories are read recursively.
+
+Flags:
+`
+
+// runValidate runs the validate command, it returns the process exit code
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("validate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr, validateUsage)
+		flags.PrintDefaults()
+	}
+	configFile := flags.String("config", "", "Path to the Kiali YAML configuration file, e.g. with the ignored checks and the validation policies.")
+	defaultNamespace := flags.String("namespace", "default", "Namespace of the objects without metadata.namespace.")
+	failOnWarnings := flags.Bool("fail-on-warnings", false, "Exit with a non-zero code on validation warnings, not only on errors.")
+	output := flags.String("output", validateOutputText, "Output format: text, json or sarif.")
+	if err := flags.Parse(args); err != nil {
+		return validateExitError
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return validateExitError
+	}
+	if *output != validateOutputText && *output != validateOutputJSON && *output != validateOutputSARIF {
+		fmt.Fprintf(stderr, "Invalid output [%s], expecting text, json or sarif\n", *output)
+		return validateExitError
+	}
+
+	conf := config.NewConfig()
+	if *configFile != "" {
+		var err error
+		if conf, err = config.LoadFromFile(*configFile); err != nil {
+			fmt.Fprintln(stderr, err)
+			return validateExitError
+		}
+	}
+	config.Set(conf)
+
+	manifests, sources, err := loadManifests(flags.Args(), *defaultNamespace)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return validateExitError
+	}
+
+	results := newValidateResults(business.ValidateManifests(manifests), sources)
+	switch *output {
+	case validateOutputJSON:
+		err = writeValidateJSON(stdout, results)
+	case validateOutputSARIF:
+		err = writeValidateSARIF(stdout, results)
+	default:
+		err = writeValidateText(stdout, results, len(manifests.IstioObjects))
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return validateExitError
+	}
+
+	errors, warnings := results.count()
+	if errors > 0 || (*failOnWarnings && warnings > 0) {
+		return validateExitFailed
+	}
+	return validateExitOK
+}
+
+// loadManifests reads the manifests of the given files and directories. It also returns the file of each
+// Istio object, by validation key.
+func loadManifests(paths []string, defaultNamespace string) (kubernetes.Manifests, map[models.IstioValidationKey]string, error) {
+	manifests := kubernetes.Manifests{}
+	sources := map[models.IstioValidationKey]string{}
+
+	load := func(path string) error {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		fileManifests := kubernetes.Manifests{}
+		if err := fileManifests.Decode(file, defaultNamespace); err != nil {
+			return fmt.Errorf("unable to read manifests of [%s]: %v", path, err)
+		}
+		for _, object := range fileManifests.IstioObjects {
+			sources[validationKey(object)] = filepath.ToSlash(path)
+		}
+		manifests.Merge(fileManifests)
+		return nil
+	}
+
+	for _, path := range paths {
+		err := filepath.Walk(path, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			switch strings.ToLower(filepath.Ext(path)) {
+			case ".json", ".yaml", ".yml":
+				return load(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return manifests, sources, err
+		}
+	}
+	return manifests, sources, nil
+}
+
+func validationKey(object kubernetes.IstioObject) models.IstioValidationKey {
+	return models.IstioValidationKey{
+		ObjectType: strings.ToLower(object.GetTypeMeta().Kind),
+		Name:       object.GetObjectMeta().Name,
+		Namespace:  object.GetObjectMeta().Namespace,
+	}
+}
+
+// validateResult is the validation of an object with checks
+type validateResult struct {
+	File      string `json:"file,omitempty"`
+	Namespace string `json:"namespace"`
+	*models.IstioValidation
+}
+
+type validateResults []validateResult
+
+// newValidateResults returns the validations with checks, sorted by namespace, type and name
+func newValidateResults(validations models.IstioValidations, sources map[models.IstioValidationKey]string) validateResults {
+	keys := make([]models.IstioValidationKey, 0, len(validations))
+	for key, validation := range validations {
+		if len(validation.Checks) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Namespace != keys[j].Namespace {
+			return keys[i].Namespace < keys[j].Namespace
+		}
+		if keys[i].ObjectType != keys[j].ObjectType {
+			return keys[i].ObjectType < keys[j].ObjectType
+		}
+		return keys[i].Name < keys[j].Name
+	})
+
+	results := make(validateResults, 0, len(keys))
+	for _, key := range keys {
+		results = append(results, validateResult{
+			File:            sources[key],
+			Namespace:       key.Namespace,
+			IstioValidation: validations[key],
+		})
+	}
+	return results
+}
+
+func (results validateResults) count() (errors, warnings int) {
+	for _, result := range results {
+		for _, check := range result.Checks {
+			if check.Severity == models.ErrorSeverity {
+				errors++
+			} else {
+				warnings++
+			}
+		}
+	}
+	return errors, warnings
+}
+
+func writeValidateText(w io.Writer, results validateResults, objectCount int) error {
+	for _, result := range results {
+		location := ""
+		if result.File != "" {
+			location = " (" + result.File + ")"
+		}
+		if _, err := fmt.Fprintf(w, "%s %s/%s%s\n", result.ObjectType, result.Namespace, result.Name, location); err != nil {
+			return err
+		}
+		for _, check := range result.Checks {
+			path := ""
+			if check.Path != "" {
+				path = " [" + check.Path + "]"
+			}
+			if _, err := fmt.Fprintf(w, "  %-7s %s %s%s\n", check.Severity, check.Code, check.Message, path); err != nil {
+				return err
+			}
+		}
+	}
+	errors, warnings := results.count()
+	_, err := fmt.Fprintf(w, "%d Istio objects validated: %d errors, %d warnings\n", objectCount, errors, warnings)
+	return err
+}
+
+func writeValidateJSON(w io.Writer, results validateResults) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// SARIF 2.1.0 (https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html), only the properties in use
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func writeValidateSARIF(w io.Writer, results validateResults) error {
+	run := sarifRun{
+		Tool: sarifTool{
+			Driver: sarifDriver{
+				Name:           "kiali",
+				Version:        version,
+				InformationURI: "https://kiali.io/documentation/latest/validations/",
+				Rules:          []sarifRule{},
+			},
+		},
+		Results: []sarifResult{},
+	}
+
+	rules := map[string]bool{}
+	for _, result := range results {
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: result.Namespace + "/" + result.ObjectType + "/" + result.Name,
+				Kind:               "resource",
+			}},
+		}
+		if result.File != "" {
+			location.PhysicalLocation = &sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: result.File}}
+		}
+
+		for _, check := range result.Checks {
+			if !rules[check.Code] {
+				rules[check.Code] = true
+				run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: check.Code, ShortDescription: sarifMessage{Text: check.Message}})
+			}
+			level := "warning"
+			if check.Severity == models.ErrorSeverity {
+				level = "error"
+			}
+			message := fmt.Sprintf("%s %s/%s: %s", result.ObjectType, result.Namespace, result.Name, check.Message)
+			if check.Path != "" {
+				message += " [" + check.Path + "]"
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    check.Code,
+				Level:     level,
+				Message:   sarifMessage{Text: message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+	sort.Slice(run.Tool.Driver.Rules, func(i, j int) bool {
+		return run.Tool.Driver.Rules[i].ID < run.Tool.Driver.Rules[j].ID
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	})
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validateTestManifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  ports:
+  - name: http
+    port: 9080
+---
+apiVersion: networking.istio.io/v1alpha3
+kind: VirtualService
+metadata:
+  name: reviews
+  namespace: bookinfo
+spec:
+  hosts:
+  - reviews
+  http:
+  - route:
+    - destination:
+        host: ratings
+`
+
+func writeValidateTestFiles(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "kiali-validate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	}
+	return dir
+}
+
+func TestValidateText(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeValidateTestFiles(t, map[string]string{
+		"bookinfo/reviews.yaml": validateTestManifests,
+		"README.md":             "not a manifest",
+	})
+	defer os.RemoveAll(dir)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	assert.Equal(validateExitFailed, runValidate([]string{dir}, stdout, stderr))
+	assert.Empty(stderr.String())
+
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	assert.Len(lines, 3)
+	assert.Equal("virtualservice bookinfo/reviews ("+filepath.ToSlash(filepath.Join(dir, "bookinfo/reviews.yaml"))+")", lines[0])
+	assert.Contains(lines[1], "error   KIA1101")
+	assert.Contains(lines[1], "[spec/http[0]/route[0]/destination/host]")
+	assert.Equal("1 Istio objects validated: 1 errors, 0 warnings", lines[2])
+}
+
+func TestValidateJSONAndSARIF(t *testing.T) {
+	assert := assert.New(t)
+	dir := writeValidateTestFiles(t, map[string]string{"reviews.yml": validateTestManifests})
+	defer os.RemoveAll(dir)
+
+	stdout := &bytes.Buffer{}
+	assert.Equal(validateExitFailed, runValidate([]string{"-output", "json", dir}, stdout, &bytes.Buffer{}))
+	results := []map[string]interface{}{}
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &results))
+	assert.Len(results, 1)
+	assert.Equal("bookinfo", results[0]["namespace"])
+	assert.Equal("reviews", results[0]["name"])
+	assert.Equal("virtualservice", results[0]["objectType"])
+	assert.Equal(false, results[0]["valid"])
+
+	stdout.Reset()
+	assert.Equal(validateExitFailed, runValidate([]string{"-output", "sarif", dir}, stdout, &bytes.Buffer{}))
+	sarif := sarifLog{}
+	assert.NoError(json.Unmarshal(stdout.Bytes(), &sarif))
+	assert.Equal("2.1.0", sarif.Version)
+	assert.Len(sarif.Runs, 1)
+	assert.Equal("KIA1101", sarif.Runs[0].Tool.Driver.Rules[0].ID)
+	assert.Len(sarif.Runs[0].Results, 1)
+	result := sarif.Runs[0].Results[0]
+	assert.Equal("error", result.Level)
+	assert.Equal(filepath.ToSlash(filepath.Join(dir, "reviews.yml")), result.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal("bookinfo/virtualservice/reviews", result.Locations[0].LogicalLocations[0].FullyQualifiedName)
+}
+
+func TestValidateExitCodes(t *testing.T) {
+	assert := assert.New(t)
+	valid := strings.Replace(validateTestManifests, "host: ratings", "host: reviews", 1)
+	dir := writeValidateTestFiles(t, map[string]string{"reviews.yaml": valid})
+	defer os.RemoveAll(dir)
+
+	assert.Equal(validateExitOK, runValidate([]string{dir}, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.Equal(validateExitError, runValidate([]string{}, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.Equal(validateExitError, runValidate([]string{"-output", "xml", dir}, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.Equal(validateExitError, runValidate([]string{filepath.Join(dir, "missing")}, &bytes.Buffer{}, &bytes.Buffer{}))
+
+	// a warning, the route subset is not defined
+	warning := strings.Replace(valid, "host: reviews\n", "host: reviews\n        subset: v1\n", 1)
+	assert.NoError(ioutil.WriteFile(filepath.Join(dir, "reviews.yaml"), []byte(warning), 0644))
+	assert.Equal(validateExitOK, runValidate([]string{dir}, &bytes.Buffer{}, &bytes.Buffer{}))
+	assert.Equal(validateExitFailed, runValidate([]string{"-fail-on-warnings", dir}, &bytes.Buffer{}, &bytes.Buffer{}))
+}